@@ -23,34 +23,38 @@ import (
 	"github.com/okteto/okteto/pkg/registry"
 )
 
+// GetBuildEnvVar returns the name of the env var exported by the builder for a service, like OKTETO_BUILD_<SVC>_IMAGE
+func GetBuildEnvVar(service, suffix string) string {
+	// Can't add env vars with -
+	sanitizedSvc := strings.ToUpper(strings.ReplaceAll(service, "-", "_"))
+	return fmt.Sprintf("OKTETO_BUILD_%s_%s", sanitizedSvc, suffix)
+}
+
 // SetServiceEnvVars set okteto build env vars
 func (bc *OktetoBuilder) SetServiceEnvVars(service, reference string) {
 	reg, repo, tag, image := registry.GetReferecenceEnvs(reference)
 
 	oktetoLog.Debugf("envs registry=%s repository=%s image=%s tag=%s", reg, repo, image, tag)
 
-	// Can't add env vars with -
-	sanitizedSvc := strings.ToUpper(strings.ReplaceAll(service, "-", "_"))
-
-	registryKey := fmt.Sprintf("OKTETO_BUILD_%s_REGISTRY", sanitizedSvc)
+	registryKey := GetBuildEnvVar(service, "REGISTRY")
 	bc.lock.Lock()
 	bc.buildEnvironments[registryKey] = reg
 	os.Setenv(registryKey, reg)
 	bc.lock.Unlock()
 
-	repositoryKey := fmt.Sprintf("OKTETO_BUILD_%s_REPOSITORY", sanitizedSvc)
+	repositoryKey := GetBuildEnvVar(service, "REPOSITORY")
 	bc.lock.Lock()
 	bc.buildEnvironments[repositoryKey] = repo
 	os.Setenv(repositoryKey, repo)
 	bc.lock.Unlock()
 
-	imageKey := fmt.Sprintf("OKTETO_BUILD_%s_IMAGE", sanitizedSvc)
+	imageKey := GetBuildEnvVar(service, "IMAGE")
 	bc.lock.Lock()
 	bc.buildEnvironments[imageKey] = reference
 	os.Setenv(imageKey, reference)
 	bc.lock.Unlock()
 
-	tagKey := fmt.Sprintf("OKTETO_BUILD_%s_TAG", sanitizedSvc)
+	tagKey := GetBuildEnvVar(service, "TAG")
 	bc.lock.Lock()
 	bc.buildEnvironments[tagKey] = tag
 	os.Setenv(tagKey, tag)
@@ -60,7 +64,7 @@ func (bc *OktetoBuilder) SetServiceEnvVars(service, reference string) {
 	if strings.HasPrefix(sha, "sha256:") {
 		sha = fmt.Sprintf("%s@%s", model.OktetoDefaultImageTag, sha)
 	}
-	shaKey := fmt.Sprintf("OKTETO_BUILD_%s_SHA", sanitizedSvc)
+	shaKey := GetBuildEnvVar(service, "SHA")
 	bc.lock.Lock()
 	bc.buildEnvironments[shaKey] = sha
 	os.Setenv(shaKey, sha)
@@ -164,3 +164,8 @@ func TestExpandStackVariables(t *testing.T) {
 	assert.NotEmpty(t, manifest.Deploy.ComposeSection.Stack.Services["test"].Image)
 	assert.NotEqual(t, manifest.Deploy.ComposeSection.Stack.Services["test"].Image, "{OKTETO_BUILD_TEST_IMAGE}")
 }
+
+func TestGetBuildEnvVar(t *testing.T) {
+	assert.Equal(t, "OKTETO_BUILD_MY_API_IMAGE", GetBuildEnvVar("my-api", "IMAGE"))
+	assert.Equal(t, "OKTETO_BUILD_FRONTEND_", GetBuildEnvVar("frontend", ""))
+}
@@ -0,0 +1,208 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/okteto/okteto/cmd/utils/displayer"
+	"github.com/okteto/okteto/cmd/utils/executor"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+const (
+	initialRetryBackoff = 2 * time.Second
+	maxRetryBackoff     = 30 * time.Second
+)
+
+// commandsRunner runs the commands of the 'deploy' section applying their retries, timeouts and conditions
+type commandsRunner struct {
+	executor  executor.ManifestExecutor
+	variables []string
+	sleep     func(time.Duration)
+
+	// outputFile is the file exposed as $OKTETO_OUTPUT to the commands
+	outputFile string
+	// outputs are the values published by the commands, available for the next ones
+	outputs map[string]string
+}
+
+func newCommandsRunner(e executor.ManifestExecutor, variables []string) *commandsRunner {
+	return &commandsRunner{
+		executor:  e,
+		variables: append([]string{}, variables...),
+		sleep:     time.Sleep,
+		outputs:   map[string]string{},
+	}
+}
+
+// run executes the commands in order. Once a command without 'continueOnError' fails, only the commands
+// conditioned to 'always' or 'failure' are executed, and the error of that command is returned at the end
+func (cr *commandsRunner) run(commands []model.DeployCommand) ([]displayer.CommandSummary, error) {
+	summaries := []displayer.CommandSummary{}
+	if len(commands) == 0 {
+		return summaries, nil
+	}
+
+	outputFile, err := os.CreateTemp("", "okteto-output-")
+	if err != nil {
+		return summaries, fmt.Errorf("error creating the file for the outputs of the commands: %w", err)
+	}
+	outputFile.Close()
+	defer os.Remove(outputFile.Name())
+	cr.outputFile = outputFile.Name()
+
+	anyFailed := false
+	var firstErr error
+	for _, command := range commands {
+		if !cr.shouldRun(command.When, anyFailed, firstErr != nil) {
+			oktetoLog.Information("Skipping '%s'", command.Name)
+			summaries = append(summaries, displayer.CommandSummary{Name: command.Name, Status: displayer.CommandSkipped})
+			continue
+		}
+
+		summary, err := cr.runWithRetries(command)
+		summaries = append(summaries, summary)
+		if err == nil {
+			continue
+		}
+		anyFailed = true
+		if command.ContinueOnError {
+			oktetoLog.Warning("%s. Continuing because 'continueOnError' is set", err.Error())
+			continue
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return summaries, firstErr
+}
+
+func (cr *commandsRunner) runWithRetries(command model.DeployCommand) (displayer.CommandSummary, error) {
+	summary := displayer.CommandSummary{Name: command.Name}
+	start := time.Now()
+	backoff := initialRetryBackoff
+	var err error
+	for {
+		summary.Attempts++
+		oktetoLog.Information("Running '%s'", command.Name)
+		oktetoLog.SetStage(command.Name)
+		err = cr.execute(command)
+		oktetoLog.SetStage("")
+		if err == nil || summary.Attempts > command.Retries {
+			break
+		}
+		oktetoLog.Warning("Command '%s' failed, retrying in %s (attempt %d of %d)", command.Name, backoff, summary.Attempts+1, command.Retries+1)
+		cr.sleep(backoff)
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+	summary.Duration = time.Since(start)
+
+	if err != nil {
+		oktetoLog.AddToBuffer(oktetoLog.ErrorLevel, "error executing command '%s': %s", command.Name, err.Error())
+		summary.Status = displayer.CommandFailed
+		summary.Error = err.Error()
+		return summary, fmt.Errorf("error executing command '%s': %s", command.Name, err.Error())
+	}
+	summary.Status = displayer.CommandSucceeded
+	return summary, nil
+}
+
+// execute runs a single attempt of a command. The outputs of failed attempts are discarded
+func (cr *commandsRunner) execute(command model.DeployCommand) error {
+	if err := os.WriteFile(cr.outputFile, []byte{}, 0600); err != nil {
+		return fmt.Errorf("error cleaning the outputs file: %w", err)
+	}
+	variables := append(cr.variables, fmt.Sprintf("%s=%s", model.OktetoOutputEnvVar, cr.outputFile))
+	if err := cr.executor.Execute(command, variables); err != nil {
+		return err
+	}
+
+	outputs, err := readOutputs(cr.outputFile)
+	if err != nil {
+		return err
+	}
+	for _, name := range getSortedKeys(outputs) {
+		oktetoLog.Infof("command '%s' published output '%s'", command.Name, name)
+		oktetoLog.AddMaskedWord(outputs[name])
+		cr.outputs[name] = outputs[name]
+		cr.variables = append(cr.variables, fmt.Sprintf("%s=%s", name, outputs[name]))
+	}
+	return nil
+}
+
+// shouldRun checks the 'when' conditions of a command. Commands without status don't run once a command has stopped the deploy
+func (cr *commandsRunner) shouldRun(when *model.DeployCommandCondition, anyFailed, stopped bool) bool {
+	status := ""
+	if when != nil {
+		status = when.Status
+	}
+	switch status {
+	case model.AlwaysCommandStatus:
+	case model.SuccessCommandStatus:
+		if anyFailed {
+			return false
+		}
+	case model.FailureCommandStatus:
+		if !anyFailed {
+			return false
+		}
+	default:
+		if stopped {
+			return false
+		}
+	}
+	if when == nil {
+		return true
+	}
+	if when.Env != "" && cr.getEnv(when.Env) == "" {
+		return false
+	}
+	if when.Branch != "" {
+		matched, err := path.Match(when.Branch, cr.getEnv(model.OktetoGitBranchEnvVar))
+		if err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// getEnv returns the value of a variable as seen by the commands: '--var' values have precedence over the environment
+func (cr *commandsRunner) getEnv(name string) string {
+	for i := len(cr.variables) - 1; i >= 0; i-- {
+		kv := strings.SplitN(cr.variables[i], "=", 2)
+		if len(kv) == 2 && kv[0] == name {
+			return kv[1]
+		}
+	}
+	return os.Getenv(name)
+}
+
+// shouldDisplaySummary returns true if any command was retried, skipped or failed
+func shouldDisplaySummary(summaries []displayer.CommandSummary) bool {
+	for _, summary := range summaries {
+		if summary.Status != displayer.CommandSucceeded || summary.Attempts > 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/cmd/utils/displayer"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeFlakyExecutor fails the first 'failures' executions of each command
+type fakeFlakyExecutor struct {
+	failures map[string]int
+	executed []string
+}
+
+func (fe *fakeFlakyExecutor) Execute(command model.DeployCommand, _ []string) error {
+	fe.executed = append(fe.executed, command.Name)
+	if fe.failures[command.Name] > 0 {
+		fe.failures[command.Name]--
+		return errors.New("exit status 1")
+	}
+	return nil
+}
+
+func (*fakeFlakyExecutor) CleanUp(_ error) {}
+
+func newTestCommandsRunner(e *fakeFlakyExecutor, variables []string) (*commandsRunner, *[]time.Duration) {
+	sleeps := &[]time.Duration{}
+	cr := newCommandsRunner(e, variables)
+	cr.sleep = func(d time.Duration) {
+		*sleeps = append(*sleeps, d)
+	}
+	return cr, sleeps
+}
+
+func TestCommandsRunnerRetries(t *testing.T) {
+	e := &fakeFlakyExecutor{failures: map[string]int{"migrate": 2}}
+	cr, sleeps := newTestCommandsRunner(e, nil)
+
+	summaries, err := cr.run([]model.DeployCommand{
+		{Name: "migrate", Command: "make migrate", Retries: 3},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"migrate", "migrate", "migrate"}, e.executed)
+	assert.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second}, *sleeps)
+	assert.Len(t, summaries, 1)
+	assert.Equal(t, displayer.CommandSucceeded, summaries[0].Status)
+	assert.Equal(t, 3, summaries[0].Attempts)
+}
+
+func TestCommandsRunnerRetriesExhausted(t *testing.T) {
+	e := &fakeFlakyExecutor{failures: map[string]int{"migrate": 10}}
+	cr, sleeps := newTestCommandsRunner(e, nil)
+
+	summaries, err := cr.run([]model.DeployCommand{
+		{Name: "migrate", Command: "make migrate", Retries: 5},
+		{Name: "deploy", Command: "make deploy"},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "error executing command 'migrate': exit status 1", err.Error())
+	assert.Len(t, e.executed, 6)
+	assert.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second}, *sleeps)
+	assert.Len(t, summaries, 2)
+	assert.Equal(t, displayer.CommandFailed, summaries[0].Status)
+	assert.Equal(t, 6, summaries[0].Attempts)
+	assert.Equal(t, "exit status 1", summaries[0].Error)
+	assert.Equal(t, displayer.CommandSkipped, summaries[1].Status)
+}
+
+func TestCommandsRunnerAfterFailure(t *testing.T) {
+	e := &fakeFlakyExecutor{failures: map[string]int{"migrate": 1, "cleanup": 1}}
+	cr, _ := newTestCommandsRunner(e, nil)
+
+	summaries, err := cr.run([]model.DeployCommand{
+		{Name: "migrate", Command: "make migrate"},
+		{Name: "deploy", Command: "make deploy"},
+		{Name: "notify", Command: "make notify", When: &model.DeployCommandCondition{Status: model.SuccessCommandStatus}},
+		{Name: "cleanup", Command: "make cleanup", When: &model.DeployCommandCondition{Status: model.AlwaysCommandStatus}},
+		{Name: "report", Command: "make report", When: &model.DeployCommandCondition{Status: model.FailureCommandStatus}},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "error executing command 'migrate': exit status 1", err.Error())
+	assert.Equal(t, []string{"migrate", "cleanup", "report"}, e.executed)
+	assert.Equal(t, []displayer.CommandStatus{
+		displayer.CommandFailed,
+		displayer.CommandSkipped,
+		displayer.CommandSkipped,
+		displayer.CommandFailed,
+		displayer.CommandSucceeded,
+	}, []displayer.CommandStatus{summaries[0].Status, summaries[1].Status, summaries[2].Status, summaries[3].Status, summaries[4].Status})
+}
+
+func TestCommandsRunnerContinueOnError(t *testing.T) {
+	e := &fakeFlakyExecutor{failures: map[string]int{"seed": 1}}
+	cr, _ := newTestCommandsRunner(e, nil)
+
+	summaries, err := cr.run([]model.DeployCommand{
+		{Name: "seed", Command: "make seed", ContinueOnError: true},
+		{Name: "deploy", Command: "make deploy"},
+		{Name: "notify", Command: "make notify", When: &model.DeployCommandCondition{Status: model.SuccessCommandStatus}},
+		{Name: "report", Command: "make report", When: &model.DeployCommandCondition{Status: model.FailureCommandStatus}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"seed", "deploy", "report"}, e.executed)
+	assert.Equal(t, []displayer.CommandStatus{
+		displayer.CommandFailed,
+		displayer.CommandSucceeded,
+		displayer.CommandSkipped,
+		displayer.CommandSucceeded,
+	}, []displayer.CommandStatus{summaries[0].Status, summaries[1].Status, summaries[2].Status, summaries[3].Status})
+}
+
+func TestCommandsRunnerShouldRun(t *testing.T) {
+	t.Setenv(model.OktetoGitBranchEnvVar, "release-1.0")
+	t.Setenv("FROM_ENV", "value")
+
+	tests := []struct {
+		name      string
+		when      *model.DeployCommandCondition
+		anyFailed bool
+		stopped   bool
+		expected  bool
+	}{
+		{
+			name:     "no-conditions",
+			expected: true,
+		},
+		{
+			name:      "always",
+			when:      &model.DeployCommandCondition{Status: model.AlwaysCommandStatus},
+			anyFailed: true,
+			expected:  true,
+		},
+		{
+			name:      "success-after-failure",
+			when:      &model.DeployCommandCondition{Status: model.SuccessCommandStatus},
+			anyFailed: true,
+			expected:  false,
+		},
+		{
+			name:      "no-conditions-after-stop",
+			anyFailed: true,
+			stopped:   true,
+			expected:  false,
+		},
+		{
+			name:      "always-after-stop",
+			when:      &model.DeployCommandCondition{Status: model.AlwaysCommandStatus},
+			anyFailed: true,
+			stopped:   true,
+			expected:  true,
+		},
+		{
+			name:     "failure-without-failures",
+			when:     &model.DeployCommandCondition{Status: model.FailureCommandStatus},
+			expected: false,
+		},
+		{
+			name:     "env-from-environment",
+			when:     &model.DeployCommandCondition{Env: "FROM_ENV"},
+			expected: true,
+		},
+		{
+			name:     "env-from-variables",
+			when:     &model.DeployCommandCondition{Env: "FROM_VAR"},
+			expected: true,
+		},
+		{
+			name:     "env-not-set",
+			when:     &model.DeployCommandCondition{Env: "MISSING"},
+			expected: false,
+		},
+		{
+			name:     "branch-matches",
+			when:     &model.DeployCommandCondition{Branch: "release-*"},
+			expected: true,
+		},
+		{
+			name:     "branch-does-not-match",
+			when:     &model.DeployCommandCondition{Branch: "main"},
+			expected: false,
+		},
+	}
+
+	cr := newCommandsRunner(&fakeFlakyExecutor{}, []string{"FROM_VAR=value"})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, cr.shouldRun(tt.when, tt.anyFailed, tt.stopped))
+		})
+	}
+}
+
+func TestShouldDisplaySummary(t *testing.T) {
+	assert.False(t, shouldDisplaySummary([]displayer.CommandSummary{
+		{Name: "build", Status: displayer.CommandSucceeded, Attempts: 1},
+	}))
+	assert.True(t, shouldDisplaySummary([]displayer.CommandSummary{
+		{Name: "build", Status: displayer.CommandSucceeded, Attempts: 2},
+	}))
+	assert.True(t, shouldDisplaySummary([]displayer.CommandSummary{
+		{Name: "build", Status: displayer.CommandSkipped},
+	}))
+}
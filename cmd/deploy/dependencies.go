@@ -0,0 +1,162 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+const (
+	// FailFastDependenciesPolicy stops scheduling dependencies as soon as one of them fails
+	FailFastDependenciesPolicy = "fail-fast"
+
+	// ContinueDependenciesPolicy keeps deploying the dependencies that don't depend on a failed one
+	ContinueDependenciesPolicy = "continue"
+
+	defaultDependenciesParallelism = 4
+)
+
+type deployDependencyFunc func(ctx context.Context, name string, dep *model.Dependency) error
+
+type dependencyResult struct {
+	name string
+	err  error
+}
+
+// deployDependencies deploys the dependencies following the graph defined by their 'depends_on' field.
+// Dependencies without pending requirements are deployed concurrently, with at most 'parallelism' running at the same time.
+// Unless policy is 'continue', no new dependency is scheduled once one of them fails.
+func deployDependencies(ctx context.Context, dependencies model.ManifestDependencies, parallelism int, policy string, deploy deployDependencyFunc) error {
+	if len(dependencies) == 0 {
+		return nil
+	}
+	if parallelism < 1 {
+		parallelism = defaultDependenciesParallelism
+	}
+	failFast := policy != ContinueDependenciesPolicy
+
+	depsCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pending := map[string]int{}
+	ready := []string{}
+	for name, dep := range dependencies {
+		// duplicated entries of 'depends_on' are only decremented once when the dependency is deployed
+		requirements := map[string]bool{}
+		for _, dependsOn := range dep.DependsOn {
+			requirements[dependsOn] = true
+		}
+		pending[name] = len(requirements)
+		if len(requirements) == 0 {
+			ready = append(ready, name)
+		}
+	}
+	sort.Strings(ready)
+
+	results := make(chan dependencyResult, len(dependencies))
+	deployed := map[string]bool{}
+	errs := map[string]error{}
+	running := 0
+
+	for len(ready) > 0 || running > 0 {
+		for len(ready) > 0 && running < parallelism {
+			if failFast && len(errs) > 0 {
+				ready = []string{}
+				break
+			}
+			name := ready[0]
+			ready = ready[1:]
+			running++
+			go func(name string) {
+				results <- dependencyResult{name: name, err: deploy(depsCtx, name, dependencies[name])}
+			}(name)
+		}
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+		if result.err != nil {
+			oktetoLog.Infof("dependency '%s' failed: %s", result.name, result.err)
+			errs[result.name] = result.err
+			if failFast {
+				cancel()
+			}
+			continue
+		}
+
+		deployed[result.name] = true
+		for _, dependent := range dependencies.GetDependents(result.name) {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	skipped := []string{}
+	for name := range dependencies {
+		if _, ok := errs[name]; ok || deployed[name] {
+			continue
+		}
+		skipped = append(skipped, name)
+	}
+	sort.Strings(skipped)
+
+	if len(errs) == 0 {
+		if len(skipped) > 0 {
+			return fmt.Errorf("dependencies [%s] could not be scheduled: check the dependencies declared in their 'depends_on' field", strings.Join(skipped, ", "))
+		}
+		return nil
+	}
+
+	if len(skipped) > 0 {
+		oktetoLog.Warning("The following dependencies were not deployed: [%s]", strings.Join(skipped, ", "))
+	}
+	return newDependenciesError(errs)
+}
+
+func newDependenciesError(errs map[string]error) error {
+	if len(errs) == 1 {
+		for name, err := range errs {
+			return fmt.Errorf("error deploying dependency '%s': %w", name, err)
+		}
+	}
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, 0, len(names))
+	for _, name := range names {
+		msgs = append(msgs, fmt.Sprintf("'%s': %s", name, errs[name].Error()))
+	}
+	return fmt.Errorf("error deploying dependencies: %s", strings.Join(msgs, ", "))
+}
+
+func validateDependenciesPolicy(policy string) error {
+	switch policy {
+	case FailFastDependenciesPolicy, ContinueDependenciesPolicy:
+		return nil
+	default:
+		return fmt.Errorf("invalid value '%s' for flag 'dependencies-policy': must be one of [%s, %s]", policy, FailFastDependenciesPolicy, ContinueDependenciesPolicy)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDependencyDeployer struct {
+	mu       sync.Mutex
+	deployed []string
+	running  int
+	maxSeen  int
+	failing  map[string]bool
+	release  chan struct{}
+}
+
+func (fd *fakeDependencyDeployer) deploy(_ context.Context, name string, _ *model.Dependency) error {
+	fd.mu.Lock()
+	fd.running++
+	if fd.running > fd.maxSeen {
+		fd.maxSeen = fd.running
+	}
+	fd.mu.Unlock()
+
+	if fd.release != nil {
+		<-fd.release
+	}
+
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	fd.running--
+	if fd.failing[name] {
+		return errors.New("deploy failed")
+	}
+	fd.deployed = append(fd.deployed, name)
+	return nil
+}
+
+func indexOf(list []string, value string) int {
+	for i, v := range list {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestDeployDependenciesOrder(t *testing.T) {
+	dependencies := model.ManifestDependencies{
+		"api":      &model.Dependency{DependsOn: []string{"db", "cache"}},
+		"frontend": &model.Dependency{DependsOn: []string{"api"}},
+		"db":       &model.Dependency{},
+		"cache":    &model.Dependency{},
+	}
+	fd := &fakeDependencyDeployer{}
+
+	err := deployDependencies(context.Background(), dependencies, 2, FailFastDependenciesPolicy, fd.deploy)
+	assert.NoError(t, err)
+	assert.Len(t, fd.deployed, 4)
+	assert.Less(t, indexOf(fd.deployed, "db"), indexOf(fd.deployed, "api"))
+	assert.Less(t, indexOf(fd.deployed, "cache"), indexOf(fd.deployed, "api"))
+	assert.Less(t, indexOf(fd.deployed, "api"), indexOf(fd.deployed, "frontend"))
+}
+
+func TestDeployDependenciesParallelism(t *testing.T) {
+	dependencies := model.ManifestDependencies{
+		"a": &model.Dependency{},
+		"b": &model.Dependency{},
+		"c": &model.Dependency{},
+		"d": &model.Dependency{},
+	}
+	fd := &fakeDependencyDeployer{release: make(chan struct{})}
+	go func() {
+		for i := 0; i < len(dependencies); i++ {
+			fd.release <- struct{}{}
+		}
+	}()
+
+	err := deployDependencies(context.Background(), dependencies, 2, FailFastDependenciesPolicy, fd.deploy)
+	assert.NoError(t, err)
+	assert.Len(t, fd.deployed, 4)
+	assert.LessOrEqual(t, fd.maxSeen, 2)
+}
+
+func TestDeployDependenciesFailurePolicy(t *testing.T) {
+	dependencies := model.ManifestDependencies{
+		"a": &model.Dependency{},
+		"b": &model.Dependency{DependsOn: []string{"a"}},
+		"c": &model.Dependency{},
+		"d": &model.Dependency{DependsOn: []string{"c"}},
+	}
+	tests := []struct {
+		name             string
+		policy           string
+		expectedDeployed []string
+	}{
+		{
+			name:             "fail-fast",
+			policy:           FailFastDependenciesPolicy,
+			expectedDeployed: []string{},
+		},
+		{
+			name:             "continue",
+			policy:           ContinueDependenciesPolicy,
+			expectedDeployed: []string{"c", "d"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fd := &fakeDependencyDeployer{
+				deployed: []string{},
+				failing:  map[string]bool{"a": true},
+			}
+			err := deployDependencies(context.Background(), dependencies, 1, tt.policy, fd.deploy)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "error deploying dependency 'a'")
+			assert.Equal(t, tt.expectedDeployed, fd.deployed)
+		})
+	}
+}
+
+func TestDeployDependenciesDuplicatedDependsOn(t *testing.T) {
+	dependencies := model.ManifestDependencies{
+		"api": &model.Dependency{DependsOn: []string{"db", "db"}},
+		"db":  &model.Dependency{},
+	}
+	fd := &fakeDependencyDeployer{}
+
+	err := deployDependencies(context.Background(), dependencies, 2, FailFastDependenciesPolicy, fd.deploy)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"db", "api"}, fd.deployed)
+}
+
+func TestDeployDependenciesNotScheduled(t *testing.T) {
+	dependencies := model.ManifestDependencies{
+		"api": &model.Dependency{DependsOn: []string{"unknown"}},
+		"db":  &model.Dependency{},
+	}
+	fd := &fakeDependencyDeployer{}
+
+	err := deployDependencies(context.Background(), dependencies, 2, FailFastDependenciesPolicy, fd.deploy)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "dependencies [api] could not be scheduled")
+	assert.Equal(t, []string{"db"}, fd.deployed)
+}
+
+func TestValidateDependenciesPolicy(t *testing.T) {
+	assert.NoError(t, validateDependenciesPolicy(FailFastDependenciesPolicy))
+	assert.NoError(t, validateDependenciesPolicy(ContinueDependenciesPolicy))
+	assert.Error(t, validateDependenciesPolicy("wrong"))
+}
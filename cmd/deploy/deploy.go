@@ -18,7 +18,9 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	buildv2 "github.com/okteto/okteto/cmd/build/v2"
@@ -27,6 +29,7 @@ import (
 	pipelineCMD "github.com/okteto/okteto/cmd/pipeline"
 	stackCMD "github.com/okteto/okteto/cmd/stack"
 	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/cmd/utils/displayer"
 	"github.com/okteto/okteto/cmd/utils/executor"
 	"github.com/okteto/okteto/pkg/analytics"
 	"github.com/okteto/okteto/pkg/cmd/pipeline"
@@ -42,6 +45,7 @@ import (
 	"github.com/okteto/okteto/pkg/types"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -67,12 +71,32 @@ type Options struct {
 	RunWithoutBash   bool
 	servicesToDeploy []string
 
+	// DependenciesParallelism is the max number of dependencies deployed at the same time
+	DependenciesParallelism int
+	// DependenciesPolicy defines what happens with the remaining dependencies when one of them fails
+	DependenciesPolicy string
+
+	// Plan shows what the deploy would do instead of deploying
+	Plan       bool
+	PlanOutput string
+	// Watch redeploys the services affected by local file changes after the deploy
+	Watch bool
+	// Profiles are the compose profiles to enable
+	Profiles []string
+
 	Repository string
 	Branch     string
 	Wait       bool
 	Timeout    time.Duration
 
 	ShowCTA bool
+
+	// outputs are the values published by the deploy commands through $OKTETO_OUTPUT
+	outputs map[string]string
+	// variablesHash identifies the variables set by the user, it is recorded in the deploy history
+	variablesHash string
+	// rollback is the deploy of the history being redeployed by 'okteto deploy rollback'
+	rollback *pipeline.HistoryEntry
 }
 
 // DeployCommand defines the config for deploying an app
@@ -102,10 +126,22 @@ func Deploy(ctx context.Context) *cobra.Command {
 			if options.Dependencies && !okteto.IsOkteto() {
 				return fmt.Errorf("'dependencies' is only supported in clusters that have Okteto installed")
 			}
+			if err := validateDependenciesPolicy(options.DependenciesPolicy); err != nil {
+				return err
+			}
+			if err := validatePlanOutput(options.PlanOutput); err != nil {
+				return err
+			}
+			if options.Watch && options.Plan {
+				return fmt.Errorf("'--watch' and '--plan' can't be used together")
+			}
 
 			if err := validateAndSet(options.Variables, os.Setenv); err != nil {
 				return err
 			}
+			if len(options.Profiles) > 0 {
+				os.Setenv(model.ComposeProfilesEnvVar, strings.Join(options.Profiles, ","))
+			}
 
 			// This is needed because the deploy command needs the original kubeconfig configuration even in the execution within another
 			// deploy command. If not, we could be proxying a proxy and we would be applying the incorrect deployed-by label
@@ -139,6 +175,16 @@ func Deploy(ctx context.Context) *cobra.Command {
 				}
 			}
 
+			if options.Plan {
+				options.servicesToDeploy = args
+				c := &DeployCommand{
+					GetManifest:       model.GetManifestV2,
+					K8sClientProvider: okteto.NewK8sClientProvider(),
+					Builder:           buildv2.NewBuilderFromScratch(),
+				}
+				return c.RunPlan(ctx, options)
+			}
+
 			if okteto.IsOkteto() {
 				create, err := utils.ShouldCreateNamespace(ctx, okteto.Context().Namespace)
 				if err != nil {
@@ -246,10 +292,17 @@ func Deploy(ctx context.Context) *cobra.Command {
 	cmd.Flags().BoolVarP(&options.Build, "build", "", false, "force build of images when deploying the development environment")
 	cmd.Flags().BoolVarP(&options.Dependencies, "dependencies", "", false, "deploy the dependencies from manifest")
 	cmd.Flags().BoolVarP(&options.RunWithoutBash, "no-bash", "", false, "execute commands without bash")
+	cmd.Flags().IntVarP(&options.DependenciesParallelism, "dependencies-parallelism", "", defaultDependenciesParallelism, "max number of dependencies deployed at the same time")
+	cmd.Flags().BoolVarP(&options.Plan, "plan", "", false, "show the actions the deploy would perform without executing them")
+	cmd.Flags().StringVarP(&options.PlanOutput, "plan-output", "", textPlanOutput, "output format of the deploy plan. One of: ['text', 'json']")
+	cmd.Flags().BoolVarP(&options.Watch, "watch", "", false, "redeploy the services affected by local file changes after the deploy")
+	cmd.Flags().StringArrayVarP(&options.Profiles, "profile", "", []string{}, "compose profile to enable (can be set more than once)")
+	cmd.Flags().StringVarP(&options.DependenciesPolicy, "dependencies-policy", "", FailFastDependenciesPolicy, "behavior when a dependency fails to deploy: 'fail-fast' or 'continue'")
 
 	cmd.Flags().BoolVarP(&options.Wait, "wait", "w", false, "wait until the development environment is deployed (defaults to false)")
 	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "t", (5 * time.Minute), "the length of time to wait for completion, zero means never. Any other values should contain a corresponding time unit e.g. 1s, 2m, 3h ")
 
+	cmd.AddCommand(Rollback(ctx))
 	return cmd
 }
 
@@ -292,14 +345,16 @@ func (dc *DeployCommand) RunDeploy(ctx context.Context, deployOptions *Options)
 	}
 
 	data := &pipeline.CfgData{
-		Name:       deployOptions.Name,
-		Namespace:  deployOptions.Manifest.Namespace,
-		Repository: os.Getenv(model.GithubRepositoryEnvVar),
-		Branch:     os.Getenv(model.OktetoGitBranchEnvVar),
-		Filename:   deployOptions.ManifestPathFlag,
-		Status:     pipeline.ProgressingStatus,
-		Manifest:   deployOptions.Manifest.Manifest,
-		Icon:       deployOptions.Manifest.Icon,
+		Name:        deployOptions.Name,
+		Namespace:   deployOptions.Manifest.Namespace,
+		Repository:  os.Getenv(model.GithubRepositoryEnvVar),
+		Branch:      os.Getenv(model.OktetoGitBranchEnvVar),
+		Filename:    deployOptions.ManifestPathFlag,
+		Status:      pipeline.ProgressingStatus,
+		Manifest:    deployOptions.Manifest.Manifest,
+		Icon:        deployOptions.Manifest.Icon,
+		IsDeploy:    true,
+		HelmRelease: getTrackedHelmRelease(deployOptions.Manifest, deployOptions.Name),
 	}
 
 	if !deployOptions.Manifest.IsV2 && deployOptions.Manifest.Type == model.StackType {
@@ -330,42 +385,28 @@ func (dc *DeployCommand) RunDeploy(ctx context.Context, deployOptions *Options)
 	oktetoLog.Debugf("starting server on %d", dc.Proxy.GetPort())
 	dc.Proxy.Start()
 
+	deployOptions.variablesHash = pipeline.GetVariablesHash(deployOptions.Variables)
 	cfg, err := getConfigMapFromData(ctx, data, c)
 	if err != nil {
 		return err
 	}
 
-	// TODO: take this out to a new function deploy dependencies
-	for depName, dep := range deployOptions.Manifest.Dependencies {
-		oktetoLog.Information("Deploying dependency '%s'", depName)
-		dep.Variables = append(dep.Variables, model.EnvVar{
-			Name:  "OKTETO_ORIGIN",
-			Value: "okteto-deploy",
-		})
-		pipOpts := &pipelineCMD.DeployOptions{
-			Name:         depName,
-			Repository:   dep.Repository,
-			Branch:       dep.Branch,
-			File:         dep.ManifestPath,
-			Variables:    model.SerializeEnvironmentVars(dep.Variables),
-			Wait:         dep.Wait,
-			Timeout:      deployOptions.Timeout,
-			SkipIfExists: !deployOptions.Dependencies,
-		}
-		pc, err := pipelineCMD.NewCommand()
-		if err != nil {
-			return err
-		}
-		if err := pc.ExecuteDeployPipeline(ctx, pipOpts); err != nil {
-			if errStatus := updateConfigMapStatus(ctx, cfg, c, data, err); errStatus != nil {
-				return errStatus
-			}
-
-			return err
+	if err := dc.deployDependencies(ctx, deployOptions, c); err != nil {
+		data.HistoryEntry = newHistoryEntry(deployOptions, data.Manifest)
+		if errStatus := updateConfigMapStatus(ctx, cfg, c, data, err); errStatus != nil {
+			return errStatus
 		}
+		return err
 	}
 
-	if err := buildImages(ctx, dc.Builder.Build, dc.Builder.GetServicesToBuild, deployOptions); err != nil {
+	if deployOptions.rollback != nil {
+		// rollbacks deploy the images recorded in the history instead of building them again
+		if err := dc.setRollbackImages(deployOptions); err != nil {
+			data.HistoryEntry = newHistoryEntry(deployOptions, data.Manifest)
+			return updateConfigMapStatusError(ctx, cfg, c, data, err)
+		}
+	} else if err := buildImages(ctx, dc.Builder.Build, dc.Builder.GetServicesToBuild, deployOptions); err != nil {
+		data.HistoryEntry = newHistoryEntry(deployOptions, data.Manifest)
 		return updateConfigMapStatusError(ctx, cfg, c, data, err)
 	}
 
@@ -397,6 +438,7 @@ func (dc *DeployCommand) RunDeploy(ctx context.Context, deployOptions *Options)
 	oktetoLog.EnableMasking()
 	err = dc.deploy(ctx, deployOptions)
 	oktetoLog.DisableMasking()
+	data.Outputs = deployOptions.outputs
 	oktetoLog.SetStage("done")
 	oktetoLog.AddToBuffer(oktetoLog.InfoLevel, "EOF")
 	oktetoLog.SetStage("")
@@ -436,21 +478,50 @@ func (dc *DeployCommand) RunDeploy(ctx context.Context, deployOptions *Options)
 		data.Status = pipeline.DeployedStatus
 	}
 
+	data.HistoryEntry = newHistoryEntry(deployOptions, data.Manifest)
 	if err := pipeline.UpdateConfigMap(ctx, cfg, data, c); err != nil {
 		return err
 	}
 
+	if err == nil && deployOptions.Watch {
+		return dc.watch(ctx, deployOptions)
+	}
 	return err
 }
 
 func (dc *DeployCommand) deploy(ctx context.Context, opts *Options) error {
 	// deploy commands if any
-	for _, command := range opts.Manifest.Deploy.Commands {
-		oktetoLog.Information("Running '%s'", command.Name)
-		oktetoLog.SetStage(command.Name)
-		if err := dc.Executor.Execute(command, opts.Variables); err != nil {
-			oktetoLog.AddToBuffer(oktetoLog.ErrorLevel, "error executing command '%s': %s", command.Name, err.Error())
-			return fmt.Errorf("error executing command '%s': %s", command.Name, err.Error())
+	cr := newCommandsRunner(dc.Executor, opts.Variables)
+	summaries, err := cr.run(opts.Manifest.Deploy.Commands)
+	opts.outputs = cr.outputs
+	if shouldDisplaySummary(summaries) {
+		displayer.DisplaySummary(oktetoLog.GetOutputFormat(), summaries)
+	}
+	if err != nil {
+		return err
+	}
+
+	// the outputs of the commands are also available for the rest of the deploy
+	for name, value := range cr.outputs {
+		os.Setenv(name, value)
+	}
+
+	// deploy kubernetes manifests if any
+	if opts.Manifest.Deploy.Kubernetes != nil {
+		oktetoLog.SetStage("Deploying kubernetes manifests")
+		if err := dc.deployKubernetes(ctx, opts); err != nil {
+			oktetoLog.AddToBuffer(oktetoLog.ErrorLevel, "error deploying kubernetes manifests: %s", err.Error())
+			return err
+		}
+		oktetoLog.SetStage("")
+	}
+
+	// deploy helm release if any
+	if opts.Manifest.Deploy.Helm != nil {
+		oktetoLog.SetStage("Deploying helm release")
+		if err := dc.deployHelm(opts); err != nil {
+			oktetoLog.AddToBuffer(oktetoLog.ErrorLevel, "error deploying helm release: %s", err.Error())
+			return err
 		}
 		oktetoLog.SetStage("")
 	}
@@ -490,6 +561,71 @@ func (dc *DeployCommand) deploy(ctx context.Context, opts *Options) error {
 
 }
 
+func (dc *DeployCommand) deployDependencies(ctx context.Context, deployOptions *Options, c kubernetes.Interface) error {
+	dependencies := deployOptions.Manifest.Dependencies
+	namespace := okteto.Context().Namespace
+	// the outputs of a dependency are only read once its deploy has finished
+	var waitedMu sync.Mutex
+	waited := map[string]bool{}
+	deployDependency := func(ctx context.Context, depName string, dep *model.Dependency) error {
+		oktetoLog.Information("Deploying dependency '%s'", depName)
+		variables := append(model.Environment{}, dep.Variables...)
+		variables = append(variables, model.EnvVar{
+			Name:  "OKTETO_ORIGIN",
+			Value: "okteto-deploy",
+		})
+		// dependencies receive the outputs of the dependencies they depend on
+		for _, variable := range getDependenciesOutputVariables(ctx, dep.DependsOn, namespace, c) {
+			kv := strings.SplitN(variable, "=", 2)
+			variables = append(variables, model.EnvVar{Name: kv[0], Value: kv[1]})
+		}
+		wait := dep.Wait
+		if !wait && len(dependencies.GetDependents(depName)) > 0 {
+			oktetoLog.Infof("waiting for dependency '%s' because other dependencies depend on it", depName)
+			wait = true
+		}
+		if !wait && isDependencyOutputConsumed(deployOptions.Manifest, depName) {
+			oktetoLog.Infof("waiting for dependency '%s' because the deploy commands use its outputs", depName)
+			wait = true
+		}
+		waitedMu.Lock()
+		waited[depName] = wait
+		waitedMu.Unlock()
+		pipOpts := &pipelineCMD.DeployOptions{
+			Name:         depName,
+			Repository:   dep.Repository,
+			Branch:       dep.Branch,
+			File:         dep.ManifestPath,
+			Variables:    model.SerializeEnvironmentVars(variables),
+			Wait:         wait,
+			Timeout:      deployOptions.Timeout,
+			SkipIfExists: !deployOptions.Dependencies,
+		}
+		pc, err := pipelineCMD.NewCommand()
+		if err != nil {
+			return err
+		}
+		return pc.ExecuteDeployPipeline(ctx, pipOpts)
+	}
+	if err := deployDependencies(ctx, dependencies, deployOptions.DependenciesParallelism, deployOptions.DependenciesPolicy, deployDependency); err != nil {
+		return err
+	}
+
+	// the outputs of the dependencies are available for the deploy commands. The outputs of the dependencies
+	// still running would be the ones of their previous deploy, so they are not exposed
+	names := make([]string, 0, len(dependencies))
+	for name := range dependencies {
+		if !waited[name] {
+			oktetoLog.Infof("outputs of dependency '%s' are not available because it was not waited for", name)
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	deployOptions.Variables = append(deployOptions.Variables, getDependenciesOutputVariables(ctx, names, namespace, c)...)
+	return nil
+}
+
 func (dc *DeployCommand) deployStack(ctx context.Context, opts *Options) error {
 	composeSectionInfo := opts.Manifest.Deploy.ComposeSection
 	composeSectionInfo.Stack.Namespace = okteto.Context().Namespace
@@ -590,20 +726,7 @@ func (dc *DeployCommand) cleanUp(ctx context.Context) {
 }
 
 func buildImages(ctx context.Context, build func(context.Context, *types.BuildOptions) error, getServicesToBuild func(context.Context, *model.Manifest, []string) ([]string, error), deployOptions *Options) error {
-	var stackServicesWithBuild map[string]bool
-
-	if stack := deployOptions.Manifest.GetStack(); stack != nil {
-		stackServicesWithBuild = stack.GetServicesWithBuildSection()
-	}
-
-	allServicesWithBuildSection := deployOptions.Manifest.GetBuildServices()
-	oktetoManifestServicesWithBuild := setDifference(allServicesWithBuildSection, stackServicesWithBuild) // Warning: this way of getting the oktetoManifestServicesWithBuild is highly dependent on the manifest struct as it is now. We are assuming that: *okteto* manifest build = manifest build - stack build section
-	servicesToDeployWithBuild := setIntersection(allServicesWithBuildSection, sliceToSet(deployOptions.servicesToDeploy))
-	// We need to build:
-	// - All the services that have a build section defined in the *okteto* manifest
-	// - Services from *deployOptions.servicesToDeploy* that have a build section
-
-	servicesToBuildSet := setUnion(oktetoManifestServicesWithBuild, servicesToDeployWithBuild)
+	servicesToBuildSet := getCandidateServicesToBuild(deployOptions)
 
 	if deployOptions.Build {
 		buildOptions := &types.BuildOptions{
@@ -637,6 +760,24 @@ func buildImages(ctx context.Context, build func(context.Context, *types.BuildOp
 	return nil
 }
 
+// getCandidateServicesToBuild returns the services that have to be built if their images are not already built
+func getCandidateServicesToBuild(deployOptions *Options) map[string]bool {
+	var stackServicesWithBuild map[string]bool
+
+	if stack := deployOptions.Manifest.GetStack(); stack != nil {
+		stackServicesWithBuild = stack.GetServicesWithBuildSection()
+	}
+
+	allServicesWithBuildSection := deployOptions.Manifest.GetBuildServices()
+	oktetoManifestServicesWithBuild := setDifference(allServicesWithBuildSection, stackServicesWithBuild) // Warning: this way of getting the oktetoManifestServicesWithBuild is highly dependent on the manifest struct as it is now. We are assuming that: *okteto* manifest build = manifest build - stack build section
+	servicesToDeployWithBuild := setIntersection(allServicesWithBuildSection, sliceToSet(deployOptions.servicesToDeploy))
+	// We need to build:
+	// - All the services that have a build section defined in the *okteto* manifest
+	// - Services from *deployOptions.servicesToDeploy* that have a build section
+
+	return setUnion(oktetoManifestServicesWithBuild, servicesToDeployWithBuild)
+}
+
 func sliceToSet[T comparable](slice []T) map[T]bool {
 	set := make(map[T]bool)
 	for _, value := range slice {
@@ -263,6 +263,12 @@ func TestCreateConfigMapWithBuildError(t *testing.T) {
 
 	expectedCfg.Data["output"] = cfg.Data["output"]
 
+	history, err := pipeline.GetHistory(cfg)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, pipeline.ErrorStatus, history[0].Status)
+	expectedCfg.Data["history"] = cfg.Data["history"]
+
 	assert.True(t, strings.Contains(oktetoLog.GetOutputBuffer().String(), errors.InvalidDockerfile))
 	assert.Equal(t, expectedCfg, cfg)
 }
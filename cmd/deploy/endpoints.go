@@ -23,7 +23,10 @@ import (
 
 	contextCMD "github.com/okteto/okteto/cmd/context"
 	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/helm"
+	"github.com/okteto/okteto/pkg/k8s/configmaps"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
@@ -138,14 +141,29 @@ func (dc *DeployCommand) getEndpoints(ctx context.Context, opts *EndpointsOption
 		defer oktetoLog.StopSpinner()
 	}
 
-	labelSelector := fmt.Sprintf("%s=%s", model.DeployedByLabel, opts.Name)
+	labelSelectors := []string{fmt.Sprintf("%s=%s", model.DeployedByLabel, opts.Name)}
+	if releaseName := dc.getDeployedHelmRelease(ctx, opts); releaseName != "" {
+		// the ingresses of the helm release are labeled by the chart, even if they weren't created through the okteto proxy
+		labelSelectors = append(labelSelectors, fmt.Sprintf("%s=%s", helm.InstanceLabel, releaseName))
+	}
 	iClient, err := dc.K8sClientProvider.GetIngressClient()
 	if err != nil {
 		return nil, err
 	}
-	eps, err := iClient.GetEndpointsBySelector(ctx, opts.Namespace, labelSelector)
-	if err != nil {
-		return nil, err
+	eps := []string{}
+	visited := map[string]bool{}
+	for _, labelSelector := range labelSelectors {
+		selectorEps, err := iClient.GetEndpointsBySelector(ctx, opts.Namespace, labelSelector)
+		if err != nil {
+			return nil, err
+		}
+		for _, ep := range selectorEps {
+			if visited[ep] {
+				continue
+			}
+			visited[ep] = true
+			eps = append(eps, ep)
+		}
 	}
 
 	if len(eps) > 0 {
@@ -156,6 +174,21 @@ func (dc *DeployCommand) getEndpoints(ctx context.Context, opts *EndpointsOption
 	return eps, nil
 }
 
+// getDeployedHelmRelease returns the helm release tracked in the pipeline configmap of the development environment
+func (dc *DeployCommand) getDeployedHelmRelease(ctx context.Context, opts *EndpointsOptions) string {
+	c, _, err := dc.K8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		oktetoLog.Infof("could not get the helm release of '%s': %s", opts.Name, err)
+		return ""
+	}
+	cmap, err := configmaps.Get(ctx, pipeline.TranslatePipelineName(opts.Name), opts.Namespace, c)
+	if err != nil {
+		oktetoLog.Infof("could not get the helm release of '%s': %s", opts.Name, err)
+		return ""
+	}
+	return pipeline.GetHelmRelease(cmap)
+}
+
 func (dc *DeployCommand) showEndpoints(ctx context.Context, opts *EndpointsOptions) error {
 	eps, err := dc.getEndpoints(ctx, opts)
 	if err != nil {
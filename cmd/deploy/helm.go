@@ -0,0 +1,62 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+
+	"github.com/okteto/okteto/pkg/helm"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+// getHelmReleaseOptions translates the 'deploy.helm' section into the options of the release
+func getHelmReleaseOptions(opts *Options) *helm.ReleaseOptions {
+	helmSection := opts.Manifest.Deploy.Helm
+	return &helm.ReleaseOptions{
+		Name:        helmSection.GetReleaseName(opts.Name),
+		Namespace:   opts.Manifest.Namespace,
+		Chart:       helmSection.Chart,
+		Repository:  helmSection.Repository,
+		Version:     helmSection.Version,
+		ValuesFiles: helmSection.ValuesFiles,
+		Set:         helmSection.Set,
+		Wait:        helmSection.Wait,
+		Timeout:     helmSection.Timeout,
+	}
+}
+
+// deployHelm installs the release of the 'deploy.helm' section.
+// It goes through the proxy kubeconfig, so the release resources get the deployed-by labels
+func (dc *DeployCommand) deployHelm(opts *Options) error {
+	releaseOpts := getHelmReleaseOptions(opts)
+	oktetoLog.Spinner(fmt.Sprintf("Deploying helm release '%s'...", releaseOpts.Name))
+	oktetoLog.StartSpinner()
+	defer oktetoLog.StopSpinner()
+
+	rel, err := helm.NewClient(dc.TempKubeconfigFile).UpgradeInstall(releaseOpts)
+	if err != nil {
+		return err
+	}
+	oktetoLog.Success("Helm release '%s' deployed (revision %d)", rel.Name, rel.Version)
+	return nil
+}
+
+// getTrackedHelmRelease returns the helm release to store in the pipeline configmap
+func getTrackedHelmRelease(manifest *model.Manifest, name string) string {
+	if manifest.Deploy == nil || manifest.Deploy.Helm == nil {
+		return ""
+	}
+	return manifest.Deploy.Helm.GetReleaseName(name)
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/helm"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHelmReleaseOptions(t *testing.T) {
+	opts := &Options{
+		Name: "movies",
+		Manifest: &model.Manifest{
+			Namespace: "ns",
+			Deploy: &model.DeployInfo{
+				Helm: &model.DeployHelm{
+					Chart:       "./chart",
+					ValuesFiles: []string{"values.yml"},
+					Set:         map[string]string{"image.tag": "dev"},
+					Wait:        true,
+					Timeout:     time.Minute,
+				},
+			},
+		},
+	}
+	assert.Equal(t, &helm.ReleaseOptions{
+		Name:        "movies",
+		Namespace:   "ns",
+		Chart:       "./chart",
+		ValuesFiles: []string{"values.yml"},
+		Set:         map[string]string{"image.tag": "dev"},
+		Wait:        true,
+		Timeout:     time.Minute,
+	}, getHelmReleaseOptions(opts))
+}
+
+func TestGetTrackedHelmRelease(t *testing.T) {
+	assert.Empty(t, getTrackedHelmRelease(&model.Manifest{}, "app"))
+	assert.Empty(t, getTrackedHelmRelease(&model.Manifest{Deploy: &model.DeployInfo{}}, "app"))
+	assert.Equal(t, "app", getTrackedHelmRelease(&model.Manifest{Deploy: &model.DeployInfo{Helm: &model.DeployHelm{Chart: "chart"}}}, "app"))
+	assert.Equal(t, "release", getTrackedHelmRelease(&model.Manifest{Deploy: &model.DeployInfo{Helm: &model.DeployHelm{Chart: "chart", ReleaseName: "release"}}}, "app"))
+}
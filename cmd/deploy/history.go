@@ -0,0 +1,52 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"os"
+	"time"
+
+	buildv2 "github.com/okteto/okteto/cmd/build/v2"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/model"
+)
+
+// newHistoryEntry returns the entry recorded in the deploy history for the current deploy
+func newHistoryEntry(opts *Options, manifest []byte) *pipeline.HistoryEntry {
+	entry := &pipeline.HistoryEntry{
+		Timestamp:     time.Now().UTC(),
+		Commit:        os.Getenv(model.OktetoGitCommitEnvVar),
+		Filename:      opts.ManifestPathFlag,
+		Manifest:      string(manifest),
+		VariablesHash: opts.variablesHash,
+	}
+	if opts.rollback != nil {
+		entry.RollbackOf = opts.rollback.Version
+	}
+	if opts.Manifest != nil {
+		entry.Images = getDeployedImages(opts.Manifest.Build)
+	}
+	return entry
+}
+
+// getDeployedImages returns the image reference with digest of each service of the 'build' section
+func getDeployedImages(build model.ManifestBuild) map[string]string {
+	images := map[string]string{}
+	for name := range build {
+		if image := os.Getenv(buildv2.GetBuildEnvVar(name, "IMAGE")); image != "" {
+			images[name] = image
+		}
+	}
+	return images
+}
@@ -0,0 +1,440 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/okteto/okteto/cmd/utils/displayer"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+const (
+	kubernetesCreatedResult    = "created"
+	kubernetesConfiguredResult = "configured"
+	kubernetesPrunedResult     = "pruned"
+
+	// kubernetesFieldManager is the field manager used to apply the objects of the 'deploy.kubernetes' section
+	kubernetesFieldManager = "okteto"
+)
+
+// defaultPruneKinds are the namespaced kinds checked for pruning even if none of the current manifests define them
+var defaultPruneKinds = []schema.GroupKind{
+	{Group: "", Kind: "ConfigMap"},
+	{Group: "", Kind: "Secret"},
+	{Group: "", Kind: "Service"},
+	{Group: "", Kind: "ServiceAccount"},
+	{Group: "", Kind: "PersistentVolumeClaim"},
+	{Group: "apps", Kind: "Deployment"},
+	{Group: "apps", Kind: "StatefulSet"},
+	{Group: "apps", Kind: "DaemonSet"},
+	{Group: "batch", Kind: "Job"},
+	{Group: "batch", Kind: "CronJob"},
+	{Group: "networking.k8s.io", Kind: "Ingress"},
+}
+
+// kubernetesApplier applies the objects of the 'deploy.kubernetes' section using the dynamic client
+type kubernetesApplier struct {
+	client    dynamic.Interface
+	mapper    meta.RESTMapper
+	name      string
+	namespace string
+}
+
+func (dc *DeployCommand) deployKubernetes(ctx context.Context, opts *Options) error {
+	k8sSection := opts.Manifest.Deploy.Kubernetes
+	objs, err := getKubernetesManifests(k8sSection)
+	if err != nil {
+		return err
+	}
+
+	dynClient, _, err := okteto.GetDynamicClient()
+	if err != nil {
+		return err
+	}
+	discClient, _, err := okteto.GetDiscoveryClient()
+	if err != nil {
+		return err
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(discClient)
+	if err != nil {
+		return err
+	}
+
+	applier := &kubernetesApplier{
+		client:    dynClient,
+		mapper:    restmapper.NewDiscoveryRESTMapper(groupResources),
+		name:      opts.Name,
+		namespace: opts.Manifest.Namespace,
+	}
+	return displayKubernetesResults("Applying kubernetes manifests", func(out io.Writer) error {
+		return applier.apply(ctx, objs, k8sSection.Prune, out)
+	})
+}
+
+// displayKubernetesResults sends the lines written by fn to the displayer of the current output format
+func displayKubernetesResults(name string, fn func(out io.Writer) error) error {
+	reader, writer := io.Pipe()
+	d := displayer.NewDisplayer(oktetoLog.GetOutputFormat(), reader, nil)
+
+	errCh := make(chan error, 1)
+	go func() {
+		err := fn(writer)
+		writer.Close()
+		errCh <- err
+	}()
+
+	d.Display(name)
+	err := <-errCh
+	d.CleanUp(err)
+	return err
+}
+
+// getKubernetesManifests reads the objects defined by the paths and kustomize folders of the 'deploy.kubernetes' section
+func getKubernetesManifests(k8sSection *model.DeployKubernetes) ([]*unstructured.Unstructured, error) {
+	result := []*unstructured.Unstructured{}
+	for _, path := range k8sSection.Paths {
+		files, err := getKubernetesManifestFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("error reading kubernetes manifest '%s': %w", file, err)
+			}
+			objs, err := decodeKubernetesManifest(content, file)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, objs...)
+		}
+	}
+
+	for _, dir := range k8sSection.Kustomize {
+		kustomizer := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+		resMap, err := kustomizer.Run(filesys.MakeFsOnDisk(), dir)
+		if err != nil {
+			return nil, fmt.Errorf("error building kustomization '%s': %w", dir, err)
+		}
+		content, err := resMap.AsYaml()
+		if err != nil {
+			return nil, fmt.Errorf("error building kustomization '%s': %w", dir, err)
+		}
+		objs, err := decodeKubernetesManifest(content, dir)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, objs...)
+	}
+	return result, nil
+}
+
+// getKubernetesManifestFiles returns the yaml and json files of a path, walking it if it is a folder
+func getKubernetesManifestFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading kubernetes manifests from '%s': %w", path, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files := []string{}
+	err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch filepath.Ext(file) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading kubernetes manifests from '%s': %w", path, err)
+	}
+	return files, nil
+}
+
+func decodeKubernetesManifest(content []byte, source string) ([]*unstructured.Unstructured, error) {
+	result := []*unstructured.Unstructured{}
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("error reading kubernetes manifest '%s': %w", source, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+			return nil, fmt.Errorf("error reading kubernetes manifest '%s': objects must define 'apiVersion' and 'kind'", source)
+		}
+		if obj.IsList() {
+			err := obj.EachListItem(func(item runtime.Object) error {
+				result = append(result, item.(*unstructured.Unstructured))
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error reading kubernetes manifest '%s': %w", source, err)
+			}
+			continue
+		}
+		result = append(result, obj)
+	}
+	return result, nil
+}
+
+// apply creates or updates every object and prunes the ones applied by a previous deploy that are no longer defined
+func (ka *kubernetesApplier) apply(ctx context.Context, objs []*unstructured.Unstructured, prune bool, out io.Writer) error {
+	applied := map[string]bool{}
+	appliedResources := map[schema.GroupVersionResource]bool{}
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		mapping, err := ka.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return fmt.Errorf("error applying %s '%s': %w", gvk.Kind, obj.GetName(), err)
+		}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace && obj.GetNamespace() == "" {
+			obj.SetNamespace(ka.namespace)
+		}
+		setKubernetesManifestLabels(obj, ka.name)
+
+		result, err := ka.applyObject(ctx, ka.getResourceInterface(mapping, obj.GetNamespace()), obj)
+		if err != nil {
+			return fmt.Errorf("error applying %s '%s': %w", gvk.Kind, obj.GetName(), err)
+		}
+		fmt.Fprintf(out, "%s %s\n", getKubernetesObjectName(gvk.GroupKind(), obj.GetName()), result)
+
+		applied[getKubernetesObjectKey(mapping.Resource, obj.GetNamespace(), obj.GetName())] = true
+		appliedResources[mapping.Resource] = true
+	}
+
+	if !prune {
+		return nil
+	}
+	return ka.prune(ctx, applied, appliedResources, out)
+}
+
+// applyObject applies an object with server-side apply, so the fields owned by other controllers (e.g. the replicas of an autoscaler) are kept
+func (ka *kubernetesApplier) applyObject(ctx context.Context, ri dynamic.ResourceInterface, obj *unstructured.Unstructured) (string, error) {
+	result := kubernetesConfiguredResult
+	old, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return "", err
+		}
+		old = nil
+		result = kubernetesCreatedResult
+	}
+
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	// objects never applied by okteto were created by previous versions of the cli or by kubectl, so okteto takes the ownership of their fields
+	force := old == nil || !isAppliedByKubernetesFieldManager(old.GetManagedFields())
+	_, err = ri.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: kubernetesFieldManager, Force: &force})
+	if isKubernetesApplyNotSupported(err) {
+		err = updateObject(ctx, ri, obj, old)
+	}
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// updateObject creates or updates an object when the cluster doesn't support server-side apply
+func updateObject(ctx context.Context, ri dynamic.ResourceInterface, obj, old *unstructured.Unstructured) error {
+	if old == nil {
+		_, err := ri.Create(ctx, obj, metav1.CreateOptions{})
+		return err
+	}
+	obj.SetResourceVersion(old.GetResourceVersion())
+	_, err := ri.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+func isAppliedByKubernetesFieldManager(managedFields []metav1.ManagedFieldsEntry) bool {
+	for _, entry := range managedFields {
+		if entry.Manager == kubernetesFieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
+
+func isKubernetesApplyNotSupported(err error) bool {
+	if err == nil {
+		return false
+	}
+	return k8sErrors.IsUnsupportedMediaType(err) || strings.Contains(err.Error(), "PatchType is not supported")
+}
+
+// prune deletes the objects labeled as applied by this deploy that were not applied this time
+func (ka *kubernetesApplier) prune(ctx context.Context, applied map[string]bool, appliedResources map[schema.GroupVersionResource]bool, out io.Writer) error {
+	selector, err := ka.getPruneSelector()
+	if err != nil {
+		return err
+	}
+
+	mappings := []*meta.RESTMapping{}
+	visited := map[schema.GroupVersionResource]bool{}
+	for _, gk := range defaultPruneKinds {
+		mapping, err := ka.mapper.RESTMapping(gk)
+		if err != nil {
+			oktetoLog.Debugf("skipping prune of kind '%s': %s", gk.String(), err)
+			continue
+		}
+		visited[mapping.Resource] = true
+		mappings = append(mappings, mapping)
+	}
+	for gvr := range appliedResources {
+		if visited[gvr] {
+			continue
+		}
+		gvk, err := ka.mapper.KindFor(gvr)
+		if err != nil {
+			return err
+		}
+		mapping, err := ka.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return err
+		}
+		visited[gvr] = true
+		mappings = append(mappings, mapping)
+	}
+
+	deletePropagation := metav1.DeletePropagationBackground
+	for _, mapping := range mappings {
+		namespace := ""
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			namespace = ka.namespace
+		}
+		list, err := ka.getResourceInterface(mapping, namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return fmt.Errorf("error listing %s to prune: %w", mapping.Resource.Resource, err)
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			if applied[getKubernetesObjectKey(mapping.Resource, item.GetNamespace(), item.GetName())] {
+				continue
+			}
+			err := ka.getResourceInterface(mapping, item.GetNamespace()).Delete(ctx, item.GetName(), metav1.DeleteOptions{PropagationPolicy: &deletePropagation})
+			if err != nil && !oktetoErrors.IsNotFound(err) {
+				return fmt.Errorf("error pruning %s '%s': %w", mapping.GroupVersionKind.Kind, item.GetName(), err)
+			}
+			fmt.Fprintf(out, "%s %s\n", getKubernetesObjectName(mapping.GroupVersionKind.GroupKind(), item.GetName()), kubernetesPrunedResult)
+		}
+	}
+	return nil
+}
+
+func (ka *kubernetesApplier) getPruneSelector() (string, error) {
+	deployedByReq, err := labels.NewRequirement(model.DeployedByLabel, selection.Equals, []string{ka.name})
+	if err != nil {
+		return "", err
+	}
+	manifestReq, err := labels.NewRequirement(model.KubernetesManifestLabel, selection.Equals, []string{"true"})
+	if err != nil {
+		return "", err
+	}
+	return labels.NewSelector().Add(*deployedByReq, *manifestReq).String(), nil
+}
+
+func (ka *kubernetesApplier) getResourceInterface(mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return ka.client.Resource(mapping.Resource).Namespace(namespace)
+	}
+	return ka.client.Resource(mapping.Resource)
+}
+
+// setKubernetesManifestLabels sets the deployed-by labels so 'okteto destroy' can find the object and its pods
+func setKubernetesManifestLabels(obj *unstructured.Unstructured, name string) {
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	objLabels[model.DeployedByLabel] = name
+	objLabels[model.KubernetesManifestLabel] = "true"
+	obj.SetLabels(objLabels)
+
+	templatePath := []string{"spec", "template"}
+	if obj.GetKind() == "CronJob" {
+		templatePath = []string{"spec", "jobTemplate", "spec", "template"}
+	}
+	if _, found, _ := unstructured.NestedMap(obj.Object, templatePath...); !found {
+		return
+	}
+	labelsPath := append(templatePath, "metadata", "labels")
+	templateLabels, _, err := unstructured.NestedStringMap(obj.Object, labelsPath...)
+	if err != nil {
+		oktetoLog.Infof("could not set labels in the pod template of %s '%s': %s", obj.GetKind(), obj.GetName(), err)
+		return
+	}
+	if templateLabels == nil {
+		templateLabels = map[string]string{}
+	}
+	templateLabels[model.DeployedByLabel] = name
+	if err := unstructured.SetNestedStringMap(obj.Object, templateLabels, labelsPath...); err != nil {
+		oktetoLog.Infof("could not set labels in the pod template of %s '%s': %s", obj.GetKind(), obj.GetName(), err)
+	}
+}
+
+// getKubernetesObjectName returns the name of an object with the same format as kubectl, e.g. deployment.apps/api
+func getKubernetesObjectName(gk schema.GroupKind, name string) string {
+	kind := strings.ToLower(gk.Kind)
+	if gk.Group != "" {
+		kind = fmt.Sprintf("%s.%s", kind, gk.Group)
+	}
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+func getKubernetesObjectKey(gvr schema.GroupVersionResource, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", gvr.Group, gvr.Resource, namespace, name)
+}
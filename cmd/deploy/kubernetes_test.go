@@ -0,0 +1,208 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+var (
+	deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	configMapsGVR  = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+)
+
+const kubernetesTestManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    metadata:
+      labels:
+        app: api
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: api-config
+data:
+  key: value
+`
+
+func newKubernetesTestApplier(objs ...runtime.Object) *kubernetesApplier {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			deploymentsGVR: "DeploymentList",
+			configMapsGVR:  "ConfigMapList",
+		},
+		objs...,
+	)
+	// the fake client doesn't create objects with server-side apply
+	client.PrependReactor("patch", "*", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8sTesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(patch.GetPatch()); err != nil {
+			return true, nil, err
+		}
+		if _, err := client.Tracker().Get(patch.GetResource(), patch.GetNamespace(), patch.GetName()); err != nil {
+			return true, obj, client.Tracker().Create(patch.GetResource(), obj, patch.GetNamespace())
+		}
+		return true, obj, client.Tracker().Update(patch.GetResource(), obj, patch.GetNamespace())
+	})
+	return &kubernetesApplier{
+		client:    client,
+		mapper:    mapper,
+		name:      "app",
+		namespace: "ns",
+	}
+}
+
+func newKubernetesTestObject(apiVersion, kind, name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace("ns")
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestDecodeKubernetesManifest(t *testing.T) {
+	objs, err := decodeKubernetesManifest([]byte(kubernetesTestManifest+"---\n"), "k8s.yml")
+	assert.NoError(t, err)
+	assert.Len(t, objs, 2)
+	assert.Equal(t, "Deployment", objs[0].GetKind())
+	assert.Equal(t, "api-config", objs[1].GetName())
+
+	_, err = decodeKubernetesManifest([]byte("metadata:\n  name: api\n"), "k8s.yml")
+	assert.Error(t, err)
+}
+
+func TestGetKubernetesManifests(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.yml"), []byte(kubernetesTestManifest), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# readme"), 0600))
+
+	objs, err := getKubernetesManifests(&model.DeployKubernetes{Paths: []string{dir}})
+	assert.NoError(t, err)
+	assert.Len(t, objs, 2)
+
+	_, err = getKubernetesManifests(&model.DeployKubernetes{Paths: []string{filepath.Join(dir, "missing")}})
+	assert.Error(t, err)
+}
+
+func TestSetKubernetesManifestLabels(t *testing.T) {
+	objs, err := decodeKubernetesManifest([]byte(kubernetesTestManifest), "k8s.yml")
+	assert.NoError(t, err)
+
+	setKubernetesManifestLabels(objs[0], "app")
+	assert.Equal(t, map[string]string{
+		model.DeployedByLabel:         "app",
+		model.KubernetesManifestLabel: "true",
+	}, objs[0].GetLabels())
+	templateLabels, _, err := unstructured.NestedStringMap(objs[0].Object, "spec", "template", "metadata", "labels")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "api", model.DeployedByLabel: "app"}, templateLabels)
+
+	setKubernetesManifestLabels(objs[1], "app")
+	_, found, _ := unstructured.NestedMap(objs[1].Object, "spec")
+	assert.False(t, found)
+}
+
+func TestKubernetesApply(t *testing.T) {
+	ctx := context.Background()
+	appliedLabels := map[string]string{
+		model.DeployedByLabel:         "app",
+		model.KubernetesManifestLabel: "true",
+	}
+	existing := newKubernetesTestObject("apps/v1", "Deployment", "api", nil)
+	removed := newKubernetesTestObject("apps/v1", "Deployment", "old-api", appliedLabels)
+	fromCommand := newKubernetesTestObject("v1", "ConfigMap", "from-command", map[string]string{model.DeployedByLabel: "app"})
+	applier := newKubernetesTestApplier(existing, removed, fromCommand)
+
+	objs, err := decodeKubernetesManifest([]byte(kubernetesTestManifest), "k8s.yml")
+	assert.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	err = applier.apply(ctx, objs, true, out)
+	assert.NoError(t, err)
+	assert.Equal(t, "deployment.apps/api configured\nconfigmap/api-config created\ndeployment.apps/old-api pruned\n", out.String())
+
+	d, err := applier.client.Resource(deploymentsGVR).Namespace("ns").Get(ctx, "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "app", d.GetLabels()[model.DeployedByLabel])
+
+	_, err = applier.client.Resource(deploymentsGVR).Namespace("ns").Get(ctx, "old-api", metav1.GetOptions{})
+	assert.Error(t, err)
+
+	_, err = applier.client.Resource(configMapsGVR).Namespace("ns").Get(ctx, "from-command", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestKubernetesApplyWithServerSideApply(t *testing.T) {
+	applier := newKubernetesTestApplier()
+	var patchType types.PatchType
+	applier.client.(*dynamicfake.FakeDynamicClient).PrependReactor("patch", "deployments", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		patchType = action.(k8sTesting.PatchAction).GetPatchType()
+		return true, newKubernetesTestObject("apps/v1", "Deployment", "api", nil), nil
+	})
+
+	objs, err := decodeKubernetesManifest([]byte(kubernetesTestManifest), "k8s.yml")
+	assert.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	err = applier.apply(context.Background(), objs[:1], false, out)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ApplyPatchType, patchType)
+	assert.Equal(t, "deployment.apps/api created\n", out.String())
+}
+
+func TestKubernetesApplyWithoutPrune(t *testing.T) {
+	removed := newKubernetesTestObject("apps/v1", "Deployment", "old-api", map[string]string{
+		model.DeployedByLabel:         "app",
+		model.KubernetesManifestLabel: "true",
+	})
+	applier := newKubernetesTestApplier(removed)
+
+	out := &bytes.Buffer{}
+	err := applier.apply(context.Background(), []*unstructured.Unstructured{}, false, out)
+	assert.NoError(t, err)
+	assert.Empty(t, out.String())
+
+	_, err = applier.client.Resource(deploymentsGVR).Namespace("ns").Get(context.Background(), "old-api", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/godotenv"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"k8s.io/client-go/kubernetes"
+)
+
+var invalidEnvVarCharsRegex = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// readOutputs reads the values written by a command in $OKTETO_OUTPUT, using the dotenv format
+func readOutputs(path string) (map[string]string, error) {
+	outputs, err := godotenv.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the outputs of the command: %w", err)
+	}
+	return outputs, nil
+}
+
+// getDependencyOutputVariableName returns the variable used to expose an output of a dependency: OKTETO_DEPENDENCY_<NAME>_VARIABLE_<KEY>
+func getDependencyOutputVariableName(dependency, key string) string {
+	name := invalidEnvVarCharsRegex.ReplaceAllString(strings.ToUpper(dependency), "_")
+	return fmt.Sprintf("OKTETO_DEPENDENCY_%s_VARIABLE_%s", name, key)
+}
+
+// isDependencyOutputConsumed checks if the deploy commands of the manifest use any output of a dependency
+func isDependencyOutputConsumed(manifest *model.Manifest, dependency string) bool {
+	if manifest == nil || manifest.Deploy == nil {
+		return false
+	}
+	prefix := getDependencyOutputVariableName(dependency, "")
+	for _, command := range manifest.Deploy.Commands {
+		if strings.Contains(command.Command, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getDependenciesOutputVariables returns the outputs of the given dependencies as variables.
+// Dependencies without outputs, or not deployed yet, are ignored
+func getDependenciesOutputVariables(ctx context.Context, dependencies []string, namespace string, c kubernetes.Interface) []string {
+	result := []string{}
+	for _, dependency := range dependencies {
+		outputs, err := pipeline.GetDeployOutputs(ctx, dependency, namespace, c)
+		if err != nil {
+			oktetoLog.Infof("could not get outputs of dependency '%s': %s", dependency, err)
+			continue
+		}
+		for _, key := range getSortedKeys(outputs) {
+			oktetoLog.AddMaskedWord(outputs[key])
+			result = append(result, fmt.Sprintf("%s=%s", getDependencyOutputVariableName(dependency, key), outputs[key]))
+		}
+	}
+	return result
+}
+
+func getSortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeOutputExecutor writes the configured outputs of each command in $OKTETO_OUTPUT
+type fakeOutputExecutor struct {
+	outputs map[string]string
+	envs    map[string][]string
+}
+
+func (fe *fakeOutputExecutor) Execute(command model.DeployCommand, env []string) error {
+	fe.envs[command.Name] = env
+	for _, e := range env {
+		if path := strings.TrimPrefix(e, model.OktetoOutputEnvVar+"="); path != e {
+			return os.WriteFile(path, []byte(fe.outputs[command.Name]), 0600)
+		}
+	}
+	return nil
+}
+
+func (*fakeOutputExecutor) CleanUp(_ error) {}
+
+func TestReadOutputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	assert.NoError(t, os.WriteFile(path, []byte("# generated values\nDATABASE_URL=postgres://db:5432/app\n\nBUCKET=\"my-bucket\"\n"), 0600))
+
+	outputs, err := readOutputs(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"DATABASE_URL": "postgres://db:5432/app", "BUCKET": "my-bucket"}, outputs)
+
+	_, err = readOutputs(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestCommandsRunnerOutputs(t *testing.T) {
+	e := &fakeOutputExecutor{
+		outputs: map[string]string{"create-db": "DATABASE_URL=postgres://db:5432/app\n"},
+		envs:    map[string][]string{},
+	}
+	cr := newCommandsRunner(e, []string{"FOO=bar"})
+
+	_, err := cr.run([]model.DeployCommand{
+		{Name: "create-db", Command: "make db"},
+		{Name: "deploy", Command: "make deploy"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"DATABASE_URL": "postgres://db:5432/app"}, cr.outputs)
+	assert.NotContains(t, e.envs["create-db"], "DATABASE_URL=postgres://db:5432/app")
+	assert.Contains(t, e.envs["deploy"], "DATABASE_URL=postgres://db:5432/app")
+	assert.Contains(t, e.envs["deploy"], "FOO=bar")
+	_, err = os.Stat(cr.outputFile)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGetDependencyOutputVariableName(t *testing.T) {
+	assert.Equal(t, "OKTETO_DEPENDENCY_MOVIES_API_VARIABLE_URL", getDependencyOutputVariableName("movies-api", "URL"))
+	assert.Equal(t, "OKTETO_DEPENDENCY_DB_VARIABLE_HOST", getDependencyOutputVariableName("db", "HOST"))
+}
+
+func TestGetDependenciesOutputVariables(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewSimpleClientset()
+	_, err := pipeline.TranslateConfigMapAndDeploy(ctx, &pipeline.CfgData{
+		Name:      "db",
+		Namespace: "ns",
+		Status:    pipeline.DeployedStatus,
+		Outputs:   map[string]string{"PORT": "5432", "HOST": "postgres"},
+	}, c)
+	assert.NoError(t, err)
+
+	variables := getDependenciesOutputVariables(ctx, []string{"db", "not-deployed"}, "ns", c)
+	assert.Equal(t, []string{"OKTETO_DEPENDENCY_DB_VARIABLE_HOST=postgres", "OKTETO_DEPENDENCY_DB_VARIABLE_PORT=5432"}, variables)
+}
+
+func TestIsDependencyOutputConsumed(t *testing.T) {
+	manifest := &model.Manifest{
+		Deploy: &model.DeployInfo{
+			Commands: []model.DeployCommand{
+				{Name: "migrate", Command: "migrate --url $OKTETO_DEPENDENCY_DB_VARIABLE_URL"},
+			},
+		},
+	}
+	assert.True(t, isDependencyOutputConsumed(manifest, "db"))
+	assert.False(t, isDependencyOutputConsumed(manifest, "api"))
+	assert.False(t, isDependencyOutputConsumed(&model.Manifest{}, "db"))
+}
@@ -0,0 +1,282 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/cmd/stack"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	textPlanOutput = "text"
+	jsonPlanOutput = "json"
+)
+
+// DeployPlan represents the actions that 'okteto deploy' would perform
+type DeployPlan struct {
+	Name         string                  `json:"name"`
+	Namespace    string                  `json:"namespace"`
+	Dependencies []PlannedDependency     `json:"dependencies,omitempty"`
+	Build        []string                `json:"build,omitempty"`
+	Commands     []model.DeployCommand   `json:"commands,omitempty"`
+	Kubernetes   *model.DeployKubernetes `json:"kubernetes,omitempty"`
+	Helm         *PlannedHelmRelease     `json:"helm,omitempty"`
+	Compose      []stack.PlannedResource `json:"compose,omitempty"`
+	Endpoints    []string                `json:"endpoints,omitempty"`
+	Divert       string                  `json:"divert,omitempty"`
+}
+
+// PlannedDependency represents a dependency that would be deployed
+type PlannedDependency struct {
+	Name       string   `json:"name"`
+	Repository string   `json:"repository"`
+	Branch     string   `json:"branch,omitempty"`
+	DependsOn  []string `json:"depends_on,omitempty"`
+}
+
+// PlannedHelmRelease represents the helm release that would be installed or upgraded
+type PlannedHelmRelease struct {
+	Name       string `json:"name"`
+	Chart      string `json:"chart"`
+	Repository string `json:"repository,omitempty"`
+	Version    string `json:"version,omitempty"`
+}
+
+func validatePlanOutput(output string) error {
+	switch output {
+	case textPlanOutput, jsonPlanOutput:
+		return nil
+	default:
+		return fmt.Errorf("plan output format is not accepted. Value must be one of: ['%s', '%s']", textPlanOutput, jsonPlanOutput)
+	}
+}
+
+// RunPlan shows what the deploy sequence would do without modifying the cluster
+func (dc *DeployCommand) RunPlan(ctx context.Context, deployOptions *Options) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get the current working directory: %w", err)
+	}
+
+	c, _, err := dc.K8sClientProvider.Provide(okteto.Context().Cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := addEnvVars(ctx, cwd); err != nil {
+		return err
+	}
+
+	deployOptions.Manifest, err = dc.GetManifest(deployOptions.ManifestPath)
+	if err != nil {
+		return err
+	}
+	if deployOptions.Manifest.Deploy == nil {
+		return oktetoErrors.ErrManifestFoundButNoDeployCommands
+	}
+	if len(deployOptions.servicesToDeploy) > 0 && deployOptions.Manifest.Deploy.ComposeSection == nil {
+		return oktetoErrors.ErrDeployCantDeploySvcsIfNotCompose
+	}
+	if err := setDeployOptionsValuesFromManifest(ctx, deployOptions, cwd, c); err != nil {
+		return err
+	}
+	os.Setenv(model.OktetoNameEnvVar, deployOptions.Name)
+
+	plan, err := getDeployPlan(ctx, deployOptions, c, dc.Builder.GetServicesToBuild)
+	if err != nil {
+		return err
+	}
+
+	for _, variable := range deployOptions.Variables {
+		value := strings.SplitN(variable, "=", 2)[1]
+		if strings.TrimSpace(value) != "" {
+			oktetoLog.AddMaskedWord(value)
+		}
+	}
+	oktetoLog.EnableMasking()
+	defer oktetoLog.DisableMasking()
+	return showPlan(plan, deployOptions.PlanOutput)
+}
+
+func getDeployPlan(ctx context.Context, deployOptions *Options, c kubernetes.Interface, getServicesToBuild func(context.Context, *model.Manifest, []string) ([]string, error)) (*DeployPlan, error) {
+	manifest := deployOptions.Manifest
+	plan := &DeployPlan{
+		Name:      deployOptions.Name,
+		Namespace: manifest.Namespace,
+	}
+
+	for name, dep := range manifest.Dependencies {
+		dependsOn := append([]string{}, dep.DependsOn...)
+		sort.Strings(dependsOn)
+		plan.Dependencies = append(plan.Dependencies, PlannedDependency{
+			Name:       name,
+			Repository: dep.Repository,
+			Branch:     dep.Branch,
+			DependsOn:  dependsOn,
+		})
+	}
+	sort.Slice(plan.Dependencies, func(i, j int) bool {
+		return plan.Dependencies[i].Name < plan.Dependencies[j].Name
+	})
+
+	servicesToBuild := setToSlice(getCandidateServicesToBuild(deployOptions))
+	if !deployOptions.Build && len(servicesToBuild) > 0 {
+		var err error
+		servicesToBuild, err = getServicesToBuild(ctx, manifest, servicesToBuild)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(servicesToBuild)
+	plan.Build = servicesToBuild
+
+	for _, command := range manifest.Deploy.Commands {
+		expanded, err := model.ExpandEnv(command.Command, true)
+		if err != nil {
+			oktetoLog.Infof("could not expand command '%s': %s", command.Name, err)
+			expanded = command.Command
+		}
+		plan.Commands = append(plan.Commands, model.DeployCommand{
+			Name:    command.Name,
+			Command: expanded,
+		})
+	}
+
+	plan.Kubernetes = manifest.Deploy.Kubernetes
+
+	if manifest.Deploy.Helm != nil {
+		plan.Helm = &PlannedHelmRelease{
+			Name:       manifest.Deploy.Helm.GetReleaseName(deployOptions.Name),
+			Chart:      manifest.Deploy.Helm.Chart,
+			Repository: manifest.Deploy.Helm.Repository,
+			Version:    manifest.Deploy.Helm.Version,
+		}
+	}
+
+	if manifest.Deploy.ComposeSection != nil && manifest.Deploy.ComposeSection.Stack != nil {
+		s := manifest.Deploy.ComposeSection.Stack
+		s.Namespace = manifest.Namespace
+		resources, err := stack.Plan(ctx, s, deployOptions.servicesToDeploy, c)
+		if err != nil {
+			return nil, err
+		}
+		plan.Compose = resources
+	}
+
+	for name := range manifest.Deploy.Endpoints {
+		plan.Endpoints = append(plan.Endpoints, name)
+	}
+	sort.Strings(plan.Endpoints)
+
+	if manifest.Deploy.Divert != nil && manifest.Deploy.Divert.Namespace != manifest.Namespace {
+		plan.Divert = manifest.Deploy.Divert.Namespace
+	}
+
+	return plan, nil
+}
+
+func showPlan(plan *DeployPlan, output string) error {
+	if output == jsonPlanOutput {
+		bytes, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		oktetoLog.Println(string(bytes))
+		return nil
+	}
+
+	oktetoLog.Information("Deploy plan for '%s' in namespace '%s':", plan.Name, plan.Namespace)
+	if len(plan.Dependencies) > 0 {
+		oktetoLog.Println("\nDependencies:")
+		for _, dep := range plan.Dependencies {
+			line := fmt.Sprintf("  - %s (%s", dep.Name, dep.Repository)
+			if dep.Branch != "" {
+				line += fmt.Sprintf("@%s", dep.Branch)
+			}
+			line += ")"
+			if len(dep.DependsOn) > 0 {
+				line += fmt.Sprintf(" after [%s]", strings.Join(dep.DependsOn, ", "))
+			}
+			oktetoLog.Println(line)
+		}
+	}
+	if len(plan.Build) > 0 {
+		oktetoLog.Println("\nImages to build:")
+		for _, svc := range plan.Build {
+			oktetoLog.Printf("  - %s\n", svc)
+		}
+	}
+	if len(plan.Commands) > 0 {
+		oktetoLog.Println("\nCommands:")
+		for i, command := range plan.Commands {
+			oktetoLog.Printf("  %d. %s\n", i+1, command.Name)
+			if command.Name != command.Command {
+				oktetoLog.Printf("     %s\n", command.Command)
+			}
+		}
+	}
+	if plan.Kubernetes != nil {
+		oktetoLog.Println("\nKubernetes manifests:")
+		for _, path := range plan.Kubernetes.Paths {
+			oktetoLog.Printf("  - %s\n", path)
+		}
+		for _, dir := range plan.Kubernetes.Kustomize {
+			oktetoLog.Printf("  - %s (kustomize)\n", dir)
+		}
+		if plan.Kubernetes.Prune {
+			oktetoLog.Println("  resources no longer defined will be pruned")
+		}
+	}
+	if plan.Helm != nil {
+		line := fmt.Sprintf("\nHelm release '%s' from chart '%s'", plan.Helm.Name, plan.Helm.Chart)
+		if plan.Helm.Version != "" {
+			line += fmt.Sprintf(" (version %s)", plan.Helm.Version)
+		}
+		if plan.Helm.Repository != "" {
+			line += fmt.Sprintf(" of repository '%s'", plan.Helm.Repository)
+		}
+		oktetoLog.Println(line)
+	}
+	if len(plan.Compose) > 0 {
+		oktetoLog.Println("\nCompose resources:")
+		for _, resource := range plan.Compose {
+			line := fmt.Sprintf("  %s %s/%s", resource.Action, resource.Kind, resource.Name)
+			if resource.Reason != "" {
+				line += fmt.Sprintf(" (%s)", resource.Reason)
+			}
+			oktetoLog.Println(line)
+		}
+	}
+	if len(plan.Endpoints) > 0 {
+		oktetoLog.Println("\nEndpoints:")
+		for _, name := range plan.Endpoints {
+			oktetoLog.Printf("  - %s\n", name)
+		}
+	}
+	if plan.Divert != "" {
+		oktetoLog.Printf("\nDivert from namespace '%s'\n", plan.Divert)
+	}
+	return nil
+}
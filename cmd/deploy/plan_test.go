@@ -0,0 +1,122 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/cmd/stack"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetDeployPlan(t *testing.T) {
+	t.Setenv("PLAN_TEST_CHART", "./chart")
+
+	manifest := &model.Manifest{
+		Namespace: "ns",
+		Build: model.ManifestBuild{
+			"api":    &model.BuildInfo{Context: "api"},
+			"worker": &model.BuildInfo{Context: "worker"},
+		},
+		Dependencies: model.ManifestDependencies{
+			"db":    &model.Dependency{Repository: "https://github.com/okteto/db"},
+			"cache": &model.Dependency{Repository: "https://github.com/okteto/cache", DependsOn: []string{"db"}},
+		},
+		Deploy: &model.DeployInfo{
+			Commands: []model.DeployCommand{
+				{
+					Name:    "install chart",
+					Command: "helm upgrade --install app ${PLAN_TEST_CHART}",
+				},
+			},
+			ComposeSection: &model.ComposeSectionInfo{
+				Stack: &model.Stack{
+					Name: "app",
+					Services: map[string]*model.Service{
+						"frontend": {
+							Image:         "frontend",
+							RestartPolicy: apiv1.RestartPolicyAlways,
+						},
+					},
+				},
+			},
+			Endpoints: model.EndpointSpec{
+				"web": model.Endpoint{},
+			},
+		},
+	}
+	opts := &Options{
+		Name:             "app",
+		Manifest:         manifest,
+		servicesToDeploy: []string{"frontend"},
+	}
+	getServicesToBuild := func(_ context.Context, _ *model.Manifest, svcs []string) ([]string, error) {
+		result := []string{}
+		for _, svc := range svcs {
+			if svc == "api" {
+				result = append(result, svc)
+			}
+		}
+		return result, nil
+	}
+
+	plan, err := getDeployPlan(context.Background(), opts, fake.NewSimpleClientset(), getServicesToBuild)
+	assert.NoError(t, err)
+	assert.Equal(t, &DeployPlan{
+		Name:      "app",
+		Namespace: "ns",
+		Dependencies: []PlannedDependency{
+			{Name: "cache", Repository: "https://github.com/okteto/cache", DependsOn: []string{"db"}},
+			{Name: "db", Repository: "https://github.com/okteto/db", DependsOn: []string{}},
+		},
+		Build: []string{"api"},
+		Commands: []model.DeployCommand{
+			{Name: "install chart", Command: "helm upgrade --install app ./chart"},
+		},
+		Compose: []stack.PlannedResource{
+			{Kind: "Deployment", Name: "frontend", Action: stack.PlanActionCreate},
+		},
+		Endpoints: []string{"web"},
+	}, plan)
+}
+
+func TestGetDeployPlanForceBuild(t *testing.T) {
+	manifest := &model.Manifest{
+		Build: model.ManifestBuild{
+			"api": &model.BuildInfo{Context: "api"},
+		},
+		Deploy: &model.DeployInfo{},
+	}
+	opts := &Options{
+		Manifest: manifest,
+		Build:    true,
+	}
+	getServicesToBuild := func(_ context.Context, _ *model.Manifest, _ []string) ([]string, error) {
+		return nil, assert.AnError
+	}
+
+	plan, err := getDeployPlan(context.Background(), opts, fake.NewSimpleClientset(), getServicesToBuild)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api"}, plan.Build)
+}
+
+func TestValidatePlanOutput(t *testing.T) {
+	assert.NoError(t, validatePlanOutput("text"))
+	assert.NoError(t, validatePlanOutput("json"))
+	assert.Error(t, validatePlanOutput("yaml"))
+}
@@ -0,0 +1,212 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	buildv2 "github.com/okteto/okteto/cmd/build/v2"
+	contextCMD "github.com/okteto/okteto/cmd/context"
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/cmd/utils/executor"
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/k8s/configmaps"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/spf13/cobra"
+)
+
+// RollbackOptions represents the options of the rollback command
+type RollbackOptions struct {
+	Name       string
+	Namespace  string
+	K8sContext string
+	Variables  []string
+	// To is the version of the deploy history to roll back to. Zero means the previous successful deploy
+	To      int
+	Wait    bool
+	Timeout time.Duration
+}
+
+// Rollback redeploys a previous deploy recorded in the deploy history
+func Rollback(ctx context.Context) *cobra.Command {
+	options := &RollbackOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Redeploy a previous deploy of your development environment using the images it deployed",
+		Args:  utils.NoArgsAccepted("https://www.okteto.com/docs/reference/cli/#deploy"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.To < 0 {
+				return fmt.Errorf("'--to' must be a positive number")
+			}
+			if err := validateAndSet(options.Variables, os.Setenv); err != nil {
+				return err
+			}
+			os.Setenv(model.OktetoSkipConfigCredentialsUpdate, "false")
+			if err := contextCMD.LoadContextFromPath(ctx, options.Namespace, options.K8sContext, ""); err != nil {
+				return err
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get the current working directory: %w", err)
+			}
+			name := options.Name
+			if name == "" {
+				name = utils.InferName(cwd)
+			}
+
+			c, _, err := okteto.NewK8sClientProvider().Provide(okteto.Context().Cfg)
+			if err != nil {
+				return err
+			}
+			cmap, err := configmaps.Get(ctx, pipeline.TranslatePipelineName(name), okteto.Context().Namespace, c)
+			if err != nil {
+				return fmt.Errorf("could not get the deploy history of '%s': %w", name, err)
+			}
+			history, err := pipeline.GetHistory(cmap)
+			if err != nil {
+				return err
+			}
+			entry, err := getRollbackEntry(history, options.To)
+			if err != nil {
+				return err
+			}
+			if hash := pipeline.GetVariablesHash(options.Variables); hash != entry.VariablesHash {
+				oktetoLog.Warning("The variables don't match the ones used by version %d. Use '--var' to set them", entry.Version)
+			}
+
+			manifestPath, err := writeRollbackManifest(cwd, entry)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(manifestPath)
+
+			kubeconfig := NewKubeConfig()
+			proxy, err := NewProxy(kubeconfig)
+			if err != nil {
+				oktetoLog.Infof("could not configure local proxy: %s", err)
+				return err
+			}
+			dc := &DeployCommand{
+				GetManifest:        model.GetManifestV2,
+				Kubeconfig:         kubeconfig,
+				Executor:           executor.NewExecutor(oktetoLog.GetOutputFormat(), false),
+				Proxy:              proxy,
+				TempKubeconfigFile: GetTempKubeConfigFile(name),
+				K8sClientProvider:  okteto.NewK8sClientProvider(),
+				Builder:            buildv2.NewBuilderFromScratch(),
+			}
+
+			oktetoLog.Information("Rolling back '%s' to version %d", name, entry.Version)
+			return dc.RunDeploy(ctx, &Options{
+				ManifestPathFlag: entry.Filename,
+				ManifestPath:     manifestPath,
+				Name:             name,
+				Namespace:        options.Namespace,
+				K8sContext:       options.K8sContext,
+				Variables:        options.Variables,
+				Wait:             options.Wait,
+				Timeout:          options.Timeout,
+				ShowCTA:          oktetoLog.IsInteractive(),
+				rollback:         entry,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Name, "name", "", "development environment name")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "overwrites the namespace where the development environment is deployed")
+	cmd.Flags().StringVarP(&options.K8sContext, "context", "c", "", "context where the development environment is deployed")
+	cmd.Flags().StringArrayVarP(&options.Variables, "var", "v", []string{}, "set a variable (can be set more than once)")
+	cmd.Flags().IntVarP(&options.To, "to", "", 0, "version of the deploy history to roll back to (defaults to the previous successful deploy)")
+	cmd.Flags().BoolVarP(&options.Wait, "wait", "w", false, "wait until the development environment is deployed (defaults to false)")
+	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "t", (5 * time.Minute), "the length of time to wait for completion, zero means never. Any other values should contain a corresponding time unit e.g. 1s, 2m, 3h ")
+	return cmd
+}
+
+// getRollbackEntry returns the entry with version 'to', or the last successful deploy before the current one if 'to' is zero
+func getRollbackEntry(history []pipeline.HistoryEntry, to int) (*pipeline.HistoryEntry, error) {
+	if len(history) == 0 {
+		return nil, errors.New("there is no deploy history to roll back to")
+	}
+
+	if to == 0 {
+		// deploys whose manifest didn't fit in the history can't be rolled back to
+		for i := len(history) - 2; i >= 0; i-- {
+			if history[i].Status == pipeline.DeployedStatus && history[i].Manifest != "" {
+				return &history[i], nil
+			}
+		}
+		return nil, errors.New("there is no previous successful deploy to roll back to")
+	}
+
+	versions := []string{}
+	for i := range history {
+		if history[i].Version == to {
+			if history[i].Manifest == "" {
+				return nil, fmt.Errorf("version %d doesn't have a manifest recorded", to)
+			}
+			return &history[i], nil
+		}
+		versions = append(versions, fmt.Sprintf("%d", history[i].Version))
+	}
+	return nil, fmt.Errorf("version %d not found in the deploy history. Available versions: [%s]", to, strings.Join(versions, ", "))
+}
+
+// writeRollbackManifest writes the manifest recorded in the history next to the original manifest, resolved against cwd,
+// so relative paths are resolved as in the original deploy
+func writeRollbackManifest(cwd string, entry *pipeline.HistoryEntry) (string, error) {
+	dir := filepath.Dir(entry.Filename)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(cwd, dir)
+	}
+	f, err := os.CreateTemp(dir, ".okteto-rollback-*.yml")
+	if err != nil {
+		return "", fmt.Errorf("error writing the manifest of version %d: %w", entry.Version, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(entry.Manifest); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("error writing the manifest of version %d: %w", entry.Version, err)
+	}
+	return f.Name(), nil
+}
+
+// setRollbackImages exports the images recorded in the history as if they had just been built
+func (dc *DeployCommand) setRollbackImages(opts *Options) error {
+	names := []string{}
+	for name := range opts.Manifest.Build {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		image, ok := opts.rollback.Images[name]
+		if !ok {
+			oktetoLog.Infof("version %d doesn't have an image recorded for service '%s'", opts.rollback.Version, name)
+			continue
+		}
+		oktetoLog.Information("Using image '%s' for service '%s'", image, name)
+		dc.Builder.SetServiceEnvVars(name, image)
+	}
+	return opts.Manifest.ExpandEnvVars()
+}
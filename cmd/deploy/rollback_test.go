@@ -0,0 +1,124 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRollbackEntry(t *testing.T) {
+	manifest := "deploy:\n- okteto build\n"
+	history := []pipeline.HistoryEntry{
+		{Version: 3, Status: pipeline.DeployedStatus, Manifest: manifest},
+		{Version: 4, Status: pipeline.DeployedStatus},
+		{Version: 5, Status: pipeline.ErrorStatus, Manifest: manifest},
+		{Version: 6, Status: pipeline.DeployedStatus, Manifest: manifest},
+	}
+
+	tests := []struct {
+		name            string
+		history         []pipeline.HistoryEntry
+		to              int
+		expectedVersion int
+		expectedErr     bool
+	}{
+		{
+			name:        "empty-history",
+			history:     []pipeline.HistoryEntry{},
+			expectedErr: true,
+		},
+		{
+			name:            "previous-successful-deploy-with-manifest",
+			history:         history,
+			expectedVersion: 3,
+		},
+		{
+			name:        "no-previous-successful-deploy",
+			history:     history[2:],
+			expectedErr: true,
+		},
+		{
+			name:            "specific-version",
+			history:         history,
+			to:              5,
+			expectedVersion: 5,
+		},
+		{
+			name:        "version-not-found",
+			history:     history,
+			to:          1,
+			expectedErr: true,
+		},
+		{
+			name:        "version-without-manifest",
+			history:     history,
+			to:          4,
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := getRollbackEntry(tt.history, tt.to)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedVersion, entry.Version)
+		})
+	}
+}
+
+func TestWriteRollbackManifest(t *testing.T) {
+	cwd := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(cwd, "deploy"), 0700))
+
+	tests := []struct {
+		name        string
+		filename    string
+		expectedDir string
+	}{
+		{
+			name:        "default-manifest",
+			expectedDir: cwd,
+		},
+		{
+			name:        "relative-manifest",
+			filename:    "deploy/okteto.yml",
+			expectedDir: filepath.Join(cwd, "deploy"),
+		},
+		{
+			name:        "absolute-manifest",
+			filename:    filepath.Join(cwd, "deploy", "okteto.yml"),
+			expectedDir: filepath.Join(cwd, "deploy"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &pipeline.HistoryEntry{Version: 2, Filename: tt.filename, Manifest: "deploy:\n- okteto build\n"}
+			path, err := writeRollbackManifest(cwd, entry)
+			assert.NoError(t, err)
+			defer os.Remove(path)
+			assert.Equal(t, tt.expectedDir, filepath.Dir(path))
+			content, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, entry.Manifest, string(content))
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/okteto/okteto/pkg/cmd/pipeline"
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/helm"
 	"github.com/okteto/okteto/pkg/k8s/deployments"
 	"github.com/okteto/okteto/pkg/k8s/statefulsets"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
@@ -93,7 +94,35 @@ func (dc *DeployCommand) waitForResourcesToBeRunning(ctx context.Context, opts *
 			if !areAllRunning {
 				continue
 			}
+			isReleaseDeployed, err := dc.isHelmReleaseDeployed(opts)
+			if err != nil {
+				return err
+			}
+			if !isReleaseDeployed {
+				continue
+			}
 			return nil
 		}
 	}
 }
+
+// isHelmReleaseDeployed returns if the release of the 'deploy.helm' section, if any, has been successfully deployed
+func (dc *DeployCommand) isHelmReleaseDeployed(opts *Options) (bool, error) {
+	releaseName := getTrackedHelmRelease(opts.Manifest, opts.Name)
+	if releaseName == "" {
+		return true, nil
+	}
+	status, err := helm.NewClient(dc.TempKubeconfigFile).GetReleaseStatus(releaseName, opts.Manifest.Namespace)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case helm.ReleaseDeployedStatus:
+		return true, nil
+	case helm.ReleaseFailedStatus:
+		return false, fmt.Errorf("helm release '%s' has failed. Please check for errors and try again", releaseName)
+	default:
+		oktetoLog.Infof("helm release '%s' is %s", releaseName, status)
+		return false, nil
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	buildv2 "github.com/okteto/okteto/cmd/build/v2"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/types"
+)
+
+// watchDebounce is the time without changes to wait before redeploying, so bursts of changes trigger a single redeploy
+const watchDebounce = 500 * time.Millisecond
+
+var errNoBuildContextsToWatch = errors.New("'--watch' requires at least one service in the 'build' section of your okteto manifest")
+
+// ignoredWatchDirs are never watched, their changes don't affect the images
+var ignoredWatchDirs = map[string]bool{
+	".git":    true,
+	".okteto": true,
+}
+
+// watch redeploys the services affected by the local file changes until ctx is done
+func (dc *DeployCommand) watch(ctx context.Context, opts *Options) error {
+	contexts, err := getBuildContexts(opts.Manifest.Build)
+	if err != nil {
+		return err
+	}
+	if len(contexts) == 0 {
+		return errNoBuildContextsToWatch
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for dir := range contexts {
+		if err := addWatchDir(watcher, dir); err != nil {
+			return err
+		}
+	}
+	oktetoLog.Information("Watching for changes in your build contexts. Press CTRL+C to stop")
+
+	changed := map[string]bool{}
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := addWatchDir(watcher, event.Name); err != nil {
+						oktetoLog.Infof("could not watch '%s': %s", event.Name, err)
+					}
+				}
+			}
+			changed[event.Name] = true
+			debounce = time.After(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			oktetoLog.Infof("error watching files: %s", err)
+		case <-debounce:
+			paths := setToSlice(changed)
+			changed = map[string]bool{}
+			debounce = nil
+			services := getChangedServices(contexts, paths)
+			if len(services) == 0 {
+				continue
+			}
+			if err := dc.redeploy(ctx, opts, addBuildDependents(opts.Manifest.Build, services)); err != nil {
+				oktetoLog.Warning("Redeploy failed: %s", err.Error())
+				continue
+			}
+			oktetoLog.Success("Services [%s] successfully redeployed", strings.Join(services, ", "))
+		}
+	}
+}
+
+// redeploy rebuilds the images of services and reruns the compose services or deploy commands that use them
+func (dc *DeployCommand) redeploy(ctx context.Context, opts *Options, services []string) error {
+	oktetoLog.Information("Changes detected in [%s], redeploying...", strings.Join(services, ", "))
+
+	// the builder keeps track of the images it already built, a new one is needed on every redeploy
+	buildOptions := &types.BuildOptions{
+		EnableStages: true,
+		Manifest:     opts.Manifest,
+		CommandArgs:  services,
+	}
+	if err := buildv2.NewBuilderFromScratch().Build(ctx, buildOptions); err != nil {
+		return err
+	}
+
+	stackServices, otherServices := splitStackServices(opts.Manifest.GetStack(), services)
+	if len(stackServices) > 0 {
+		opts.servicesToDeploy = stackServices
+		if err := dc.deployStack(ctx, opts); err != nil {
+			return err
+		}
+	}
+
+	if len(otherServices) == 0 {
+		return nil
+	}
+	commands := getCommandsToRedeploy(opts.Manifest.Deploy.Commands, otherServices)
+	oktetoLog.EnableMasking()
+	defer oktetoLog.DisableMasking()
+	_, err := newCommandsRunner(dc.Executor, opts.Variables).run(commands)
+	return err
+}
+
+// getBuildContexts returns the absolute path of each build context and the services built from it
+func getBuildContexts(build model.ManifestBuild) (map[string][]string, error) {
+	contexts := map[string][]string{}
+	for name, info := range build {
+		if info == nil {
+			continue
+		}
+		dir, err := filepath.Abs(info.Context)
+		if err != nil {
+			return nil, fmt.Errorf("error getting build context of service '%s': %w", name, err)
+		}
+		contexts[dir] = append(contexts[dir], name)
+	}
+	for dir := range contexts {
+		sort.Strings(contexts[dir])
+	}
+	return contexts, nil
+}
+
+// addWatchDir watches dir and all its subfolders, fsnotify is not recursive
+func addWatchDir(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if ignoredWatchDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("error watching '%s': %w", path, err)
+		}
+		return nil
+	})
+}
+
+// getChangedServices returns the services whose build context contains any of the changed paths
+func getChangedServices(contexts map[string][]string, paths []string) []string {
+	services := map[string]bool{}
+	for _, path := range paths {
+		for dir, names := range contexts {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+			if isIgnoredWatchPath(rel) {
+				continue
+			}
+			for _, name := range names {
+				services[name] = true
+			}
+		}
+	}
+	result := setToSlice(services)
+	sort.Strings(result)
+	return result
+}
+
+func isIgnoredWatchPath(path string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if ignoredWatchDirs[part] {
+			return true
+		}
+	}
+	return false
+}
+
+// addBuildDependents adds the services whose build depends on any of the given services
+func addBuildDependents(build model.ManifestBuild, services []string) []string {
+	result := sliceToSet(services)
+	added := true
+	for added {
+		added = false
+		for name, info := range build {
+			if info == nil || result[name] {
+				continue
+			}
+			for _, dependency := range info.DependsOn {
+				if result[dependency] {
+					result[name] = true
+					added = true
+					break
+				}
+			}
+		}
+	}
+	services = setToSlice(result)
+	sort.Strings(services)
+	return services
+}
+
+// splitStackServices splits the services between the ones deployed by the compose section and the rest
+func splitStackServices(stack *model.Stack, services []string) ([]string, []string) {
+	stackServices := []string{}
+	otherServices := []string{}
+	for _, name := range services {
+		if stack != nil {
+			if _, ok := stack.Services[name]; ok {
+				stackServices = append(stackServices, name)
+				continue
+			}
+		}
+		otherServices = append(otherServices, name)
+	}
+	return stackServices, otherServices
+}
+
+// getCommandsToRedeploy returns the commands that reference the images of the services.
+// If none of them does, all the commands are returned as there is no way to know which ones use them
+func getCommandsToRedeploy(commands []model.DeployCommand, services []string) []model.DeployCommand {
+	result := []model.DeployCommand{}
+	for _, command := range commands {
+		for _, name := range services {
+			if strings.Contains(command.Command, buildv2.GetBuildEnvVar(name, "")) {
+				result = append(result, command)
+				break
+			}
+		}
+	}
+	if len(result) == 0 {
+		return commands
+	}
+	return result
+}
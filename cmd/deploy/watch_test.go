@@ -0,0 +1,97 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChangedServices(t *testing.T) {
+	root := t.TempDir()
+	build := model.ManifestBuild{
+		"api":    &model.BuildInfo{Context: filepath.Join(root, "api")},
+		"worker": &model.BuildInfo{Context: filepath.Join(root, "api")},
+		"web":    &model.BuildInfo{Context: filepath.Join(root, "web")},
+	}
+	contexts, err := getBuildContexts(build)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api", "worker"}, contexts[filepath.Join(root, "api")])
+
+	tests := []struct {
+		name     string
+		paths    []string
+		expected []string
+	}{
+		{
+			name:     "file-in-context",
+			paths:    []string{filepath.Join(root, "web", "src", "index.js")},
+			expected: []string{"web"},
+		},
+		{
+			name:     "shared-context",
+			paths:    []string{filepath.Join(root, "api", "main.go"), filepath.Join(root, "web", "index.html")},
+			expected: []string{"api", "web", "worker"},
+		},
+		{
+			name:     "outside-contexts",
+			paths:    []string{filepath.Join(root, "README.md"), filepath.Join(root, "apis", "main.go")},
+			expected: []string{},
+		},
+		{
+			name:     "ignored-folder",
+			paths:    []string{filepath.Join(root, "web", ".git", "index")},
+			expected: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getChangedServices(contexts, tt.paths))
+		})
+	}
+}
+
+func TestAddBuildDependents(t *testing.T) {
+	build := model.ManifestBuild{
+		"base":   &model.BuildInfo{},
+		"api":    &model.BuildInfo{DependsOn: model.BuildDependsOn{"base"}},
+		"worker": &model.BuildInfo{DependsOn: model.BuildDependsOn{"api"}},
+		"web":    &model.BuildInfo{},
+	}
+	assert.Equal(t, []string{"api", "base", "worker"}, addBuildDependents(build, []string{"base"}))
+	assert.Equal(t, []string{"web"}, addBuildDependents(build, []string{"web"}))
+}
+
+func TestSplitStackServices(t *testing.T) {
+	stack := &model.Stack{Services: map[string]*model.Service{"api": {}}}
+	stackServices, otherServices := splitStackServices(stack, []string{"api", "web"})
+	assert.Equal(t, []string{"api"}, stackServices)
+	assert.Equal(t, []string{"web"}, otherServices)
+
+	stackServices, otherServices = splitStackServices(nil, []string{"api"})
+	assert.Empty(t, stackServices)
+	assert.Equal(t, []string{"api"}, otherServices)
+}
+
+func TestGetCommandsToRedeploy(t *testing.T) {
+	commands := []model.DeployCommand{
+		{Name: "api", Command: "helm upgrade --install api chart --set image=${OKTETO_BUILD_MY_API_IMAGE}"},
+		{Name: "web", Command: "kubectl set image deployment/web web=${OKTETO_BUILD_WEB_IMAGE}"},
+	}
+	assert.Equal(t, commands[:1], getCommandsToRedeploy(commands, []string{"my-api"}))
+	assert.Equal(t, commands, getCommandsToRedeploy(commands, []string{"worker"}))
+}
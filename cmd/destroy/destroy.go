@@ -33,6 +33,7 @@ import (
 
 	"github.com/okteto/okteto/pkg/analytics"
 	"github.com/okteto/okteto/pkg/cmd/pipeline"
+	"github.com/okteto/okteto/pkg/helm"
 	"github.com/okteto/okteto/pkg/k8s/kubeconfig"
 	"github.com/okteto/okteto/pkg/k8s/namespaces"
 	"github.com/okteto/okteto/pkg/k8s/secrets"
@@ -62,6 +63,10 @@ type secretHandler interface {
 	List(ctx context.Context, ns, labelSelector string) ([]v1.Secret, error)
 }
 
+type helmUninstaller interface {
+	Uninstall(name, namespace string) error
+}
+
 // Options destroy commands options
 type Options struct {
 	// ManifestPathFlag is the option -f as introduced by the user when executing this command.
@@ -86,6 +91,7 @@ type destroyCommand struct {
 	executor          executor.ManifestExecutor
 	nsDestroyer       destroyer
 	secrets           secretHandler
+	helm              helmUninstaller
 	k8sClientProvider okteto.K8sClientProvider
 	configMapHandler  configMapHandler
 }
@@ -160,6 +166,7 @@ func Destroy(ctx context.Context) *cobra.Command {
 				options.Namespace = okteto.Context().Namespace
 			}
 
+			kubeconfigPath := getTempKubeConfigFile(name)
 			c := &destroyCommand{
 				getManifest: model.GetManifestV2,
 
@@ -167,10 +174,10 @@ func Destroy(ctx context.Context) *cobra.Command {
 				configMapHandler:  newConfigmapHandler(k8sClient),
 				nsDestroyer:       namespaces.NewNamespace(dynClient, discClient, cfg, k8sClient),
 				secrets:           secrets.NewSecrets(k8sClient),
+				helm:              helm.NewClient(kubeconfigPath),
 				k8sClientProvider: okteto.NewK8sClientProvider(),
 			}
 
-			kubeconfigPath := getTempKubeConfigFile(name)
 			if err := kubeconfig.Write(okteto.Context().Cfg, kubeconfigPath); err != nil {
 				return err
 			}
@@ -356,6 +363,14 @@ func (dc *destroyCommand) runDestroy(ctx context.Context, opts *Options) error {
 	}
 
 	oktetoLog.SetStage("Destroying Helm release")
+	if err := dc.destroyTrackedHelmRelease(cfg, namespace); err != nil {
+		if !opts.ForceDestroy {
+			if err := dc.configMapHandler.setErrorStatus(ctx, cfg, data, err); err != nil {
+				return err
+			}
+			return err
+		}
+	}
 	if err := dc.destroyHelmReleasesIfPresent(ctx, opts, deployedBySelector); err != nil {
 		if !opts.ForceDestroy {
 			if err := dc.configMapHandler.setErrorStatus(ctx, cfg, data, err); err != nil {
@@ -384,6 +399,20 @@ func (dc *destroyCommand) runDestroy(ctx context.Context, opts *Options) error {
 	return commandErr
 }
 
+// destroyTrackedHelmRelease uninstalls the release deployed by the 'deploy.helm' section without the helm binary
+func (dc *destroyCommand) destroyTrackedHelmRelease(cfg *v1.ConfigMap, namespace string) error {
+	releaseName := pipeline.GetHelmRelease(cfg)
+	if releaseName == "" {
+		return nil
+	}
+	oktetoLog.Information("Uninstalling helm release '%s'", releaseName)
+	if err := dc.helm.Uninstall(releaseName, namespace); err != nil {
+		oktetoLog.Infof("could not uninstall helm release '%s': %s", releaseName, err)
+		return err
+	}
+	return nil
+}
+
 func (dc *destroyCommand) destroyHelmReleasesIfPresent(ctx context.Context, opts *Options, labelSelector string) error {
 	sList, err := dc.secrets.List(ctx, opts.Namespace, labelSelector)
 	if err != nil {
@@ -66,6 +66,11 @@ type fakeExecutor struct {
 	executed []model.DeployCommand
 }
 
+type fakeHelmUninstaller struct {
+	err         error
+	uninstalled []string
+}
+
 func (fd *fakeDestroyer) DestroyWithLabel(_ context.Context, _ string, _ namespaces.DeleteAllOptions) error {
 	if fd.err != nil {
 		return fd.err
@@ -102,6 +107,14 @@ func (fe *fakeExecutor) Execute(command model.DeployCommand, _ []string) error {
 }
 
 func (*fakeExecutor) CleanUp(_ error) {}
+
+func (fh *fakeHelmUninstaller) Uninstall(name, namespace string) error {
+	if fh.err != nil {
+		return fh.err
+	}
+	fh.uninstalled = append(fh.uninstalled, fmt.Sprintf("%s/%s", namespace, name))
+	return nil
+}
 func getManifestWithError(_ string) (*model.Manifest, error) {
 	return nil, assert.AnError
 }
@@ -924,3 +937,70 @@ func TestDestroyWithForceOptionAndFailedCommands(t *testing.T) {
 	cfg, _ := configmaps.Get(ctx, pipeline.TranslatePipelineName(opts.Name), okteto.Context().Namespace, fakeClient)
 	assert.Nil(t, cfg)
 }
+
+func TestDestroyWithTrackedHelmRelease(t *testing.T) {
+	ctx := context.Background()
+	okteto.CurrentStore = &okteto.OktetoContextStore{
+		Contexts: map[string]*okteto.OktetoContext{
+			"test": {
+				Namespace: "test",
+			},
+		},
+		CurrentContext: "test",
+	}
+	opts := &Options{
+		Name: "test-app",
+	}
+	k8sClientProvider := test.NewFakeK8sProvider()
+	fakeClient, _, err := k8sClientProvider.Provide(api.NewConfig())
+	if err != nil {
+		t.Fatal("could not create fake k8s client")
+	}
+	_, err = pipeline.TranslateConfigMapAndDeploy(ctx, &pipeline.CfgData{
+		Name:        opts.Name,
+		Namespace:   "test",
+		Status:      pipeline.DeployedStatus,
+		HelmRelease: "test-release",
+	}, fakeClient)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		helm          *fakeHelmUninstaller
+		expectedError bool
+	}{
+		{
+			name:          "error uninstalling",
+			helm:          &fakeHelmUninstaller{err: assert.AnError},
+			expectedError: true,
+		},
+		{
+			name: "uninstalled",
+			helm: &fakeHelmUninstaller{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destroyer := &fakeDestroyer{}
+			cmd := &destroyCommand{
+				getManifest:       getManifestWithError,
+				secrets:           &fakeSecretHandler{},
+				executor:          &fakeExecutor{},
+				nsDestroyer:       destroyer,
+				helm:              tt.helm,
+				k8sClientProvider: k8sClientProvider,
+				configMapHandler:  newConfigmapHandler(fakeClient),
+			}
+
+			err := cmd.runDestroy(ctx, opts)
+			if tt.expectedError {
+				assert.Error(t, err)
+				assert.False(t, destroyer.destroyed)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"test/test-release"}, tt.helm.uninstalled)
+			assert.True(t, destroyer.destroyed)
+		})
+	}
+}
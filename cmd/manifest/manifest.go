@@ -0,0 +1,132 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/discovery"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// Manifest manifest management commands
+func Manifest() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Manifest management commands",
+		Args:  utils.NoArgsAccepted("https://okteto.com/docs/reference/manifest/"),
+	}
+	cmd.AddCommand(Schema())
+	cmd.AddCommand(Validate())
+	return cmd
+}
+
+// Schema prints the json schema of the okteto manifest
+func Schema() *cobra.Command {
+	var compose bool
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON schema of the okteto manifest",
+		Args:  utils.NoArgsAccepted("https://okteto.com/docs/reference/manifest/"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schema := model.GetManifestSchema()
+			if compose {
+				schema = model.GetStackSchema()
+			}
+			bytes, err := json.MarshalIndent(schema, "", "  ")
+			if err != nil {
+				return err
+			}
+			oktetoLog.Println(string(bytes))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&compose, "compose", "", false, "print the JSON schema of compose files")
+	return cmd
+}
+
+// Validate reports all the problems of an okteto manifest or compose file
+func Validate() *cobra.Command {
+	var (
+		manifestPath string
+		compose      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate your okteto manifest",
+		Args:  utils.NoArgsAccepted("https://okteto.com/docs/reference/manifest/"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if manifestPath == "" {
+				wd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				manifestPath, err = getManifestPathToValidate(wd)
+				if err != nil {
+					return err
+				}
+			}
+			if !compose {
+				compose = isComposeManifestPath(manifestPath)
+			}
+
+			validate := model.ValidateManifestFile
+			if compose {
+				validate = model.ValidateStackFile
+			}
+			problems, err := validate(manifestPath)
+			if err != nil {
+				return err
+			}
+			if len(problems) == 0 {
+				oktetoLog.Success("'%s' is valid", manifestPath)
+				return nil
+			}
+
+			oktetoLog.Fail("'%s' has %d problems:", manifestPath, len(problems))
+			for _, problem := range problems {
+				oktetoLog.Println(fmt.Sprintf("  - %s", problem.Error()))
+			}
+			return fmt.Errorf("'%s' is not valid", manifestPath)
+		},
+	}
+	cmd.Flags().StringVarP(&manifestPath, "file", "f", "", "path to the okteto manifest or compose file")
+	cmd.Flags().BoolVarP(&compose, "compose", "", false, "validate the file as a compose file")
+	return cmd
+}
+
+// getManifestPathToValidate returns the okteto manifest of a folder or, if it doesn't have one, its compose file
+func getManifestPathToValidate(wd string) (string, error) {
+	manifestPath, err := discovery.GetOktetoManifestPath(wd)
+	if err == nil {
+		return manifestPath, nil
+	}
+	if !errors.Is(err, discovery.ErrOktetoManifestNotFound) {
+		return "", err
+	}
+	return discovery.GetComposePath(wd)
+}
+
+func isComposeManifestPath(manifestPath string) bool {
+	base := filepath.Base(manifestPath)
+	return strings.Contains(base, "compose") || strings.Contains(base, "stack")
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getManifestPathToValidate(t *testing.T) {
+	dir := t.TempDir()
+	_, err := getManifestPathToValidate(dir)
+	assert.ErrorIs(t, err, discovery.ErrComposeFileNotFound)
+
+	composePath := filepath.Join(dir, "docker-compose.yml")
+	assert.NoError(t, os.WriteFile(composePath, []byte("services: {}"), 0600))
+	result, err := getManifestPathToValidate(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, composePath, result)
+
+	manifestPath := filepath.Join(dir, "okteto.yml")
+	assert.NoError(t, os.WriteFile(manifestPath, []byte("dev: {}"), 0600))
+	result, err = getManifestPathToValidate(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, manifestPath, result)
+}
+
+func Test_isComposeManifestPath(t *testing.T) {
+	assert.True(t, isComposeManifestPath("docker-compose.yml"))
+	assert.True(t, isComposeManifestPath(filepath.Join(".okteto", "okteto-stack.yaml")))
+	assert.False(t, isComposeManifestPath("okteto.yml"))
+	assert.False(t, isComposeManifestPath(filepath.Join("compose", "okteto.yml")))
+}
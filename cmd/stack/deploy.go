@@ -50,6 +50,9 @@ func deploy(ctx context.Context) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			oktetoLog.Warning("'okteto stack deploy' is deprecated in favor of 'okteto deploy', and will be removed in a future version")
 			options.ServicesToDeploy = args
+			if len(options.Profiles) > 0 {
+				os.Setenv(model.ComposeProfilesEnvVar, strings.Join(options.Profiles, ","))
+			}
 
 			options.StackPaths = loadComposePaths(options.StackPaths)
 			if len(options.StackPaths) == 1 {
@@ -81,6 +84,7 @@ func deploy(ctx context.Context) *cobra.Command {
 	cmd.Flags().BoolVarP(&options.Wait, "wait", "", false, "wait until a minimum number of containers are in a ready state for every service")
 	cmd.Flags().BoolVarP(&options.NoCache, "no-cache", "", false, "do not use cache when building the image")
 	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "t", (10 * time.Minute), "the length of time to wait for completion, zero means never. Any other values should contain a corresponding time unit e.g. 1s, 2m, 3h ")
+	cmd.Flags().StringArrayVarP(&options.Profiles, "profile", "", []string{}, "compose profile to enable (can be set more than once)")
 	cmd.Flags().StringVarP(&options.Progress, "progress", "", oktetoLog.TTYFormat, "show plain/tty build output (default \"tty\")")
 	return cmd
 }
@@ -0,0 +1,80 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+
+	contextCMD "github.com/okteto/okteto/cmd/context"
+	"github.com/okteto/okteto/pkg/cmd/stack"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/spf13/cobra"
+)
+
+// Diff shows the differences between a compose and the resources deployed in the cluster
+func Diff(ctx context.Context) *cobra.Command {
+	var (
+		name      string
+		namespace string
+		stackPath []string
+	)
+	cmd := &cobra.Command{
+		Use:   "diff [service...]",
+		Short: "Show the differences between a compose and the deployed resources",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := contextCMD.LoadStackWithContext(ctx, name, namespace, loadComposePaths(stackPath))
+			if err != nil {
+				return err
+			}
+
+			servicesToDiff := args
+			if len(servicesToDiff) == 0 {
+				for svcName := range s.Services {
+					servicesToDiff = append(servicesToDiff, svcName)
+				}
+			}
+			for _, svcName := range servicesToDiff {
+				if _, ok := s.Services[svcName]; !ok {
+					return fmt.Errorf("service '%s' is not defined in the compose '%s'", svcName, s.Name)
+				}
+			}
+
+			c, _, err := okteto.NewK8sClientProvider().Provide(okteto.Context().Cfg)
+			if err != nil {
+				return err
+			}
+			diffs, err := stack.Diff(ctx, s, servicesToDiff, c)
+			if err != nil {
+				return err
+			}
+			if len(diffs) == 0 {
+				oktetoLog.Success("Compose '%s' is up to date", s.Name)
+				return nil
+			}
+			for _, diff := range diffs {
+				if diff.Reason != "" {
+					oktetoLog.Information(diff.Reason)
+				}
+				oktetoLog.Println(diff.Diff)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&stackPath, "file", "f", []string{}, "path to the compose manifest files. If more than one is passed the latest will overwrite the fields from the previous")
+	cmd.Flags().StringVarP(&name, "name", "", "", "overwrites the compose name")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "overwrites the compose namespace where the compose is deployed")
+	return cmd
+}
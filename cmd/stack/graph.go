@@ -0,0 +1,50 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"github.com/okteto/okteto/pkg/cmd/stack"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// Graph renders the dependencies between the services of a compose
+func Graph() *cobra.Command {
+	var (
+		output    string
+		name      string
+		stackPath []string
+	)
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Show the dependencies between the services of a compose",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := model.LoadStack(name, loadComposePaths(stackPath), true)
+			if err != nil {
+				return err
+			}
+			graph, err := stack.RenderDependencyGraph(s, output)
+			if err != nil {
+				return err
+			}
+			oktetoLog.Print(graph)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", stack.GraphFormatDot, "output format. One of: ['dot', 'mermaid']")
+	cmd.Flags().StringArrayVarP(&stackPath, "file", "f", []string{}, "path to the compose manifest files. If more than one is passed the latest will overwrite the fields from the previous")
+	cmd.Flags().StringVarP(&name, "name", "", "", "overwrites the compose name")
+	return cmd
+}
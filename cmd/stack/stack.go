@@ -31,5 +31,7 @@ func Stack(ctx context.Context) *cobra.Command {
 	cmd.AddCommand(deploy(ctx))
 	cmd.AddCommand(Destroy(ctx))
 	cmd.AddCommand(Endpoints(ctx))
+	cmd.AddCommand(Diff(ctx))
+	cmd.AddCommand(Graph())
 	return cmd
 }
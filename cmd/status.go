@@ -15,7 +15,9 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"time"
@@ -27,6 +29,7 @@ import (
 	"github.com/okteto/okteto/pkg/config"
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/okteto"
 	"github.com/okteto/okteto/pkg/syncthing"
 	"github.com/spf13/cobra"
@@ -39,6 +42,8 @@ func Status() *cobra.Command {
 	var k8sContext string
 	var showInfo bool
 	var watch bool
+	var detailed bool
+	var output string
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Status of the synchronization process",
@@ -48,6 +53,12 @@ func Status() *cobra.Command {
 			if okteto.InDevContainer() {
 				return oktetoErrors.ErrNotInDevContainer
 			}
+			if output != "" && output != "json" {
+				return fmt.Errorf("output format is not accepted. Value must be one of: ['json']")
+			}
+			if watch && (detailed || output != "") {
+				return fmt.Errorf("'--watch' cannot be used together with '--detailed' or '--output'")
+			}
 
 			ctx := context.Background()
 
@@ -73,6 +84,10 @@ func Status() *cobra.Command {
 				}
 			}
 
+			if dev.Sync.GetEngine() != model.SyncEngineSyncthing {
+				return fmt.Errorf("'okteto status' is only available for the '%s' sync engine", model.SyncEngineSyncthing)
+			}
+
 			waitForStates := []config.UpState{config.Synchronizing, config.Ready}
 			if err := status.Wait(dev, waitForStates); err != nil {
 				return err
@@ -83,18 +98,27 @@ func Status() *cobra.Command {
 				oktetoLog.Infof("error accessing the syncthing info file: %s", err)
 				return oktetoErrors.ErrNotInDevMode
 			}
+			if output == "json" {
+				err = runWithJSONOutput(ctx, sy)
+				analytics.TrackStatus(err == nil, showInfo)
+				return err
+			}
 			if showInfo {
 				oktetoLog.Information("Local syncthing url: http://%s", sy.GUIAddress)
 				oktetoLog.Information("Remote syncthing url: http://%s", sy.RemoteGUIAddress)
 				oktetoLog.Information("Syncthing username: okteto")
 				oktetoLog.Information("Syncthing password: %s", sy.GUIPassword)
 			}
+			printSyncSettings(sy)
 
 			if watch {
 				err = runWithWatch(ctx, sy)
 			} else {
 				err = runWithoutWatch(ctx, sy)
 			}
+			if err == nil && detailed {
+				err = printTelemetry(ctx, sy)
+			}
 
 			analytics.TrackStatus(err == nil, showInfo)
 			return err
@@ -105,6 +129,8 @@ func Status() *cobra.Command {
 	cmd.Flags().StringVarP(&k8sContext, "context", "c", "", "context where the up command is executing")
 	cmd.Flags().BoolVarP(&showInfo, "info", "i", false, "show syncthing links for troubleshooting the synchronization service")
 	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "watch for changes")
+	cmd.Flags().BoolVarP(&detailed, "detailed", "d", false, "show the transfer rates, pending files and large directories of the synchronization")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "output format. One of: ['json']")
 	return cmd
 }
 
@@ -164,3 +190,98 @@ func runWithoutWatch(ctx context.Context, sy *syncthing.Syncthing) error {
 	}
 	return nil
 }
+
+func printSyncSettings(sy *syncthing.Syncthing) {
+	for _, folder := range sy.Folders {
+		mode := folder.Mode
+		if mode == "" {
+			mode = model.SyncModeTwoWay
+		}
+		if folder.IgnoreDelete {
+			oktetoLog.Information("Sync '%s' -> '%s': %s, ignoring deletions", folder.LocalPath, folder.RemotePath, mode)
+		} else {
+			oktetoLog.Information("Sync '%s' -> '%s': %s", folder.LocalPath, folder.RemotePath, mode)
+		}
+	}
+	oktetoLog.Information("Upload limit: %s", getSyncLimitMessage(sy.UploadLimit))
+	oktetoLog.Information("Download limit: %s", getSyncLimitMessage(sy.DownloadLimit))
+}
+
+func getSyncLimitMessage(limit int) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d KB/s", limit)
+}
+
+// statusOutput represents the json output of the status command
+type statusOutput struct {
+	Progress float64 `json:"progress"`
+	*syncthing.Telemetry
+}
+
+func runWithJSONOutput(ctx context.Context, sy *syncthing.Syncthing) error {
+	progress, err := status.Run(ctx, sy)
+	if err != nil {
+		return err
+	}
+	telemetry, err := sy.GetTelemetry(ctx, time.Second)
+	if err != nil {
+		return err
+	}
+	bytes, err := json.MarshalIndent(statusOutput{Progress: progress, Telemetry: telemetry}, "", "  ")
+	if err != nil {
+		return err
+	}
+	oktetoLog.Println(string(bytes))
+	return nil
+}
+
+func printTelemetry(ctx context.Context, sy *syncthing.Syncthing) error {
+	oktetoLog.Spinner("Measuring the synchronization performance...")
+	oktetoLog.StartSpinner()
+	telemetry, err := sy.GetTelemetry(ctx, time.Second)
+	oktetoLog.StopSpinner()
+	if err != nil {
+		return err
+	}
+
+	oktetoLog.Information("Upload rate: %s/s", formatBytes(telemetry.UploadRate))
+	oktetoLog.Information("Download rate: %s/s", formatBytes(telemetry.DownloadRate))
+	for _, folder := range telemetry.Folders {
+		oktetoLog.Information("Folder '%s' (%s): %d files", folder.LocalPath, folder.State, folder.LocalFiles)
+		oktetoLog.Println(fmt.Sprintf("    Pending upload: %d files (%s)", folder.PendingUploadFiles, formatBytes(float64(folder.PendingUploadBytes))))
+		oktetoLog.Println(fmt.Sprintf("    Pending download: %d files (%s)", folder.PendingDownloadFiles, formatBytes(float64(folder.PendingDownloadBytes))))
+		oktetoLog.Println(fmt.Sprintf("    Last scan: %.1fs local, %.1fs remote", folder.LocalScanDuration, folder.RemoteScanDuration))
+	}
+
+	if len(telemetry.InFlightFiles) > 0 {
+		oktetoLog.Information("Largest files in transit:")
+		for _, f := range telemetry.InFlightFiles {
+			oktetoLog.Println(fmt.Sprintf("  - %s '%s': %s of %s", f.Direction, f.Path, formatBytes(float64(f.BytesDone)), formatBytes(float64(f.BytesTotal))))
+		}
+	}
+
+	if len(telemetry.LargeDirectories) > 0 {
+		oktetoLog.Warning(`Large directories are being synchronized
+    Consider to add them to your '.stignore' file if they are not needed in your development container
+    More information is available here: https://okteto.com/docs/reference/file-synchronization/`)
+		for _, dir := range telemetry.LargeDirectories {
+			oktetoLog.Println(fmt.Sprintf("  - '%s' (%s, %d files): add '%s'", dir.Path, formatBytes(float64(dir.Bytes)), dir.Files, dir.Stignore))
+		}
+	}
+	return nil
+}
+
+func formatBytes(b float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	i := 0
+	for b >= 1024 && i < len(units)-1 {
+		b /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", int64(b))
+	}
+	return fmt.Sprintf("%.1f %s", b, units[i])
+}
@@ -0,0 +1,45 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/syncthing"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_formatBytes(t *testing.T) {
+	assert.Equal(t, "0 B", formatBytes(0))
+	assert.Equal(t, "1023 B", formatBytes(1023))
+	assert.Equal(t, "1.5 KB", formatBytes(1536))
+	assert.Equal(t, "100.0 MB", formatBytes(100*1024*1024))
+	assert.Equal(t, "2048.0 TB", formatBytes(2*1024*1024*1024*1024*1024))
+}
+
+func Test_statusOutput(t *testing.T) {
+	output := statusOutput{
+		Progress:  50,
+		Telemetry: &syncthing.Telemetry{UploadRate: 1024, Folders: []syncthing.FolderTelemetry{}},
+	}
+	bytes, err := json.Marshal(output)
+	assert.NoError(t, err)
+
+	result := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(bytes, &result))
+	assert.Equal(t, float64(50), result["progress"])
+	assert.Equal(t, float64(1024), result["uploadBytesPerSecond"])
+	assert.Contains(t, result, "folders")
+}
@@ -0,0 +1,180 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sync
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	contextCMD "github.com/okteto/okteto/cmd/context"
+	"github.com/okteto/okteto/cmd/utils"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/syncthing"
+	"github.com/spf13/cobra"
+)
+
+const (
+	keepLocal  = "local"
+	keepRemote = "remote"
+
+	keepLocalOption  = "Keep the local version"
+	keepRemoteOption = "Keep the remote version"
+	showDiffOption   = "Show the differences"
+	skipOption       = "Skip"
+)
+
+// ConflictsOptions represents the options of the sync conflicts command
+type ConflictsOptions struct {
+	DevPath    string
+	Namespace  string
+	K8sContext string
+	Resolve    bool
+	Keep       string
+}
+
+// Conflicts lists and resolves the synchronization conflicts of a development container
+func Conflicts() *cobra.Command {
+	options := &ConflictsOptions{}
+	cmd := &cobra.Command{
+		Use:   "conflicts [devName]",
+		Short: "List and resolve the synchronization conflicts of a development container",
+		Args:  utils.MaximumNArgsAccepted(1, "https://okteto.com/docs/reference/cli/#sync"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if okteto.InDevContainer() {
+				return oktetoErrors.ErrNotInDevContainer
+			}
+			if options.Keep != "" && options.Keep != keepLocal && options.Keep != keepRemote {
+				return fmt.Errorf("supported values for '--keep' are: '%s' or '%s'", keepLocal, keepRemote)
+			}
+
+			ctx := context.Background()
+			manifestOpts := contextCMD.ManifestOptions{Filename: options.DevPath, Namespace: options.Namespace, K8sContext: options.K8sContext}
+			dev, err := getDev(ctx, manifestOpts, args)
+			if err != nil {
+				return err
+			}
+
+			sy, err := syncthing.Load(dev)
+			if err != nil {
+				oktetoLog.Infof("error accessing the syncthing info file: %s", err)
+				return oktetoErrors.ErrNotInDevMode
+			}
+
+			conflicts, err := sy.GetConflicts()
+			if err != nil {
+				return err
+			}
+			if len(conflicts) == 0 {
+				oktetoLog.Success("No synchronization conflicts found")
+				return nil
+			}
+
+			switch {
+			case options.Keep != "":
+				return resolveConflicts(conflicts, options.Keep)
+			case options.Resolve:
+				return resolveConflictsInteractively(conflicts)
+			default:
+				printConflicts(conflicts)
+				return nil
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&options.DevPath, "file", "f", utils.DefaultManifest, "path to the manifest file")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "namespace where the up command is executing")
+	cmd.Flags().StringVarP(&options.K8sContext, "context", "c", "", "context where the up command is executing")
+	cmd.Flags().BoolVarP(&options.Resolve, "resolve", "r", false, "resolve the conflicts one by one")
+	cmd.Flags().StringVarP(&options.Keep, "keep", "k", "", "resolve all the conflicts keeping the 'local' or the 'remote' version")
+	return cmd
+}
+
+func printConflicts(conflicts []syncthing.Conflict) {
+	oktetoLog.Information("Found %d synchronization conflicts:", len(conflicts))
+	for i := range conflicts {
+		oktetoLog.Println(fmt.Sprintf("  - %s", getConflictDescription(&conflicts[i])))
+	}
+	oktetoLog.Information("Run 'okteto sync conflicts --resolve' to resolve them")
+}
+
+func getConflictDescription(c *syncthing.Conflict) string {
+	version := keepRemote
+	if c.IsLocalCopy() {
+		version = keepLocal
+	}
+	return fmt.Sprintf("%s: the %s version was saved in '%s' at %s", filepath.Join(c.Folder.LocalPath, c.Path), version, c.ConflictPath, c.Time.Format("2006-01-02 15:04:05"))
+}
+
+func resolveConflicts(conflicts []syncthing.Conflict, keep string) error {
+	for i := range conflicts {
+		if err := resolveConflict(&conflicts[i], keep); err != nil {
+			return err
+		}
+	}
+	oktetoLog.Success("%d synchronization conflicts resolved", len(conflicts))
+	return nil
+}
+
+func resolveConflict(c *syncthing.Conflict, keep string) error {
+	if keep == keepLocal {
+		return c.KeepLocal()
+	}
+	return c.KeepRemote()
+}
+
+func resolveConflictsInteractively(conflicts []syncthing.Conflict) error {
+	resolved := 0
+	for i := range conflicts {
+		oktetoLog.Information("%s", getConflictDescription(&conflicts[i]))
+		ok, err := resolveConflictInteractively(&conflicts[i])
+		if err != nil {
+			return err
+		}
+		if ok {
+			resolved++
+		}
+	}
+	oktetoLog.Success("%d of %d synchronization conflicts resolved", resolved, len(conflicts))
+	return nil
+}
+
+// resolveConflictInteractively asks how to resolve a conflict and returns false if it is skipped
+func resolveConflictInteractively(c *syncthing.Conflict) (bool, error) {
+	for {
+		option, err := utils.AskForOptions(
+			[]string{keepLocalOption, keepRemoteOption, showDiffOption, skipOption},
+			"How do you want to resolve the conflict?",
+		)
+		if err != nil {
+			return false, err
+		}
+
+		switch option {
+		case keepLocalOption:
+			return true, resolveConflict(c, keepLocal)
+		case keepRemoteOption:
+			return true, resolveConflict(c, keepRemote)
+		case showDiffOption:
+			diff, err := c.Diff()
+			if err != nil {
+				return false, err
+			}
+			oktetoLog.Println(diff)
+		default:
+			return false, nil
+		}
+	}
+}
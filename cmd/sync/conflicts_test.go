@@ -0,0 +1,50 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/syncthing"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resolveConflicts(t *testing.T) {
+	dir := t.TempDir()
+	folder := &syncthing.Folder{Name: "1", LocalPath: dir}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("remote"), 0600))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.sync-conflict-20221010-101010-ABKAVQF.txt"), []byte("local"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.sync-conflict-20221010-101010-ABKAVQF.txt"), []byte("local"), 0600))
+
+	sy := &syncthing.Syncthing{Folders: []*syncthing.Folder{folder}}
+	conflicts, err := sy.GetConflicts()
+	assert.NoError(t, err)
+	assert.Len(t, conflicts, 2)
+	assert.Equal(t, filepath.Join(dir, "a.txt")+": the local version was saved in 'a.sync-conflict-20221010-101010-ABKAVQF.txt' at 2022-10-10 10:10:10", getConflictDescription(&conflicts[0]))
+
+	assert.NoError(t, resolveConflicts(conflicts, keepLocal))
+	for _, name := range []string{"a.txt", "b.txt"} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, "local", string(b))
+	}
+
+	conflicts, err = sy.GetConflicts()
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+}
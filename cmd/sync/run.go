@@ -0,0 +1,250 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/okteto/okteto/cmd/utils"
+	"github.com/okteto/okteto/pkg/config"
+	forwardk8s "github.com/okteto/okteto/pkg/k8s/forward"
+	"github.com/okteto/okteto/pkg/k8s/pods"
+	"github.com/okteto/okteto/pkg/k8s/secrets"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/model/forward"
+	"github.com/okteto/okteto/pkg/syncthing"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	syncContainerPrefix = "okteto-sync-"
+
+	// targetRootPath is the filesystem of the target container, seen from the ephemeral container sharing its process namespace
+	targetRootPath = "/proc/1/root"
+
+	remoteSyncthingHome = "/tmp/okteto-syncthing"
+	remoteSyncthingBin  = "/usr/local/bin/syncthing"
+
+	configEnvVar = "OKTETO_SYNC_CONFIG"
+	certEnvVar   = "OKTETO_SYNC_CERT"
+	keyEnvVar    = "OKTETO_SYNC_KEY"
+)
+
+// syncContext is the context of a file synchronization with a running container
+type syncContext struct {
+	Dev        *model.Dev
+	Pod        *apiv1.Pod
+	Container  string
+	Continuous bool
+	Client     *kubernetes.Clientset
+	RestConfig *rest.Config
+	Sy         *syncthing.Syncthing
+	Forwarder  *forwardk8s.PortForwardManager
+	Disconnect chan error
+}
+
+func (s *syncContext) run(ctx context.Context) (err error) {
+	s.Sy, err = syncthing.New(s.Dev)
+	if err != nil {
+		return err
+	}
+	// the remote syncthing starts with an empty database every time
+	s.Sy.ResetDatabase = true
+	name := fmt.Sprintf("%s-sync", s.Pod.Name)
+	s.Sy.Home = config.GetAppHome(s.Pod.Namespace, name)
+	s.Sy.LogPath = syncthing.GetLogFile(s.Pod.Namespace, name)
+	defer func() {
+		s.shutdown()
+		if err == nil {
+			if err := os.RemoveAll(s.Sy.Home); err != nil {
+				oktetoLog.Infof("failed to delete the syncthing home directory '%s': %s", s.Sy.Home, err)
+			}
+		}
+	}()
+
+	if err := s.startSyncthing(ctx); err != nil {
+		return err
+	}
+	oktetoLog.Success("Connected to '%s'", s.Pod.Name)
+
+	if err := s.synchronizeFiles(ctx); err != nil {
+		return err
+	}
+	oktetoLog.Success("Files synchronized")
+
+	if !s.Continuous {
+		return nil
+	}
+
+	oktetoLog.Information("Synchronizing your changes to '%s', press Ctrl+C to stop", s.Pod.Name)
+	go s.Sy.Monitor(ctx, s.Disconnect)
+	go s.Sy.MonitorStatus(ctx, s.Disconnect)
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-s.Disconnect:
+		return err
+	}
+}
+
+func (s *syncContext) startSyncthing(ctx context.Context) error {
+	oktetoLog.Spinner("Starting the file synchronization service...")
+	oktetoLog.StartSpinner()
+	defer oktetoLog.StopSpinner()
+
+	if err := s.injectSyncthing(ctx); err != nil {
+		return err
+	}
+
+	s.Forwarder = forwardk8s.NewPortForwardManager(ctx, s.Dev.Interface, s.RestConfig, s.Client, s.Pod.Namespace)
+	if err := s.Forwarder.Add(forward.Forward{Local: s.Sy.RemotePort, Remote: syncthing.ClusterPort}); err != nil {
+		return err
+	}
+	if err := s.Forwarder.Add(forward.Forward{Local: s.Sy.RemoteGUIPort, Remote: syncthing.GUIPort}); err != nil {
+		return err
+	}
+	if err := s.Forwarder.Start(s.Pod.Name, s.Pod.Namespace); err != nil {
+		return err
+	}
+
+	if err := s.Sy.Run(); err != nil {
+		return err
+	}
+	if err := s.Sy.WaitForPing(ctx, true); err != nil {
+		return err
+	}
+	if err := s.Sy.WaitForPing(ctx, false); err != nil {
+		return fmt.Errorf("failed to connect to the synchronization service of '%s': %w", s.Pod.Name, err)
+	}
+
+	oktetoLog.Spinner("Scanning file system...")
+	if err := s.Sy.WaitForScanning(ctx, true); err != nil {
+		return err
+	}
+	if err := s.Sy.WaitForScanning(ctx, false); err != nil {
+		return err
+	}
+	return s.Sy.WaitForConnected(ctx)
+}
+
+// injectSyncthing runs syncthing in an ephemeral container of the pod targeting the synchronized container
+func (s *syncContext) injectSyncthing(ctx context.Context) error {
+	data, err := secrets.GetSyncthingData(getRemoteSyncthing(s.Sy))
+	if err != nil {
+		return err
+	}
+
+	var runAsUser *int64
+	uid, err := pods.GetUserByPod(ctx, s.Pod, s.Container, s.RestConfig, s.Client)
+	if err != nil {
+		oktetoLog.Infof("failed to get the user of container '%s', running syncthing with the default user: %s", s.Container, err)
+	} else {
+		runAsUser = &uid
+	}
+
+	name := fmt.Sprintf("%s%s", syncContainerPrefix, strconv.FormatInt(time.Now().Unix(), 36))
+	container := getSyncContainer(name, s.Dev.InitContainer.Image, s.Container, runAsUser, data)
+	oktetoLog.Infof("adding ephemeral container '%s' to pod '%s'", name, s.Pod.Name)
+	if err := pods.AddEphemeralContainer(ctx, s.Pod, container, s.Client); err != nil {
+		return err
+	}
+	return pods.WaitUntilEphemeralContainerIsRunning(ctx, s.Pod.Name, name, s.Pod.Namespace, s.Dev.Timeout.Resources, s.Client)
+}
+
+// getRemoteSyncthing returns the syncthing configuration of the ephemeral container
+func getRemoteSyncthing(sy *syncthing.Syncthing) *syncthing.Syncthing {
+	remote := *sy
+	remote.Folders = make([]*syncthing.Folder, 0, len(sy.Folders))
+	for _, f := range sy.Folders {
+		folder := *f
+		folder.RemotePath = path.Join(targetRootPath, f.RemotePath)
+		// the container is not in development mode, its files are never deleted
+		folder.IgnoreDelete = true
+		remote.Folders = append(remote.Folders, &folder)
+	}
+	return &remote
+}
+
+func getSyncContainer(name, image, target string, runAsUser *int64, data map[string][]byte) apiv1.EphemeralContainer {
+	script := fmt.Sprintf(
+		"mkdir -p %[1]s && printenv %[2]s > %[1]s/config.xml && printenv %[3]s > %[1]s/cert.pem && printenv %[4]s > %[1]s/key.pem && exec %[5]s -home %[1]s -gui-address 0.0.0.0:%[6]d -no-browser",
+		remoteSyncthingHome, configEnvVar, certEnvVar, keyEnvVar, remoteSyncthingBin, syncthing.GUIPort,
+	)
+	return apiv1.EphemeralContainer{
+		EphemeralContainerCommon: apiv1.EphemeralContainerCommon{
+			Name:    name,
+			Image:   image,
+			Command: []string{"sh", "-c", script},
+			Env: []apiv1.EnvVar{
+				{Name: configEnvVar, Value: string(data["config.xml"])},
+				{Name: certEnvVar, Value: string(data["cert.pem"])},
+				{Name: keyEnvVar, Value: string(data["key.pem"])},
+				{Name: "STNOUPGRADE", Value: "1"},
+			},
+			SecurityContext: &apiv1.SecurityContext{
+				RunAsUser: runAsUser,
+			},
+		},
+		TargetContainerName: target,
+	}
+}
+
+func (s *syncContext) synchronizeFiles(ctx context.Context) error {
+	oktetoLog.Spinner("Synchronizing your files...")
+	oktetoLog.StartSpinner()
+	defer oktetoLog.StopSpinner()
+
+	progressBar := utils.NewSyncthingProgressBar(40)
+	defer progressBar.Finish()
+
+	reporter := make(chan float64)
+	go func() {
+		for c := range reporter {
+			value := int64(c)
+			if value > 0 && value < 100 {
+				if oktetoLog.GetOutputFormat() == oktetoLog.PlainFormat {
+					oktetoLog.Spinner(fmt.Sprintf("Synchronizing your files [%d]...", value))
+				} else {
+					oktetoLog.StopSpinner()
+					progressBar.SetCurrent(value)
+				}
+			}
+		}
+	}()
+
+	return s.Sy.WaitForCompletion(ctx, reporter)
+}
+
+// shutdown stops the local and the remote syncthing and the port forwarding
+func (s *syncContext) shutdown() {
+	// the context of the command might be already cancelled
+	ctx := context.Background()
+	if _, err := s.Sy.APICall(ctx, "rest/system/shutdown", "POST", 200, nil, false, nil, false, 0); err != nil {
+		oktetoLog.Infof("failed to stop the remote syncthing: %s", err)
+	}
+	if err := s.Sy.SoftTerminate(); err != nil {
+		oktetoLog.Infof("failed to stop the local syncthing: %s", err)
+	}
+	if s.Forwarder != nil {
+		s.Forwarder.Stop()
+	}
+}
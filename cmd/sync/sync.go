@@ -0,0 +1,269 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	contextCMD "github.com/okteto/okteto/cmd/context"
+	"github.com/okteto/okteto/cmd/utils"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/k8s/apps"
+	"github.com/okteto/okteto/pkg/k8s/pods"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/okteto"
+	"github.com/okteto/okteto/pkg/syncthing"
+	"github.com/spf13/cobra"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Options represents the options of the sync command
+type Options struct {
+	DevPath    string
+	Namespace  string
+	K8sContext string
+	Pod        string
+	Selector   string
+	Container  string
+	Sync       []string
+	Continuous bool
+}
+
+// Sync synchronizes files with a running container without activating the development mode
+func Sync() *cobra.Command {
+	options := &Options{}
+	cmd := &cobra.Command{
+		Use:   "sync [devName]",
+		Short: "Synchronize your files with a running container",
+		Long: `Synchronize your files with a running container without activating the development mode.
+
+The container is selected by the name of a development container of your okteto manifest, by a label selector or by a pod name.
+By default, the command exits once your files are synchronized. Use '--continuous' to keep synchronizing your changes until you press Ctrl+C.`,
+		Args: utils.MaximumNArgsAccepted(1, "https://okteto.com/docs/reference/cli/#sync"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if okteto.InDevContainer() {
+				return oktetoErrors.ErrNotInDevContainer
+			}
+			if options.Pod != "" && options.Selector != "" {
+				return fmt.Errorf("'--pod' and '--selector' cannot be used at the same time")
+			}
+			if (options.Pod != "" || options.Selector != "") && len(args) > 0 {
+				return fmt.Errorf("a development container name cannot be used together with '--pod' or '--selector'")
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			dev, err := options.getDev(ctx, args)
+			if err != nil {
+				return err
+			}
+
+			if syncthing.ShouldUpgrade() {
+				oktetoLog.Println("Installing dependencies...")
+				if err := syncthing.Install(&utils.ProgressBar{}); err != nil {
+					oktetoLog.Infof("failed to install syncthing: %s", err)
+					if !syncthing.IsInstalled() {
+						return fmt.Errorf("couldn't download syncthing, please try again")
+					}
+				}
+			}
+
+			c, restConfig, err := okteto.GetK8sClient()
+			if err != nil {
+				return err
+			}
+			pod, err := options.getPod(ctx, dev, c)
+			if err != nil {
+				return err
+			}
+
+			s := &syncContext{
+				Dev:        dev,
+				Pod:        pod,
+				Container:  options.getContainer(dev, pod),
+				Continuous: options.Continuous,
+				Client:     c,
+				RestConfig: restConfig,
+				Disconnect: make(chan error, 1),
+			}
+
+			stop := make(chan os.Signal, 1)
+			signal.Notify(stop, os.Interrupt)
+			go func() {
+				<-stop
+				oktetoLog.Infof("CTRL+C received, stopping the synchronization")
+				cancel()
+			}()
+
+			return s.run(ctx)
+		},
+	}
+	cmd.Flags().StringVarP(&options.DevPath, "file", "f", utils.DefaultManifest, "path to the manifest file")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "namespace where the container is running")
+	cmd.Flags().StringVarP(&options.K8sContext, "context", "c", "", "context where the container is running")
+	cmd.Flags().StringVarP(&options.Pod, "pod", "p", "", "name of the pod to synchronize your files with")
+	cmd.Flags().StringVar(&options.Selector, "selector", "", "label selector of the pod to synchronize your files with (e.g. 'app=api')")
+	cmd.Flags().StringVar(&options.Container, "container", "", "name of the container to synchronize your files with")
+	cmd.Flags().StringArrayVarP(&options.Sync, "sync", "s", []string{}, "folder to synchronize with the syntax 'localPath:remotePath'. Overrides the sync folders of the development container")
+	cmd.Flags().BoolVar(&options.Continuous, "continuous", false, "keep synchronizing your changes until the command is stopped")
+	cmd.AddCommand(Conflicts())
+	return cmd
+}
+
+// getDev returns the development container with the sync configuration of the command
+func (o *Options) getDev(ctx context.Context, args []string) (*model.Dev, error) {
+	folders, err := parseSyncFolders(o.Sync)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Pod == "" && o.Selector == "" {
+		manifestOpts := contextCMD.ManifestOptions{Filename: o.DevPath, Namespace: o.Namespace, K8sContext: o.K8sContext}
+		dev, err := getDev(ctx, manifestOpts, args)
+		if err != nil {
+			return nil, err
+		}
+		if len(folders) > 0 {
+			dev.Sync.Folders = folders
+		}
+		return dev, nil
+	}
+
+	ctxOptions := &contextCMD.ContextOptions{
+		Context:   o.K8sContext,
+		Namespace: o.Namespace,
+	}
+	if err := contextCMD.NewContextCommand().Run(ctx, ctxOptions); err != nil {
+		return nil, err
+	}
+	if len(folders) == 0 {
+		return nil, fmt.Errorf("'--sync' is required when using '--pod' or '--selector'")
+	}
+
+	dev := model.NewDev()
+	dev.Name = o.Pod
+	if dev.Name == "" {
+		dev.Name = "sync"
+	}
+	dev.Namespace = okteto.Context().Namespace
+	dev.Sync.Folders = folders
+	if err := dev.SetDefaults(); err != nil {
+		return nil, err
+	}
+	return dev, nil
+}
+
+// getPod returns the running pod to synchronize the files with
+func (o *Options) getPod(ctx context.Context, dev *model.Dev, c kubernetes.Interface) (*apiv1.Pod, error) {
+	switch {
+	case o.Pod != "":
+		pod, err := c.CoreV1().Pods(dev.Namespace).Get(ctx, o.Pod, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod '%s': %w", o.Pod, err)
+		}
+		if pod.Status.Phase != apiv1.PodRunning {
+			return nil, fmt.Errorf("pod '%s' is not running", o.Pod)
+		}
+		return pod, nil
+	case o.Selector != "":
+		selector, err := labels.ConvertSelectorToLabelsMap(o.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector '%s': %w", o.Selector, err)
+		}
+		podList, err := pods.ListBySelector(ctx, dev.Namespace, selector, c)
+		if err != nil {
+			return nil, err
+		}
+		for i := range podList {
+			if podList[i].Status.Phase == apiv1.PodRunning && podList[i].DeletionTimestamp == nil {
+				return &podList[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no running pod found for selector '%s'", o.Selector)
+	default:
+		app, err := apps.Get(ctx, dev, dev.Namespace, c)
+		if err != nil {
+			return nil, err
+		}
+		if apps.IsDevModeOn(app) {
+			return nil, fmt.Errorf("'%s' is in development mode, its files are already synchronized by 'okteto up'", dev.Name)
+		}
+		return app.GetRunningPod(ctx, c)
+	}
+}
+
+// getContainer returns the name of the container to synchronize the files with
+func (o *Options) getContainer(dev *model.Dev, pod *apiv1.Pod) string {
+	if o.Container != "" {
+		return o.Container
+	}
+	if dev.Container != "" {
+		return dev.Container
+	}
+	return pod.Spec.Containers[0].Name
+}
+
+// parseSyncFolders parses the values of the sync flag with the syntax 'localPath:remotePath'
+func parseSyncFolders(values []string) ([]model.SyncFolder, error) {
+	result := []model.SyncFolder{}
+	for _, value := range values {
+		index := strings.LastIndex(value, ":")
+		if index <= 0 || index == len(value)-1 {
+			return nil, fmt.Errorf("invalid sync folder '%s': the syntax is 'localPath:remotePath'", value)
+		}
+		remotePath := value[index+1:]
+		if !strings.HasPrefix(remotePath, "/") {
+			return nil, fmt.Errorf("invalid sync folder '%s': the remote path must be absolute", value)
+		}
+		localPath, err := filepath.Abs(value[:index])
+		if err != nil {
+			return nil, fmt.Errorf("invalid sync folder '%s': %w", value, err)
+		}
+		result = append(result, model.SyncFolder{LocalPath: localPath, RemotePath: remotePath})
+	}
+	return result, nil
+}
+
+// getDev returns the development container selected by the args of a command
+func getDev(ctx context.Context, manifestOpts contextCMD.ManifestOptions, args []string) (*model.Dev, error) {
+	manifest, err := contextCMD.LoadManifestWithContext(ctx, manifestOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	devName := ""
+	if len(args) == 1 {
+		devName = args[0]
+	}
+	dev, err := utils.GetDevFromManifest(manifest, devName)
+	if err != nil {
+		if !errors.Is(err, utils.ErrNoDevSelected) {
+			return nil, err
+		}
+		selector := utils.NewOktetoSelector("Select which development container to synchronize:", "Development container")
+		return utils.SelectDevFromManifest(manifest, selector, manifest.Dev.GetDevs())
+	}
+	return dev, nil
+}
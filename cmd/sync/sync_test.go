@@ -0,0 +1,71 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sync
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/syncthing"
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func Test_parseSyncFolders(t *testing.T) {
+	src, err := filepath.Abs("src")
+	assert.NoError(t, err)
+
+	result, err := parseSyncFolders([]string{"src:/app", "src:/var/www"})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.SyncFolder{{LocalPath: src, RemotePath: "/app"}, {LocalPath: src, RemotePath: "/var/www"}}, result)
+
+	for _, value := range []string{"src", ":/app", "src:", "src:app"} {
+		_, err := parseSyncFolders([]string{value})
+		assert.Error(t, err, value)
+	}
+}
+
+func Test_getContainer(t *testing.T) {
+	pod := &apiv1.Pod{Spec: apiv1.PodSpec{Containers: []apiv1.Container{{Name: "api"}, {Name: "sidecar"}}}}
+
+	assert.Equal(t, "api", (&Options{}).getContainer(&model.Dev{}, pod))
+	assert.Equal(t, "sidecar", (&Options{}).getContainer(&model.Dev{Container: "sidecar"}, pod))
+	assert.Equal(t, "other", (&Options{Container: "other"}).getContainer(&model.Dev{Container: "sidecar"}, pod))
+}
+
+func Test_getRemoteSyncthing(t *testing.T) {
+	sy := &syncthing.Syncthing{
+		Folders: []*syncthing.Folder{{Name: "1", LocalPath: "/src", RemotePath: "/app"}},
+	}
+
+	remote := getRemoteSyncthing(sy)
+	assert.Equal(t, "/proc/1/root/app", remote.Folders[0].RemotePath)
+	assert.True(t, remote.Folders[0].IgnoreDelete)
+	assert.Equal(t, "/app", sy.Folders[0].RemotePath)
+	assert.False(t, sy.Folders[0].IgnoreDelete)
+}
+
+func Test_getSyncContainer(t *testing.T) {
+	uid := int64(1000)
+	data := map[string][]byte{"config.xml": []byte("<configuration/>"), "cert.pem": []byte("cert"), "key.pem": []byte("key")}
+
+	container := getSyncContainer("okteto-sync-1", model.OktetoBinImageTag, "api", &uid, data)
+	assert.Equal(t, "okteto-sync-1", container.Name)
+	assert.Equal(t, model.OktetoBinImageTag, container.Image)
+	assert.Equal(t, "api", container.TargetContainerName)
+	assert.Equal(t, &uid, container.SecurityContext.RunAsUser)
+	assert.Contains(t, container.Env, apiv1.EnvVar{Name: configEnvVar, Value: "<configuration/>"})
+	assert.Contains(t, container.Command[2], "-gui-address 0.0.0.0:8384")
+}
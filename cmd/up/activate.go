@@ -15,6 +15,7 @@ package up
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -51,6 +52,7 @@ func (up *upContext) activate() error {
 	up.Cancel = cancel
 	up.ShutdownCompleted = make(chan bool, 1)
 	up.Sy = nil
+	up.SyncEngine = nil
 	up.Forwarder = nil
 	defer up.shutdown()
 
@@ -213,7 +215,11 @@ func (up *upContext) shouldRetry(ctx context.Context, err error) bool {
 	case oktetoErrors.ErrLostSyncthing:
 		return true
 	case oktetoErrors.ErrCommandFailed:
-		return !up.Sy.Ping(ctx, false)
+		if up.SyncEngine == nil {
+			return false
+		}
+		_, err := up.SyncEngine.Status(ctx)
+		return errors.Is(err, oktetoErrors.ErrLostSyncthing)
 	case oktetoErrors.ErrApplyToApp:
 		return true
 	}
@@ -0,0 +1,153 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package up
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/okteto/okteto/pkg/cmd/status"
+	"github.com/okteto/okteto/pkg/config"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/okteto/okteto/pkg/ssh"
+	"github.com/okteto/okteto/pkg/syncthing"
+)
+
+// syncEngine synchronizes the sync folders with the development container
+type syncEngine interface {
+	// Start starts the synchronization service
+	Start(ctx context.Context) error
+	// WaitForCompletion waits until the initial synchronization is completed, reporting its progress
+	WaitForCompletion(ctx context.Context, reporter chan float64) error
+	// Status returns the progress of the synchronization
+	Status(ctx context.Context) (float64, error)
+	// Conflicts returns the files modified in both sides
+	Conflicts() ([]syncthing.Conflict, error)
+	// Watch keeps the files synchronized until ctx is done. Errors are notified on the disconnect channel
+	Watch(ctx context.Context, disconnect chan error) error
+	// Stop stops the synchronization service
+	Stop() error
+}
+
+// validateSyncEngine checks that the sync engine of a development container can be used
+func validateSyncEngine(dev *model.Dev) error {
+	if dev.Sync.GetEngine() == model.SyncEngineSSH && !dev.RemoteModeEnabled() {
+		return oktetoErrors.UserError{
+			E:    fmt.Errorf("the 'ssh' sync engine requires the SSH server of your development container"),
+			Hint: fmt.Sprintf("Unset the '%s' environment variable or use the 'syncthing' sync engine", model.OktetoExecuteSSHEnvVar),
+		}
+	}
+	return nil
+}
+
+func (up *upContext) newSyncEngine() (syncEngine, error) {
+	if up.Dev.Sync.GetEngine() == model.SyncEngineSSH {
+		syncer, err := ssh.NewSyncer(up.Dev)
+		if err != nil {
+			return nil, err
+		}
+		return &sshEngine{dev: up.Dev, syncer: syncer}, nil
+	}
+	return &syncthingEngine{up: up}, nil
+}
+
+// syncthingEngine synchronizes the files with a local syncthing connected to the syncthing of the development container
+type syncthingEngine struct {
+	up *upContext
+}
+
+func (e *syncthingEngine) Start(ctx context.Context) error {
+	return e.up.startSyncthing(ctx)
+}
+
+func (e *syncthingEngine) WaitForCompletion(ctx context.Context, reporter chan float64) error {
+	return e.up.Sy.WaitForCompletion(ctx, reporter)
+}
+
+func (e *syncthingEngine) Status(ctx context.Context) (float64, error) {
+	if !e.up.Sy.Ping(ctx, false) {
+		return 0, oktetoErrors.ErrLostSyncthing
+	}
+	return status.Run(ctx, e.up.Sy)
+}
+
+func (e *syncthingEngine) Conflicts() ([]syncthing.Conflict, error) {
+	return e.up.Sy.GetConflicts()
+}
+
+// Watch switches the folders to sendreceive once the initial synchronization is completed
+func (e *syncthingEngine) Watch(ctx context.Context, disconnect chan error) error {
+	e.up.Sy.Type = "sendreceive"
+	e.up.Sy.IgnoreDelete = false
+	if err := e.up.Sy.UpdateConfig(); err != nil {
+		return err
+	}
+
+	go e.up.Sy.Monitor(ctx, disconnect)
+	go e.up.Sy.MonitorStatus(ctx, disconnect)
+	go e.up.notifyConflicts(ctx)
+	oktetoLog.Infof("restarting syncthing to update sync mode to sendreceive")
+	return e.up.Sy.Restart(ctx)
+}
+
+func (e *syncthingEngine) Stop() error {
+	return e.up.Sy.SoftTerminate()
+}
+
+// GetInSynchronizationFile returns the file being synchronized
+func (e *syncthingEngine) GetInSynchronizationFile(ctx context.Context) string {
+	return e.up.Sy.GetInSynchronizationFile(ctx)
+}
+
+// sshEngine pushes the local changes through the SSH server of the development container,
+// for environments where running syncthing or opening its ports is not allowed
+type sshEngine struct {
+	dev    *model.Dev
+	syncer *ssh.Syncer
+}
+
+func (e *sshEngine) Start(ctx context.Context) error {
+	oktetoLog.Spinner("Connecting to your development container...")
+	oktetoLog.StartSpinner()
+	defer oktetoLog.StopSpinner()
+
+	if err := config.UpdateStateFile(e.dev, config.StartingSync); err != nil {
+		return err
+	}
+	return e.syncer.Start(ctx)
+}
+
+func (e *sshEngine) WaitForCompletion(ctx context.Context, reporter chan float64) error {
+	return e.syncer.WaitForCompletion(ctx, reporter)
+}
+
+func (e *sshEngine) Status(_ context.Context) (float64, error) {
+	return e.syncer.Status()
+}
+
+// Conflicts returns no conflicts, the ssh engine never downloads changes from the development container
+func (*sshEngine) Conflicts() ([]syncthing.Conflict, error) {
+	return []syncthing.Conflict{}, nil
+}
+
+func (e *sshEngine) Watch(ctx context.Context, disconnect chan error) error {
+	go e.syncer.Watch(ctx, disconnect)
+	return nil
+}
+
+func (e *sshEngine) Stop() error {
+	return e.syncer.Stop()
+}
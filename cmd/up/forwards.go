@@ -22,6 +22,7 @@ import (
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	forwardk8s "github.com/okteto/okteto/pkg/k8s/forward"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/model/forward"
 	"github.com/okteto/okteto/pkg/ssh"
 	"github.com/okteto/okteto/pkg/syncthing"
@@ -53,11 +54,7 @@ func (up *upContext) forwards(ctx context.Context) error {
 		}
 	}
 
-	if err := up.Forwarder.Add(forward.Forward{Local: up.Sy.RemotePort, Remote: syncthing.ClusterPort}); err != nil {
-		return err
-	}
-
-	if err := up.Forwarder.Add(forward.Forward{Local: up.Sy.RemoteGUIPort, Remote: syncthing.GUIPort}); err != nil {
+	if err := up.addSyncthingForwards(); err != nil {
 		return err
 	}
 
@@ -83,11 +80,7 @@ func (up *upContext) sshForwards(ctx context.Context) error {
 
 	up.Forwarder = ssh.NewForwardManager(ctx, fmt.Sprintf(":%d", up.Dev.RemotePort), up.Dev.Interface, "0.0.0.0", f, up.Dev.Namespace)
 
-	if err := up.Forwarder.Add(forward.Forward{Local: up.Sy.RemotePort, Remote: syncthing.ClusterPort}); err != nil {
-		return err
-	}
-
-	if err := up.Forwarder.Add(forward.Forward{Local: up.Sy.RemoteGUIPort, Remote: syncthing.GUIPort}); err != nil {
+	if err := up.addSyncthingForwards(); err != nil {
 		return err
 	}
 
@@ -129,6 +122,19 @@ func (up *upContext) sshForwards(ctx context.Context) error {
 	return nil
 }
 
+// addSyncthingForwards forwards the syncthing ports of the development container, unless another sync engine is used
+func (up *upContext) addSyncthingForwards() error {
+	if up.Dev.Sync.GetEngine() != model.SyncEngineSyncthing {
+		return nil
+	}
+
+	if err := up.Forwarder.Add(forward.Forward{Local: up.Sy.RemotePort, Remote: syncthing.ClusterPort}); err != nil {
+		return err
+	}
+
+	return up.Forwarder.Add(forward.Forward{Local: up.Sy.RemoteGUIPort, Remote: syncthing.GUIPort})
+}
+
 func (up *upContext) setGlobalForwardsIfRequiredLoop(ctx context.Context) {
 	ticker := time.NewTicker(time.Second)
 
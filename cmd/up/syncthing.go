@@ -16,6 +16,7 @@ package up
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/okteto/okteto/cmd/utils"
@@ -47,7 +48,13 @@ func (up *upContext) initializeSyncthing() error {
 }
 
 func (up *upContext) sync(ctx context.Context) error {
-	if err := up.startSyncthing(ctx); err != nil {
+	engine, err := up.newSyncEngine()
+	if err != nil {
+		return err
+	}
+	up.SyncEngine = engine
+
+	if err := up.SyncEngine.Start(ctx); err != nil {
 		return err
 	}
 
@@ -74,16 +81,22 @@ func (up *upContext) sync(ctx context.Context) error {
     More information is available here: https://okteto.com/docs/reference/file-synchronization/`, minutes, seconds)
 	}
 
-	up.Sy.Type = "sendreceive"
-	up.Sy.IgnoreDelete = false
-	if err := up.Sy.UpdateConfig(); err != nil {
-		return err
-	}
+	return up.SyncEngine.Watch(ctx, up.Disconnect)
+}
 
-	go up.Sy.Monitor(ctx, up.Disconnect)
-	go up.Sy.MonitorStatus(ctx, up.Disconnect)
-	oktetoLog.Infof("restarting syncthing to update sync mode to sendreceive")
-	return up.Sy.Restart(ctx)
+func (up *upContext) notifyConflicts(ctx context.Context) {
+	conflicts := make(chan syncthing.Conflict)
+	go up.Sy.MonitorConflicts(ctx, conflicts)
+	for {
+		select {
+		case conflict := <-conflicts:
+			oktetoLog.Warning(`Synchronization conflict detected in '%s'
+    The other version of the file was saved in '%s'
+    Run 'okteto sync conflicts --resolve' to resolve it`, filepath.Join(conflict.Folder.LocalPath, conflict.Path), conflict.ConflictPath)
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
 func (up *upContext) startSyncthing(ctx context.Context) error {
@@ -139,7 +152,7 @@ func (up *upContext) synchronizeFiles(ctx context.Context) error {
 			case <-quit:
 				return
 			case <-time.NewTicker(1 * time.Second).C:
-				inSynchronizationFile := up.Sy.GetInSynchronizationFile(ctx)
+				inSynchronizationFile := getInSynchronizationFile(ctx, up.SyncEngine)
 				if inSynchronizationFile != "" && oktetoLog.GetOutputFormat() != oktetoLog.PlainFormat {
 					oktetoLog.StopSpinner()
 					progressBar.UpdateItemInSync(inSynchronizationFile)
@@ -164,7 +177,7 @@ func (up *upContext) synchronizeFiles(ctx context.Context) error {
 		quit <- true
 	}()
 
-	if err := up.Sy.WaitForCompletion(ctx, reporter); err != nil {
+	if err := up.SyncEngine.WaitForCompletion(ctx, reporter); err != nil {
 		analytics.TrackSyncError()
 		switch err {
 		case oktetoErrors.ErrLostSyncthing:
@@ -190,3 +203,14 @@ func (up *upContext) synchronizeFiles(ctx context.Context) error {
 
 	return nil
 }
+
+// getInSynchronizationFile returns the file being synchronized, if the sync engine reports it
+func getInSynchronizationFile(ctx context.Context, engine syncEngine) string {
+	reporter, ok := engine.(interface {
+		GetInSynchronizationFile(ctx context.Context) string
+	})
+	if !ok {
+		return ""
+	}
+	return reporter.GetInSynchronizationFile(ctx)
+}
@@ -44,6 +44,7 @@ type upContext struct {
 	CommandResult         chan error
 	Exit                  chan error
 	Sy                    *syncthing.Syncthing
+	SyncEngine            syncEngine
 	cleaned               chan string
 	hardTerminate         chan error
 	success               bool
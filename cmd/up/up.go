@@ -20,6 +20,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -285,11 +286,24 @@ func Up() *cobra.Command {
 				return err
 			}
 
+			if up.Manifest.IsV2 {
+				outputs, err := pipeline.GetDeployOutputs(ctx, up.Manifest.Name, up.Manifest.Namespace, up.Client)
+				if err != nil {
+					oktetoLog.Infof("could not get the outputs of '%s': %s", up.Manifest.Name, err)
+				} else {
+					addDeployOutputs(dev, outputs)
+				}
+			}
+
 			if err := loadManifestOverrides(dev, upOptions); err != nil {
 				return err
 			}
 
-			if syncthing.ShouldUpgrade() {
+			if err := validateSyncEngine(dev); err != nil {
+				return err
+			}
+
+			if dev.Sync.GetEngine() == model.SyncEngineSyncthing && syncthing.ShouldUpgrade() {
 				oktetoLog.Println("Installing dependencies...")
 				if err := downloadSyncthing(); err != nil {
 					oktetoLog.Infof("failed to upgrade syncthing: %s", err)
@@ -418,6 +432,26 @@ func LoadManifestWithInit(ctx context.Context, k8sContext, namespace, devPath st
 	return manifest, nil
 }
 
+// addDeployOutputs adds the outputs published by the deploy commands to the environment of the development container.
+// Variables defined in the manifest take precedence
+func addDeployOutputs(dev *model.Dev, outputs map[string]string) {
+	defined := map[string]bool{}
+	for _, envVar := range dev.Environment {
+		defined[envVar.Name] = true
+	}
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if defined[name] {
+			continue
+		}
+		dev.Environment = append(dev.Environment, model.EnvVar{Name: name, Value: outputs[name]})
+	}
+}
+
 func loadManifestOverrides(dev *model.Dev, upOptions *UpOptions) error {
 	if upOptions.Remote > 0 {
 		dev.RemotePort = upOptions.Remote
@@ -787,7 +821,12 @@ func (up *upContext) shutdown() {
 		oktetoLog.Info("sent cancellation signal")
 	}
 
-	if up.Sy != nil {
+	if up.SyncEngine != nil {
+		oktetoLog.Infof("stopping the file synchronization")
+		if err := up.SyncEngine.Stop(); err != nil {
+			oktetoLog.Infof("failed to stop the file synchronization during shutdown: %s", err.Error())
+		}
+	} else if up.Sy != nil {
 		oktetoLog.Infof("stopping syncthing")
 		if err := up.Sy.SoftTerminate(); err != nil {
 			oktetoLog.Infof("failed to stop syncthing during shutdown: %s", err.Error())
@@ -307,3 +307,28 @@ func TestEnvVarIsNotAddedWhenHasBuiltInOktetoEnvVarsFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestAddDeployOutputs(t *testing.T) {
+	dev := &model.Dev{
+		Environment: model.Environment{
+			{Name: "DATABASE_URL", Value: "from-manifest"},
+		},
+	}
+	addDeployOutputs(dev, map[string]string{
+		"DATABASE_URL": "from-outputs",
+		"API_TOKEN":    "token",
+	})
+
+	expected := model.Environment{
+		{Name: "DATABASE_URL", Value: "from-manifest"},
+		{Name: "API_TOKEN", Value: "token"},
+	}
+	if len(dev.Environment) != len(expected) {
+		t.Fatalf("expected %d variables but got %d", len(expected), len(dev.Environment))
+	}
+	for i := range expected {
+		if dev.Environment[i] != expected[i] {
+			t.Fatalf("expected variable %v but got %v", expected[i], dev.Environment[i])
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package displayer
+
+import (
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+)
+
+// CommandStatus represents how a command finished
+type CommandStatus string
+
+const (
+	// CommandSucceeded means the command succeeded, maybe after some retries
+	CommandSucceeded CommandStatus = "success"
+	// CommandFailed means all the attempts of the command failed
+	CommandFailed CommandStatus = "failure"
+	// CommandSkipped means the command was not executed because its conditions were not met
+	CommandSkipped CommandStatus = "skipped"
+)
+
+// CommandSummary represents the result of a command after all its attempts
+type CommandSummary struct {
+	Name     string
+	Status   CommandStatus
+	Attempts int
+	Duration time.Duration
+	Error    string
+}
+
+// commandsSummaryEvent is the name of the json event with the summary of the commands
+const commandsSummaryEvent = "commands-summary"
+
+type jsonCommandSummary struct {
+	Name     string        `json:"name"`
+	Status   CommandStatus `json:"status"`
+	Attempts int           `json:"attempts"`
+	Duration string        `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// DisplaySummary displays the result of a list of commands with the displayer of the output format
+func DisplaySummary(output string, summaries []CommandSummary) {
+	switch output {
+	case oktetoLog.JSONFormat:
+		displayJSONSummary(summaries)
+	case oktetoLog.PlainFormat:
+		displayPlainSummary(summaries)
+	default:
+		(&TTYCollapseDisplayer{}).DisplaySummary(summaries)
+	}
+}
+
+// DisplaySummary displays a line per command with its status, attempts and duration
+func (*TTYCollapseDisplayer) DisplaySummary(summaries []CommandSummary) {
+	oktetoLog.Println("")
+	for _, summary := range summaries {
+		message := getSummaryMessage(summary)
+		var line []byte
+		switch summary.Status {
+		case CommandSucceeded:
+			line = renderSuccessCommand(message)
+		case CommandFailed:
+			line = renderFailCommand(message, fmt.Errorf("%s", summary.Error))
+		default:
+			line = renderSkippedCommand(message)
+		}
+		oktetoLog.Println(string(line))
+	}
+}
+
+func displayJSONSummary(summaries []CommandSummary) {
+	events := make([]jsonCommandSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		events = append(events, jsonCommandSummary{
+			Name:     summary.Name,
+			Status:   summary.Status,
+			Attempts: summary.Attempts,
+			Duration: summary.Duration.Round(time.Millisecond).String(),
+			Error:    summary.Error,
+		})
+	}
+	oktetoLog.AddEvent(commandsSummaryEvent, events)
+}
+
+func displayPlainSummary(summaries []CommandSummary) {
+	for _, summary := range summaries {
+		message := getSummaryMessage(summary)
+		if summary.Status == CommandFailed {
+			message = fmt.Sprintf("%s: %s", message, summary.Error)
+		}
+		oktetoLog.Println(message)
+	}
+}
+
+func getSummaryMessage(summary CommandSummary) string {
+	if summary.Status == CommandSkipped {
+		return fmt.Sprintf("%s (skipped)", summary.Name)
+	}
+	attempts := "1 attempt"
+	if summary.Attempts != 1 {
+		attempts = fmt.Sprintf("%d attempts", summary.Attempts)
+	}
+	return fmt.Sprintf("%s (%s, %s)", summary.Name, attempts, summary.Duration.Round(time.Millisecond))
+}
+
+func renderSkippedCommand(command string) []byte {
+	commandTemplate := `{{ " - " | bgYellow | black }} {{ . | yellow }}`
+	tpl, err := template.New("").Funcs(promptui.FuncMap).Parse(commandTemplate)
+	if err != nil {
+		return []byte{}
+	}
+
+	return render(tpl, command)
+}
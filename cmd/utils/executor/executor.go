@@ -14,8 +14,11 @@
 package executor
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"sync/atomic"
+	"time"
 
 	oktetoLog "github.com/okteto/okteto/pkg/log"
 	"github.com/okteto/okteto/pkg/model"
@@ -60,7 +63,8 @@ func NewExecutor(output string, runWithoutBash bool) *Executor {
 	}
 }
 
-// Execute executes the specified command adding `env` to the execution environment
+// Execute executes the specified command adding `env` to the execution environment.
+// The command and its children are killed if it takes longer than its timeout
 func (e *Executor) Execute(cmdInfo model.DeployCommand, env []string) error {
 
 	cmd := exec.Command("bash", "-c", cmdInfo.Command)
@@ -68,13 +72,30 @@ func (e *Executor) Execute(cmdInfo model.DeployCommand, env []string) error {
 		cmd = exec.Command(cmdInfo.Command)
 	}
 	cmd.Env = append(os.Environ(), env...)
+	if cmdInfo.Timeout > 0 {
+		setProcessGroup(cmd)
+	}
 	if err := e.displayer.startCommand(cmd); err != nil {
 		return err
 	}
 
+	var timedOut int32
+	if cmdInfo.Timeout > 0 {
+		timer := time.AfterFunc(cmdInfo.Timeout, func() {
+			atomic.StoreInt32(&timedOut, 1)
+			if err := killProcessGroup(cmd); err != nil {
+				oktetoLog.Infof("could not kill command '%s': %s", cmdInfo.Name, err)
+			}
+		})
+		defer timer.Stop()
+	}
+
 	e.displayer.display(cmdInfo.Name)
 
 	err := cmd.Wait()
+	if err != nil && atomic.LoadInt32(&timedOut) == 1 {
+		err = fmt.Errorf("command timed out after %s", cmdInfo.Timeout)
+	}
 
 	e.CleanUp(err)
 	return err
@@ -29,10 +29,12 @@ import (
 	contextCMD "github.com/okteto/okteto/cmd/context"
 	"github.com/okteto/okteto/cmd/deploy"
 	"github.com/okteto/okteto/cmd/destroy"
+	"github.com/okteto/okteto/cmd/manifest"
 	"github.com/okteto/okteto/cmd/namespace"
 	"github.com/okteto/okteto/cmd/pipeline"
 	"github.com/okteto/okteto/cmd/preview"
 	"github.com/okteto/okteto/cmd/stack"
+	syncCMD "github.com/okteto/okteto/cmd/sync"
 	"github.com/okteto/okteto/cmd/up"
 	"github.com/okteto/okteto/pkg/analytics"
 	"github.com/okteto/okteto/pkg/config"
@@ -123,6 +125,7 @@ func main() {
 	root.AddCommand(up.Up())
 	root.AddCommand(cmd.Down())
 	root.AddCommand(cmd.Status())
+	root.AddCommand(syncCMD.Sync())
 	root.AddCommand(cmd.Doctor())
 	root.AddCommand(cmd.Exec())
 	root.AddCommand(preview.Preview(ctx))
@@ -138,6 +141,7 @@ func main() {
 	root.AddCommand(cmd.List(ctx))
 	root.AddCommand(cmd.Delete(ctx))
 	root.AddCommand(stack.Stack(ctx))
+	root.AddCommand(manifest.Manifest())
 	root.AddCommand(cmd.Push(ctx))
 	root.AddCommand(pipeline.Pipeline(ctx))
 
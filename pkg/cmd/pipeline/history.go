@@ -0,0 +1,111 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+const (
+	historyField = "history"
+
+	// maxHistoryEntries is the number of deploys kept in the history
+	maxHistoryEntries = 10
+
+	// maxHistorySize is the size in bytes the history can take in the pipeline configmap.
+	// Each entry contains the manifest, and configmaps can't be bigger than 1MiB
+	maxHistorySize = 256 * 1024
+)
+
+// HistoryEntry represents a deploy recorded in the pipeline configmap
+type HistoryEntry struct {
+	Version   int       `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+	Status    string    `json:"status"`
+	Commit    string    `json:"commit,omitempty"`
+	Filename  string    `json:"filename,omitempty"`
+	Manifest  string    `json:"manifest,omitempty"`
+	// Images are the image references with digest deployed for each service of the 'build' section
+	Images map[string]string `json:"images,omitempty"`
+	// VariablesHash identifies the variables used by the deploy without storing their values
+	VariablesHash string `json:"variablesHash,omitempty"`
+	// RollbackOf is the version redeployed by a rollback
+	RollbackOf int `json:"rollbackOf,omitempty"`
+}
+
+// GetHistory returns the deploys recorded in the pipeline configmap, from the oldest to the newest
+func GetHistory(cmap *apiv1.ConfigMap) ([]HistoryEntry, error) {
+	history := []HistoryEntry{}
+	if cmap == nil || cmap.Data[historyField] == "" {
+		return history, nil
+	}
+	if err := json.Unmarshal([]byte(cmap.Data[historyField]), &history); err != nil {
+		return nil, fmt.Errorf("error decoding the deploy history of '%s': %w", cmap.Name, err)
+	}
+	return history, nil
+}
+
+// addHistoryEntry records a new deploy in the history, dropping the oldest entries if it exceeds the number of entries or the size limit
+func addHistoryEntry(cmap *apiv1.ConfigMap, entry HistoryEntry) {
+	history, err := GetHistory(cmap)
+	if err != nil {
+		oktetoLog.Infof("discarding deploy history: %s", err)
+		history = []HistoryEntry{}
+	}
+	entry.Version = 1
+	if len(history) > 0 {
+		entry.Version = history[len(history)-1].Version + 1
+	}
+	history = append(history, entry)
+	if len(history) > maxHistoryEntries {
+		history = history[len(history)-maxHistoryEntries:]
+	}
+
+	for {
+		encoded, err := json.Marshal(history)
+		if err != nil {
+			oktetoLog.Infof("could not encode the deploy history of '%s': %s", cmap.Name, err)
+			return
+		}
+		if len(encoded) <= maxHistorySize {
+			cmap.Data[historyField] = string(encoded)
+			return
+		}
+		if len(history) > 1 {
+			history = history[1:]
+			continue
+		}
+		// the manifest of the last deploy doesn't fit: the deploy is recorded but it can't be rolled back to
+		oktetoLog.Infof("the manifest of version %d of '%s' is too big to be recorded in the deploy history", entry.Version, cmap.Name)
+		history[0].Manifest = ""
+	}
+}
+
+// GetVariablesHash returns a hash of the variables that doesn't depend on their order
+func GetVariablesHash(variables []string) string {
+	if len(variables) == 0 {
+		return ""
+	}
+	sorted := append([]string{}, variables...)
+	sort.Strings(sorted)
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(sorted, "\n"))))
+}
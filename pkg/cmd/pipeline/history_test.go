@@ -0,0 +1,111 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHistoryTracking(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewSimpleClientset()
+
+	data := &CfgData{
+		Name:      "app",
+		Namespace: "test",
+		Status:    ProgressingStatus,
+		Manifest:  []byte("deploy:\n- okteto build\n"),
+	}
+	cfg, err := TranslateConfigMapAndDeploy(ctx, data, fakeClient)
+	assert.NoError(t, err)
+
+	data.Status = DeployedStatus
+	data.HistoryEntry = &HistoryEntry{
+		Commit:   "1234",
+		Manifest: string(data.Manifest),
+		Images:   map[string]string{"api": "okteto.dev/api@sha256:abc"},
+	}
+	assert.NoError(t, UpdateConfigMap(ctx, cfg, data, fakeClient))
+
+	data.Status = ErrorStatus
+	data.HistoryEntry = &HistoryEntry{Commit: "5678"}
+	assert.NoError(t, UpdateConfigMap(ctx, cfg, data, fakeClient))
+
+	cfg, err = fakeClient.CoreV1().ConfigMaps("test").Get(ctx, cfg.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	history, err := GetHistory(cfg)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, 1, history[0].Version)
+	assert.Equal(t, DeployedStatus, history[0].Status)
+	assert.Equal(t, "okteto.dev/api@sha256:abc", history[0].Images["api"])
+	assert.Equal(t, "deploy:\n- okteto build\n", history[0].Manifest)
+	assert.Equal(t, 2, history[1].Version)
+	assert.Equal(t, ErrorStatus, history[1].Status)
+}
+
+func TestAddHistoryEntryLimit(t *testing.T) {
+	cmap := &apiv1.ConfigMap{Data: map[string]string{}}
+	for i := 0; i < maxHistoryEntries+3; i++ {
+		addHistoryEntry(cmap, HistoryEntry{Status: DeployedStatus})
+	}
+	history, err := GetHistory(cmap)
+	assert.NoError(t, err)
+	assert.Len(t, history, maxHistoryEntries)
+	assert.Equal(t, 4, history[0].Version)
+	assert.Equal(t, maxHistoryEntries+3, history[len(history)-1].Version)
+
+	cmap.Data[historyField] = "wrong"
+	_, err = GetHistory(cmap)
+	assert.Error(t, err)
+
+	history, err = GetHistory(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestAddHistoryEntrySizeLimit(t *testing.T) {
+	cmap := &apiv1.ConfigMap{Data: map[string]string{}}
+	manifest := strings.Repeat("a", maxHistorySize/4)
+	for i := 0; i < 5; i++ {
+		addHistoryEntry(cmap, HistoryEntry{Status: DeployedStatus, Manifest: manifest})
+	}
+	assert.LessOrEqual(t, len(cmap.Data[historyField]), maxHistorySize)
+	history, err := GetHistory(cmap)
+	assert.NoError(t, err)
+	assert.Len(t, history, 3)
+	assert.Equal(t, 5, history[len(history)-1].Version)
+	assert.Equal(t, manifest, history[len(history)-1].Manifest)
+
+	// a manifest bigger than the limit is not recorded, but the deploy is
+	addHistoryEntry(cmap, HistoryEntry{Status: DeployedStatus, Manifest: strings.Repeat("a", maxHistorySize)})
+	history, err = GetHistory(cmap)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, 6, history[0].Version)
+	assert.Empty(t, history[0].Manifest)
+}
+
+func TestGetVariablesHash(t *testing.T) {
+	assert.Empty(t, GetVariablesHash(nil))
+	assert.Equal(t, GetVariablesHash([]string{"A=1", "B=2"}), GetVariablesHash([]string{"B=2", "A=1"}))
+	assert.NotEqual(t, GetVariablesHash([]string{"A=1"}), GetVariablesHash([]string{"A=2"}))
+}
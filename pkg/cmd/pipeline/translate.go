@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -36,16 +37,18 @@ import (
 )
 
 const (
-	nameField       = "name"
-	statusField     = "status"
-	outputField     = "output"
-	repoField       = "repository"
-	branchField     = "branch"
-	filenameField   = "filename"
-	yamlField       = "yaml"
-	iconField       = "icon"
-	actionLockField = "actionLock"
-	actionNameField = "actionName"
+	nameField        = "name"
+	statusField      = "status"
+	outputField      = "output"
+	repoField        = "repository"
+	branchField      = "branch"
+	filenameField    = "filename"
+	yamlField        = "yaml"
+	iconField        = "icon"
+	actionLockField  = "actionLock"
+	actionNameField  = "actionName"
+	helmReleaseField = "helmRelease"
+	outputsField     = "outputs"
 
 	actionDefaultName = "cli"
 
@@ -82,6 +85,14 @@ type CfgData struct {
 	Filename   string
 	Manifest   []byte
 	Icon       string
+	// IsDeploy is true when the data is set by 'okteto deploy', the only operation that updates the helm release
+	IsDeploy bool
+	// HelmRelease is the release installed by the 'deploy.helm' section, if any
+	HelmRelease string
+	// Outputs are the values published by the deploy commands through $OKTETO_OUTPUT
+	Outputs map[string]string
+	// HistoryEntry is recorded in the deploy history when the deploy finishes
+	HistoryEntry *HistoryEntry
 }
 
 // TranslateConfigMapAndDeploy translates the app into a configMap
@@ -131,6 +142,39 @@ func UpdateConfigMap(ctx context.Context, cmap *apiv1.ConfigMap, data *CfgData,
 	return configmaps.Deploy(ctx, cmap, cmap.Namespace, c)
 }
 
+// GetHelmRelease returns the helm release tracked in the pipeline configmap
+func GetHelmRelease(cmap *apiv1.ConfigMap) string {
+	if cmap == nil {
+		return ""
+	}
+	return cmap.Data[helmReleaseField]
+}
+
+// GetOutputs returns the outputs of the deploy commands stored in the pipeline configmap
+func GetOutputs(cmap *apiv1.ConfigMap) (map[string]string, error) {
+	outputs := map[string]string{}
+	if cmap == nil || cmap.Data[outputsField] == "" {
+		return outputs, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(cmap.Data[outputsField])
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the outputs of '%s': %w", cmap.Name, err)
+	}
+	if err := json.Unmarshal(decoded, &outputs); err != nil {
+		return nil, fmt.Errorf("error decoding the outputs of '%s': %w", cmap.Name, err)
+	}
+	return outputs, nil
+}
+
+// GetDeployOutputs returns the outputs of the deploy commands of a pipeline
+func GetDeployOutputs(ctx context.Context, name, namespace string, c kubernetes.Interface) (map[string]string, error) {
+	cmap, err := configmaps.Get(ctx, TranslatePipelineName(name), namespace, c)
+	if err != nil {
+		return nil, err
+	}
+	return GetOutputs(cmap)
+}
+
 // TranslatePipelineName translate the name into the pipeline name
 func TranslatePipelineName(name string) string {
 	return fmt.Sprintf("okteto-git-%s", name)
@@ -187,6 +231,12 @@ func translateConfigMapSandBox(data *CfgData) *apiv1.ConfigMap {
 	if data.Repository != "" {
 		cmap.Data[filenameField] = data.Filename
 	}
+	if data.HelmRelease != "" {
+		cmap.Data[helmReleaseField] = data.HelmRelease
+	}
+	if len(data.Outputs) > 0 {
+		setOutputs(cmap, data.Outputs)
+	}
 
 	output := oktetoLog.GetOutputBuffer()
 	outputData := translateOutput(output)
@@ -220,12 +270,46 @@ func updateCmap(cmap *apiv1.ConfigMap, data *CfgData) error {
 		cmap.Data[branchField] = data.Branch
 	}
 
+	if data.IsDeploy {
+		// the release and the outputs of a previous deploy are removed when the new deploy doesn't define them
+		setHelmRelease(cmap, data.HelmRelease)
+		setOutputs(cmap, data.Outputs)
+	}
+
+	if data.HistoryEntry != nil {
+		entry := *data.HistoryEntry
+		entry.Status = data.Status
+		addHistoryEntry(cmap, entry)
+	}
+
 	output := oktetoLog.GetOutputBuffer()
 	outputData := translateOutput(output)
 	cmap.Data[outputField] = base64.StdEncoding.EncodeToString([]byte(outputData))
 	return nil
 }
 
+func setHelmRelease(cmap *apiv1.ConfigMap, releaseName string) {
+	if releaseName == "" {
+		delete(cmap.Data, helmReleaseField)
+		return
+	}
+	cmap.Data[helmReleaseField] = releaseName
+}
+
+// setOutputs stores the outputs of the deploy commands encoded in base64, as they might contain any character
+func setOutputs(cmap *apiv1.ConfigMap, outputs map[string]string) {
+	if len(outputs) == 0 {
+		delete(cmap.Data, outputsField)
+		return
+	}
+	encoded, err := json.Marshal(outputs)
+	if err != nil {
+		oktetoLog.Infof("could not encode the outputs of '%s': %s", cmap.Name, err)
+		return
+	}
+	cmap.Data[outputsField] = base64.StdEncoding.EncodeToString(encoded)
+}
+
 // AddDevAnnotations add deploy labels to the deployments/sfs
 func AddDevAnnotations(ctx context.Context, manifest *model.Manifest, c kubernetes.Interface) {
 	repo := os.Getenv(model.GithubRepositoryEnvVar)
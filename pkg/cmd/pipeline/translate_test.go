@@ -76,6 +76,85 @@ func Test_translateConfigMap(t *testing.T) {
 	}
 }
 
+func TestHelmReleaseTracking(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewSimpleClientset()
+
+	data := &CfgData{
+		Name:        "app",
+		Namespace:   "test",
+		Status:      ProgressingStatus,
+		IsDeploy:    true,
+		HelmRelease: "app-release",
+	}
+	cfg, err := TranslateConfigMapAndDeploy(ctx, data, fakeClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "app-release", GetHelmRelease(cfg))
+
+	// destroy doesn't know the release, but the tracked one must be kept
+	data = &CfgData{
+		Name:      "app",
+		Namespace: "test",
+		Status:    DestroyingStatus,
+	}
+	cfg, err = TranslateConfigMapAndDeploy(ctx, data, fakeClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "app-release", GetHelmRelease(cfg))
+
+	// a deploy without the helm section removes the tracked release
+	data = &CfgData{
+		Name:      "app",
+		Namespace: "test",
+		Status:    ProgressingStatus,
+		IsDeploy:  true,
+	}
+	cfg, err = TranslateConfigMapAndDeploy(ctx, data, fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, GetHelmRelease(cfg))
+
+	assert.Empty(t, GetHelmRelease(nil))
+}
+
+func TestOutputsTracking(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewSimpleClientset()
+
+	data := &CfgData{
+		Name:      "app",
+		Namespace: "test",
+		Status:    ProgressingStatus,
+		IsDeploy:  true,
+	}
+	_, err := TranslateConfigMapAndDeploy(ctx, data, fakeClient)
+	assert.NoError(t, err)
+	outputs, err := GetDeployOutputs(ctx, "app", "test", fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, outputs)
+
+	data.Status = DeployedStatus
+	data.Outputs = map[string]string{"DATABASE_URL": "postgres://db:5432/app?sslmode=disable"}
+	_, err = TranslateConfigMapAndDeploy(ctx, data, fakeClient)
+	assert.NoError(t, err)
+	outputs, err = GetDeployOutputs(ctx, "app", "test", fakeClient)
+	assert.NoError(t, err)
+	assert.Equal(t, data.Outputs, outputs)
+
+	// a deploy without outputs removes the outputs of the previous deploy
+	data.Outputs = nil
+	_, err = TranslateConfigMapAndDeploy(ctx, data, fakeClient)
+	assert.NoError(t, err)
+	outputs, err = GetDeployOutputs(ctx, "app", "test", fakeClient)
+	assert.NoError(t, err)
+	assert.Empty(t, outputs)
+
+	_, err = GetDeployOutputs(ctx, "missing", "test", fakeClient)
+	assert.Error(t, err)
+
+	outputs, err = GetOutputs(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, outputs)
+}
+
 func Test_AddDevAnnotations(t *testing.T) {
 	ctx := context.Background()
 	d := &appsv1.Deployment{
@@ -0,0 +1,194 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/k8s/services"
+	"github.com/okteto/okteto/pkg/k8s/statefulsets"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// fieldManager is the field manager used to apply the stack resources
+const fieldManager = "okteto"
+
+var (
+	deploymentGVK  = appsv1.SchemeGroupVersion.WithKind("Deployment")
+	statefulSetGVK = appsv1.SchemeGroupVersion.WithKind("StatefulSet")
+	serviceGVK     = apiv1.SchemeGroupVersion.WithKind("Service")
+)
+
+// getApplyPatch returns the server-side apply patch of a translated object
+func getApplyPatch(obj runtime.Object, gvk schema.GroupVersionKind) ([]byte, error) {
+	obj = obj.DeepCopyObject()
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	if accessor, ok := obj.(metav1.Object); ok {
+		accessor.SetResourceVersion("")
+		accessor.SetManagedFields(nil)
+	}
+	return json.Marshal(obj)
+}
+
+// getDeploymentApplyPatch returns the apply patch of a deployment, without the replicas if they are owned by an autoscaler
+func getDeploymentApplyPatch(d *appsv1.Deployment, autoscaled bool) ([]byte, error) {
+	if autoscaled {
+		d = d.DeepCopy()
+		d.Spec.Replicas = nil
+	}
+	return getApplyPatch(d, deploymentGVK)
+}
+
+// getStatefulSetApplyPatch returns the apply patch of a statefulset, without the replicas if they are owned by an autoscaler
+func getStatefulSetApplyPatch(sfs *appsv1.StatefulSet, autoscaled bool) ([]byte, error) {
+	if autoscaled {
+		sfs = sfs.DeepCopy()
+		sfs.Spec.Replicas = nil
+	}
+	return getApplyPatch(sfs, statefulSetGVK)
+}
+
+// getApplyOptions returns the patch options to apply a stack resource.
+// Objects never applied by okteto were updated by previous versions of the cli, so okteto takes the ownership of their fields.
+func getApplyOptions(managedFields []metav1.ManagedFieldsEntry) metav1.PatchOptions {
+	force := !isAppliedByOkteto(managedFields)
+	return metav1.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        &force,
+	}
+}
+
+func isAppliedByOkteto(managedFields []metav1.ManagedFieldsEntry) bool {
+	for _, entry := range managedFields {
+		if entry.Manager == fieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
+
+// isApplyNotSupported returns if the cluster or the client don't support server-side apply, so the resource must be updated instead
+func isApplyNotSupported(err error) bool {
+	if err == nil {
+		return false
+	}
+	return k8sErrors.IsUnsupportedMediaType(err) || strings.Contains(err.Error(), "PatchType is not supported")
+}
+
+// getApplyConflictError returns an error naming the field managers that own the fields in conflict
+func getApplyConflictError(kind, name string, err error) error {
+	statusErr := &k8sErrors.StatusError{}
+	if !k8sErrors.IsConflict(err) || !errors.As(err, &statusErr) || statusErr.ErrStatus.Details == nil {
+		return err
+	}
+
+	fieldsByManager := map[string][]string{}
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		manager := getConflictManager(cause.Message)
+		fieldsByManager[manager] = append(fieldsByManager[manager], cause.Field)
+	}
+	if len(fieldsByManager) == 0 {
+		return err
+	}
+
+	managers := []string{}
+	for manager := range fieldsByManager {
+		managers = append(managers, manager)
+	}
+	sort.Strings(managers)
+	conflicts := []string{}
+	for _, manager := range managers {
+		conflicts = append(conflicts, fmt.Sprintf("'%s' (%s)", manager, strings.Join(fieldsByManager[manager], ", ")))
+	}
+	return fmt.Errorf("%s '%s' has fields modified by other field managers: %s. Run 'okteto stack diff' to review the changes and revert them before deploying again", kind, name, strings.Join(conflicts, ", "))
+}
+
+// getConflictManager extracts the field manager from messages like 'conflict with "kubectl-edit" using apps/v1'
+func getConflictManager(message string) string {
+	_, quoted, found := strings.Cut(message, "conflict with ")
+	if !found {
+		return message
+	}
+	prefix, err := strconv.QuotedPrefix(quoted)
+	if err != nil {
+		return message
+	}
+	manager, err := strconv.Unquote(prefix)
+	if err != nil {
+		return message
+	}
+	return manager
+}
+
+// applyDeployment applies a deployment. The replicas of autoscaled deployments are left out of the apply configuration
+// because they are owned by the autoscaler, but the update fallback keeps the replicas of d.
+func applyDeployment(ctx context.Context, d *appsv1.Deployment, managedFields []metav1.ManagedFieldsEntry, autoscaled bool, c kubernetes.Interface) (*appsv1.Deployment, error) {
+	patch, err := getDeploymentApplyPatch(d, autoscaled)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := c.AppsV1().Deployments(d.Namespace).Patch(ctx, d.Name, types.ApplyPatchType, patch, getApplyOptions(managedFields))
+	if isApplyNotSupported(err) {
+		applied, err = deployments.Deploy(ctx, d, c)
+	}
+	if err != nil {
+		return nil, getApplyConflictError("deployment", d.Name, err)
+	}
+	return applied, nil
+}
+
+// applyStatefulSet applies a statefulset, leaving the replicas out of the apply configuration if it is autoscaled
+func applyStatefulSet(ctx context.Context, sfs *appsv1.StatefulSet, managedFields []metav1.ManagedFieldsEntry, autoscaled bool, c kubernetes.Interface) (*appsv1.StatefulSet, error) {
+	patch, err := getStatefulSetApplyPatch(sfs, autoscaled)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := c.AppsV1().StatefulSets(sfs.Namespace).Patch(ctx, sfs.Name, types.ApplyPatchType, patch, getApplyOptions(managedFields))
+	if isApplyNotSupported(err) {
+		applied, err = statefulsets.Deploy(ctx, sfs, c)
+	}
+	if err != nil {
+		return nil, getApplyConflictError("statefulset", sfs.Name, err)
+	}
+	return applied, nil
+}
+
+func applyK8sService(ctx context.Context, svc *apiv1.Service, managedFields []metav1.ManagedFieldsEntry, c kubernetes.Interface) error {
+	patch, err := getApplyPatch(svc, serviceGVK)
+	if err != nil {
+		return err
+	}
+	_, err = c.CoreV1().Services(svc.Namespace).Patch(ctx, svc.Name, types.ApplyPatchType, patch, getApplyOptions(managedFields))
+	if isApplyNotSupported(err) {
+		err = services.Deploy(ctx, svc, c)
+	}
+	return getApplyConflictError("kubernetes service", svc.Name, err)
+}
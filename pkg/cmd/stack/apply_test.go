@@ -0,0 +1,188 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8sTesting "k8s.io/client-go/testing"
+	"k8s.io/utils/pointer"
+)
+
+// newFakeClientset returns a fake clientset that creates the objects applied with server-side apply,
+// the fake object tracker returns a not found error instead
+func newFakeClientset(objects ...runtime.Object) *fake.Clientset {
+	c := fake.NewSimpleClientset(objects...)
+	c.Fake.PrependReactor("patch", "*", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(k8sTesting.PatchAction)
+		if patchAction.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		if _, err := c.Tracker().Get(patchAction.GetResource(), patchAction.GetNamespace(), patchAction.GetName()); err == nil {
+			return false, nil, nil
+		}
+		obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(patchAction.GetPatch(), nil, nil)
+		if err != nil {
+			return true, nil, err
+		}
+		return true, obj, c.Tracker().Create(patchAction.GetResource(), obj, patchAction.GetNamespace())
+	})
+	return c
+}
+
+func Test_getApplyConflictError(t *testing.T) {
+	conflict := k8sErrors.NewApplyConflict([]metav1.StatusCause{
+		{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kubectl-edit" using apps/v1`, Field: ".spec.replicas"},
+		{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "hpa-controller" using apps/v1`, Field: ".spec.template.spec.containers[name=\"api\"].image"},
+		{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kubectl-edit" using apps/v1`, Field: ".metadata.labels.app"},
+	}, "Apply failed with 3 conflicts")
+
+	err := getApplyConflictError("deployment", "api", conflict)
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "deployment 'api' has fields modified by other field managers: 'hpa-controller' (.spec.template.spec.containers[name=\"api\"].image), 'kubectl-edit' (.spec.replicas, .metadata.labels.app)."))
+
+	other := k8sErrors.NewBadRequest("invalid")
+	assert.Equal(t, other, getApplyConflictError("deployment", "api", other))
+	assert.Nil(t, getApplyConflictError("deployment", "api", nil))
+}
+
+func Test_getConflictManager(t *testing.T) {
+	assert.Equal(t, "kubectl-edit", getConflictManager(`conflict with "kubectl-edit" using apps/v1`))
+	assert.Equal(t, "kube-controller-manager", getConflictManager(`conflict with "kube-controller-manager"`))
+	assert.Equal(t, "unexpected message", getConflictManager("unexpected message"))
+}
+
+func Test_isAppliedByOkteto(t *testing.T) {
+	assert.False(t, isAppliedByOkteto(nil))
+	assert.False(t, isAppliedByOkteto([]metav1.ManagedFieldsEntry{{Manager: fieldManager, Operation: metav1.ManagedFieldsOperationUpdate}}))
+	assert.True(t, isAppliedByOkteto([]metav1.ManagedFieldsEntry{
+		{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate},
+		{Manager: fieldManager, Operation: metav1.ManagedFieldsOperationApply},
+	}))
+	assert.True(t, *getApplyOptions(nil).Force)
+	assert.Equal(t, fieldManager, getApplyOptions(nil).FieldManager)
+}
+
+func Test_deployDeploymentWithServerSideApply(t *testing.T) {
+	ctx := context.Background()
+	s := &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api": {Image: "api:v2", Replicas: 1},
+		},
+	}
+	live := translateDeployment("api", s)
+	live.Spec.Template.Spec.Containers[0].Image = "api:v1"
+	live.Generation = 1
+
+	c := fake.NewSimpleClientset(live)
+	var patch []byte
+	c.Fake.PrependReactor("patch", "deployments", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(k8sTesting.PatchAction)
+		if patchAction.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		patch = patchAction.GetPatch()
+		applied := translateDeployment("api", s)
+		applied.Generation = 2
+		return true, applied, nil
+	})
+
+	isNew, updated, err := deployDeployment(ctx, "api", s, c)
+	assert.NoError(t, err)
+	assert.False(t, isNew)
+	assert.True(t, updated)
+
+	applied := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(patch, &applied))
+	assert.Equal(t, "apps/v1", applied["apiVersion"])
+	assert.Equal(t, "Deployment", applied["kind"])
+
+	c.Fake.PrependReactor("patch", "deployments", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8sErrors.NewApplyConflict([]metav1.StatusCause{
+			{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kubectl-edit" using apps/v1`, Field: ".spec.replicas"},
+		}, "Apply failed with 1 conflict")
+	})
+	_, _, err = deployDeployment(ctx, "api", s, c)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "'kubectl-edit' (.spec.replicas)"))
+}
+
+func Test_deployDeploymentWithoutServerSideApply(t *testing.T) {
+	ctx := context.Background()
+	s := &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api": {Image: "api:v2", Replicas: 1},
+		},
+	}
+	c := fake.NewSimpleClientset()
+	c.Fake.PrependReactor("patch", "deployments", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8sErrors.NewGenericServerResponse(415, "patch", appsv1.Resource("deployments"), "api", "unsupported media type", 0, false)
+	})
+
+	isNew, updated, err := deployDeployment(ctx, "api", s, c)
+	assert.NoError(t, err)
+	assert.True(t, isNew)
+	assert.False(t, updated)
+	d, err := c.AppsV1().Deployments("ns").Get(ctx, "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "api:v2", d.Spec.Template.Spec.Containers[0].Image)
+}
+
+func Test_deployDeploymentWithAutoscaler(t *testing.T) {
+	ctx := context.Background()
+	s := &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api": {Image: "api:v2", Replicas: 1, RestartPolicy: apiv1.RestartPolicyAlways, Autoscaling: &model.ServiceAutoscaling{MinReplicas: 2, MaxReplicas: 5}},
+		},
+	}
+	live := translateDeployment("api", s)
+	live.Spec.Replicas = pointer.Int32(4)
+
+	c := fake.NewSimpleClientset(live)
+	var patch []byte
+	c.Fake.PrependReactor("patch", "deployments", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		patch = action.(k8sTesting.PatchAction).GetPatch()
+		return true, nil, k8sErrors.NewGenericServerResponse(415, "patch", appsv1.Resource("deployments"), "api", "unsupported media type", 0, false)
+	})
+
+	_, _, err := deployDeployment(ctx, "api", s, c)
+	assert.NoError(t, err)
+
+	applied := &appsv1.Deployment{}
+	assert.NoError(t, json.Unmarshal(patch, applied))
+	assert.Nil(t, applied.Spec.Replicas)
+
+	d, err := c.AppsV1().Deployments("ns").Get(ctx, "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), *d.Spec.Replicas)
+}
@@ -0,0 +1,223 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	apiv1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func translateHorizontalPodAutoscaler(svcName string, s *model.Stack) *autoscalingv2.HorizontalPodAutoscaler {
+	svc := s.Services[svcName]
+	kind := "Deployment"
+	if svc.IsStatefulset() {
+		kind = "StatefulSet"
+	}
+
+	metrics := []autoscalingv2.MetricSpec{}
+	for _, target := range []struct {
+		name        apiv1.ResourceName
+		utilization int32
+	}{
+		{name: apiv1.ResourceCPU, utilization: svc.Autoscaling.CPU},
+		{name: apiv1.ResourceMemory, utilization: svc.Autoscaling.Memory},
+	} {
+		if target.utilization == 0 {
+			continue
+		}
+		utilization := target.utilization
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: target.name,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: &utilization,
+				},
+			},
+		})
+	}
+
+	minReplicas := svc.Autoscaling.MinReplicas
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svcName,
+			Namespace: s.Namespace,
+			Labels:    translateLabelSelector(svcName, s),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       kind,
+				Name:       svcName,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: svc.Autoscaling.MaxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
+
+func translatePodDisruptionBudget(svcName string, s *model.Stack) *policyv1.PodDisruptionBudget {
+	svc := s.Services[svcName]
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svcName,
+			Namespace: s.Namespace,
+			Labels:    translateLabelSelector(svcName, s),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:   svc.DisruptionBudget.MinAvailable,
+			MaxUnavailable: svc.DisruptionBudget.MaxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: translateLabelSelector(svcName, s),
+			},
+		},
+	}
+}
+
+// hasAutoscaler returns if the stack creates an autoscaler for a service
+func hasAutoscaler(svcName string, s *model.Stack) bool {
+	svc, ok := s.Services[svcName]
+	return ok && svc.Autoscaling != nil && (svc.IsDeployment() || svc.IsStatefulset())
+}
+
+// hasDisruptionBudget returns if the stack creates a disruption budget for a service
+func hasDisruptionBudget(svcName string, s *model.Stack) bool {
+	svc, ok := s.Services[svcName]
+	return ok && svc.DisruptionBudget != nil && (svc.IsDeployment() || svc.IsStatefulset())
+}
+
+// deployAutoscaling creates or updates the autoscaler and the disruption budget of a service
+func deployAutoscaling(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) error {
+	if hasAutoscaler(svcName, s) {
+		if err := deployHorizontalPodAutoscaler(ctx, svcName, s, c); err != nil {
+			return err
+		}
+	}
+	if hasDisruptionBudget(svcName, s) {
+		if err := deployPodDisruptionBudget(ctx, svcName, s, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deployHorizontalPodAutoscaler(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) error {
+	hpa := translateHorizontalPodAutoscaler(svcName, s)
+	old, err := c.AutoscalingV2().HorizontalPodAutoscalers(s.Namespace).Get(ctx, hpa.Name, metav1.GetOptions{})
+	if err != nil && !oktetoErrors.IsNotFound(err) {
+		return fmt.Errorf("error getting autoscaler of service '%s': %w", svcName, err)
+	}
+	if old == nil || old.Name == "" {
+		if _, err := c.AutoscalingV2().HorizontalPodAutoscalers(s.Namespace).Create(ctx, hpa, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating autoscaler of service '%s': %w", svcName, err)
+		}
+		oktetoLog.Infof("autoscaler of service '%s' created", svcName)
+		return nil
+	}
+	if err := checkStackNameCollision("autoscaler", hpa.Name, old.Labels, s); err != nil {
+		oktetoLog.Warning(err.Error())
+		return nil
+	}
+	hpa.ResourceVersion = old.ResourceVersion
+	if _, err := c.AutoscalingV2().HorizontalPodAutoscalers(s.Namespace).Update(ctx, hpa, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating autoscaler of service '%s': %w", svcName, err)
+	}
+	oktetoLog.Infof("autoscaler of service '%s' updated", svcName)
+	return nil
+}
+
+func deployPodDisruptionBudget(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) error {
+	pdb := translatePodDisruptionBudget(svcName, s)
+	old, err := c.PolicyV1().PodDisruptionBudgets(s.Namespace).Get(ctx, pdb.Name, metav1.GetOptions{})
+	if err != nil && !oktetoErrors.IsNotFound(err) {
+		return fmt.Errorf("error getting disruption budget of service '%s': %w", svcName, err)
+	}
+	if old == nil || old.Name == "" {
+		if _, err := c.PolicyV1().PodDisruptionBudgets(s.Namespace).Create(ctx, pdb, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating disruption budget of service '%s': %w", svcName, err)
+		}
+		oktetoLog.Infof("disruption budget of service '%s' created", svcName)
+		return nil
+	}
+	if err := checkStackNameCollision("disruption budget", pdb.Name, old.Labels, s); err != nil {
+		oktetoLog.Warning(err.Error())
+		return nil
+	}
+	pdb.ResourceVersion = old.ResourceVersion
+	if _, err := c.PolicyV1().PodDisruptionBudgets(s.Namespace).Update(ctx, pdb, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating disruption budget of service '%s': %w", svcName, err)
+	}
+	oktetoLog.Infof("disruption budget of service '%s' updated", svcName)
+	return nil
+}
+
+// getDeployedReplicas returns the replicas to deploy for a service: when the service is autoscaled,
+// the replicas set by the autoscaler are kept on redeploy
+func getDeployedReplicas(svcName string, s *model.Stack, current *int32) *int32 {
+	if current == nil || !hasAutoscaler(svcName, s) {
+		return nil
+	}
+	replicas := *current
+	svc := s.Services[svcName]
+	if replicas < svc.Autoscaling.MinReplicas {
+		replicas = svc.Autoscaling.MinReplicas
+	}
+	if replicas > svc.Autoscaling.MaxReplicas {
+		replicas = svc.Autoscaling.MaxReplicas
+	}
+	return &replicas
+}
+
+// destroyAutoscaling destroys the autoscalers and disruption budgets created by the stack that are no longer part of it
+func destroyAutoscaling(ctx context.Context, s *model.Stack, c kubernetes.Interface) error {
+	hpaList, err := c.AutoscalingV2().HorizontalPodAutoscalers(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: s.GetLabelSelector()})
+	if err != nil {
+		return err
+	}
+	for _, hpa := range hpaList.Items {
+		if hasAutoscaler(hpa.Name, s) {
+			continue
+		}
+		if err := c.AutoscalingV2().HorizontalPodAutoscalers(hpa.Namespace).Delete(ctx, hpa.Name, metav1.DeleteOptions{}); err != nil && !oktetoErrors.IsNotFound(err) {
+			return fmt.Errorf("error destroying autoscaler '%s': %w", hpa.Name, err)
+		}
+		oktetoLog.Infof("autoscaler '%s' destroyed", hpa.Name)
+	}
+
+	pdbList, err := c.PolicyV1().PodDisruptionBudgets(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: s.GetLabelSelector()})
+	if err != nil {
+		return err
+	}
+	for _, pdb := range pdbList.Items {
+		if hasDisruptionBudget(pdb.Name, s) {
+			continue
+		}
+		if err := c.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Delete(ctx, pdb.Name, metav1.DeleteOptions{}); err != nil && !oktetoErrors.IsNotFound(err) {
+			return fmt.Errorf("error destroying disruption budget '%s': %w", pdb.Name, err)
+		}
+		oktetoLog.Infof("disruption budget '%s' destroyed", pdb.Name)
+	}
+	return nil
+}
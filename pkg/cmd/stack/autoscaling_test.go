@@ -0,0 +1,117 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+)
+
+func newAutoscalingTestStack() *model.Stack {
+	minAvailable := intstr.FromString("50%")
+	return &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api": {
+				Image:            "api",
+				RestartPolicy:    apiv1.RestartPolicyAlways,
+				Replicas:         2,
+				Autoscaling:      &model.ServiceAutoscaling{MinReplicas: 2, MaxReplicas: 5, CPU: 70, Memory: 80},
+				DisruptionBudget: &model.ServiceDisruptionBudget{MinAvailable: &minAvailable},
+			},
+			"db": {
+				Image:         "postgres",
+				RestartPolicy: apiv1.RestartPolicyAlways,
+				Replicas:      1,
+				Volumes:       []model.StackVolume{{RemotePath: "/data"}},
+				Autoscaling:   &model.ServiceAutoscaling{MinReplicas: 1, MaxReplicas: 3, CPU: 80},
+			},
+		},
+	}
+}
+
+func Test_translateAutoscaling(t *testing.T) {
+	s := newAutoscalingTestStack()
+
+	hpa := translateHorizontalPodAutoscaler("api", s)
+	assert.Equal(t, translateLabelSelector("api", s), hpa.Labels)
+	assert.Equal(t, autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "api"}, hpa.Spec.ScaleTargetRef)
+	assert.Equal(t, pointer.Int32Ptr(2), hpa.Spec.MinReplicas)
+	assert.Equal(t, int32(5), hpa.Spec.MaxReplicas)
+	assert.Len(t, hpa.Spec.Metrics, 2)
+	assert.Equal(t, apiv1.ResourceCPU, hpa.Spec.Metrics[0].Resource.Name)
+	assert.Equal(t, pointer.Int32Ptr(70), hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+	assert.Equal(t, apiv1.ResourceMemory, hpa.Spec.Metrics[1].Resource.Name)
+
+	hpa = translateHorizontalPodAutoscaler("db", s)
+	assert.Equal(t, "StatefulSet", hpa.Spec.ScaleTargetRef.Kind)
+	assert.Len(t, hpa.Spec.Metrics, 1)
+
+	pdb := translatePodDisruptionBudget("api", s)
+	assert.Equal(t, "50%", pdb.Spec.MinAvailable.StrVal)
+	assert.Nil(t, pdb.Spec.MaxUnavailable)
+	assert.Equal(t, translateLabelSelector("api", s), pdb.Spec.Selector.MatchLabels)
+
+	assert.Nil(t, getDeployedReplicas("api", s, nil))
+	assert.Equal(t, pointer.Int32Ptr(4), getDeployedReplicas("api", s, pointer.Int32Ptr(4)))
+	assert.Equal(t, pointer.Int32Ptr(5), getDeployedReplicas("api", s, pointer.Int32Ptr(8)))
+	assert.Equal(t, pointer.Int32Ptr(2), getDeployedReplicas("api", s, pointer.Int32Ptr(1)))
+}
+
+func Test_deployAndDestroyAutoscaling(t *testing.T) {
+	ctx := context.Background()
+	s := newAutoscalingTestStack()
+	c := fake.NewSimpleClientset()
+
+	assert.NoError(t, deployAutoscaling(ctx, "api", s, c))
+	assert.NoError(t, deployAutoscaling(ctx, "db", s, c))
+	hpaList, err := c.AutoscalingV2().HorizontalPodAutoscalers("ns").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, hpaList.Items, 2)
+	pdbList, err := c.PolicyV1().PodDisruptionBudgets("ns").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, pdbList.Items, 1)
+
+	s.Services["api"].Autoscaling.MaxReplicas = 10
+	assert.NoError(t, deployAutoscaling(ctx, "api", s, c))
+	hpa, err := c.AutoscalingV2().HorizontalPodAutoscalers("ns").Get(ctx, "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), hpa.Spec.MaxReplicas)
+
+	s.Services["api"].Autoscaling = nil
+	s.Services["api"].DisruptionBudget = nil
+	assert.NoError(t, destroyAutoscaling(ctx, s, c))
+	_, err = c.AutoscalingV2().HorizontalPodAutoscalers("ns").Get(ctx, "api", metav1.GetOptions{})
+	assert.Error(t, err)
+	_, err = c.PolicyV1().PodDisruptionBudgets("ns").Get(ctx, "api", metav1.GetOptions{})
+	assert.Error(t, err)
+	_, err = c.AutoscalingV2().HorizontalPodAutoscalers("ns").Get(ctx, "db", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	s.Services = nil
+	assert.NoError(t, destroyAutoscaling(ctx, s, c))
+	hpaList, err = c.AutoscalingV2().HorizontalPodAutoscalers("ns").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, hpaList.Items)
+}
@@ -0,0 +1,437 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	configVolumePrefix = "config"
+	secretVolumePrefix = "secret"
+
+	// configsSourceDir and configsTargetDir are the folders where the init container mounts the configs and secrets
+	// that need a specific owner, so they can be copied with the right uid/gid
+	configsSourceDir = "/okteto/configs/source"
+	configsTargetDir = "/okteto/configs/target"
+)
+
+// configReference represents a config or secret mounted by a service
+type configReference struct {
+	model.ServiceConfig
+	volumeName string
+	objectName string
+	isSecret   bool
+}
+
+// getConfigObjectName returns the name of the configmap or secret of a stack config.
+// The key of the config is used as data key, so external objects must define it too
+func getConfigObjectName(name string, config *model.StackConfig, s *model.Stack) string {
+	if config.Name != "" {
+		return config.Name
+	}
+	if config.External {
+		return name
+	}
+	return fmt.Sprintf("%s-%s", s.Name, name)
+}
+
+// getConfigContent returns the content of a config or secret from its file, environment variable or inline content
+func getConfigContent(name string, config *model.StackConfig) ([]byte, error) {
+	switch {
+	case config.File != "":
+		content, err := os.ReadFile(config.File)
+		if err != nil {
+			return nil, fmt.Errorf("error reading '%s': %w", name, err)
+		}
+		return content, nil
+	case config.Environment != "":
+		value, ok := os.LookupEnv(config.Environment)
+		if !ok {
+			return nil, fmt.Errorf("error reading '%s': environment variable '%s' is not defined", name, config.Environment)
+		}
+		return []byte(value), nil
+	default:
+		return []byte(config.Content), nil
+	}
+}
+
+func translateStackConfigLabels(label, name string, s *model.Stack) map[string]string {
+	return map[string]string{
+		model.StackNameLabel: s.Name,
+		label:                name,
+	}
+}
+
+func translateConfigConfigMap(name string, s *model.Stack) (*apiv1.ConfigMap, error) {
+	config := s.Configs[name]
+	content, err := getConfigContent(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getConfigObjectName(name, config, s),
+			Namespace: s.Namespace,
+			Labels:    translateStackConfigLabels(model.StackConfigNameLabel, name, s),
+		},
+		BinaryData: map[string][]byte{
+			name: content,
+		},
+	}, nil
+}
+
+func translateConfigSecret(name string, s *model.Stack) (*apiv1.Secret, error) {
+	secret := s.Secrets[name]
+	content, err := getConfigContent(name, secret)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getConfigObjectName(name, secret, s),
+			Namespace: s.Namespace,
+			Labels:    translateStackConfigLabels(model.StackSecretNameLabel, name, s),
+		},
+		Type: apiv1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			name: content,
+		},
+	}, nil
+}
+
+// getSvcConfigReferences returns the configs and secrets mounted by a service
+func getSvcConfigReferences(svcName string, s *model.Stack) []configReference {
+	svc := s.Services[svcName]
+	result := []configReference{}
+	for i, config := range svc.Configs {
+		spec, ok := s.Configs[config.Source]
+		if !ok {
+			continue
+		}
+		result = append(result, configReference{
+			ServiceConfig: config,
+			volumeName:    fmt.Sprintf("%s-%d", configVolumePrefix, i),
+			objectName:    getConfigObjectName(config.Source, spec, s),
+		})
+	}
+	for i, secret := range svc.Secrets {
+		spec, ok := s.Secrets[secret.Source]
+		if !ok {
+			continue
+		}
+		result = append(result, configReference{
+			ServiceConfig: secret,
+			volumeName:    fmt.Sprintf("%s-%d", secretVolumePrefix, i),
+			objectName:    getConfigObjectName(secret.Source, spec, s),
+			isSecret:      true,
+		})
+	}
+	return result
+}
+
+func (r *configReference) hasOwner() bool {
+	return r.UID != "" || r.GID != ""
+}
+
+func (r *configReference) fileName() string {
+	return path.Base(r.Target)
+}
+
+func (r *configReference) translateVolumeSource() apiv1.VolumeSource {
+	items := []apiv1.KeyToPath{
+		{
+			Key:  r.Source,
+			Path: r.fileName(),
+			Mode: r.Mode,
+		},
+	}
+	if r.isSecret {
+		return apiv1.VolumeSource{
+			Secret: &apiv1.SecretVolumeSource{
+				SecretName: r.objectName,
+				Items:      items,
+			},
+		}
+	}
+	return apiv1.VolumeSource{
+		ConfigMap: &apiv1.ConfigMapVolumeSource{
+			LocalObjectReference: apiv1.LocalObjectReference{Name: r.objectName},
+			Items:                items,
+		},
+	}
+}
+
+// translateConfigVolumes returns the volumes of the configs and secrets mounted by a service.
+// Configs with uid or gid are copied by an init container into an emptyDir volume, kubernetes can't set the owner of the files
+func translateConfigVolumes(svcName string, s *model.Stack) []apiv1.Volume {
+	var result []apiv1.Volume
+	for _, ref := range getSvcConfigReferences(svcName, s) {
+		if !ref.hasOwner() {
+			result = append(result, apiv1.Volume{Name: ref.volumeName, VolumeSource: ref.translateVolumeSource()})
+			continue
+		}
+		result = append(
+			result,
+			apiv1.Volume{Name: fmt.Sprintf("%s-source", ref.volumeName), VolumeSource: ref.translateVolumeSource()},
+			apiv1.Volume{Name: ref.volumeName, VolumeSource: apiv1.VolumeSource{EmptyDir: &apiv1.EmptyDirVolumeSource{}}},
+		)
+	}
+	return result
+}
+
+func translateConfigVolumeMounts(svcName string, s *model.Stack) []apiv1.VolumeMount {
+	var result []apiv1.VolumeMount
+	for _, ref := range getSvcConfigReferences(svcName, s) {
+		result = append(result, apiv1.VolumeMount{
+			Name:      ref.volumeName,
+			MountPath: ref.Target,
+			SubPath:   ref.fileName(),
+			ReadOnly:  true,
+		})
+	}
+	return result
+}
+
+// getConfigsInitContainer returns the init container that copies the configs and secrets with uid or gid, nil if there is none
+func getConfigsInitContainer(svcName string, s *model.Stack) *apiv1.Container {
+	commands := []string{}
+	volumeMounts := []apiv1.VolumeMount{}
+	for _, ref := range getSvcConfigReferences(svcName, s) {
+		if !ref.hasOwner() {
+			continue
+		}
+		source := path.Join(configsSourceDir, ref.volumeName)
+		target := path.Join(configsTargetDir, ref.volumeName)
+		volumeMounts = append(
+			volumeMounts,
+			apiv1.VolumeMount{Name: fmt.Sprintf("%s-source", ref.volumeName), MountPath: source, ReadOnly: true},
+			apiv1.VolumeMount{Name: ref.volumeName, MountPath: target},
+		)
+		file := path.Join(target, ref.fileName())
+		commands = append(commands, fmt.Sprintf("cp %s %s", path.Join(source, ref.fileName()), file))
+		owner := ref.UID
+		if ref.GID != "" {
+			owner = fmt.Sprintf("%s:%s", owner, ref.GID)
+		}
+		commands = append(commands, fmt.Sprintf("chown %s %s", owner, file))
+		if ref.Mode != nil {
+			commands = append(commands, fmt.Sprintf("chmod %o %s", *ref.Mode, file))
+		}
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+	return &apiv1.Container{
+		Name:         fmt.Sprintf("init-configs-%s", svcName),
+		Image:        "busybox",
+		Command:      []string{"sh", "-c", strings.Join(commands, " && ")},
+		VolumeMounts: volumeMounts,
+	}
+}
+
+// translatePodAnnotations returns the annotations of the pods of a service.
+// The checksum of its configs and secrets restarts the pods when any of them is updated.
+// AppArmor profiles are only configurable with annotations
+func translatePodAnnotations(svcName string, s *model.Stack) map[string]string {
+	result := translateAnnotations(s.Services[svcName])
+	if checksum := getConfigsChecksum(svcName, s); checksum != "" {
+		result[model.OktetoConfigsChecksumAnnotation] = checksum
+	}
+	if securityOpts, _ := model.ParseSecurityOpts(s.Services[svcName].SecurityOpt); securityOpts.AppArmorProfile != "" {
+		result[fmt.Sprintf("%s/%s", appArmorAnnotationPrefix, svcName)] = securityOpts.AppArmorProfile
+	}
+	return result
+}
+
+func getConfigsChecksum(svcName string, s *model.Stack) string {
+	svc := s.Services[svcName]
+	if len(svc.Configs) == 0 && len(svc.Secrets) == 0 {
+		return ""
+	}
+	h := sha256.New()
+	for _, ref := range getSvcConfigReferences(svcName, s) {
+		spec := s.Configs[ref.Source]
+		if ref.isSecret {
+			spec = s.Secrets[ref.Source]
+		}
+		if spec.External {
+			continue
+		}
+		content, err := getConfigContent(ref.Source, spec)
+		if err != nil {
+			oktetoLog.Infof("could not compute checksum of '%s': %s", ref.Source, err)
+			continue
+		}
+		fmt.Fprintf(h, "%s=%x\n", ref.objectName, sha256.Sum256(content))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// getConfigsToDeployFromServicesToDeploy returns the configs and secrets created by okteto that are mounted by the services to deploy
+func getConfigsToDeployFromServicesToDeploy(s *model.Stack, servicesToDeploy map[string]bool) ([]string, []string) {
+	configsSet := map[string]bool{}
+	secretsSet := map[string]bool{}
+	for svcName, svc := range s.Services {
+		if !servicesToDeploy[svcName] {
+			continue
+		}
+		for _, config := range svc.Configs {
+			if spec, ok := s.Configs[config.Source]; ok && !spec.External {
+				configsSet[config.Source] = true
+			}
+		}
+		for _, secret := range svc.Secrets {
+			if spec, ok := s.Secrets[secret.Source]; ok && !spec.External {
+				secretsSet[secret.Source] = true
+			}
+		}
+	}
+	configs := []string{}
+	for name := range configsSet {
+		configs = append(configs, name)
+	}
+	secrets := []string{}
+	for name := range secretsSet {
+		secrets = append(secrets, name)
+	}
+	sort.Strings(configs)
+	sort.Strings(secrets)
+	return configs, secrets
+}
+
+// checkStackNameCollision returns an error if an object with the same name was not created by this stack
+func checkStackNameCollision(kind, name string, labels map[string]string, s *model.Stack) error {
+	if labels[model.StackNameLabel] == "" {
+		return fmt.Errorf("skipping deploy of %s '%s' due to name collision with pre-existing %s", kind, name, kind)
+	}
+	if labels[model.StackNameLabel] != s.Name {
+		return fmt.Errorf("skipping deploy of %s '%s' due to name collision with %s in compose '%s'", kind, name, kind, labels[model.StackNameLabel])
+	}
+	return nil
+}
+
+func deployConfig(ctx context.Context, name string, s *model.Stack, c kubernetes.Interface) error {
+	cmap, err := translateConfigConfigMap(name, s)
+	if err != nil {
+		return err
+	}
+	old, err := c.CoreV1().ConfigMaps(s.Namespace).Get(ctx, cmap.Name, metav1.GetOptions{})
+	if err != nil && !oktetoErrors.IsNotFound(err) {
+		return fmt.Errorf("error getting config '%s': %w", name, err)
+	}
+	if old == nil || old.Name == "" {
+		if _, err := c.CoreV1().ConfigMaps(s.Namespace).Create(ctx, cmap, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating config '%s': %w", name, err)
+		}
+		oktetoLog.Success("Config '%s' created", name)
+		return nil
+	}
+	if err := checkStackNameCollision("config", cmap.Name, old.Labels, s); err != nil {
+		oktetoLog.Warning(err.Error())
+		return nil
+	}
+	cmap.ResourceVersion = old.ResourceVersion
+	if _, err := c.CoreV1().ConfigMaps(s.Namespace).Update(ctx, cmap, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating config '%s': %w", name, err)
+	}
+	oktetoLog.Success("Config '%s' updated", name)
+	return nil
+}
+
+func deploySecret(ctx context.Context, name string, s *model.Stack, c kubernetes.Interface) error {
+	secret, err := translateConfigSecret(name, s)
+	if err != nil {
+		return err
+	}
+	old, err := c.CoreV1().Secrets(s.Namespace).Get(ctx, secret.Name, metav1.GetOptions{})
+	if err != nil && !oktetoErrors.IsNotFound(err) {
+		return fmt.Errorf("error getting secret '%s': %w", name, err)
+	}
+	if old == nil || old.Name == "" {
+		if _, err := c.CoreV1().Secrets(s.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating secret '%s': %w", name, err)
+		}
+		oktetoLog.Success("Secret '%s' created", name)
+		return nil
+	}
+	if err := checkStackNameCollision("secret", secret.Name, old.Labels, s); err != nil {
+		oktetoLog.Warning(err.Error())
+		return nil
+	}
+	secret.ResourceVersion = old.ResourceVersion
+	if _, err := c.CoreV1().Secrets(s.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating secret '%s': %w", name, err)
+	}
+	oktetoLog.Success("Secret '%s' updated", name)
+	return nil
+}
+
+// getStackLabelSelectorWith selects the objects of the stack that have the given label
+func getStackLabelSelectorWith(label string, s *model.Stack) string {
+	return fmt.Sprintf("%s,%s", s.GetLabelSelector(), label)
+}
+
+// isStackConfigObject returns if an object created for a config or secret is still part of the stack
+func isStackConfigObject(configs map[string]*model.StackConfig, name, objectName string, s *model.Stack) bool {
+	config, ok := configs[name]
+	return ok && !config.External && getConfigObjectName(name, config, s) == objectName
+}
+
+// destroyConfigs destroys the configmaps and secrets created by the stack that are no longer part of it
+func destroyConfigs(ctx context.Context, s *model.Stack, c kubernetes.Interface) error {
+	cmaps, err := c.CoreV1().ConfigMaps(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: getStackLabelSelectorWith(model.StackConfigNameLabel, s)})
+	if err != nil {
+		return err
+	}
+	for _, cmap := range cmaps.Items {
+		name := cmap.Labels[model.StackConfigNameLabel]
+		if isStackConfigObject(s.Configs, name, cmap.Name, s) {
+			continue
+		}
+		if err := c.CoreV1().ConfigMaps(cmap.Namespace).Delete(ctx, cmap.Name, metav1.DeleteOptions{}); err != nil && !oktetoErrors.IsNotFound(err) {
+			return fmt.Errorf("error destroying config '%s': %w", name, err)
+		}
+		oktetoLog.Success("Config '%s' destroyed", name)
+	}
+
+	secrets, err := c.CoreV1().Secrets(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: getStackLabelSelectorWith(model.StackSecretNameLabel, s)})
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets.Items {
+		name := secret.Labels[model.StackSecretNameLabel]
+		if isStackConfigObject(s.Secrets, name, secret.Name, s) {
+			continue
+		}
+		if err := c.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !oktetoErrors.IsNotFound(err) {
+			return fmt.Errorf("error destroying secret '%s': %w", name, err)
+		}
+		oktetoLog.Success("Secret '%s' destroyed", name)
+	}
+	return nil
+}
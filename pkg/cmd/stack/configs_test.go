@@ -0,0 +1,207 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+)
+
+func newConfigsTestStack(t *testing.T) *model.Stack {
+	file := filepath.Join(t.TempDir(), "nginx.conf")
+	if err := os.WriteFile(file, []byte("server {}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("API_TOKEN", "token")
+	return &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Configs: map[string]*model.StackConfig{
+			"nginx":    {File: file},
+			"settings": {Content: "debug=true"},
+			"shared":   {External: true},
+		},
+		Secrets: map[string]*model.StackConfig{
+			"token": {Environment: "API_TOKEN"},
+		},
+		Services: map[string]*model.Service{
+			"api": {
+				Image:         "api",
+				RestartPolicy: apiv1.RestartPolicyAlways,
+				Replicas:      1,
+				Configs: []model.ServiceConfig{
+					{Source: "nginx", Target: "/etc/nginx/nginx.conf", Mode: pointer.Int32Ptr(0440)},
+					{Source: "shared", Target: "/shared"},
+				},
+				Secrets: []model.ServiceConfig{
+					{Source: "token", Target: "/run/secrets/token", UID: "1000", GID: "1000", Mode: pointer.Int32Ptr(0400)},
+				},
+			},
+			"worker": {
+				Image:         "worker",
+				RestartPolicy: apiv1.RestartPolicyAlways,
+				Replicas:      1,
+			},
+		},
+	}
+}
+
+func Test_translateConfigObjects(t *testing.T) {
+	s := newConfigsTestStack(t)
+
+	cmap, err := translateConfigConfigMap("nginx", s)
+	assert.NoError(t, err)
+	assert.Equal(t, "stack-test-nginx", cmap.Name)
+	assert.Equal(t, map[string]string{model.StackNameLabel: "stack-test", model.StackConfigNameLabel: "nginx"}, cmap.Labels)
+	assert.Equal(t, map[string][]byte{"nginx": []byte("server {}")}, cmap.BinaryData)
+
+	cmap, err = translateConfigConfigMap("settings", s)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("debug=true"), cmap.BinaryData["settings"])
+
+	secret, err := translateConfigSecret("token", s)
+	assert.NoError(t, err)
+	assert.Equal(t, "stack-test-token", secret.Name)
+	assert.Equal(t, map[string][]byte{"token": []byte("token")}, secret.Data)
+
+	s.Secrets["token"].Environment = "NOT_DEFINED_ENV_VAR"
+	_, err = translateConfigSecret("token", s)
+	assert.Error(t, err)
+}
+
+func Test_translateDeploymentWithConfigs(t *testing.T) {
+	s := newConfigsTestStack(t)
+
+	d := translateDeployment("api", s)
+	podSpec := d.Spec.Template.Spec
+	assert.Equal(t, []apiv1.Volume{
+		{
+			Name: "config-0",
+			VolumeSource: apiv1.VolumeSource{
+				ConfigMap: &apiv1.ConfigMapVolumeSource{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: "stack-test-nginx"},
+					Items:                []apiv1.KeyToPath{{Key: "nginx", Path: "nginx.conf", Mode: pointer.Int32Ptr(0440)}},
+				},
+			},
+		},
+		{
+			Name: "config-1",
+			VolumeSource: apiv1.VolumeSource{
+				ConfigMap: &apiv1.ConfigMapVolumeSource{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: "shared"},
+					Items:                []apiv1.KeyToPath{{Key: "shared", Path: "shared"}},
+				},
+			},
+		},
+		{
+			Name: "secret-0-source",
+			VolumeSource: apiv1.VolumeSource{
+				Secret: &apiv1.SecretVolumeSource{
+					SecretName: "stack-test-token",
+					Items:      []apiv1.KeyToPath{{Key: "token", Path: "token", Mode: pointer.Int32Ptr(0400)}},
+				},
+			},
+		},
+		{
+			Name:         "secret-0",
+			VolumeSource: apiv1.VolumeSource{EmptyDir: &apiv1.EmptyDirVolumeSource{}},
+		},
+	}, podSpec.Volumes)
+	assert.Equal(t, []apiv1.VolumeMount{
+		{Name: "config-0", MountPath: "/etc/nginx/nginx.conf", SubPath: "nginx.conf", ReadOnly: true},
+		{Name: "config-1", MountPath: "/shared", SubPath: "shared", ReadOnly: true},
+		{Name: "secret-0", MountPath: "/run/secrets/token", SubPath: "token", ReadOnly: true},
+	}, podSpec.Containers[0].VolumeMounts)
+
+	assert.Len(t, podSpec.InitContainers, 1)
+	assert.Equal(t, []string{
+		"sh",
+		"-c",
+		"cp /okteto/configs/source/secret-0/token /okteto/configs/target/secret-0/token && chown 1000:1000 /okteto/configs/target/secret-0/token && chmod 400 /okteto/configs/target/secret-0/token",
+	}, podSpec.InitContainers[0].Command)
+
+	checksum := d.Spec.Template.Annotations[model.OktetoConfigsChecksumAnnotation]
+	assert.NotEmpty(t, checksum)
+	assert.Empty(t, d.Annotations[model.OktetoConfigsChecksumAnnotation])
+
+	s.Configs["nginx"].File = filepath.Join(t.TempDir(), "nginx.conf")
+	if err := os.WriteFile(s.Configs["nginx"].File, []byte("server { listen 80; }"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	d = translateDeployment("api", s)
+	assert.NotEqual(t, checksum, d.Spec.Template.Annotations[model.OktetoConfigsChecksumAnnotation])
+
+	d = translateDeployment("worker", s)
+	assert.Nil(t, d.Spec.Template.Spec.Volumes)
+	assert.Nil(t, d.Spec.Template.Spec.InitContainers)
+	assert.NotContains(t, d.Spec.Template.Annotations, model.OktetoConfigsChecksumAnnotation)
+}
+
+func Test_deployAndDestroyConfigs(t *testing.T) {
+	ctx := context.Background()
+	s := newConfigsTestStack(t)
+	foreign := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "stack-test-settings", Namespace: "ns"},
+	}
+	removed := &apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stack-test-removed",
+			Namespace: "ns",
+			Labels:    map[string]string{model.StackNameLabel: "stack-test", model.StackSecretNameLabel: "removed"},
+		},
+	}
+	c := fake.NewSimpleClientset(foreign, removed)
+
+	configs, secrets := getConfigsToDeployFromServicesToDeploy(s, map[string]bool{"api": true})
+	assert.Equal(t, []string{"nginx"}, configs)
+	assert.Equal(t, []string{"token"}, secrets)
+
+	assert.NoError(t, deployConfig(ctx, "nginx", s, c))
+	assert.NoError(t, deployConfig(ctx, "settings", s, c))
+	assert.NoError(t, deploySecret(ctx, "token", s, c))
+
+	cmap, err := c.CoreV1().ConfigMaps("ns").Get(ctx, "stack-test-settings", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, cmap.BinaryData)
+
+	s.Configs["nginx"].File = ""
+	s.Configs["nginx"].Content = "updated"
+	assert.NoError(t, deployConfig(ctx, "nginx", s, c))
+	cmap, err = c.CoreV1().ConfigMaps("ns").Get(ctx, "stack-test-nginx", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("updated"), cmap.BinaryData["nginx"])
+
+	assert.NoError(t, destroyConfigs(ctx, s, c))
+	_, err = c.CoreV1().Secrets("ns").Get(ctx, "stack-test-removed", metav1.GetOptions{})
+	assert.Error(t, err)
+	_, err = c.CoreV1().Secrets("ns").Get(ctx, "stack-test-token", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	s.Configs = nil
+	s.Secrets = nil
+	assert.NoError(t, destroyConfigs(ctx, s, c))
+	_, err = c.CoreV1().ConfigMaps("ns").Get(ctx, "stack-test-nginx", metav1.GetOptions{})
+	assert.Error(t, err)
+	_, err = c.CoreV1().ConfigMaps("ns").Get(ctx, "stack-test-settings", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
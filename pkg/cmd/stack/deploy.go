@@ -16,17 +16,21 @@ package stack
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/k8s/configmaps"
+	"github.com/okteto/okteto/pkg/k8s/cronjobs"
 	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/k8s/events"
 	forwardK8s "github.com/okteto/okteto/pkg/k8s/forward"
 	"github.com/okteto/okteto/pkg/k8s/ingresses"
 	"github.com/okteto/okteto/pkg/k8s/jobs"
@@ -38,6 +42,7 @@ import (
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/okteto/okteto/pkg/model/forward"
 	"github.com/okteto/okteto/pkg/registry"
+	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -56,6 +61,7 @@ type StackDeployOptions struct {
 	ServicesToDeploy []string
 	Progress         string
 	InsidePipeline   bool
+	Profiles         []string
 }
 
 // Stack is the executor of stack commands
@@ -96,6 +102,9 @@ func (sd *Stack) Deploy(ctx context.Context, s *model.Stack, options *StackDeplo
 		cfg.Data[outputField] = base64.StdEncoding.EncodeToString([]byte(output))
 	} else {
 		output = fmt.Sprintf("%s\nCompose '%s' successfully deployed", output, s.Name)
+		if scheduled := getScheduledServicesInfo(s, time.Now().UTC()); len(scheduled) > 0 {
+			output = fmt.Sprintf("%s\nScheduled services:\n  - %s", output, strings.Join(scheduled, "\n  - "))
+		}
 		cfg.Data[statusField] = deployedStatus
 		cfg.Data[outputField] = base64.StdEncoding.EncodeToString([]byte(output))
 	}
@@ -115,6 +124,13 @@ func deploy(ctx context.Context, s *model.Stack, c kubernetes.Interface, config
 	oktetoLog.StartSpinner()
 	defer oktetoLog.StopSpinner()
 
+	progress := newDeployProgress(s, options.ServicesToDeploy)
+	progressCtx, stopProgress := context.WithCancel(ctx)
+	defer stopProgress()
+	if err := progress.watch(progressCtx, c); err != nil {
+		oktetoLog.Infof("could not watch the progress of compose '%s': %s", s.Name, err)
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt)
 	exit := make(chan error, 1)
@@ -138,6 +154,12 @@ func deploy(ctx context.Context, s *model.Stack, c kubernetes.Interface, config
 				exit <- err
 				return
 			}
+			for _, alias := range getSvcAliases(serviceName, s) {
+				if err := deployAliasService(ctx, alias, serviceName, s, c); err != nil {
+					exit <- err
+					return
+				}
+			}
 			// get the public ports from the compose service - this will be deployed into ingresses
 			ingressPortsToDeploy := getSvcPublicPorts(serviceName, s)
 			for _, ingressPort := range ingressPortsToDeploy {
@@ -196,6 +218,25 @@ func deploy(ctx context.Context, s *model.Stack, c kubernetes.Interface, config
 			}
 		}
 
+		configsToDeploy, secretsToDeploy := getConfigsToDeployFromServicesToDeploy(s, servicesToDeploySet)
+		for _, name := range configsToDeploy {
+			if err := deployConfig(ctx, name, s, c); err != nil {
+				exit <- err
+				return
+			}
+		}
+		for _, name := range secretsToDeploy {
+			if err := deploySecret(ctx, name, s, c); err != nil {
+				exit <- err
+				return
+			}
+		}
+
+		if err := deployNetworkPolicies(ctx, s, c); err != nil {
+			exit <- err
+			return
+		}
+
 		if err := deployServices(ctx, s, c, config, options); err != nil {
 			exit <- err
 			return
@@ -248,6 +289,7 @@ func deploy(ctx context.Context, s *model.Stack, c kubernetes.Interface, config
 		}
 
 		oktetoLog.Spinner("Waiting for services to be ready...")
+		progress.setLive(progressCtx, c)
 		exit <- waitForPodsToBeRunning(ctx, s, c)
 	}()
 
@@ -257,6 +299,8 @@ func deploy(ctx context.Context, s *model.Stack, c kubernetes.Interface, config
 		oktetoLog.StopSpinner()
 		return oktetoErrors.ErrIntSig
 	case err := <-exit:
+		stopProgress()
+		progress.render()
 		if err != nil {
 			oktetoLog.Infof("exit signal received due to error: %s", err)
 			return err
@@ -322,6 +366,8 @@ func getEndpointsToDeployFromServicesToDeploy(endpoints model.EndpointSpec, serv
 
 func deployServices(ctx context.Context, stack *model.Stack, k8sClient kubernetes.Interface, config *rest.Config, options *StackDeployOptions) error {
 	deployedSvcs := make(map[string]bool)
+	waitingSince := make(map[string]time.Time)
+	pendingRestarts := make(map[string]bool)
 	t := time.NewTicker(1 * time.Second)
 	to := time.NewTicker(options.Timeout)
 
@@ -337,8 +383,14 @@ func deployServices(ctx context.Context, stack *model.Stack, k8sClient kubernete
 					}
 
 					if !canSvcBeDeployed(ctx, stack, svcName, k8sClient, config) {
+						if _, ok := waitingSince[svcName]; !ok {
+							waitingSince[svcName] = time.Now()
+						}
 						if failedJobs := getDependingFailedJobs(ctx, stack, svcName, k8sClient); len(failedJobs) > 0 {
 							if len(failedJobs) == 1 {
+								if message := jobs.GetFailedMessage(ctx, stack.Namespace, failedJobs[0], k8sClient); message != "" {
+									return fmt.Errorf("service '%s' dependency '%s' failed: %s", svcName, failedJobs[0], message)
+								}
 								return fmt.Errorf("service '%s' dependency '%s' failed", svcName, failedJobs[0])
 							}
 							return fmt.Errorf("service '%s' dependencies '%s' failed", svcName, strings.Join(failedJobs, ", "))
@@ -351,14 +403,28 @@ func deployServices(ctx context.Context, stack *model.Stack, k8sClient kubernete
 						if err := getErrorDueToRestartLimit(ctx, stack, svcName, k8sClient); err != nil {
 							return err
 						}
+						if err := getDependencyTimeoutError(ctx, stack, svcName, time.Since(waitingSince[svcName]), k8sClient, config); err != nil {
+							return err
+						}
 						continue
 					}
 					oktetoLog.Spinner(fmt.Sprintf("Deploying service '%s'...", svcName))
-					err := deploySvc(ctx, stack, svcName, k8sClient)
+					updated, err := deploySvc(ctx, stack, svcName, k8sClient)
 					if err != nil {
 						return err
 					}
 					deployedSvcs[svcName] = true
+					// the pods of an updated service are already rolled out
+					if pendingRestarts[svcName] && !updated {
+						if err := restartService(ctx, svcName, stack, k8sClient); err != nil {
+							return err
+						}
+					}
+					if updated {
+						if err := restartDependants(ctx, stack, svcName, options.ServicesToDeploy, deployedSvcs, pendingRestarts, k8sClient); err != nil {
+							return err
+						}
+					}
 					oktetoLog.Spinner("Waiting for services to be ready...")
 				}
 			}
@@ -367,23 +433,29 @@ func deployServices(ctx context.Context, stack *model.Stack, k8sClient kubernete
 	}
 }
 
-func deploySvc(ctx context.Context, stack *model.Stack, svcName string, client kubernetes.Interface) error {
-	isNew := false
+// deploySvc deploys a service and returns if the spec of its existing deployment or statefulset changed
+func deploySvc(ctx context.Context, stack *model.Stack, svcName string, client kubernetes.Interface) (bool, error) {
+	isNew, updated := false, false
 	var err error
-	if stack.Services[svcName].IsJob() {
+	if stack.Services[svcName].IsCronJob() {
+		isNew, err = deployCronJob(ctx, svcName, stack, client)
+	} else if stack.Services[svcName].IsJob() {
 		isNew, err = deployJob(ctx, svcName, stack, client)
 	} else if len(stack.Services[svcName].Volumes) == 0 {
-		isNew, err = deployDeployment(ctx, svcName, stack, client)
+		isNew, updated, err = deployDeployment(ctx, svcName, stack, client)
 	} else {
-		isNew, err = deployStatefulSet(ctx, svcName, stack, client)
+		isNew, updated, err = deployStatefulSet(ctx, svcName, stack, client)
 	}
 
 	if err != nil {
 		if strings.Contains(err.Error(), "skipping ") {
 			oktetoLog.Warning(err.Error())
-			return nil
+			return false, nil
 		}
-		return err
+		return false, err
+	}
+	if err := deployAutoscaling(ctx, svcName, stack, client); err != nil {
+		return false, err
 	}
 	if isNew {
 		oktetoLog.Success("Service '%s' created", svcName)
@@ -391,6 +463,108 @@ func deploySvc(ctx context.Context, stack *model.Stack, svcName string, client k
 		oktetoLog.Success("Service '%s' updated", svcName)
 	}
 
+	return updated, nil
+}
+
+// restartDependants restarts the services with 'restart: true' in their dependency on an updated service.
+// Dependants pending to be deployed are restarted after their deploy.
+func restartDependants(ctx context.Context, stack *model.Stack, svcName string, servicesToDeploy []string, deployedSvcs, pendingRestarts map[string]bool, client kubernetes.Interface) error {
+	toDeploy := map[string]bool{}
+	for _, name := range servicesToDeploy {
+		toDeploy[name] = true
+	}
+
+	dependants := []string{}
+	for dependant, svc := range stack.Services {
+		if condition, ok := svc.DependsOn[svcName]; ok && condition.Restart {
+			dependants = append(dependants, dependant)
+		}
+	}
+	sort.Strings(dependants)
+
+	for _, dependant := range dependants {
+		svc := stack.Services[dependant]
+		if !svc.IsDeployment() && !svc.IsStatefulset() {
+			oktetoLog.Warning("Service '%s' is not restarted after '%s' was updated: only services running continuously can be restarted", dependant, svcName)
+			continue
+		}
+		if toDeploy[dependant] && !deployedSvcs[dependant] {
+			pendingRestarts[dependant] = true
+			continue
+		}
+		if err := restartService(ctx, dependant, stack, client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restartService rolls out the pods of a service applying a new value of the restart annotation of its pod template
+func restartService(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) error {
+	svc := s.Services[svcName]
+	restartedAt := time.Now().UTC().Format(time.RFC3339Nano)
+	var err error
+	switch {
+	case svc.IsDeployment():
+		err = restartDeployment(ctx, svcName, s, restartedAt, c)
+	case svc.IsStatefulset():
+		err = restartStatefulSet(ctx, svcName, s, restartedAt, c)
+	default:
+		return nil
+	}
+	if err != nil {
+		if oktetoErrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error restarting service '%s': %w", svcName, err)
+	}
+	oktetoLog.Success("Service '%s' restarted", svcName)
+	return nil
+}
+
+func restartDeployment(ctx context.Context, svcName string, s *model.Stack, restartedAt string, c kubernetes.Interface) error {
+	old, err := c.AppsV1().Deployments(s.Namespace).Get(ctx, svcName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if old.Labels[model.StackNameLabel] != s.Name {
+		return fmt.Errorf("deployment '%s' doesn't belong to compose '%s'", svcName, s.Name)
+	}
+	d := translateDeployment(svcName, s)
+	setLiveDeploymentFields(svcName, s, d, old)
+	setRestartedAt(&d.Spec.Template, restartedAt)
+	_, err = applyDeployment(ctx, d, old.ManagedFields, hasAutoscaler(svcName, s), c)
+	return err
+}
+
+func restartStatefulSet(ctx context.Context, svcName string, s *model.Stack, restartedAt string, c kubernetes.Interface) error {
+	old, err := c.AppsV1().StatefulSets(s.Namespace).Get(ctx, svcName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if old.Labels[model.StackNameLabel] != s.Name {
+		return fmt.Errorf("statefulset '%s' doesn't belong to compose '%s'", svcName, s.Name)
+	}
+	sfs := translateStatefulSet(svcName, s)
+	setLiveStatefulSetFields(svcName, s, sfs, old)
+	setRestartedAt(&sfs.Spec.Template, restartedAt)
+	_, err = applyStatefulSet(ctx, sfs, old.ManagedFields, hasAutoscaler(svcName, s), c)
+	return err
+}
+
+// getDependencyTimeoutError returns an error if a dependency of a service hasn't reached its condition within its timeout
+func getDependencyTimeoutError(ctx context.Context, stack *model.Stack, svcName string, waiting time.Duration, client kubernetes.Interface, config *rest.Config) error {
+	svc := stack.Services[svcName]
+	for _, dependency := range getSortedDependencies(svc) {
+		condition := svc.DependsOn[dependency]
+		if condition.Timeout == 0 || waiting < condition.Timeout {
+			continue
+		}
+		if isSvcReady(ctx, stack, dependency, condition, client, config) {
+			continue
+		}
+		return fmt.Errorf("service '%s' dependency '%s' didn't reach the condition '%s' after %s", svcName, dependency, condition.Condition, condition.Timeout.String())
+	}
 	return nil
 }
 
@@ -559,7 +733,7 @@ func deployK8sService(ctx context.Context, svcName string, s *model.Stack, c kub
 		if !oktetoErrors.IsNotFound(err) {
 			return fmt.Errorf("error getting service '%s': %w", svcName, err)
 		}
-		if err := services.Deploy(ctx, svcK8s, c); err != nil {
+		if err := applyK8sService(ctx, svcK8s, nil, c); err != nil {
 			return err
 		}
 		oktetoLog.Success("Kubernetes service '%s' created", svcName)
@@ -576,24 +750,24 @@ func deployK8sService(ctx context.Context, svcName string, s *model.Stack, c kub
 		return nil
 	}
 
-	svcK8s.ObjectMeta.ResourceVersion = old.ObjectMeta.ResourceVersion
-	if err := services.Deploy(ctx, svcK8s, c); err != nil {
+	if err := applyK8sService(ctx, svcK8s, old.ManagedFields, c); err != nil {
 		return err
 	}
 	oktetoLog.Success("Kubernetes service '%s' updated", svcName)
 	return nil
 }
 
-func deployDeployment(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (bool, error) {
+// deployDeployment deploys the deployment of a service and returns if it was created and if the spec of an existing deployment changed
+func deployDeployment(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (bool, bool, error) {
 	d := translateDeployment(svcName, s)
 	old, err := c.AppsV1().Deployments(s.Namespace).Get(ctx, svcName, metav1.GetOptions{})
 	if err != nil && !oktetoErrors.IsNotFound(err) {
-		return false, fmt.Errorf("error getting deployment of service '%s': %s", svcName, err.Error())
+		return false, false, fmt.Errorf("error getting deployment of service '%s': %s", svcName, err.Error())
 	}
 	isNewDeployment := old == nil || old.Name == ""
 	if !isNewDeployment {
 		if old.Labels[model.StackNameLabel] == "" {
-			return false, fmt.Errorf("skipping deploy of deployment '%s' due to name collision with pre-existing deployment", svcName)
+			return false, false, fmt.Errorf("skipping deploy of deployment '%s' due to name collision with pre-existing deployment", svcName)
 		}
 		// PR 2742 https://github.com/okteto/okteto/pull/2742
 		// we are introducing this check for the old stack label as we resolved the bug
@@ -601,74 +775,113 @@ func deployDeployment(ctx context.Context, svcName string, s *model.Stack, c kub
 		// for those users which will have a dev environment deployed with old version
 		// when re-deploying we switch the name for the environment and we have to move the resources to the new name
 		if old.Labels[model.StackNameLabel] != s.Name && old.Labels[model.StackNameLabel] != "okteto" {
-			return false, fmt.Errorf("skipping deploy of deployment '%s' due to name collision with deployment in compose '%s'", svcName, old.Labels[model.StackNameLabel])
-		}
-		if v, ok := old.Labels[model.DeployedByLabel]; ok {
-			d.Labels[model.DeployedByLabel] = v
-			if old.Labels[model.StackNameLabel] == "okteto" {
-				d.Labels[model.DeployedByLabel] = s.Name
-			}
+			return false, false, fmt.Errorf("skipping deploy of deployment '%s' due to name collision with deployment in compose '%s'", svcName, old.Labels[model.StackNameLabel])
 		}
+		setLiveDeploymentFields(svcName, s, d, old)
 	}
 
 	if !isNewDeployment && old.Labels[model.StackNameLabel] == "okteto" {
 		if err := deployments.Destroy(ctx, old.Name, old.Namespace, c); err != nil {
-			return false, fmt.Errorf("error updating deployment of service '%s': %s", svcName, err.Error())
+			return false, false, fmt.Errorf("error updating deployment of service '%s': %s", svcName, err.Error())
 		}
-		if _, err := deployments.Deploy(ctx, d, c); err != nil {
-			return false, fmt.Errorf("error updating deployment of service '%s': %s", svcName, err.Error())
+		if _, err := applyDeployment(ctx, d, nil, hasAutoscaler(svcName, s), c); err != nil {
+			return false, false, fmt.Errorf("error updating deployment of service '%s': %s", svcName, err.Error())
 		}
-		return isNewDeployment, nil
+		return false, true, nil
 	}
 
-	if _, err := deployments.Deploy(ctx, d, c); err != nil {
+	var managedFields []metav1.ManagedFieldsEntry
+	if !isNewDeployment {
+		managedFields = old.ManagedFields
+	}
+	applied, err := applyDeployment(ctx, d, managedFields, hasAutoscaler(svcName, s), c)
+	if err != nil {
 		if isNewDeployment {
-			return false, fmt.Errorf("error creating deployment of service '%s': %s", svcName, err.Error())
+			return false, false, fmt.Errorf("error creating deployment of service '%s': %s", svcName, err.Error())
 		}
-		return false, fmt.Errorf("error updating deployment of service '%s': %s", svcName, err.Error())
+		return false, false, fmt.Errorf("error updating deployment of service '%s': %s", svcName, err.Error())
+	}
+	if isNewDeployment {
+		return true, false, nil
 	}
+	return false, applied.Generation != old.Generation, nil
+}
 
-	return isNewDeployment, nil
+// setLiveDeploymentFields keeps the fields of a deployed deployment that are not defined by the compose
+func setLiveDeploymentFields(svcName string, s *model.Stack, d, old *appsv1.Deployment) {
+	if v, ok := old.Labels[model.DeployedByLabel]; ok {
+		d.Labels[model.DeployedByLabel] = v
+		if old.Labels[model.StackNameLabel] == "okteto" {
+			d.Labels[model.DeployedByLabel] = s.Name
+		}
+	}
+	if replicas := getDeployedReplicas(svcName, s, old.Spec.Replicas); replicas != nil {
+		d.Spec.Replicas = replicas
+	}
+	setRestartedAt(&d.Spec.Template, old.Spec.Template.Annotations[model.OktetoComposeRestartedAtAnnotation])
 }
 
-func deployStatefulSet(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (bool, error) {
+// deployStatefulSet deploys the statefulset of a service and returns if it was created and if the spec of an existing statefulset changed
+func deployStatefulSet(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (bool, bool, error) {
 	sfs := translateStatefulSet(svcName, s)
 	old, err := c.AppsV1().StatefulSets(s.Namespace).Get(ctx, svcName, metav1.GetOptions{})
 	if err != nil && !oktetoErrors.IsNotFound(err) {
-		return false, fmt.Errorf("error getting statefulset of service '%s': %s", svcName, err.Error())
+		return false, false, fmt.Errorf("error getting statefulset of service '%s': %s", svcName, err.Error())
 	}
 	if old == nil || old.Name == "" {
-		if _, err := statefulsets.Deploy(ctx, sfs, c); err != nil {
-			return false, fmt.Errorf("error creating statefulset of service '%s': %s", svcName, err.Error())
+		if _, err := applyStatefulSet(ctx, sfs, nil, hasAutoscaler(svcName, s), c); err != nil {
+			return false, false, fmt.Errorf("error creating statefulset of service '%s': %s", svcName, err.Error())
 		}
-		return true, nil
+		return true, false, nil
 	}
 
 	if old.Labels[model.StackNameLabel] == "" {
-		return false, fmt.Errorf("skipping deploy of statefulset '%s' due to name collision with pre-existing statefulset", svcName)
+		return false, false, fmt.Errorf("skipping deploy of statefulset '%s' due to name collision with pre-existing statefulset", svcName)
 	}
 	if old.Labels[model.StackNameLabel] != s.Name && old.Labels[model.StackNameLabel] != "okteto" {
-		return false, fmt.Errorf("skipping deploy of statefulset '%s' due to name collision with statefulset in compose '%s'", svcName, old.Labels[model.StackNameLabel])
+		return false, false, fmt.Errorf("skipping deploy of statefulset '%s' due to name collision with statefulset in compose '%s'", svcName, old.Labels[model.StackNameLabel])
 	}
+	setLiveStatefulSetFields(svcName, s, sfs, old)
+	applied, err := applyStatefulSet(ctx, sfs, old.ManagedFields, hasAutoscaler(svcName, s), c)
+	if err != nil {
+		if !strings.Contains(err.Error(), "Forbidden: updates to statefulset spec") {
+			return false, false, fmt.Errorf("error updating statefulset of service '%s': %s", svcName, err.Error())
+		}
+		if err := statefulsets.Destroy(ctx, sfs.Name, sfs.Namespace, c); err != nil {
+			return false, false, fmt.Errorf("error updating statefulset of service '%s': %s", svcName, err.Error())
+		}
+		if _, err := applyStatefulSet(ctx, sfs, nil, hasAutoscaler(svcName, s), c); err != nil {
+			return false, false, fmt.Errorf("error updating statefulset of service '%s': %s", svcName, err.Error())
+		}
+		return false, true, nil
+	}
+
+	return false, applied.Generation != old.Generation, nil
+}
+
+// setLiveStatefulSetFields keeps the fields of a deployed statefulset that are not defined by the compose
+func setLiveStatefulSetFields(svcName string, s *model.Stack, sfs, old *appsv1.StatefulSet) {
 	if v, ok := old.Labels[model.DeployedByLabel]; ok {
 		sfs.Labels[model.DeployedByLabel] = v
 		if old.Labels[model.StackNameLabel] == "okteto" {
 			sfs.Labels[model.DeployedByLabel] = s.Name
 		}
 	}
-	if _, err := statefulsets.Deploy(ctx, sfs, c); err != nil {
-		if !strings.Contains(err.Error(), "Forbidden: updates to statefulset spec") {
-			return false, fmt.Errorf("error updating statefulset of service '%s': %s", svcName, err.Error())
-		}
-		if err := statefulsets.Destroy(ctx, sfs.Name, sfs.Namespace, c); err != nil {
-			return false, fmt.Errorf("error updating statefulset of service '%s': %s", svcName, err.Error())
-		}
-		if _, err := statefulsets.Deploy(ctx, sfs, c); err != nil {
-			return false, fmt.Errorf("error updating statefulset of service '%s': %s", svcName, err.Error())
-		}
+	if replicas := getDeployedReplicas(svcName, s, old.Spec.Replicas); replicas != nil {
+		sfs.Spec.Replicas = replicas
 	}
+	setRestartedAt(&sfs.Spec.Template, old.Spec.Template.Annotations[model.OktetoComposeRestartedAtAnnotation])
+}
 
-	return false, nil
+// setRestartedAt sets the annotation that rolls out the pods of a service when it is restarted
+func setRestartedAt(template *apiv1.PodTemplateSpec, restartedAt string) {
+	if restartedAt == "" {
+		return
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[model.OktetoComposeRestartedAtAnnotation] = restartedAt
 }
 
 func deployJob(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (bool, error) {
@@ -699,6 +912,31 @@ func deployJob(ctx context.Context, svcName string, s *model.Stack, c kubernetes
 	return isNewJob, nil
 }
 
+func deployCronJob(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (bool, error) {
+	cronjob := translateCronJob(svcName, s)
+	old, err := cronjobs.Get(ctx, svcName, s.Namespace, c)
+	if err != nil && !oktetoErrors.IsNotFound(err) {
+		return false, fmt.Errorf("error getting cronjob of service '%s': %s", svcName, err.Error())
+	}
+	isNewCronJob := old == nil || old.Name == ""
+	if !isNewCronJob {
+		if old.Labels[model.StackNameLabel] == "" {
+			return false, fmt.Errorf("skipping deploy of cronjob '%s' due to name collision with pre-existing cronjob", svcName)
+		}
+		if old.Labels[model.StackNameLabel] != s.Name && old.Labels[model.StackNameLabel] != "okteto" {
+			return false, fmt.Errorf("skipping deploy of cronjob '%s' due to name collision with cronjob in stack '%s'", svcName, old.Labels[model.StackNameLabel])
+		}
+	}
+
+	if _, err := cronjobs.Deploy(ctx, cronjob, c); err != nil {
+		if isNewCronJob {
+			return false, fmt.Errorf("error creating cronjob of service '%s': %s", svcName, err.Error())
+		}
+		return false, fmt.Errorf("error updating cronjob of service '%s': %s", svcName, err.Error())
+	}
+	return isNewCronJob, nil
+}
+
 func deployVolume(ctx context.Context, volumeName string, s *model.Stack, c kubernetes.Interface) error {
 	pvc := translatePersistentVolumeClaim(volumeName, s)
 
@@ -746,6 +984,9 @@ func deployVolume(ctx context.Context, volumeName string, s *model.Stack, c kube
 func waitForPodsToBeRunning(ctx context.Context, s *model.Stack, c kubernetes.Interface) error {
 	var numPods int32 = 0
 	for _, svc := range s.Services {
+		if svc.IsCronJob() {
+			continue
+		}
 		numPods += svc.Replicas
 	}
 
@@ -753,6 +994,8 @@ func waitForPodsToBeRunning(ctx context.Context, s *model.Stack, c kubernetes.In
 	timeout := time.Now().Add(600 * time.Second)
 
 	selector := map[string]string{model.StackNameLabel: s.Name}
+	// services that can't be rolled back because they were never updated
+	withoutPreviousRevision := map[string]bool{}
 	for time.Now().Before(timeout) {
 		<-ticker.C
 		pendingPods := numPods
@@ -761,14 +1004,40 @@ func waitForPodsToBeRunning(ctx context.Context, s *model.Stack, c kubernetes.In
 			return err
 		}
 		for i := range podList {
+			svcName := podList[i].Labels[model.StackServiceNameLabel]
+			if svc, ok := s.Services[svcName]; ok && svc.IsCronJob() {
+				// the pods of scheduled services run periodically, they aren't expected to be running after deploy
+				continue
+			}
 			if podList[i].Status.Phase == apiv1.PodRunning || podList[i].Status.Phase == apiv1.PodSucceeded {
 				pendingPods--
 			}
+			if shouldRollback(s.Services[svcName]) && !withoutPreviousRevision[svcName] && isPodFailing(&podList[i]) {
+				isCurrent, err := isPodOfCurrentRevision(ctx, &podList[i], svcName, s, c)
+				if err != nil {
+					return fmt.Errorf("error getting the revision of service '%s': %w", svcName, err)
+				}
+				if isCurrent {
+					err := rollbackService(ctx, svcName, s, c)
+					if err == nil {
+						return fmt.Errorf("service '%s' has failed and has been rolled back to its previous revision. Please check for errors and try again", svcName)
+					}
+					if !errors.Is(err, errNoPreviousRevision) {
+						return fmt.Errorf("service '%s' has failed and couldn't be rolled back: %w", svcName, err)
+					}
+					oktetoLog.Infof("service '%s' is not rolled back: %s", svcName, err)
+					withoutPreviousRevision[svcName] = true
+				}
+			}
 			if podList[i].Status.Phase == apiv1.PodFailed {
-				return fmt.Errorf("service '%s' has failed. Please check for errors and try again", podList[i].Labels[model.StackServiceNameLabel])
+				if message := events.GetLastWarningMessage(ctx, s.Namespace, podList[i].Name, c); message != "" {
+					return fmt.Errorf("service '%s' has failed: %s. Please check for errors and try again", svcName, message)
+				}
+				return fmt.Errorf("service '%s' has failed. Please check for errors and try again", svcName)
 			}
 		}
-		if pendingPods == 0 {
+		// autoscaled services can have more pods than replicas
+		if pendingPods <= 0 {
 			return nil
 		}
 	}
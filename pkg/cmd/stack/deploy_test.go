@@ -19,6 +19,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/okteto/okteto/pkg/k8s/services"
 	"github.com/okteto/okteto/pkg/model"
@@ -48,7 +49,7 @@ func TestMain(m *testing.M) {
 
 func Test_deploySvc(t *testing.T) {
 	ctx := context.Background()
-	client := fake.NewSimpleClientset()
+	client := newFakeClientset()
 	var tests = []struct {
 		name    string
 		stack   *model.Stack
@@ -109,7 +110,7 @@ func Test_deploySvc(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := deploySvc(ctx, tt.stack, tt.svcName, client)
+			_, err := deploySvc(ctx, tt.stack, tt.svcName, client)
 			if err != nil {
 				t.Fatal("Not deployed correctly")
 			}
@@ -155,7 +156,7 @@ func Test_reDeploySvc(t *testing.T) {
 			ReadyReplicas: 1,
 		},
 	}
-	fakeClient := fake.NewSimpleClientset(oldJobSucceeded, oldSfs, oldDep)
+	fakeClient := newFakeClientset(oldJobSucceeded, oldSfs, oldDep)
 	var tests = []struct {
 		name      string
 		component string
@@ -220,7 +221,7 @@ func Test_reDeploySvc(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := deploySvc(ctx, tt.stack, tt.svcName, fakeClient)
+			_, err := deploySvc(ctx, tt.stack, tt.svcName, fakeClient)
 			if err != nil {
 				t.Fatal("Not re-deployed correctly")
 			}
@@ -274,9 +275,9 @@ func Test_deployDeployment(t *testing.T) {
 			},
 		},
 	}
-	client := fake.NewSimpleClientset()
+	client := newFakeClientset()
 
-	_, err := deployDeployment(ctx, "test", stack, client)
+	_, _, err := deployDeployment(ctx, "test", stack, client)
 	if err != nil {
 		t.Fatal("Not deployed correctly")
 	}
@@ -342,9 +343,9 @@ func Test_deploySfs(t *testing.T) {
 			"a": {},
 		},
 	}
-	client := fake.NewSimpleClientset()
+	client := newFakeClientset()
 
-	_, err := deployStatefulSet(ctx, "test", stack, client)
+	_, _, err := deployStatefulSet(ctx, "test", stack, client)
 	if err != nil {
 		t.Fatal("Not deployed correctly")
 	}
@@ -380,6 +381,41 @@ func Test_deployJob(t *testing.T) {
 	}
 }
 
+func Test_deployCronJob(t *testing.T) {
+	ctx := context.Background()
+	stack := &model.Stack{
+		Namespace: "ns",
+		Name:      "stack-test",
+		Services: map[string]*model.Service{
+			"test": {
+				Image:         "test_image",
+				RestartPolicy: corev1.RestartPolicyOnFailure,
+				Schedule:      &model.ServiceSchedule{Cron: "*/5 * * * *", ConcurrencyPolicy: model.ScheduleConcurrencyForbid},
+			},
+		},
+	}
+	client := fake.NewSimpleClientset()
+
+	isNew, err := deployCronJob(ctx, "test", stack, client)
+	if err != nil || !isNew {
+		t.Fatal("Not deployed correctly")
+	}
+
+	stack.Services["test"].Schedule.Cron = "@hourly"
+	isNew, err = deployCronJob(ctx, "test", stack, client)
+	if err != nil || isNew {
+		t.Fatal("Not updated correctly")
+	}
+
+	cronjob, err := client.BatchV1().CronJobs("ns").Get(ctx, "test", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal("Not deployed correctly")
+	}
+	if cronjob.Spec.Schedule != "@hourly" {
+		t.Fatalf("expected schedule '@hourly', got '%s'", cronjob.Spec.Schedule)
+	}
+}
+
 func Test_ValidateDeploySomeServices(t *testing.T) {
 	var tests = []struct {
 		name             string
@@ -849,7 +885,7 @@ func TestDeployK8sService(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			fakeClient := fake.NewSimpleClientset(tt.k8sObjects...)
+			fakeClient := newFakeClientset(tt.k8sObjects...)
 			err := deployK8sService(context.Background(), "test", tt.stack, fakeClient)
 			assert.NoError(t, err)
 			svc, _ := services.Get(context.Background(), "test", "ns", fakeClient)
@@ -961,3 +997,65 @@ func TestGetErrorDueToRestartLimit(t *testing.T) {
 	}
 
 }
+
+func Test_restartDependants(t *testing.T) {
+	ctx := context.Background()
+	s := &model.Stack{
+		Name:      "test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"db": {RestartPolicy: corev1.RestartPolicyAlways},
+			"api": {
+				RestartPolicy: corev1.RestartPolicyAlways,
+				DependsOn:     model.DependsOn{"db": {Condition: model.DependsOnServiceRunning, Restart: true}},
+			},
+			"worker": {
+				RestartPolicy: corev1.RestartPolicyAlways,
+				DependsOn:     model.DependsOn{"db": {Condition: model.DependsOnServiceRunning, Restart: true}},
+			},
+			"frontend": {
+				RestartPolicy: corev1.RestartPolicyAlways,
+				DependsOn:     model.DependsOn{"db": {Condition: model.DependsOnServiceRunning}},
+			},
+			"seed": {
+				RestartPolicy: corev1.RestartPolicyNever,
+				DependsOn:     model.DependsOn{"db": {Condition: model.DependsOnServiceRunning, Restart: true}},
+			},
+		},
+	}
+	c := fake.NewSimpleClientset(translateDeployment("api", s), translateDeployment("frontend", s))
+
+	pendingRestarts := map[string]bool{}
+	err := restartDependants(ctx, s, "db", []string{"db", "api", "worker", "frontend", "seed"}, map[string]bool{"db": true, "api": true}, pendingRestarts, c)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"worker": true}, pendingRestarts)
+
+	api, err := c.AppsV1().Deployments("ns").Get(ctx, "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, api.Spec.Template.Annotations[model.OktetoComposeRestartedAtAnnotation])
+
+	frontend, err := c.AppsV1().Deployments("ns").Get(ctx, "frontend", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, frontend.Spec.Template.Annotations[model.OktetoComposeRestartedAtAnnotation])
+}
+
+func Test_getDependencyTimeoutError(t *testing.T) {
+	ctx := context.Background()
+	s := &model.Stack{
+		Name:      "test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"db": {RestartPolicy: corev1.RestartPolicyAlways},
+			"api": {
+				RestartPolicy: corev1.RestartPolicyAlways,
+				DependsOn:     model.DependsOn{"db": {Condition: model.DependsOnServiceRunning, Timeout: time.Minute}},
+			},
+		},
+	}
+	c := fake.NewSimpleClientset()
+
+	assert.NoError(t, getDependencyTimeoutError(ctx, s, "api", 30*time.Second, c, nil))
+	assert.Equal(t,
+		fmt.Errorf("service 'api' dependency 'db' didn't reach the condition 'service_started' after 1m0s"),
+		getDependencyTimeoutError(ctx, s, "api", time.Minute, c, nil))
+}
@@ -23,6 +23,7 @@ import (
 
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/k8s/configmaps"
+	"github.com/okteto/okteto/pkg/k8s/cronjobs"
 	"github.com/okteto/okteto/pkg/k8s/deployments"
 	"github.com/okteto/okteto/pkg/k8s/ingresses"
 	"github.com/okteto/okteto/pkg/k8s/jobs"
@@ -77,6 +78,9 @@ func destroyStack(ctx context.Context, s *model.Stack, removeVolumes bool, c *ku
 	go func() {
 		s.Services = nil
 		s.Endpoints = nil
+		s.Configs = nil
+		s.Secrets = nil
+		s.Networks = nil
 		if err := destroyServicesNotInStack(ctx, s, c); err != nil {
 			exit <- err
 			return
@@ -124,11 +128,27 @@ func destroyServicesNotInStack(ctx context.Context, s *model.Stack, c kubernetes
 		return err
 	}
 
+	if err := destroyCronJobs(ctx, s, c); err != nil {
+		return err
+	}
+
 	err := destroyIngresses(ctx, s, c)
 	if err != nil {
 		return err
 	}
 
+	if err := destroyConfigs(ctx, s, c); err != nil {
+		return err
+	}
+
+	if err := destroyNetworks(ctx, s, c); err != nil {
+		return err
+	}
+
+	if err := destroyAutoscaling(ctx, s, c); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -205,6 +225,30 @@ func destroyJobs(ctx context.Context, s *model.Stack, c kubernetes.Interface) er
 	return nil
 }
 
+func destroyCronJobs(ctx context.Context, s *model.Stack, c kubernetes.Interface) error {
+	cronjobList, err := cronjobs.List(ctx, s.Namespace, s.GetLabelSelector(), c)
+	if err != nil {
+		return err
+	}
+	for i := range cronjobList {
+		if _, ok := s.Services[cronjobList[i].Name]; ok && s.Services[cronjobList[i].Name].IsCronJob() {
+			continue
+		}
+		if err := cronjobs.Destroy(ctx, cronjobList[i].Name, cronjobList[i].Namespace, c); err != nil {
+			return fmt.Errorf("error destroying cronjob of service '%s': %s", cronjobList[i].Name, err)
+		}
+		if err := services.Destroy(ctx, cronjobList[i].Name, cronjobList[i].Namespace, c); err != nil {
+			return fmt.Errorf("error destroying service '%s': %s", cronjobList[i].Name, err)
+		}
+		if _, ok := s.Services[cronjobList[i].Name]; ok {
+			oktetoLog.Success("Destroyed previous service '%s'", cronjobList[i].Name)
+		} else {
+			oktetoLog.Success("Service '%s' destroyed", cronjobList[i].Name)
+		}
+	}
+	return nil
+}
+
 func destroyIngresses(ctx context.Context, s *model.Stack, c kubernetes.Interface) error {
 	iClient, err := ingresses.GetClient(c)
 	if err != nil {
@@ -215,19 +259,7 @@ func destroyIngresses(ctx context.Context, s *model.Stack, c kubernetes.Interfac
 	if err != nil {
 		return err
 	}
-	publicSvcsMap := map[string]bool{}
-	for svcName, svcInfo := range s.Services {
-		if len(svcInfo.Ports) > 0 {
-			ingressPorts := getSvcPublicPorts(svcName, s)
-			if len(ingressPorts) == 1 {
-				publicSvcsMap[svcName] = true
-			} else if len(ingressPorts) > 1 {
-				for _, p := range ingressPorts {
-					publicSvcsMap[fmt.Sprintf("%s-%d", svcName, p.ContainerPort)] = true
-				}
-			}
-		}
-	}
+	publicSvcsMap := getPublicIngressNames(s)
 	for i := range iList {
 		if _, ok := s.Endpoints[iList[i].GetName()]; ok {
 			continue
@@ -247,6 +279,33 @@ func destroyIngresses(ctx context.Context, s *model.Stack, c kubernetes.Interfac
 	return nil
 }
 
+// getPublicIngressNames returns the names of the ingresses created for the public ports of the stack services
+func getPublicIngressNames(s *model.Stack) map[string]bool {
+	publicSvcsMap := map[string]bool{}
+	for svcName := range s.Services {
+		for _, ingressName := range getSvcIngressNames(svcName, s) {
+			publicSvcsMap[ingressName] = true
+		}
+	}
+	return publicSvcsMap
+}
+
+// getSvcIngressNames returns the names of the ingresses created for the public ports of a service
+func getSvcIngressNames(svcName string, s *model.Stack) []string {
+	result := []string{}
+	if len(s.Services[svcName].Ports) == 0 {
+		return result
+	}
+	ingressPorts := getSvcPublicPorts(svcName, s)
+	if len(ingressPorts) == 1 {
+		return append(result, svcName)
+	}
+	for _, p := range ingressPorts {
+		result = append(result, fmt.Sprintf("%s-%d", svcName, p.ContainerPort))
+	}
+	return result
+}
+
 func waitForPodsToBeDestroyed(ctx context.Context, s *model.Stack, c *kubernetes.Clientset) error {
 	ticker := time.NewTicker(100 * time.Millisecond)
 	timeout := time.Now().Add(300 * time.Second)
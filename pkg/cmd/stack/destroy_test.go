@@ -17,6 +17,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/okteto/okteto/pkg/k8s/cronjobs"
 	"github.com/okteto/okteto/pkg/k8s/deployments"
 	"github.com/okteto/okteto/pkg/k8s/jobs"
 	"github.com/okteto/okteto/pkg/k8s/statefulsets"
@@ -273,3 +274,68 @@ func Test_destroyJobs(t *testing.T) {
 		})
 	}
 }
+
+func Test_destroyCronJobs(t *testing.T) {
+	ctx := context.Background()
+
+	cronjob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "ns",
+			Labels:    map[string]string{model.StackNameLabel: "stack-test"},
+		},
+	}
+
+	client := fake.NewSimpleClientset(cronjob)
+	var tests = []struct {
+		name             string
+		stack            *model.Stack
+		expectedCronJobs int
+	}{
+		{
+			name: "not destroy anything",
+			stack: &model.Stack{
+				Namespace: "ns",
+				Name:      "stack-test",
+				Services: map[string]*model.Service{
+					"test": {
+						Image:         "test_image",
+						RestartPolicy: corev1.RestartPolicyOnFailure,
+						Schedule:      &model.ServiceSchedule{Cron: "@daily"},
+					},
+				},
+			},
+			expectedCronJobs: 1,
+		},
+		{
+			name: "destroy cronjob which is not scheduled anymore",
+			stack: &model.Stack{
+				Namespace: "ns",
+				Name:      "stack-test",
+				Services: map[string]*model.Service{
+					"test": {
+						Image:         "test_image",
+						RestartPolicy: corev1.RestartPolicyNever,
+					},
+				},
+			},
+			expectedCronJobs: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := destroyCronJobs(ctx, tt.stack, client)
+			if err != nil {
+				t.Fatal("Not destroyed correctly")
+			}
+			cronjobList, err := cronjobs.List(ctx, "ns", tt.stack.GetLabelSelector(), client)
+			if err != nil {
+				t.Fatal("could not retrieve list correctly")
+			}
+			if len(cronjobList) != tt.expectedCronJobs {
+				t.Fatal("Not destroyed correctly")
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/pmezard/go-difflib/difflib"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// ResourceDiff represents the differences between a live kubernetes resource and its compose translation
+type ResourceDiff struct {
+	Kind string
+	Name string
+	Diff string
+	// Reason explains the differences made by okteto on purpose, like rolling back a failed update
+	Reason string
+}
+
+// Diff returns the differences between the live kubernetes services, deployments and statefulsets of a stack
+// and the result of applying their compose translation. The translation is applied with a server-side dry run,
+// so the defaults of the cluster and the fields owned by other managers don't show up as differences.
+func Diff(ctx context.Context, s *model.Stack, servicesToDiff []string, c kubernetes.Interface) ([]ResourceDiff, error) {
+	svcNames := append([]string{}, servicesToDiff...)
+	sort.Strings(svcNames)
+
+	result := []ResourceDiff{}
+	for _, svcName := range svcNames {
+		svc, ok := s.Services[svcName]
+		if !ok {
+			continue
+		}
+		if len(svc.Ports) > 0 {
+			diff, err := diffK8sService(ctx, svcName, s, c)
+			if err != nil {
+				return nil, err
+			}
+			if diff != nil {
+				result = append(result, *diff)
+			}
+		}
+
+		var diff *ResourceDiff
+		var err error
+		switch {
+		case svc.IsDeployment():
+			diff, err = diffDeployment(ctx, svcName, s, c)
+		case svc.IsStatefulset():
+			diff, err = diffStatefulSet(ctx, svcName, s, c)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if diff != nil {
+			result = append(result, *diff)
+		}
+	}
+	return result, nil
+}
+
+// getDryRunApplyOptions returns the patch options to compute the result of applying a stack resource
+func getDryRunApplyOptions() metav1.PatchOptions {
+	force := true
+	return metav1.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        &force,
+		DryRun:       []string{metav1.DryRunAll},
+	}
+}
+
+func diffK8sService(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (*ResourceDiff, error) {
+	svcK8s := translateService(svcName, s)
+	var live runtime.Object
+	old, err := c.CoreV1().Services(s.Namespace).Get(ctx, svcName, metav1.GetOptions{})
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting service '%s': %w", svcName, err)
+		}
+	} else {
+		live = old
+	}
+
+	patch, err := getApplyPatch(svcK8s, serviceGVK)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := c.CoreV1().Services(s.Namespace).Patch(ctx, svcName, types.ApplyPatchType, patch, getDryRunApplyOptions())
+	if err != nil {
+		return nil, fmt.Errorf("error computing the changes of service '%s': %w", svcName, err)
+	}
+	return getResourceDiff(serviceGVK, svcName, live, applied)
+}
+
+func diffDeployment(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (*ResourceDiff, error) {
+	d := translateDeployment(svcName, s)
+	var live runtime.Object
+	reason := ""
+	old, err := c.AppsV1().Deployments(s.Namespace).Get(ctx, svcName, metav1.GetOptions{})
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting deployment of service '%s': %w", svcName, err)
+		}
+	} else {
+		live = old
+		reason = getRollbackReason(svcName, old.Annotations)
+		setLiveDeploymentFields(svcName, s, d, old)
+	}
+
+	patch, err := getDeploymentApplyPatch(d, hasAutoscaler(svcName, s))
+	if err != nil {
+		return nil, err
+	}
+	applied, err := c.AppsV1().Deployments(s.Namespace).Patch(ctx, svcName, types.ApplyPatchType, patch, getDryRunApplyOptions())
+	if err != nil {
+		return nil, fmt.Errorf("error computing the changes of deployment '%s': %w", svcName, err)
+	}
+	diff, err := getResourceDiff(deploymentGVK, svcName, live, applied)
+	if diff != nil {
+		diff.Reason = reason
+	}
+	return diff, err
+}
+
+func diffStatefulSet(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (*ResourceDiff, error) {
+	sfs := translateStatefulSet(svcName, s)
+	var live runtime.Object
+	reason := ""
+	old, err := c.AppsV1().StatefulSets(s.Namespace).Get(ctx, svcName, metav1.GetOptions{})
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting statefulset of service '%s': %w", svcName, err)
+		}
+	} else {
+		live = old
+		reason = getRollbackReason(svcName, old.Annotations)
+		setLiveStatefulSetFields(svcName, s, sfs, old)
+	}
+
+	patch, err := getStatefulSetApplyPatch(sfs, hasAutoscaler(svcName, s))
+	if err != nil {
+		return nil, err
+	}
+	applied, err := c.AppsV1().StatefulSets(s.Namespace).Patch(ctx, svcName, types.ApplyPatchType, patch, getDryRunApplyOptions())
+	if err != nil {
+		return nil, fmt.Errorf("error computing the changes of statefulset '%s': %w", svcName, err)
+	}
+	diff, err := getResourceDiff(statefulSetGVK, svcName, live, applied)
+	if diff != nil {
+		diff.Reason = reason
+	}
+	return diff, err
+}
+
+// getRollbackReason explains the differences of a service rolled back after a failed update
+func getRollbackReason(svcName string, annotations map[string]string) string {
+	revision, ok := annotations[model.OktetoComposeRolledBackAnnotation]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("Service '%s' was rolled back to revision %s after a failed update. Deploy the compose again to apply its definition", svcName, revision)
+}
+
+// getResourceDiff returns the unified diff between the live object and the applied one, or nil if they are equal
+func getResourceDiff(gvk schema.GroupVersionKind, name string, live, applied runtime.Object) (*ResourceDiff, error) {
+	liveYAML, err := getDiffableYAML(gvk, live)
+	if err != nil {
+		return nil, err
+	}
+	appliedYAML, err := getDiffableYAML(gvk, applied)
+	if err != nil {
+		return nil, err
+	}
+	if liveYAML == appliedYAML {
+		return nil, nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(liveYAML),
+		B:        difflib.SplitLines(appliedYAML),
+		FromFile: fmt.Sprintf("live/%s/%s", gvk.Kind, name),
+		ToFile:   fmt.Sprintf("compose/%s/%s", gvk.Kind, name),
+		Context:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ResourceDiff{Kind: gvk.Kind, Name: name, Diff: diff}, nil
+}
+
+// getDiffableYAML serializes an object without the fields that change on every apply
+func getDiffableYAML(gvk schema.GroupVersionKind, obj runtime.Object) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	obj = obj.DeepCopyObject()
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	if accessor, ok := obj.(metav1.Object); ok {
+		accessor.SetManagedFields(nil)
+		accessor.SetResourceVersion("")
+		accessor.SetGeneration(0)
+	}
+	bytes, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func Test_Diff(t *testing.T) {
+	ctx := context.Background()
+	s := &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api": {Image: "api:v2", Replicas: 1, RestartPolicy: apiv1.RestartPolicyAlways},
+		},
+	}
+	live := translateDeployment("api", s)
+	live.Spec.Template.Spec.Containers[0].Image = "api:v1"
+	live.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate}}
+
+	c := fake.NewSimpleClientset(live)
+	var dryRun runtime.Object = translateDeployment("api", s)
+	c.Fake.PrependReactor("patch", "deployments", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, dryRun, nil
+	})
+
+	diffs, err := Diff(ctx, s, []string{"api"}, c)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 1)
+	assert.Equal(t, "Deployment", diffs[0].Kind)
+	assert.Equal(t, "api", diffs[0].Name)
+	assert.True(t, strings.Contains(diffs[0].Diff, "--- live/Deployment/api"))
+	removed, added := []string{}, []string{}
+	for _, line := range strings.Split(diffs[0].Diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---"):
+			removed = append(removed, strings.TrimSpace(strings.TrimPrefix(line, "-")))
+		case strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++"):
+			added = append(added, strings.TrimSpace(strings.TrimPrefix(line, "+")))
+		}
+	}
+	assert.Len(t, removed, 1)
+	assert.True(t, strings.HasSuffix(removed[0], "image: api:v1"))
+	assert.Len(t, added, 1)
+	assert.True(t, strings.HasSuffix(added[0], "image: api:v2"))
+	assert.False(t, strings.Contains(diffs[0].Diff, "managedFields"))
+
+	dryRun = live
+	diffs, err = Diff(ctx, s, []string{"api"}, c)
+	assert.NoError(t, err)
+	assert.Empty(t, diffs)
+}
+
+func Test_DiffRolledBack(t *testing.T) {
+	ctx := context.Background()
+	s := &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api": {Image: "api:v2", Replicas: 1, RestartPolicy: apiv1.RestartPolicyAlways},
+		},
+	}
+	live := translateDeployment("api", s)
+	live.Spec.Template.Spec.Containers[0].Image = "api:v1"
+	live.Annotations[model.OktetoComposeRolledBackAnnotation] = "1"
+
+	c := fake.NewSimpleClientset(live)
+	c.Fake.PrependReactor("patch", "deployments", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+		return true, translateDeployment("api", s), nil
+	})
+
+	diffs, err := Diff(ctx, s, []string{"api"}, c)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 1)
+	assert.True(t, strings.Contains(diffs[0].Reason, "rolled back to revision 1"))
+	assert.True(t, strings.Contains(diffs[0].Diff, model.OktetoComposeRolledBackAnnotation))
+}
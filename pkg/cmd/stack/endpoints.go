@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/okteto/okteto/pkg/k8s/ingresses"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
@@ -45,5 +46,30 @@ func ListEndpoints(ctx context.Context, stack *model.Stack) error {
 		})
 		oktetoLog.Information("Endpoints available:\n  - %s\n", strings.Join(endpointList, "\n  - "))
 	}
+	if scheduled := getScheduledServicesInfo(stack, time.Now().UTC()); len(scheduled) > 0 {
+		oktetoLog.Information("Scheduled services:\n  - %s\n", strings.Join(scheduled, "\n  - "))
+	}
 	return nil
 }
+
+// getScheduledServicesInfo returns the schedule and the next run of the scheduled services of a stack
+func getScheduledServicesInfo(stack *model.Stack, now time.Time) []string {
+	result := []string{}
+	for svcName, svc := range stack.Services {
+		if !svc.IsCronJob() {
+			continue
+		}
+		if svc.Schedule.Suspend {
+			result = append(result, fmt.Sprintf("%s: '%s' (suspended)", svcName, svc.Schedule.Cron))
+			continue
+		}
+		next, err := svc.Schedule.NextRun(now)
+		if err != nil || next.IsZero() {
+			result = append(result, fmt.Sprintf("%s: '%s'", svcName, svc.Schedule.Cron))
+			continue
+		}
+		result = append(result, fmt.Sprintf("%s: '%s' (next run at %s)", svcName, svc.Schedule.Cron, next.Format(time.RFC1123)))
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/okteto/okteto/pkg/model"
+)
+
+const (
+	// GraphFormatDot renders the dependency graph in the graphviz DOT language
+	GraphFormatDot = "dot"
+
+	// GraphFormatMermaid renders the dependency graph as a mermaid flowchart
+	GraphFormatMermaid = "mermaid"
+)
+
+// RenderDependencyGraph renders the depends_on relations between the services of a stack.
+// Edges go from a service to each of its dependencies.
+func RenderDependencyGraph(s *model.Stack, format string) (string, error) {
+	svcNames := []string{}
+	for svcName := range s.Services {
+		svcNames = append(svcNames, svcName)
+	}
+	sort.Strings(svcNames)
+
+	switch format {
+	case GraphFormatDot:
+		return renderDotGraph(s, svcNames), nil
+	case GraphFormatMermaid:
+		return renderMermaidGraph(s, svcNames), nil
+	default:
+		return "", fmt.Errorf("graph format '%s' is not supported. Value must be one of: ['%s', '%s']", format, GraphFormatDot, GraphFormatMermaid)
+	}
+}
+
+func renderDotGraph(s *model.Stack, svcNames []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", s.Name)
+	for _, svcName := range svcNames {
+		fmt.Fprintf(&b, "  %q [label=%q];\n", svcName, getGraphNodeLabel(svcName, s.Services[svcName]))
+	}
+	for _, svcName := range svcNames {
+		svc := s.Services[svcName]
+		for _, dependency := range getSortedDependencies(svc) {
+			fmt.Fprintf(&b, "  %q -> %q [label=%q];\n", svcName, dependency, getGraphEdgeLabel(svc.DependsOn[dependency]))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func renderMermaidGraph(s *model.Stack, svcNames []string) string {
+	// service names can contain characters with special meaning in mermaid, so nodes use generated ids
+	ids := map[string]string{}
+	for i, svcName := range svcNames {
+		ids[svcName] = fmt.Sprintf("svc%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, svcName := range svcNames {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[svcName], getGraphNodeLabel(svcName, s.Services[svcName]))
+	}
+	for _, svcName := range svcNames {
+		svc := s.Services[svcName]
+		for _, dependency := range getSortedDependencies(svc) {
+			fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[svcName], getGraphEdgeLabel(svc.DependsOn[dependency]), ids[dependency])
+		}
+	}
+	return b.String()
+}
+
+func getSortedDependencies(svc *model.Service) []string {
+	result := []string{}
+	for dependency := range svc.DependsOn {
+		result = append(result, dependency)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func getGraphNodeLabel(svcName string, svc *model.Service) string {
+	kind := "deployment"
+	switch {
+	case svc.IsCronJob():
+		kind = "cronjob"
+	case svc.IsJob():
+		kind = "job"
+	case svc.IsStatefulset():
+		kind = "statefulset"
+	}
+	return fmt.Sprintf("%s (%s)", svcName, kind)
+}
+
+func getGraphEdgeLabel(condition model.DependsOnConditionSpec) string {
+	parts := []string{string(condition.Condition)}
+	if condition.Timeout > 0 {
+		parts = append(parts, fmt.Sprintf("timeout %s", condition.Timeout.String()))
+	}
+	if condition.Restart {
+		parts = append(parts, "restart")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func Test_RenderDependencyGraph(t *testing.T) {
+	s := &model.Stack{
+		Name: "stack-test",
+		Services: map[string]*model.Service{
+			"api": {
+				RestartPolicy: apiv1.RestartPolicyAlways,
+				DependsOn: model.DependsOn{
+					"db":         {Condition: model.DependsOnServiceHealthy, Restart: true, Timeout: 2 * time.Minute},
+					"migrations": {Condition: model.DependsOnServiceCompleted},
+				},
+			},
+			"db":         {RestartPolicy: apiv1.RestartPolicyAlways, Volumes: []model.StackVolume{{RemotePath: "/data"}}},
+			"migrations": {RestartPolicy: apiv1.RestartPolicyNever},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{
+			name:   "dot",
+			format: GraphFormatDot,
+			expected: `digraph "stack-test" {
+  "api" [label="api (deployment)"];
+  "db" [label="db (statefulset)"];
+  "migrations" [label="migrations (job)"];
+  "api" -> "db" [label="service_healthy, timeout 2m0s, restart"];
+  "api" -> "migrations" [label="service_completed_successfully"];
+}
+`,
+		},
+		{
+			name:   "mermaid",
+			format: GraphFormatMermaid,
+			expected: `flowchart TD
+  svc0["api (deployment)"]
+  svc1["db (statefulset)"]
+  svc2["migrations (job)"]
+  svc0 -->|service_healthy, timeout 2m0s, restart| svc1
+  svc0 -->|service_completed_successfully| svc2
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderDependencyGraph(s, tt.format)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	_, err := RenderDependencyGraph(s, "svg")
+	assert.Error(t, err)
+}
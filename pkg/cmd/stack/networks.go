@@ -0,0 +1,329 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/k8s/services"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const dnsPort = 53
+
+// getNetworkLabel returns the pod label of the services attached to a network
+func getNetworkLabel(networkName string) string {
+	return fmt.Sprintf("%s-%s", model.StackNetworkNameLabel, networkName)
+}
+
+func getNetworkPolicyName(networkName string, s *model.Stack) string {
+	return fmt.Sprintf("%s-network-%s", s.Name, networkName)
+}
+
+func getPublicNetworkPolicyName(svcName string, s *model.Stack) string {
+	return fmt.Sprintf("%s-public-%s", s.Name, svcName)
+}
+
+func translateNetworkSelector(networkName string, s *model.Stack) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			model.StackNameLabel:         s.Name,
+			getNetworkLabel(networkName): "true",
+		},
+	}
+}
+
+// translateNetworkPolicy allows the traffic between the services attached to a network.
+// The services of internal networks can only reach the services of the network and the cluster DNS
+func translateNetworkPolicy(networkName string, s *model.Stack) *networkingv1.NetworkPolicy {
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getNetworkPolicyName(networkName, s),
+			Namespace: s.Namespace,
+			Labels: map[string]string{
+				model.StackNameLabel:        s.Name,
+				model.StackNetworkNameLabel: networkName,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: *translateNetworkSelector(networkName, s),
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: []networkingv1.NetworkPolicyPeer{{PodSelector: translateNetworkSelector(networkName, s)}},
+				},
+			},
+		},
+	}
+
+	if network := s.Networks[networkName]; network == nil || !network.Internal {
+		// policies are additive: services attached to internal and non internal networks keep their egress traffic
+		policy.Spec.Egress = []networkingv1.NetworkPolicyEgressRule{{}}
+		return policy
+	}
+
+	udp := apiv1.ProtocolUDP
+	tcp := apiv1.ProtocolTCP
+	port := intstr.FromInt(dnsPort)
+	policy.Spec.Egress = []networkingv1.NetworkPolicyEgressRule{
+		{
+			To: []networkingv1.NetworkPolicyPeer{{PodSelector: translateNetworkSelector(networkName, s)}},
+		},
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &udp, Port: &port},
+				{Protocol: &tcp, Port: &port},
+			},
+		},
+	}
+	return policy
+}
+
+// getSvcExposedPorts returns the ports of a service reachable from outside of its networks: its public ports and the ports of its endpoints
+func getSvcExposedPorts(svcName string, s *model.Stack) []networkingv1.NetworkPolicyPort {
+	added := map[int32]bool{}
+	result := []networkingv1.NetworkPolicyPort{}
+	addPort := func(port int32, protocol apiv1.Protocol) {
+		if added[port] {
+			return
+		}
+		added[port] = true
+		if protocol == "" {
+			protocol = apiv1.ProtocolTCP
+		}
+		p := intstr.FromInt(int(port))
+		result = append(result, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &p})
+	}
+
+	for _, p := range getSvcPublicPorts(svcName, s) {
+		addPort(p.ContainerPort, p.Protocol)
+	}
+
+	endpointNames := make([]string, 0, len(s.Endpoints))
+	for name := range s.Endpoints {
+		endpointNames = append(endpointNames, name)
+	}
+	sort.Strings(endpointNames)
+	for _, name := range endpointNames {
+		for _, rule := range s.Endpoints[name].Rules {
+			if rule.Service == svcName {
+				addPort(rule.Port, apiv1.ProtocolTCP)
+			}
+		}
+	}
+	return result
+}
+
+// translatePublicNetworkPolicy allows the traffic from any namespace to the public ports and endpoints of a service,
+// so the ingress controller can still reach them once the service is isolated by its networks
+func translatePublicNetworkPolicy(svcName string, s *model.Stack) *networkingv1.NetworkPolicy {
+	ports := getSvcExposedPorts(svcName, s)
+	if len(ports) == 0 {
+		return nil
+	}
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getPublicNetworkPolicyName(svcName, s),
+			Namespace: s.Namespace,
+			Labels: map[string]string{
+				model.StackNameLabel:        s.Name,
+				model.StackServiceNameLabel: svcName,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: translateLabelSelector(svcName, s)},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From:  []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}},
+					Ports: ports,
+				},
+			},
+		},
+	}
+}
+
+// translateNetworkPolicies returns the network policies of the stack.
+// Stacks without networks don't have network policies: all their services can reach each other
+func translateNetworkPolicies(s *model.Stack) []*networkingv1.NetworkPolicy {
+	result := []*networkingv1.NetworkPolicy{}
+	if len(s.Networks) == 0 {
+		return result
+	}
+
+	networkNames := make([]string, 0, len(s.Networks))
+	for name := range s.Networks {
+		networkNames = append(networkNames, name)
+	}
+	sort.Strings(networkNames)
+	for _, name := range networkNames {
+		result = append(result, translateNetworkPolicy(name, s))
+	}
+
+	svcNames := make([]string, 0, len(s.Services))
+	for name := range s.Services {
+		svcNames = append(svcNames, name)
+	}
+	sort.Strings(svcNames)
+	for _, name := range svcNames {
+		if policy := translatePublicNetworkPolicy(name, s); policy != nil {
+			result = append(result, policy)
+		}
+	}
+	return result
+}
+
+// getSvcAliases returns the network aliases of a service
+func getSvcAliases(svcName string, s *model.Stack) []string {
+	aliasesSet := map[string]bool{}
+	for _, network := range s.Services[svcName].Networks {
+		if network == nil {
+			continue
+		}
+		for _, alias := range network.Aliases {
+			if alias != svcName {
+				aliasesSet[alias] = true
+			}
+		}
+	}
+	result := []string{}
+	for alias := range aliasesSet {
+		result = append(result, alias)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// translateAliasService returns a kubernetes service that resolves a network alias to the pods of a service
+func translateAliasService(alias, svcName string, s *model.Stack) *apiv1.Service {
+	svcK8s := translateService(svcName, s)
+	svcK8s.Name = alias
+	svcK8s.Labels[model.StackNetworkAliasLabel] = alias
+	return svcK8s
+}
+
+func deployNetworkPolicies(ctx context.Context, s *model.Stack, c kubernetes.Interface) error {
+	for _, policy := range translateNetworkPolicies(s) {
+		old, err := c.NetworkingV1().NetworkPolicies(s.Namespace).Get(ctx, policy.Name, metav1.GetOptions{})
+		if err != nil && !oktetoErrors.IsNotFound(err) {
+			return fmt.Errorf("error getting network policy '%s': %w", policy.Name, err)
+		}
+		if old == nil || old.Name == "" {
+			if _, err := c.NetworkingV1().NetworkPolicies(s.Namespace).Create(ctx, policy, metav1.CreateOptions{}); err != nil {
+				return fmt.Errorf("error creating network policy '%s': %w", policy.Name, err)
+			}
+			oktetoLog.Infof("network policy '%s' created", policy.Name)
+			continue
+		}
+		if err := checkStackNameCollision("network policy", policy.Name, old.Labels, s); err != nil {
+			oktetoLog.Warning(err.Error())
+			continue
+		}
+		policy.ResourceVersion = old.ResourceVersion
+		if _, err := c.NetworkingV1().NetworkPolicies(s.Namespace).Update(ctx, policy, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("error updating network policy '%s': %w", policy.Name, err)
+		}
+		oktetoLog.Infof("network policy '%s' updated", policy.Name)
+	}
+	return nil
+}
+
+func deployAliasService(ctx context.Context, alias, svcName string, s *model.Stack, c kubernetes.Interface) error {
+	svcK8s := translateAliasService(alias, svcName, s)
+	old, err := services.Get(ctx, alias, s.Namespace, c)
+	if err != nil && !oktetoErrors.IsNotFound(err) {
+		return fmt.Errorf("error getting service '%s': %w", alias, err)
+	}
+	var managedFields []metav1.ManagedFieldsEntry
+	if err == nil {
+		managedFields = old.ManagedFields
+		if err := checkStackNameCollision("kubernetes service", alias, old.Labels, s); err != nil {
+			oktetoLog.Warning(err.Error())
+			return nil
+		}
+		if old.Labels[model.StackNetworkAliasLabel] == "" {
+			oktetoLog.Warning("skipping deploy of alias '%s' due to name collision with service '%s'", alias, alias)
+			return nil
+		}
+	}
+	if err := applyK8sService(ctx, svcK8s, managedFields, c); err != nil {
+		return err
+	}
+	oktetoLog.Success("Alias '%s' of service '%s' deployed", alias, svcName)
+	return nil
+}
+
+// getStackAliases returns the service of each network alias with a kubernetes service
+func getStackAliases(s *model.Stack) map[string]string {
+	result := map[string]string{}
+	for svcName, svc := range s.Services {
+		if len(svc.Ports) == 0 {
+			continue
+		}
+		for _, alias := range getSvcAliases(svcName, s) {
+			result[alias] = svcName
+		}
+	}
+	return result
+}
+
+// destroyNetworks destroys the network policies and alias services created by the stack that are no longer part of it
+func destroyNetworks(ctx context.Context, s *model.Stack, c kubernetes.Interface) error {
+	policiesSet := map[string]bool{}
+	for _, policy := range translateNetworkPolicies(s) {
+		policiesSet[policy.Name] = true
+	}
+	policies, err := c.NetworkingV1().NetworkPolicies(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: s.GetLabelSelector()})
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies.Items {
+		if policiesSet[policy.Name] {
+			continue
+		}
+		if err := c.NetworkingV1().NetworkPolicies(policy.Namespace).Delete(ctx, policy.Name, metav1.DeleteOptions{}); err != nil && !oktetoErrors.IsNotFound(err) {
+			return fmt.Errorf("error destroying network policy '%s': %w", policy.Name, err)
+		}
+		if name := policy.Labels[model.StackNetworkNameLabel]; name != "" {
+			oktetoLog.Success("Network '%s' destroyed", name)
+		} else {
+			oktetoLog.Infof("network policy '%s' destroyed", policy.Name)
+		}
+	}
+
+	aliases := getStackAliases(s)
+	svcList, err := services.List(ctx, s.Namespace, getStackLabelSelectorWith(model.StackNetworkAliasLabel, s), c)
+	if err != nil {
+		return err
+	}
+	for i := range svcList {
+		if _, ok := aliases[svcList[i].Name]; ok {
+			continue
+		}
+		if err := services.Destroy(ctx, svcList[i].Name, svcList[i].Namespace, c); err != nil {
+			return fmt.Errorf("error destroying alias '%s': %s", svcList[i].Name, err)
+		}
+		oktetoLog.Success("Alias '%s' destroyed", svcList[i].Name)
+	}
+	return nil
+}
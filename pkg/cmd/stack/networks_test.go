@@ -0,0 +1,153 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newNetworksTestStack() *model.Stack {
+	return &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Networks: map[string]*model.StackNetwork{
+			"front": {},
+			"back":  {Internal: true},
+		},
+		Services: map[string]*model.Service{
+			"web": {
+				Image:         "web",
+				RestartPolicy: apiv1.RestartPolicyAlways,
+				Replicas:      1,
+				Ports:         []model.Port{{HostPort: 80, ContainerPort: 8080, Protocol: apiv1.ProtocolTCP}},
+				Networks:      map[string]*model.ServiceNetwork{"front": {}},
+			},
+			"api": {
+				Image:         "api",
+				RestartPolicy: apiv1.RestartPolicyAlways,
+				Replicas:      1,
+				Ports:         []model.Port{{ContainerPort: 3000, Protocol: apiv1.ProtocolTCP}},
+				Networks: map[string]*model.ServiceNetwork{
+					"front": {Aliases: []string{"backend", "api"}},
+					"back":  {},
+				},
+			},
+			"db": {
+				Image:         "postgres",
+				RestartPolicy: apiv1.RestartPolicyAlways,
+				Replicas:      1,
+				Ports:         []model.Port{{ContainerPort: 5432, Protocol: apiv1.ProtocolTCP}},
+				Networks:      map[string]*model.ServiceNetwork{"back": {}},
+			},
+		},
+	}
+}
+
+func Test_translateNetworkPolicies(t *testing.T) {
+	s := newNetworksTestStack()
+
+	policies := translateNetworkPolicies(s)
+	names := []string{}
+	for _, policy := range policies {
+		names = append(names, policy.Name)
+	}
+	assert.Equal(t, []string{"stack-test-network-back", "stack-test-network-front", "stack-test-public-web"}, names)
+
+	back := policies[0]
+	backSelector := map[string]string{model.StackNameLabel: "stack-test", "stack.okteto.com/network-back": "true"}
+	assert.Equal(t, map[string]string{model.StackNameLabel: "stack-test", model.StackNetworkNameLabel: "back"}, back.Labels)
+	assert.Equal(t, backSelector, back.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, backSelector, back.Spec.Ingress[0].From[0].PodSelector.MatchLabels)
+	assert.Len(t, back.Spec.Egress, 2)
+	assert.Equal(t, backSelector, back.Spec.Egress[0].To[0].PodSelector.MatchLabels)
+	assert.Equal(t, int32(53), back.Spec.Egress[1].Ports[0].Port.IntVal)
+
+	front := policies[1]
+	assert.Equal(t, []networkingv1.NetworkPolicyEgressRule{{}}, front.Spec.Egress)
+
+	public := policies[2]
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, public.Spec.PolicyTypes)
+	assert.Len(t, public.Spec.Ingress[0].Ports, 1)
+	assert.Equal(t, int32(8080), public.Spec.Ingress[0].Ports[0].Port.IntVal)
+
+	labels := translateLabels("api", s)
+	assert.Equal(t, "true", labels["stack.okteto.com/network-front"])
+	assert.Equal(t, "true", labels["stack.okteto.com/network-back"])
+
+	s.Networks = nil
+	assert.Empty(t, translateNetworkPolicies(s))
+}
+
+func Test_translateAliasService(t *testing.T) {
+	s := newNetworksTestStack()
+
+	assert.Equal(t, []string{"backend"}, getSvcAliases("api", s))
+	assert.Empty(t, getSvcAliases("web", s))
+
+	svc := translateAliasService("backend", "api", s)
+	assert.Equal(t, "backend", svc.Name)
+	assert.Equal(t, "backend", svc.Labels[model.StackNetworkAliasLabel])
+	assert.Equal(t, translateLabelSelector("api", s), svc.Spec.Selector)
+	assert.Equal(t, translateServicePorts(*s.Services["api"]), svc.Spec.Ports)
+}
+
+func Test_deployAndDestroyNetworks(t *testing.T) {
+	ctx := context.Background()
+	s := newNetworksTestStack()
+	foreign := &apiv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "ns"},
+	}
+	c := newFakeClientset(foreign)
+
+	assert.NoError(t, deployNetworkPolicies(ctx, s, c))
+	policies, err := c.NetworkingV1().NetworkPolicies("ns").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, policies.Items, 3)
+
+	assert.NoError(t, deployAliasService(ctx, "backend", "api", s, c))
+	svc, err := c.CoreV1().Services("ns").Get(ctx, "backend", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, svc.Labels)
+
+	assert.NoError(t, c.CoreV1().Services("ns").Delete(ctx, "backend", metav1.DeleteOptions{}))
+	assert.NoError(t, deployAliasService(ctx, "backend", "api", s, c))
+	svc, err = c.CoreV1().Services("ns").Get(ctx, "backend", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "stack-test", svc.Labels[model.StackNameLabel])
+
+	delete(s.Networks, "back")
+	s.Services["api"].Networks = map[string]*model.ServiceNetwork{"front": {}}
+	delete(s.Services, "db")
+	assert.NoError(t, destroyNetworks(ctx, s, c))
+	_, err = c.NetworkingV1().NetworkPolicies("ns").Get(ctx, "stack-test-network-back", metav1.GetOptions{})
+	assert.Error(t, err)
+	_, err = c.NetworkingV1().NetworkPolicies("ns").Get(ctx, "stack-test-network-front", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = c.CoreV1().Services("ns").Get(ctx, "backend", metav1.GetOptions{})
+	assert.Error(t, err)
+
+	s.Services = nil
+	s.Networks = nil
+	assert.NoError(t, destroyNetworks(ctx, s, c))
+	policies, err = c.NetworkingV1().NetworkPolicies("ns").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, policies.Items)
+}
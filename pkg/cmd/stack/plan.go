@@ -0,0 +1,476 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/k8s/cronjobs"
+	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/k8s/ingresses"
+	"github.com/okteto/okteto/pkg/k8s/jobs"
+	"github.com/okteto/okteto/pkg/k8s/services"
+	"github.com/okteto/okteto/pkg/k8s/statefulsets"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PlanAction represents what a deploy would do with a resource
+type PlanAction string
+
+const (
+	// PlanActionCreate means the resource doesn't exist and would be created
+	PlanActionCreate PlanAction = "create"
+
+	// PlanActionUpdate means the resource exists and would be updated
+	PlanActionUpdate PlanAction = "update"
+
+	// PlanActionDelete means the resource is no longer part of the stack and would be deleted
+	PlanActionDelete PlanAction = "delete"
+
+	// PlanActionSkip means the resource would not be deployed due to a name collision
+	PlanActionSkip PlanAction = "skip"
+)
+
+// PlannedResource represents a kubernetes resource affected by a stack deploy
+type PlannedResource struct {
+	Kind   string     `json:"kind"`
+	Name   string     `json:"name"`
+	Action PlanAction `json:"action"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// Plan returns the resources that deploying the stack would create, update or delete.
+// It only reads from the cluster.
+func Plan(ctx context.Context, s *model.Stack, servicesToDeploy []string, c kubernetes.Interface) ([]PlannedResource, error) {
+	result := []PlannedResource{}
+
+	svcNames := append([]string{}, servicesToDeploy...)
+	sort.Strings(svcNames)
+	servicesToDeploySet := map[string]bool{}
+	for _, svcName := range svcNames {
+		servicesToDeploySet[svcName] = true
+	}
+
+	iClient, err := ingresses.GetClient(c)
+	if err != nil {
+		oktetoLog.Infof("could not get ingress client, endpoints won't be planned: %s", err)
+		iClient = nil
+	}
+
+	for _, svcName := range svcNames {
+		svc, ok := s.Services[svcName]
+		if !ok {
+			continue
+		}
+		if len(svc.Ports) > 0 {
+			planned, err := planK8sService(ctx, svcName, s, c)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, planned)
+			for _, alias := range getSvcAliases(svcName, s) {
+				planned, err := planK8sService(ctx, alias, s, c)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, planned)
+			}
+			if iClient != nil {
+				for _, name := range getSvcIngressNames(svcName, s) {
+					planned, err := planIngress(ctx, name, s, iClient)
+					if err != nil {
+						return nil, err
+					}
+					result = append(result, planned)
+				}
+			}
+		}
+
+		planned, err := planWorkload(ctx, svcName, s, c)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, planned)
+
+		if hasAutoscaler(svcName, s) {
+			planned, err := planHorizontalPodAutoscaler(ctx, svcName, s, c)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, planned)
+		}
+		if hasDisruptionBudget(svcName, s) {
+			planned, err := planPodDisruptionBudget(ctx, svcName, s, c)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, planned)
+		}
+	}
+
+	volumeNames := getVolumesToDeployFromServicesToDeploy(s, servicesToDeploySet)
+	sort.Strings(volumeNames)
+	for _, volumeName := range volumeNames {
+		planned, err := planVolume(ctx, volumeName, s, c)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, planned)
+	}
+
+	configNames, secretNames := getConfigsToDeployFromServicesToDeploy(s, servicesToDeploySet)
+	for _, name := range configNames {
+		planned, err := planConfig(ctx, name, s, c)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, planned)
+	}
+	for _, name := range secretNames {
+		planned, err := planSecret(ctx, name, s, c)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, planned)
+	}
+
+	for _, policy := range translateNetworkPolicies(s) {
+		planned, err := planNetworkPolicy(ctx, policy.Name, s, c)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, planned)
+	}
+
+	if iClient != nil {
+		endpointNames := getEndpointsToDeployFromServicesToDeploy(s.Endpoints, servicesToDeploySet)
+		sort.Strings(endpointNames)
+		for _, endpointName := range endpointNames {
+			planned, err := planIngress(ctx, endpointName, s, iClient)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, planned)
+		}
+	}
+
+	toDelete, err := planResourcesNotInStack(ctx, s, c, iClient)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, toDelete...)
+	return result, nil
+}
+
+func planK8sService(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (PlannedResource, error) {
+	planned := PlannedResource{Kind: "Service", Name: svcName}
+	old, err := services.Get(ctx, svcName, s.Namespace, c)
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return planned, fmt.Errorf("error getting service '%s': %w", svcName, err)
+		}
+		planned.Action = PlanActionCreate
+		return planned, nil
+	}
+	planned.Action, planned.Reason = getPlanActionForExisting(old.Labels, s, "service")
+	return planned, nil
+}
+
+func planWorkload(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (PlannedResource, error) {
+	var (
+		old metav1.Object
+		err error
+	)
+	planned := PlannedResource{Name: svcName}
+	svc := s.Services[svcName]
+	switch {
+	case svc.IsCronJob():
+		planned.Kind = "CronJob"
+		old, err = cronjobs.Get(ctx, svcName, s.Namespace, c)
+	case svc.IsJob():
+		planned.Kind = "Job"
+		old, err = c.BatchV1().Jobs(s.Namespace).Get(ctx, svcName, metav1.GetOptions{})
+	case len(svc.Volumes) == 0:
+		planned.Kind = "Deployment"
+		old, err = deployments.Get(ctx, svcName, s.Namespace, c)
+	default:
+		planned.Kind = "StatefulSet"
+		old, err = statefulsets.Get(ctx, svcName, s.Namespace, c)
+	}
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return planned, fmt.Errorf("error getting %s of service '%s': %w", strings.ToLower(planned.Kind), svcName, err)
+		}
+		planned.Action = PlanActionCreate
+		return planned, nil
+	}
+	planned.Action, planned.Reason = getPlanActionForExisting(old.GetLabels(), s, strings.ToLower(planned.Kind))
+	return planned, nil
+}
+
+func planVolume(ctx context.Context, volumeName string, s *model.Stack, c kubernetes.Interface) (PlannedResource, error) {
+	pvc := translatePersistentVolumeClaim(volumeName, s)
+	planned := PlannedResource{Kind: "PersistentVolumeClaim", Name: pvc.Name}
+	old, err := c.CoreV1().PersistentVolumeClaims(s.Namespace).Get(ctx, pvc.Name, metav1.GetOptions{})
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return planned, fmt.Errorf("error getting volume '%s': %w", pvc.Name, err)
+		}
+		planned.Action = PlanActionCreate
+		return planned, nil
+	}
+	planned.Action, planned.Reason = getPlanActionForExisting(old.Labels, s, "volume")
+	return planned, nil
+}
+
+func planConfig(ctx context.Context, name string, s *model.Stack, c kubernetes.Interface) (PlannedResource, error) {
+	objectName := getConfigObjectName(name, s.Configs[name], s)
+	planned := PlannedResource{Kind: "ConfigMap", Name: objectName}
+	old, err := c.CoreV1().ConfigMaps(s.Namespace).Get(ctx, objectName, metav1.GetOptions{})
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return planned, fmt.Errorf("error getting config '%s': %w", name, err)
+		}
+		planned.Action = PlanActionCreate
+		return planned, nil
+	}
+	planned.Action, planned.Reason = getPlanActionForExisting(old.Labels, s, "config")
+	return planned, nil
+}
+
+func planSecret(ctx context.Context, name string, s *model.Stack, c kubernetes.Interface) (PlannedResource, error) {
+	objectName := getConfigObjectName(name, s.Secrets[name], s)
+	planned := PlannedResource{Kind: "Secret", Name: objectName}
+	old, err := c.CoreV1().Secrets(s.Namespace).Get(ctx, objectName, metav1.GetOptions{})
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return planned, fmt.Errorf("error getting secret '%s': %w", name, err)
+		}
+		planned.Action = PlanActionCreate
+		return planned, nil
+	}
+	planned.Action, planned.Reason = getPlanActionForExisting(old.Labels, s, "secret")
+	return planned, nil
+}
+
+func planNetworkPolicy(ctx context.Context, name string, s *model.Stack, c kubernetes.Interface) (PlannedResource, error) {
+	planned := PlannedResource{Kind: "NetworkPolicy", Name: name}
+	old, err := c.NetworkingV1().NetworkPolicies(s.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return planned, fmt.Errorf("error getting network policy '%s': %w", name, err)
+		}
+		planned.Action = PlanActionCreate
+		return planned, nil
+	}
+	planned.Action, planned.Reason = getPlanActionForExisting(old.Labels, s, "network policy")
+	return planned, nil
+}
+
+func planHorizontalPodAutoscaler(ctx context.Context, name string, s *model.Stack, c kubernetes.Interface) (PlannedResource, error) {
+	planned := PlannedResource{Kind: "HorizontalPodAutoscaler", Name: name}
+	old, err := c.AutoscalingV2().HorizontalPodAutoscalers(s.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return planned, fmt.Errorf("error getting autoscaler '%s': %w", name, err)
+		}
+		planned.Action = PlanActionCreate
+		return planned, nil
+	}
+	planned.Action, planned.Reason = getPlanActionForExisting(old.Labels, s, "autoscaler")
+	return planned, nil
+}
+
+func planPodDisruptionBudget(ctx context.Context, name string, s *model.Stack, c kubernetes.Interface) (PlannedResource, error) {
+	planned := PlannedResource{Kind: "PodDisruptionBudget", Name: name}
+	old, err := c.PolicyV1().PodDisruptionBudgets(s.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return planned, fmt.Errorf("error getting disruption budget '%s': %w", name, err)
+		}
+		planned.Action = PlanActionCreate
+		return planned, nil
+	}
+	planned.Action, planned.Reason = getPlanActionForExisting(old.Labels, s, "disruption budget")
+	return planned, nil
+}
+
+func planIngress(ctx context.Context, name string, s *model.Stack, iClient *ingresses.Client) (PlannedResource, error) {
+	planned := PlannedResource{Kind: "Ingress", Name: name}
+	old, err := iClient.Get(ctx, name, s.Namespace)
+	if err != nil {
+		if !oktetoErrors.IsNotFound(err) {
+			return planned, fmt.Errorf("error getting ingress '%s': %w", name, err)
+		}
+		planned.Action = PlanActionCreate
+		return planned, nil
+	}
+	planned.Action, planned.Reason = getPlanActionForExisting(old.GetLabels(), s, "ingress")
+	return planned, nil
+}
+
+// getPlanActionForExisting mimics the name collision checks done when deploying a stack
+func getPlanActionForExisting(labels map[string]string, s *model.Stack, kind string) (PlanAction, string) {
+	stackName := labels[model.StackNameLabel]
+	if stackName == "" {
+		return PlanActionSkip, fmt.Sprintf("name collision with pre-existing %s", kind)
+	}
+	if stackName != s.Name && stackName != "okteto" {
+		return PlanActionSkip, fmt.Sprintf("name collision with %s in compose '%s'", kind, stackName)
+	}
+	return PlanActionUpdate, ""
+}
+
+// planResourcesNotInStack returns the resources that destroyServicesNotInStack would delete
+func planResourcesNotInStack(ctx context.Context, s *model.Stack, c kubernetes.Interface, iClient *ingresses.Client) ([]PlannedResource, error) {
+	result := []PlannedResource{}
+
+	dList, err := deployments.List(ctx, s.Namespace, s.GetLabelSelector(), c)
+	if err != nil {
+		return nil, err
+	}
+	for i := range dList {
+		if svc, ok := s.Services[dList[i].Name]; ok && svc.IsDeployment() {
+			continue
+		}
+		result = append(result, PlannedResource{Kind: "Deployment", Name: dList[i].Name, Action: PlanActionDelete})
+	}
+
+	sfsList, err := statefulsets.List(ctx, s.Namespace, s.GetLabelSelector(), c)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sfsList {
+		if svc, ok := s.Services[sfsList[i].Name]; ok && svc.IsStatefulset() {
+			continue
+		}
+		result = append(result, PlannedResource{Kind: "StatefulSet", Name: sfsList[i].Name, Action: PlanActionDelete})
+	}
+
+	jobsList, err := jobs.List(ctx, s.Namespace, s.GetLabelSelector(), c)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobsList {
+		if svc, ok := s.Services[jobsList[i].Name]; ok && svc.IsJob() {
+			continue
+		}
+		result = append(result, PlannedResource{Kind: "Job", Name: jobsList[i].Name, Action: PlanActionDelete})
+	}
+
+	cronjobList, err := cronjobs.List(ctx, s.Namespace, s.GetLabelSelector(), c)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cronjobList {
+		if svc, ok := s.Services[cronjobList[i].Name]; ok && svc.IsCronJob() {
+			continue
+		}
+		result = append(result, PlannedResource{Kind: "CronJob", Name: cronjobList[i].Name, Action: PlanActionDelete})
+	}
+
+	hpaList, err := c.AutoscalingV2().HorizontalPodAutoscalers(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: s.GetLabelSelector()})
+	if err != nil {
+		return nil, err
+	}
+	for _, hpa := range hpaList.Items {
+		if !hasAutoscaler(hpa.Name, s) {
+			result = append(result, PlannedResource{Kind: "HorizontalPodAutoscaler", Name: hpa.Name, Action: PlanActionDelete})
+		}
+	}
+
+	pdbList, err := c.PolicyV1().PodDisruptionBudgets(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: s.GetLabelSelector()})
+	if err != nil {
+		return nil, err
+	}
+	for _, pdb := range pdbList.Items {
+		if !hasDisruptionBudget(pdb.Name, s) {
+			result = append(result, PlannedResource{Kind: "PodDisruptionBudget", Name: pdb.Name, Action: PlanActionDelete})
+		}
+	}
+
+	cmaps, err := c.CoreV1().ConfigMaps(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: getStackLabelSelectorWith(model.StackConfigNameLabel, s)})
+	if err != nil {
+		return nil, err
+	}
+	for _, cmap := range cmaps.Items {
+		if !isStackConfigObject(s.Configs, cmap.Labels[model.StackConfigNameLabel], cmap.Name, s) {
+			result = append(result, PlannedResource{Kind: "ConfigMap", Name: cmap.Name, Action: PlanActionDelete})
+		}
+	}
+
+	secrets, err := c.CoreV1().Secrets(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: getStackLabelSelectorWith(model.StackSecretNameLabel, s)})
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets.Items {
+		if !isStackConfigObject(s.Secrets, secret.Labels[model.StackSecretNameLabel], secret.Name, s) {
+			result = append(result, PlannedResource{Kind: "Secret", Name: secret.Name, Action: PlanActionDelete})
+		}
+	}
+
+	policiesSet := map[string]bool{}
+	for _, policy := range translateNetworkPolicies(s) {
+		policiesSet[policy.Name] = true
+	}
+	policies, err := c.NetworkingV1().NetworkPolicies(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: s.GetLabelSelector()})
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies.Items {
+		if !policiesSet[policy.Name] {
+			result = append(result, PlannedResource{Kind: "NetworkPolicy", Name: policy.Name, Action: PlanActionDelete})
+		}
+	}
+
+	aliases := getStackAliases(s)
+	svcList, err := services.List(ctx, s.Namespace, getStackLabelSelectorWith(model.StackNetworkAliasLabel, s), c)
+	if err != nil {
+		return nil, err
+	}
+	for i := range svcList {
+		if _, ok := aliases[svcList[i].Name]; !ok {
+			result = append(result, PlannedResource{Kind: "Service", Name: svcList[i].Name, Action: PlanActionDelete})
+		}
+	}
+
+	if iClient == nil {
+		return result, nil
+	}
+	iList, err := iClient.List(ctx, s.Namespace, s.GetLabelSelector())
+	if err != nil {
+		return nil, err
+	}
+	publicSvcsMap := getPublicIngressNames(s)
+	for i := range iList {
+		if _, ok := s.Endpoints[iList[i].GetName()]; ok {
+			continue
+		}
+		if _, ok := publicSvcsMap[iList[i].GetName()]; ok {
+			continue
+		}
+		if iList[i].GetLabels()[model.StackEndpointNameLabel] == "" {
+			continue
+		}
+		result = append(result, PlannedResource{Kind: "Ingress", Name: iList[i].GetName(), Action: PlanActionDelete})
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+	existingAPI := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "api",
+			Namespace: "ns",
+			Labels:    map[string]string{model.StackNameLabel: "stack-test"},
+		},
+	}
+	removedWorker := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "worker",
+			Namespace: "ns",
+			Labels:    map[string]string{model.StackNameLabel: "stack-test"},
+		},
+	}
+	foreignDB := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db",
+			Namespace: "ns",
+		},
+	}
+	client := fake.NewSimpleClientset(existingAPI, removedWorker, foreignDB)
+
+	s := &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api": {
+				Image:         "api",
+				RestartPolicy: corev1.RestartPolicyAlways,
+			},
+			"db": {
+				Image:         "db",
+				RestartPolicy: corev1.RestartPolicyAlways,
+			},
+			"frontend": {
+				Image:         "frontend",
+				RestartPolicy: corev1.RestartPolicyAlways,
+				Ports:         []model.Port{{ContainerPort: 8080}},
+			},
+		},
+	}
+
+	planned, err := Plan(ctx, s, []string{"frontend", "api", "db"}, client)
+	assert.NoError(t, err)
+	assert.Equal(t, []PlannedResource{
+		{Kind: "Deployment", Name: "api", Action: PlanActionUpdate},
+		{Kind: "Deployment", Name: "db", Action: PlanActionSkip, Reason: "name collision with pre-existing deployment"},
+		{Kind: "Service", Name: "frontend", Action: PlanActionCreate},
+		{Kind: "Deployment", Name: "frontend", Action: PlanActionCreate},
+		{Kind: "Deployment", Name: "worker", Action: PlanActionDelete},
+	}, planned)
+
+	dList, err := client.AppsV1().Deployments("ns").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, dList.Items, 3)
+}
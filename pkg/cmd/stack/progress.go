@@ -0,0 +1,384 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/okteto/okteto/pkg/k8s/events"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ServiceDeployStatus represents the status of a service during a stack deploy
+type ServiceDeployStatus string
+
+const (
+	// ServiceDeployStatusPending means no pod of the service has been scheduled yet
+	ServiceDeployStatusPending ServiceDeployStatus = "pending"
+
+	// ServiceDeployStatusPulling means the images of the service are being pulled
+	ServiceDeployStatusPulling ServiceDeployStatus = "pulling"
+
+	// ServiceDeployStatusStarting means the containers of the service are starting
+	ServiceDeployStatusStarting ServiceDeployStatus = "starting"
+
+	// ServiceDeployStatusHealthy means all the replicas of the service are ready
+	ServiceDeployStatusHealthy ServiceDeployStatus = "healthy"
+
+	// ServiceDeployStatusFailed means a pod of the service has failed or can't start
+	ServiceDeployStatusFailed ServiceDeployStatus = "failed"
+)
+
+// serviceProgressEvent is the event with the progress of a service in json output mode
+const serviceProgressEvent = "service-progress"
+
+// ServiceProgress represents the progress of a service during a stack deploy
+type ServiceProgress struct {
+	Name     string              `json:"name"`
+	Status   ServiceDeployStatus `json:"status"`
+	Ready    int32               `json:"ready"`
+	Replicas int32               `json:"replicas"`
+	Restarts int32               `json:"restarts"`
+	Message  string              `json:"message,omitempty"`
+}
+
+// deployProgress keeps track of the pods and events of the services of a stack while it is deployed
+type deployProgress struct {
+	stack    *model.Stack
+	services []string
+
+	mu       sync.Mutex
+	pods     map[types.UID]*apiv1.Pod
+	pulling  map[types.UID]bool
+	messages map[types.UID]string
+	// revisions are the current revision of each service, the pods of other revisions are ignored
+	revisions map[string]string
+
+	// live is true when the progress is displayed as a table redrawn in place
+	live       bool
+	tableLines int
+	printed    map[string]ServiceProgress
+}
+
+func newDeployProgress(s *model.Stack, servicesToDeploy []string) *deployProgress {
+	services := []string{}
+	for _, svcName := range servicesToDeploy {
+		if svc, ok := s.Services[svcName]; ok && !svc.IsCronJob() {
+			services = append(services, svcName)
+		}
+	}
+	sort.Strings(services)
+	return &deployProgress{
+		stack:     s,
+		services:  services,
+		pods:      map[types.UID]*apiv1.Pod{},
+		pulling:   map[types.UID]bool{},
+		messages:  map[types.UID]string{},
+		revisions: map[string]string{},
+		printed:   map[string]ServiceProgress{},
+	}
+}
+
+// watch loads the pods and events of the stack and keeps them updated until the context is done
+func (p *deployProgress) watch(ctx context.Context, c kubernetes.Interface) error {
+	podList, err := c.CoreV1().Pods(p.stack.Namespace).List(ctx, metav1.ListOptions{LabelSelector: p.stack.GetLabelSelector()})
+	if err != nil {
+		return err
+	}
+	eventList, err := events.ListPodEvents(ctx, p.stack.Namespace, c)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	for i := range podList.Items {
+		p.updatePod(watch.Added, &podList.Items[i])
+	}
+	sort.SliceStable(eventList.Items, func(i, j int) bool {
+		return events.GetTime(&eventList.Items[i]).Before(events.GetTime(&eventList.Items[j]))
+	})
+	for i := range eventList.Items {
+		p.updateEvent(&eventList.Items[i])
+	}
+	p.mu.Unlock()
+
+	podWatcher, err := c.CoreV1().Pods(p.stack.Namespace).Watch(ctx, metav1.ListOptions{
+		Watch:           true,
+		LabelSelector:   p.stack.GetLabelSelector(),
+		ResourceVersion: podList.ResourceVersion,
+	})
+	if err != nil {
+		return err
+	}
+	eventWatcher, err := events.WatchPodEvents(ctx, p.stack.Namespace, eventList.ResourceVersion, c)
+	if err != nil {
+		podWatcher.Stop()
+		return err
+	}
+
+	p.render()
+	go func() {
+		defer podWatcher.Stop()
+		defer eventWatcher.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-podWatcher.ResultChan():
+				if !ok {
+					oktetoLog.Infof("stopped watching the pods of compose '%s'", p.stack.Name)
+					return
+				}
+				pod, ok := e.Object.(*apiv1.Pod)
+				if !ok {
+					continue
+				}
+				p.mu.Lock()
+				p.updatePod(e.Type, pod)
+				svcName := pod.Labels[model.StackServiceNameLabel]
+				isNewRevision := e.Type == watch.Added && getPodRevision(pod) != p.revisions[svcName]
+				p.mu.Unlock()
+				if isNewRevision {
+					p.updateRevisions(ctx, c, svcName)
+				}
+			case e, ok := <-eventWatcher.ResultChan():
+				if !ok {
+					oktetoLog.Infof("stopped watching the events of compose '%s'", p.stack.Name)
+					return
+				}
+				event, ok := e.Object.(*apiv1.Event)
+				if !ok {
+					continue
+				}
+				p.mu.Lock()
+				p.updateEvent(event)
+				p.mu.Unlock()
+			}
+			p.render()
+		}
+	}()
+	return nil
+}
+
+func (p *deployProgress) updatePod(eventType watch.EventType, pod *apiv1.Pod) {
+	if eventType == watch.Deleted || pod.DeletionTimestamp != nil {
+		delete(p.pods, pod.UID)
+		delete(p.pulling, pod.UID)
+		delete(p.messages, pod.UID)
+		return
+	}
+	p.pods[pod.UID] = pod
+}
+
+// updateEvent keeps the pulling status and the last warning of the pods of the stack
+func (p *deployProgress) updateEvent(e *apiv1.Event) {
+	uid := e.InvolvedObject.UID
+	if _, ok := p.pods[uid]; !ok {
+		return
+	}
+	switch e.Reason {
+	case "Pulling":
+		p.pulling[uid] = true
+	case "Pulled", "Failed", "BackOff", "ErrImageNeverPull":
+		p.pulling[uid] = false
+	}
+	if e.Type == apiv1.EventTypeWarning {
+		p.messages[uid] = e.Message
+	}
+}
+
+// updateRevisions loads the current revision of the services
+func (p *deployProgress) updateRevisions(ctx context.Context, c kubernetes.Interface, services ...string) {
+	for _, svcName := range services {
+		if _, ok := p.stack.Services[svcName]; !ok {
+			continue
+		}
+		revision, err := getCurrentRevision(ctx, svcName, p.stack, c)
+		if err != nil {
+			oktetoLog.Infof("error getting the revision of service '%s': %s", svcName, err)
+			continue
+		}
+		p.mu.Lock()
+		p.revisions[svcName] = revision
+		p.mu.Unlock()
+	}
+}
+
+// setLive loads the revisions of the services once they are deployed and displays the progress as a table redrawn in place.
+// The table is only available in interactive tty outputs, and the spinner is stopped so it doesn't write over the table.
+func (p *deployProgress) setLive(ctx context.Context, c kubernetes.Interface) {
+	p.updateRevisions(ctx, c, p.services...)
+	p.mu.Lock()
+	p.live = oktetoLog.GetOutputFormat() == oktetoLog.TTYFormat && oktetoLog.IsInteractive()
+	if p.live {
+		oktetoLog.StopSpinner()
+	}
+	p.mu.Unlock()
+	p.render()
+}
+
+// getServicesProgress returns the progress of the services being deployed sorted by name
+func (p *deployProgress) getServicesProgress() []ServiceProgress {
+	podsBySvc := map[string][]*apiv1.Pod{}
+	for _, pod := range p.pods {
+		svcName := pod.Labels[model.StackServiceNameLabel]
+		if revision := p.revisions[svcName]; revision != "" && getPodRevision(pod) != revision {
+			continue
+		}
+		podsBySvc[svcName] = append(podsBySvc[svcName], pod)
+	}
+
+	result := []ServiceProgress{}
+	for _, svcName := range p.services {
+		pods := podsBySvc[svcName]
+		sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		result = append(result, getServiceProgress(svcName, p.stack.Services[svcName], pods, p.pulling, p.messages))
+	}
+	return result
+}
+
+func getServiceProgress(svcName string, svc *model.Service, pods []*apiv1.Pod, pulling map[types.UID]bool, messages map[types.UID]string) ServiceProgress {
+	result := ServiceProgress{
+		Name:     svcName,
+		Status:   ServiceDeployStatusPending,
+		Replicas: svc.Replicas,
+	}
+	isPulling, isStarting := false, false
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			result.Restarts += status.RestartCount
+		}
+		switch {
+		case isPodFailing(pod):
+			result.Status = ServiceDeployStatusFailed
+			result.Message = getPodFailureMessage(pod, messages[pod.UID])
+		case pod.Status.Phase == apiv1.PodSucceeded || isPodReady(pod):
+			result.Ready++
+		case pulling[pod.UID]:
+			isPulling = true
+		case pod.Spec.NodeName != "":
+			isStarting = true
+		default:
+			if result.Message == "" {
+				result.Message = messages[pod.UID]
+			}
+		}
+	}
+
+	switch {
+	case result.Status == ServiceDeployStatusFailed:
+	case result.Ready >= result.Replicas:
+		result.Status = ServiceDeployStatusHealthy
+		result.Message = ""
+	case isPulling:
+		result.Status = ServiceDeployStatusPulling
+	case isStarting:
+		result.Status = ServiceDeployStatusStarting
+	}
+	return result
+}
+
+func isPodReady(pod *apiv1.Pod) bool {
+	if pod.Status.Phase != apiv1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == apiv1.PodReady {
+			return condition.Status == apiv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getPodFailureMessage returns the last warning event of a failing pod, or the reason reported by its status
+func getPodFailureMessage(pod *apiv1.Pod, lastWarning string) string {
+	if lastWarning != "" {
+		return lastWarning
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting == nil || !failingContainerReasons[status.State.Waiting.Reason] {
+			continue
+		}
+		if status.State.Waiting.Message != "" {
+			return fmt.Sprintf("%s: %s", status.State.Waiting.Reason, status.State.Waiting.Message)
+		}
+		return status.State.Waiting.Reason
+	}
+	return pod.Status.Message
+}
+
+// render displays the progress of the services: a table in live mode, or a line for each service that changed otherwise
+func (p *deployProgress) render() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	services := p.getServicesProgress()
+	if p.live {
+		p.renderTable(services)
+		return
+	}
+	for _, svc := range services {
+		if previous, ok := p.printed[svc.Name]; ok && previous == svc {
+			continue
+		}
+		p.printed[svc.Name] = svc
+		if oktetoLog.GetOutputFormat() == oktetoLog.JSONFormat {
+			oktetoLog.AddEvent(serviceProgressEvent, svc)
+			continue
+		}
+		oktetoLog.Println(formatServiceProgress(svc))
+	}
+}
+
+func (p *deployProgress) renderTable(services []ServiceProgress) {
+	buffer := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buffer, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tSTATUS\tREADY\tRESTARTS\tMESSAGE")
+	for _, svc := range services {
+		fmt.Fprintf(w, "%s\t%s\t%d/%d\t%d\t%s\n", svc.Name, svc.Status, svc.Ready, svc.Replicas, svc.Restarts, svc.Message)
+	}
+	w.Flush()
+
+	table := buffer.String()
+	if p.tableLines > 0 {
+		// moves the cursor to the beginning of the previous table and clears it
+		table = fmt.Sprintf("\033[%dA\033[J%s", p.tableLines, table)
+	}
+	p.tableLines = strings.Count(buffer.String(), "\n")
+	oktetoLog.Printf("%s", table)
+}
+
+func formatServiceProgress(svc ServiceProgress) string {
+	line := fmt.Sprintf("Service '%s' is %s (%d/%d ready", svc.Name, svc.Status, svc.Ready, svc.Replicas)
+	if svc.Restarts > 0 {
+		line += fmt.Sprintf(", %d restarts", svc.Restarts)
+	}
+	line += ")"
+	if svc.Message != "" {
+		line += fmt.Sprintf(": %s", svc.Message)
+	}
+	return line
+}
@@ -0,0 +1,201 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newProgressPod(name string, uid types.UID, nodeName string, status apiv1.PodStatus) *apiv1.Pod {
+	return &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+			UID:       uid,
+			Labels:    map[string]string{model.StackNameLabel: "stack-test", model.StackServiceNameLabel: "api"},
+		},
+		Spec:   apiv1.PodSpec{NodeName: nodeName},
+		Status: status,
+	}
+}
+
+func Test_getServiceProgress(t *testing.T) {
+	svc := &model.Service{Replicas: 2}
+	ready := apiv1.PodStatus{
+		Phase:             apiv1.PodRunning,
+		Conditions:        []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionTrue}},
+		ContainerStatuses: []apiv1.ContainerStatus{{RestartCount: 1}},
+	}
+	crashing := apiv1.PodStatus{
+		Phase: apiv1.PodRunning,
+		ContainerStatuses: []apiv1.ContainerStatus{{
+			RestartCount: 3,
+			State:        apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off restarting failed container"}},
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		pods     []*apiv1.Pod
+		pulling  map[types.UID]bool
+		messages map[types.UID]string
+		expected ServiceProgress
+	}{
+		{
+			name:     "no-pods",
+			expected: ServiceProgress{Name: "api", Status: ServiceDeployStatusPending, Replicas: 2},
+		},
+		{
+			name:     "unschedulable",
+			pods:     []*apiv1.Pod{newProgressPod("api-1", "1", "", apiv1.PodStatus{Phase: apiv1.PodPending})},
+			messages: map[types.UID]string{"1": "0/3 nodes are available: 3 Insufficient cpu."},
+			expected: ServiceProgress{Name: "api", Status: ServiceDeployStatusPending, Replicas: 2, Message: "0/3 nodes are available: 3 Insufficient cpu."},
+		},
+		{
+			name: "pulling",
+			pods: []*apiv1.Pod{
+				newProgressPod("api-1", "1", "node", ready),
+				newProgressPod("api-2", "2", "node", apiv1.PodStatus{Phase: apiv1.PodPending}),
+			},
+			pulling:  map[types.UID]bool{"2": true},
+			expected: ServiceProgress{Name: "api", Status: ServiceDeployStatusPulling, Ready: 1, Replicas: 2, Restarts: 1},
+		},
+		{
+			name: "starting",
+			pods: []*apiv1.Pod{
+				newProgressPod("api-1", "1", "node", apiv1.PodStatus{Phase: apiv1.PodPending}),
+			},
+			pulling:  map[types.UID]bool{"1": false},
+			expected: ServiceProgress{Name: "api", Status: ServiceDeployStatusStarting, Replicas: 2},
+		},
+		{
+			name: "healthy",
+			pods: []*apiv1.Pod{
+				newProgressPod("api-1", "1", "node", ready),
+				newProgressPod("api-2", "2", "node", ready),
+			},
+			messages: map[types.UID]string{"1": "Readiness probe failed"},
+			expected: ServiceProgress{Name: "api", Status: ServiceDeployStatusHealthy, Ready: 2, Replicas: 2, Restarts: 2},
+		},
+		{
+			name: "failed-with-event",
+			pods: []*apiv1.Pod{
+				newProgressPod("api-1", "1", "node", ready),
+				newProgressPod("api-2", "2", "node", crashing),
+			},
+			messages: map[types.UID]string{"2": "Back-off restarting failed container"},
+			expected: ServiceProgress{Name: "api", Status: ServiceDeployStatusFailed, Ready: 1, Replicas: 2, Restarts: 4, Message: "Back-off restarting failed container"},
+		},
+		{
+			name: "failed-without-event",
+			pods: []*apiv1.Pod{
+				newProgressPod("api-2", "2", "node", crashing),
+			},
+			expected: ServiceProgress{Name: "api", Status: ServiceDeployStatusFailed, Replicas: 2, Restarts: 3, Message: "CrashLoopBackOff: back-off restarting failed container"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getServiceProgress("api", svc, tt.pods, tt.pulling, tt.messages)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_deployProgressWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api":    {Replicas: 1},
+			"backup": {Replicas: 1, Schedule: &model.ServiceSchedule{Cron: "@daily"}},
+		},
+	}
+	pod := newProgressPod("api-1", "1", "node", apiv1.PodStatus{Phase: apiv1.PodPending})
+	event := &apiv1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "api-1.pulling", Namespace: "ns"},
+		InvolvedObject: apiv1.ObjectReference{Kind: "Pod", Name: "api-1", UID: "1"},
+		Reason:         "Pulling",
+		Message:        `Pulling image "api"`,
+		Type:           apiv1.EventTypeNormal,
+	}
+	c := fake.NewSimpleClientset(pod, event)
+
+	p := newDeployProgress(s, []string{"api", "backup"})
+	assert.Equal(t, []string{"api"}, p.services)
+	assert.NoError(t, p.watch(ctx, c))
+
+	getStatus := func() ServiceDeployStatus {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.getServicesProgress()[0].Status
+	}
+	assert.Equal(t, ServiceDeployStatusPulling, getStatus())
+
+	pod.Status = apiv1.PodStatus{
+		Phase:      apiv1.PodRunning,
+		Conditions: []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionTrue}},
+	}
+	_, err := c.CoreV1().Pods("ns").Update(ctx, pod, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool { return getStatus() == ServiceDeployStatusHealthy }, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_getServicesProgressOfCurrentRevision(t *testing.T) {
+	s := &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api": {Replicas: 1},
+		},
+	}
+	p := newDeployProgress(s, []string{"api"})
+	ready := apiv1.PodStatus{
+		Phase:      apiv1.PodRunning,
+		Conditions: []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionTrue}},
+	}
+	oldPod := newProgressPod("api-old", "old", "node", ready)
+	oldPod.Labels[appsv1.DefaultDeploymentUniqueLabelKey] = "old"
+	newPod := newProgressPod("api-new", "new", "node", apiv1.PodStatus{Phase: apiv1.PodPending})
+	newPod.Labels[appsv1.DefaultDeploymentUniqueLabelKey] = "new"
+	p.updatePod(watch.Added, oldPod)
+	p.updatePod(watch.Added, newPod)
+	p.revisions["api"] = "new"
+
+	p.updateEvent(&apiv1.Event{InvolvedObject: apiv1.ObjectReference{UID: "other"}, Type: apiv1.EventTypeWarning, Message: "other pod"})
+	assert.NotContains(t, p.messages, types.UID("other"))
+	p.updateEvent(&apiv1.Event{InvolvedObject: apiv1.ObjectReference{UID: "new"}, Reason: "Pulling"})
+
+	result := p.getServicesProgress()
+	assert.Equal(t, int32(0), result[0].Ready)
+	assert.Equal(t, ServiceDeployStatusPulling, result[0].Status)
+}
+
+func Test_formatServiceProgress(t *testing.T) {
+	assert.Equal(t, "Service 'api' is starting (0/1 ready)", formatServiceProgress(ServiceProgress{Name: "api", Status: ServiceDeployStatusStarting, Replicas: 1}))
+	assert.Equal(t, "Service 'api' is failed (0/1 ready, 3 restarts): Back-off restarting failed container", formatServiceProgress(ServiceProgress{Name: "api", Status: ServiceDeployStatusFailed, Replicas: 1, Restarts: 3, Message: "Back-off restarting failed container"}))
+}
@@ -0,0 +1,255 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	"github.com/okteto/okteto/pkg/k8s/deployments"
+	"github.com/okteto/okteto/pkg/k8s/replicasets"
+	"github.com/okteto/okteto/pkg/k8s/statefulsets"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// failingContainerReasons are the waiting reasons of a container that will never be ready without a new deploy
+var failingContainerReasons = map[string]bool{
+	"CrashLoopBackOff": true,
+	"ErrImagePull":     true,
+	"ImagePullBackOff": true,
+}
+
+// isPodFailing returns if a pod has failed or if one of its containers can't start
+func isPodFailing(pod *apiv1.Pod) bool {
+	if pod.Status.Phase == apiv1.PodFailed {
+		return true
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && failingContainerReasons[status.State.Waiting.Reason] {
+			return true
+		}
+	}
+	return false
+}
+
+// errNoPreviousRevision is returned when a service can't be rolled back because it was never updated
+var errNoPreviousRevision = errors.New("there is no previous revision")
+
+// shouldRollback returns if a service must be rolled back when its update fails
+func shouldRollback(svc *model.Service) bool {
+	if svc == nil || svc.UpdateConfig == nil || svc.UpdateConfig.FailureAction != model.UpdateFailureActionRollback {
+		return false
+	}
+	return svc.IsDeployment() || svc.IsStatefulset()
+}
+
+// isPodOfCurrentRevision returns if a pod was created by the current revision of its service.
+// The pods of previous revisions might still be failing while the update rolls out
+func isPodOfCurrentRevision(ctx context.Context, pod *apiv1.Pod, svcName string, s *model.Stack, c kubernetes.Interface) (bool, error) {
+	revision, err := getCurrentRevision(ctx, svcName, s, c)
+	if err != nil {
+		return false, err
+	}
+	return revision != "" && getPodRevision(pod) == revision, nil
+}
+
+// getCurrentRevision returns the pod-template-hash of the current replicaset of a deployment or the update revision of a statefulset.
+// It returns an empty string if the service doesn't have revisions or the current one isn't created yet.
+func getCurrentRevision(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) (string, error) {
+	svc := s.Services[svcName]
+	switch {
+	case svc.IsDeployment():
+		d, err := deployments.Get(ctx, svcName, s.Namespace, c)
+		if err != nil {
+			return "", err
+		}
+		rs, err := replicasets.GetReplicaSetByDeployment(ctx, d, c)
+		if err != nil {
+			if oktetoErrors.IsNotFound(err) {
+				return "", nil
+			}
+			return "", err
+		}
+		return rs.Labels[appsv1.DefaultDeploymentUniqueLabelKey], nil
+	case svc.IsStatefulset():
+		sfs, err := statefulsets.Get(ctx, svcName, s.Namespace, c)
+		if err != nil {
+			return "", err
+		}
+		return sfs.Status.UpdateRevision, nil
+	}
+	return "", nil
+}
+
+// getPodRevision returns the revision of the deployment or statefulset that created a pod
+func getPodRevision(pod *apiv1.Pod) string {
+	if revision, ok := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok {
+		return revision
+	}
+	return pod.Labels[appsv1.ControllerRevisionHashLabelKey]
+}
+
+// rollbackService restores the previous revision of a service through the same apply as the deploy,
+// recording the revision in an annotation so 'okteto stack diff' can explain the differences with the compose file
+func rollbackService(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) error {
+	svc := s.Services[svcName]
+	oktetoLog.Infof("rolling back service '%s'", svcName)
+	switch {
+	case svc.IsDeployment():
+		return rollbackDeployment(ctx, svcName, s, c)
+	case svc.IsStatefulset():
+		return rollbackStatefulSet(ctx, svcName, s, c)
+	}
+	return fmt.Errorf("service '%s' can't be rolled back", svcName)
+}
+
+func rollbackDeployment(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) error {
+	old, err := deployments.Get(ctx, svcName, s.Namespace, c)
+	if err != nil {
+		return err
+	}
+	rs, err := replicasets.GetPreviousReplicaSetByDeployment(ctx, old, c)
+	if err != nil {
+		if oktetoErrors.IsNotFound(err) {
+			return errNoPreviousRevision
+		}
+		return err
+	}
+
+	d := translateDeployment(svcName, s)
+	setLiveDeploymentFields(svcName, s, d, old)
+	template := rs.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	d.Spec.Template = *template
+	d.Annotations[model.OktetoComposeRolledBackAnnotation] = rs.Annotations[model.DeploymentRevisionAnnotation]
+
+	if rollbackConfig := s.Services[svcName].RollbackConfig; rollbackConfig != nil {
+		d.Spec.Strategy = appsv1.DeploymentStrategy{
+			Type:          appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: translateRollingUpdateDeployment(rollbackConfig),
+		}
+		d.Spec.MinReadySeconds = int32(rollbackConfig.Delay)
+	}
+	_, err = applyDeployment(ctx, d, old.ManagedFields, hasAutoscaler(svcName, s), c)
+	return err
+}
+
+func rollbackStatefulSet(ctx context.Context, svcName string, s *model.Stack, c kubernetes.Interface) error {
+	old, err := statefulsets.Get(ctx, svcName, s.Namespace, c)
+	if err != nil {
+		return err
+	}
+	revision, err := getPreviousControllerRevision(ctx, old, c)
+	if err != nil {
+		return err
+	}
+	template, err := getControllerRevisionTemplate(revision)
+	if err != nil {
+		return err
+	}
+
+	sfs := translateStatefulSet(svcName, s)
+	setLiveStatefulSetFields(svcName, s, sfs, old)
+	sfs.Spec.Template = *template
+	sfs.Annotations[model.OktetoComposeRolledBackAnnotation] = strconv.FormatInt(revision.Revision, 10)
+
+	if rollbackConfig := s.Services[svcName].RollbackConfig; rollbackConfig != nil {
+		sfs.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: translateRollingUpdateStatefulSet(rollbackConfig),
+		}
+		sfs.Spec.MinReadySeconds = int32(rollbackConfig.Delay)
+	}
+	_, err = applyStatefulSet(ctx, sfs, old.ManagedFields, hasAutoscaler(svcName, s), c)
+	return err
+}
+
+// getControllerRevisionTemplate returns the pod template stored in a controller revision of a statefulset.
+// The data of the revision is a patch that replaces the pod template of the statefulset
+func getControllerRevisionTemplate(revision *appsv1.ControllerRevision) (*apiv1.PodTemplateSpec, error) {
+	data := revision.Data.Raw
+	if len(data) == 0 {
+		var err error
+		data, err = json.Marshal(revision.Data.Object)
+		if err != nil {
+			return nil, err
+		}
+	}
+	patch := struct {
+		Spec struct {
+			Template apiv1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, fmt.Errorf("error reading revision '%s': %w", revision.Name, err)
+	}
+	return &patch.Spec.Template, nil
+}
+
+// getPreviousControllerRevision returns the controller revision previous to the update revision of a statefulset
+func getPreviousControllerRevision(ctx context.Context, sfs *appsv1.StatefulSet, c kubernetes.Interface) (*appsv1.ControllerRevision, error) {
+	selector, err := metav1.LabelSelectorAsSelector(sfs.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	revisionList, err := c.AppsV1().ControllerRevisions(sfs.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []*appsv1.ControllerRevision{}
+	var current int64 = -1
+	for i := range revisionList.Items {
+		revision := &revisionList.Items[i]
+		if !isControllerRevisionOwnedBy(revision, sfs) {
+			continue
+		}
+		revisions = append(revisions, revision)
+		if revision.Name == sfs.Status.UpdateRevision || (sfs.Status.UpdateRevision == "" && revision.Revision > current) {
+			current = revision.Revision
+		}
+	}
+
+	var result *appsv1.ControllerRevision
+	for _, revision := range revisions {
+		if revision.Revision >= current {
+			continue
+		}
+		if result == nil || revision.Revision > result.Revision {
+			result = revision
+		}
+	}
+	if result == nil {
+		return nil, errNoPreviousRevision
+	}
+	return result, nil
+}
+
+func isControllerRevisionOwnedBy(revision *appsv1.ControllerRevision, sfs *appsv1.StatefulSet) bool {
+	for _, or := range revision.OwnerReferences {
+		if or.UID == sfs.UID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/okteto/okteto/pkg/model"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+)
+
+func newReplicaSet(name, revision, image string, owner types.UID) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "ns",
+			Labels:          map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: name},
+			Annotations:     map[string]string{model.DeploymentRevisionAnnotation: revision},
+			OwnerReferences: []metav1.OwnerReference{{UID: owner}},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Template: apiv1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: name, model.StackServiceNameLabel: "api"},
+				},
+				Spec: apiv1.PodSpec{Containers: []apiv1.Container{{Name: "api", Image: image}}},
+			},
+		},
+	}
+}
+
+func Test_waitForPodsToBeRunningWithRollback(t *testing.T) {
+	ctx := context.Background()
+	s := &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api": {
+				Image:         "api:v2",
+				RestartPolicy: apiv1.RestartPolicyAlways,
+				Replicas:      1,
+				UpdateConfig: &model.UpdateConfig{
+					Parallelism:   pointer.Int32(1),
+					Order:         model.UpdateOrderStopFirst,
+					FailureAction: model.UpdateFailureActionRollback,
+				},
+				RollbackConfig: &model.UpdateConfig{
+					Parallelism: pointer.Int32(0),
+					Order:       model.UpdateOrderStartFirst,
+				},
+			},
+		},
+	}
+
+	d := translateDeployment("api", s)
+	d.UID = "api-uid"
+	d.Annotations = map[string]string{model.DeploymentRevisionAnnotation: "2"}
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "api-2",
+			Namespace: "ns",
+			Labels: map[string]string{
+				model.StackNameLabel:                   "stack-test",
+				model.StackServiceNameLabel:            "api",
+				appsv1.DefaultDeploymentUniqueLabelKey: "api-2",
+			},
+		},
+		Status: apiv1.PodStatus{
+			Phase: apiv1.PodPending,
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{State: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+			},
+		},
+	}
+	c := fake.NewSimpleClientset(
+		d,
+		pod,
+		newReplicaSet("api-1", "1", "api:v1", d.UID),
+		newReplicaSet("api-2", "2", "api:v2", d.UID),
+		newReplicaSet("other-1", "1", "other:v1", "other-uid"),
+	)
+
+	err := waitForPodsToBeRunning(ctx, s, c)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "rolled back"))
+
+	d, err = c.AppsV1().Deployments("ns").Get(ctx, "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "api:v1", d.Spec.Template.Spec.Containers[0].Image)
+	assert.NotContains(t, d.Spec.Template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	assert.Equal(t, "100%", d.Spec.Strategy.RollingUpdate.MaxSurge.StrVal)
+	assert.Equal(t, "1", d.Annotations[model.OktetoComposeRolledBackAnnotation])
+
+	assert.True(t, isPodFailing(pod))
+	s.Services["api"].UpdateConfig.FailureAction = model.UpdateFailureActionPause
+	assert.False(t, shouldRollback(s.Services["api"]))
+}
+
+func Test_isPodOfCurrentRevision(t *testing.T) {
+	ctx := context.Background()
+	s := &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api": {Image: "api:v2", RestartPolicy: apiv1.RestartPolicyAlways, Replicas: 1},
+		},
+	}
+	d := translateDeployment("api", s)
+	d.UID = "api-uid"
+	d.Annotations = map[string]string{model.DeploymentRevisionAnnotation: "2"}
+	c := fake.NewSimpleClientset(
+		d,
+		newReplicaSet("api-1", "1", "api:v1", d.UID),
+		newReplicaSet("api-2", "2", "api:v2", d.UID),
+	)
+
+	var tests = []struct {
+		name     string
+		hash     string
+		expected bool
+	}{
+		{name: "current-revision", hash: "api-2", expected: true},
+		{name: "previous-revision", hash: "api-1", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: tt.hash}}}
+			result, err := isPodOfCurrentRevision(ctx, pod, "api", s, c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_rollbackServiceWithoutPreviousRevision(t *testing.T) {
+	ctx := context.Background()
+	s := &model.Stack{
+		Name:      "stack-test",
+		Namespace: "ns",
+		Services: map[string]*model.Service{
+			"api": {Image: "api:v1", RestartPolicy: apiv1.RestartPolicyAlways, Replicas: 1},
+		},
+	}
+	d := translateDeployment("api", s)
+	d.UID = "api-uid"
+	d.Annotations = map[string]string{model.DeploymentRevisionAnnotation: "1"}
+	c := fake.NewSimpleClientset(d, newReplicaSet("api-1", "1", "api:v1", d.UID))
+
+	err := rollbackService(ctx, "api", s, c)
+	assert.True(t, errors.Is(err, errNoPreviousRevision))
+
+	d, err = c.AppsV1().Deployments("ns").Get(ctx, "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotContains(t, d.Annotations, model.OktetoComposeRolledBackAnnotation)
+}
+
+func Test_getControllerRevisionTemplate(t *testing.T) {
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1"},
+		Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"metadata":{"labels":{"app":"api"}},"spec":{"containers":[{"name":"api","image":"api:v1"}]}}}}`)},
+	}
+	template, err := getControllerRevisionTemplate(revision)
+	assert.NoError(t, err)
+	assert.Equal(t, "api", template.Labels["app"])
+	assert.Equal(t, "api:v1", template.Spec.Containers[0].Image)
+
+	revision.Data.Raw = []byte(`{`)
+	_, err = getControllerRevisionTemplate(revision)
+	assert.Error(t, err)
+}
@@ -49,6 +49,8 @@ const (
 	errorStatus       = "error"
 	destroyingStatus  = "destroying"
 
+	appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io"
+
 	pvcName = "pvc"
 )
 
@@ -118,6 +120,11 @@ func translateDeployment(svcName string, s *model.Stack) *appsv1.Deployment {
 
 	svcHealthchecks := getSvcHealthProbe(svc)
 
+	var initContainers []apiv1.Container
+	if configsInitContainer := getConfigsInitContainer(svcName, s); configsInitContainer != nil {
+		initContainers = append(initContainers, *configsInitContainer)
+	}
+
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        svcName,
@@ -130,14 +137,25 @@ func translateDeployment(svcName string, s *model.Stack) *appsv1.Deployment {
 			Selector: &metav1.LabelSelector{
 				MatchLabels: translateLabelSelector(svcName, s),
 			},
-			Strategy: getDeploymentUpdateStrategy(svc),
+			Strategy:        getDeploymentUpdateStrategy(svc),
+			MinReadySeconds: translateMinReadySeconds(svc),
 			Template: apiv1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      translateLabels(svcName, s),
-					Annotations: translateAnnotations(svc),
+					Annotations: translatePodAnnotations(svcName, s),
 				},
 				Spec: apiv1.PodSpec{
 					TerminationGracePeriodSeconds: pointer.Int64Ptr(svc.StopGracePeriod),
+					InitContainers:                initContainers,
+					Volumes:                       translateConfigVolumes(svcName, s),
+					Affinity:                      translateAffinity(svc),
+					TopologySpreadConstraints:     translateTopologySpreadConstraints(svcName, s),
+					Hostname:                      svc.Hostname,
+					Subdomain:                     svc.DomainName,
+					HostAliases:                   translateHostAliases(svc),
+					ShareProcessNamespace:         translateShareProcessNamespace(svc),
+					SecurityContext:               translatePodSecurityContext(svc),
+					DNSConfig:                     translateDNSConfig(svc),
 					Containers: []apiv1.Container{
 						{
 							Name:            svcName,
@@ -147,10 +165,13 @@ func translateDeployment(svcName string, s *model.Stack) *appsv1.Deployment {
 							Env:             translateServiceEnvironment(svc),
 							Ports:           translateContainerPorts(svc),
 							SecurityContext: translateSecurityContext(svc),
+							VolumeMounts:    translateConfigVolumeMounts(svcName, s),
 							Resources:       translateResources(svc),
 							WorkingDir:      svc.Workdir,
 							ReadinessProbe:  svcHealthchecks.readiness,
 							LivenessProbe:   svcHealthchecks.liveness,
+							TTY:             svc.Tty,
+							Stdin:           svc.StdinOpen,
 						},
 					},
 				},
@@ -201,18 +222,25 @@ func translateStatefulSet(svcName string, s *model.Stack) *appsv1.StatefulSet {
 			Selector: &metav1.LabelSelector{
 				MatchLabels: translateLabelSelector(svcName, s),
 			},
-			UpdateStrategy: getStatefulsetUpdateStrategy(svc),
-			ServiceName:    svcName,
+			UpdateStrategy:  getStatefulsetUpdateStrategy(svc),
+			MinReadySeconds: translateMinReadySeconds(svc),
+			ServiceName:     svcName,
 			Template: apiv1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      translateLabels(svcName, s),
-					Annotations: translateAnnotations(svc),
+					Annotations: translatePodAnnotations(svcName, s),
 				},
 				Spec: apiv1.PodSpec{
 					TerminationGracePeriodSeconds: pointer.Int64Ptr(svc.StopGracePeriod),
 					InitContainers:                initContainers,
 					Affinity:                      translateAffinity(svc),
-					Volumes:                       translateVolumes(svc),
+					TopologySpreadConstraints:     translateTopologySpreadConstraints(svcName, s),
+					Volumes:                       append(translateVolumes(svc), translateConfigVolumes(svcName, s)...),
+					// the statefulset controller sets the hostname and subdomain of its pods
+					HostAliases:           translateHostAliases(svc),
+					ShareProcessNamespace: translateShareProcessNamespace(svc),
+					SecurityContext:       translatePodSecurityContext(svc),
+					DNSConfig:             translateDNSConfig(svc),
 					Containers: []apiv1.Container{
 						{
 							Name:            svcName,
@@ -222,11 +250,13 @@ func translateStatefulSet(svcName string, s *model.Stack) *appsv1.StatefulSet {
 							Env:             translateServiceEnvironment(svc),
 							Ports:           translateContainerPorts(svc),
 							SecurityContext: translateSecurityContext(svc),
-							VolumeMounts:    translateVolumeMounts(svc),
+							VolumeMounts:    append(translateVolumeMounts(svc), translateConfigVolumeMounts(svcName, s)...),
 							Resources:       translateResources(svc),
 							WorkingDir:      svc.Workdir,
 							ReadinessProbe:  svcHealthchecks.readiness,
 							LivenessProbe:   svcHealthchecks.liveness,
+							TTY:             svc.Tty,
+							Stdin:           svc.StdinOpen,
 						},
 					},
 				},
@@ -239,8 +269,6 @@ func translateStatefulSet(svcName string, s *model.Stack) *appsv1.StatefulSet {
 func translateJob(svcName string, s *model.Stack) *batchv1.Job {
 	svc := s.Services[svcName]
 
-	initContainers := getInitContainers(svcName, s)
-	svcHealthchecks := getSvcHealthProbe(svc)
 	return &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        svcName,
@@ -248,38 +276,79 @@ func translateJob(svcName string, s *model.Stack) *batchv1.Job {
 			Labels:      translateLabels(svcName, s),
 			Annotations: translateAnnotations(svc),
 		},
-		Spec: batchv1.JobSpec{
-			Completions:  pointer.Int32Ptr(svc.Replicas),
-			Parallelism:  pointer.Int32Ptr(1),
-			BackoffLimit: &svc.BackOffLimit,
-			Template: apiv1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels:      translateLabels(svcName, s),
-					Annotations: translateAnnotations(svc),
-				},
-				Spec: apiv1.PodSpec{
-					RestartPolicy:                 svc.RestartPolicy,
-					TerminationGracePeriodSeconds: pointer.Int64Ptr(svc.StopGracePeriod),
-					InitContainers:                initContainers,
-					Affinity:                      translateAffinity(svc),
-					Containers: []apiv1.Container{
-						{
-							Name:            svcName,
-							Image:           svc.Image,
-							Command:         svc.Entrypoint.Values,
-							Args:            svc.Command.Values,
-							Env:             translateServiceEnvironment(svc),
-							Ports:           translateContainerPorts(svc),
-							SecurityContext: translateSecurityContext(svc),
-							VolumeMounts:    translateVolumeMounts(svc),
-							Resources:       translateResources(svc),
-							WorkingDir:      svc.Workdir,
-							ReadinessProbe:  svcHealthchecks.readiness,
-							LivenessProbe:   svcHealthchecks.liveness,
-						},
+		Spec: translateJobSpec(svcName, s),
+	}
+}
+
+func translateCronJob(svcName string, s *model.Stack) *batchv1.CronJob {
+	svc := s.Services[svcName]
+
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        svcName,
+			Namespace:   s.Namespace,
+			Labels:      translateLabels(svcName, s),
+			Annotations: translateAnnotations(svc),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   svc.Schedule.Cron,
+			ConcurrencyPolicy:          batchv1.ConcurrencyPolicy(svc.Schedule.ConcurrencyPolicy),
+			Suspend:                    pointer.BoolPtr(svc.Schedule.Suspend),
+			SuccessfulJobsHistoryLimit: svc.Schedule.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     svc.Schedule.FailedJobsHistoryLimit,
+			// the jobs of a cronjob don't have the stack labels so they aren't managed as stack services
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: translateJobSpec(svcName, s),
+			},
+		},
+	}
+}
+
+func translateJobSpec(svcName string, s *model.Stack) batchv1.JobSpec {
+	svc := s.Services[svcName]
+
+	initContainers := getInitContainers(svcName, s)
+	svcHealthchecks := getSvcHealthProbe(svc)
+	return batchv1.JobSpec{
+		Completions:  pointer.Int32Ptr(svc.Replicas),
+		Parallelism:  pointer.Int32Ptr(1),
+		BackoffLimit: &svc.BackOffLimit,
+		Template: apiv1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      translateLabels(svcName, s),
+				Annotations: translatePodAnnotations(svcName, s),
+			},
+			Spec: apiv1.PodSpec{
+				RestartPolicy:                 svc.RestartPolicy,
+				TerminationGracePeriodSeconds: pointer.Int64Ptr(svc.StopGracePeriod),
+				InitContainers:                initContainers,
+				Affinity:                      translateAffinity(svc),
+				TopologySpreadConstraints:     translateTopologySpreadConstraints(svcName, s),
+				Hostname:                      svc.Hostname,
+				Subdomain:                     svc.DomainName,
+				HostAliases:                   translateHostAliases(svc),
+				ShareProcessNamespace:         translateShareProcessNamespace(svc),
+				SecurityContext:               translatePodSecurityContext(svc),
+				DNSConfig:                     translateDNSConfig(svc),
+				Containers: []apiv1.Container{
+					{
+						Name:            svcName,
+						Image:           svc.Image,
+						Command:         svc.Entrypoint.Values,
+						Args:            svc.Command.Values,
+						Env:             translateServiceEnvironment(svc),
+						Ports:           translateContainerPorts(svc),
+						SecurityContext: translateSecurityContext(svc),
+						VolumeMounts:    append(translateVolumeMounts(svc), translateConfigVolumeMounts(svcName, s)...),
+						Resources:       translateResources(svc),
+						WorkingDir:      svc.Workdir,
+						ReadinessProbe:  svcHealthchecks.readiness,
+						LivenessProbe:   svcHealthchecks.liveness,
+						TTY:             svc.Tty,
+						Stdin:           svc.StdinOpen,
 					},
-					Volumes: translateVolumes(svc),
 				},
+				Volumes: append(translateVolumes(svc), translateConfigVolumes(svcName, s)...),
 			},
 		},
 	}
@@ -297,6 +366,9 @@ func getInitContainers(svcName string, s *model.Stack) []apiv1.Container {
 	if initializationContainer != nil {
 		initContainers = append(initContainers, *initializationContainer)
 	}
+	if configsInitContainer := getConfigsInitContainer(svcName, s); configsInitContainer != nil {
+		initContainers = append(initContainers, *configsInitContainer)
+	}
 
 	return initContainers
 }
@@ -486,15 +558,64 @@ func translateAffinity(svc *model.Service) *apiv1.Affinity {
 		},
 		)
 	}
+	nodeAffinity := translateNodeAffinity(svc)
+	if len(requirements) == 0 && nodeAffinity == nil {
+		return nil
+	}
+
+	result := &apiv1.Affinity{NodeAffinity: nodeAffinity}
 	if len(requirements) > 0 {
-		return &apiv1.Affinity{
-			PodAffinity: &apiv1.PodAffinity{
-				RequiredDuringSchedulingIgnoredDuringExecution: requirements,
-			},
+		result.PodAffinity = &apiv1.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: requirements,
 		}
 	}
+	return result
+}
 
-	return nil
+// translateNodeAffinity translates the placement constraints of a service into a required node affinity
+func translateNodeAffinity(svc *model.Service) *apiv1.NodeAffinity {
+	if svc.Placement == nil || len(svc.Placement.Constraints) == 0 {
+		return nil
+	}
+	requirements := make([]apiv1.NodeSelectorRequirement, 0, len(svc.Placement.Constraints))
+	for _, constraint := range svc.Placement.Constraints {
+		operator := apiv1.NodeSelectorOpIn
+		if constraint.Negated {
+			operator = apiv1.NodeSelectorOpNotIn
+		}
+		requirements = append(requirements, apiv1.NodeSelectorRequirement{
+			Key:      constraint.Key,
+			Operator: operator,
+			Values:   []string{constraint.Value},
+		})
+	}
+	return &apiv1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &apiv1.NodeSelector{
+			NodeSelectorTerms: []apiv1.NodeSelectorTerm{
+				{MatchExpressions: requirements},
+			},
+		},
+	}
+}
+
+// translateTopologySpreadConstraints translates the placement preferences of a service into topology spread constraints
+func translateTopologySpreadConstraints(svcName string, s *model.Stack) []apiv1.TopologySpreadConstraint {
+	svc := s.Services[svcName]
+	if svc.Placement == nil || len(svc.Placement.Spread) == 0 {
+		return nil
+	}
+	result := make([]apiv1.TopologySpreadConstraint, 0, len(svc.Placement.Spread))
+	for _, key := range svc.Placement.Spread {
+		result = append(result, apiv1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       key,
+			WhenUnsatisfiable: apiv1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: translateLabelSelector(svcName, s),
+			},
+		})
+	}
+	return result
 }
 
 func translateLabels(svcName string, s *model.Stack) map[string]string {
@@ -512,6 +633,10 @@ func translateLabels(svcName string, s *model.Stack) map[string]string {
 			labels[fmt.Sprintf("%s-%s", model.StackVolumeNameLabel, volume.LocalPath)] = "true"
 		}
 	}
+
+	for network := range svc.Networks {
+		labels[getNetworkLabel(network)] = "true"
+	}
 	return labels
 }
 
@@ -572,7 +697,9 @@ func getVolumeClaimName(v *model.StackVolume) string {
 }
 
 func translateSecurityContext(svc *model.Service) *apiv1.SecurityContext {
-	if len(svc.CapAdd) == 0 && len(svc.CapDrop) == 0 && svc.User == nil {
+	securityOpts, _ := model.ParseSecurityOpts(svc.SecurityOpt)
+	hasSecurityOpts := securityOpts.NoNewPrivileges || securityOpts.SELinuxOptions != nil || securityOpts.SeccompProfile != nil
+	if len(svc.CapAdd) == 0 && len(svc.CapDrop) == 0 && svc.User == nil && !svc.Privileged && !svc.ReadOnly && !hasSecurityOpts {
 		return nil
 	}
 	result := &apiv1.SecurityContext{Capabilities: &apiv1.Capabilities{}}
@@ -586,6 +713,85 @@ func translateSecurityContext(svc *model.Service) *apiv1.SecurityContext {
 		result.RunAsUser = svc.User.RunAsUser
 		result.RunAsGroup = svc.User.RunAsGroup
 	}
+	if svc.Privileged {
+		result.Privileged = pointer.BoolPtr(true)
+	} else if securityOpts.NoNewPrivileges {
+		// privileged containers always allow privilege escalation
+		result.AllowPrivilegeEscalation = pointer.BoolPtr(false)
+	}
+	if svc.ReadOnly {
+		result.ReadOnlyRootFilesystem = pointer.BoolPtr(true)
+	}
+	result.SELinuxOptions = securityOpts.SELinuxOptions
+	result.SeccompProfile = securityOpts.SeccompProfile
+	return result
+}
+
+func translatePodSecurityContext(svc *model.Service) *apiv1.PodSecurityContext {
+	if len(svc.Sysctls) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(svc.Sysctls))
+	for name := range svc.Sysctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := &apiv1.PodSecurityContext{}
+	for _, name := range names {
+		result.Sysctls = append(result.Sysctls, apiv1.Sysctl{Name: name, Value: svc.Sysctls[name]})
+	}
+	return result
+}
+
+// translateHostAliases groups the extra hosts of a service by ip
+func translateHostAliases(svc *model.Service) []apiv1.HostAlias {
+	if len(svc.ExtraHosts) == 0 {
+		return nil
+	}
+	hostsByIP := map[string][]string{}
+	for host, ip := range svc.ExtraHosts {
+		hostsByIP[ip] = append(hostsByIP[ip], host)
+	}
+	ips := make([]string, 0, len(hostsByIP))
+	for ip := range hostsByIP {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+	result := []apiv1.HostAlias{}
+	for _, ip := range ips {
+		hosts := hostsByIP[ip]
+		sort.Strings(hosts)
+		result = append(result, apiv1.HostAlias{IP: ip, Hostnames: hosts})
+	}
+	return result
+}
+
+// translateShareProcessNamespace emulates 'init: true': the pause container becomes PID 1 and reaps zombie processes
+func translateShareProcessNamespace(svc *model.Service) *bool {
+	if !svc.Init {
+		return nil
+	}
+	return pointer.BoolPtr(true)
+}
+
+// translateDNSConfig appends the dns options of a service to the cluster DNS configuration,
+// so the services of the stack can still be resolved
+func translateDNSConfig(svc *model.Service) *apiv1.PodDNSConfig {
+	if len(svc.DNS) == 0 && len(svc.DNSSearch) == 0 && len(svc.DNSOpt) == 0 {
+		return nil
+	}
+	result := &apiv1.PodDNSConfig{
+		Nameservers: svc.DNS,
+		Searches:    svc.DNSSearch,
+	}
+	for _, opt := range svc.DNSOpt {
+		option := apiv1.PodDNSConfigOption{Name: opt}
+		if name, value, found := strings.Cut(opt, ":"); found {
+			option.Name = name
+			option.Value = pointer.StringPtr(value)
+		}
+		result.Options = append(result.Options, option)
+	}
 	return result
 }
 
@@ -760,7 +966,8 @@ func getDeploymentUpdateStrategy(svc *model.Service) appsv1.DeploymentStrategy {
 	result := getUpdateStrategy(svc, &deploymentStrategyGetter{})
 	if result == rollingUpdateStrategy {
 		return appsv1.DeploymentStrategy{
-			Type: appsv1.RollingUpdateDeploymentStrategyType,
+			Type:          appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: translateRollingUpdateDeployment(svc.UpdateConfig),
 		}
 	}
 	return appsv1.DeploymentStrategy{
@@ -772,7 +979,8 @@ func getStatefulsetUpdateStrategy(svc *model.Service) appsv1.StatefulSetUpdateSt
 	result := getUpdateStrategy(svc, &statefulSetStrategyGetter{})
 	if result == rollingUpdateStrategy {
 		return appsv1.StatefulSetUpdateStrategy{
-			Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: translateRollingUpdateStatefulSet(svc.UpdateConfig),
 		}
 	}
 	return appsv1.StatefulSetUpdateStrategy{
@@ -780,6 +988,55 @@ func getStatefulsetUpdateStrategy(svc *model.Service) appsv1.StatefulSetUpdateSt
 	}
 }
 
+// translateRollingUpdateDeployment translates the parallelism and order of an update config into
+// the max surge and max unavailable pods of a deployment rolling update
+func translateRollingUpdateDeployment(config *model.UpdateConfig) *appsv1.RollingUpdateDeployment {
+	if config == nil {
+		return nil
+	}
+	parallelism := translateParallelism(config)
+	zero := intstr.FromInt(0)
+	if config.Order == model.UpdateOrderStartFirst {
+		return &appsv1.RollingUpdateDeployment{
+			MaxSurge:       &parallelism,
+			MaxUnavailable: &zero,
+		}
+	}
+	return &appsv1.RollingUpdateDeployment{
+		MaxSurge:       &zero,
+		MaxUnavailable: &parallelism,
+	}
+}
+
+// translateRollingUpdateStatefulSet translates the parallelism of an update config into
+// the max unavailable pods of a statefulset rolling update. Statefulsets always stop the old pods first
+func translateRollingUpdateStatefulSet(config *model.UpdateConfig) *appsv1.RollingUpdateStatefulSetStrategy {
+	if config == nil {
+		return nil
+	}
+	parallelism := translateParallelism(config)
+	return &appsv1.RollingUpdateStatefulSetStrategy{
+		MaxUnavailable: &parallelism,
+	}
+}
+
+func translateParallelism(config *model.UpdateConfig) intstr.IntOrString {
+	if config.Parallelism == nil {
+		return intstr.FromInt(1)
+	}
+	if *config.Parallelism == 0 {
+		return intstr.FromString("100%")
+	}
+	return intstr.FromInt(int(*config.Parallelism))
+}
+
+func translateMinReadySeconds(svc *model.Service) int32 {
+	if svc.UpdateConfig == nil {
+		return 0
+	}
+	return int32(svc.UpdateConfig.Delay)
+}
+
 func getUpdateStrategy(svc *model.Service, strategy updateStrategyGetter) updateStrategy {
 	if result := getUpdateStrategyByAnnotation(svc); result != "" {
 		err := strategy.validate(result)
@@ -788,6 +1045,9 @@ func getUpdateStrategy(svc *model.Service, strategy updateStrategyGetter) update
 		}
 		oktetoLog.Debugf("invalid strategy: %w", err)
 	}
+	if svc.UpdateConfig != nil {
+		return rollingUpdateStrategy
+	}
 	if result := getUpdateStrategyByEnvVar(); result != "" {
 		err := strategy.validate(result)
 		if err == nil {
@@ -23,6 +23,7 @@ import (
 	"github.com/okteto/okteto/pkg/model"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -1674,3 +1675,189 @@ func TestGetStrategyStrategy(t *testing.T) {
 		})
 	}
 }
+
+func Test_translateContainerOptions(t *testing.T) {
+	s := &model.Stack{
+		Name: "stack-test",
+		Services: map[string]*model.Service{
+			"api": {
+				Image:         "api",
+				RestartPolicy: apiv1.RestartPolicyAlways,
+				Replicas:      1,
+				Hostname:      "backend",
+				DomainName:    "internal",
+				ExtraHosts:    map[string]string{"db": "10.0.0.2", "cache": "10.0.0.2", "api": "10.0.0.1"},
+				Init:          true,
+				Privileged:    true,
+				ReadOnly:      true,
+				SecurityOpt:   []string{"no-new-privileges", "label:type:svirt_lxc_net_t", "apparmor:unconfined", "seccomp:unconfined"},
+				Sysctls:       map[string]string{"net.core.somaxconn": "1024", "kernel.shm_rmid_forced": "1"},
+				DNS:           []string{"8.8.8.8"},
+				DNSSearch:     []string{"example.com"},
+				DNSOpt:        []string{"ndots:2", "use-vc"},
+				Tty:           true,
+				StdinOpen:     true,
+			},
+		},
+	}
+
+	d := translateDeployment("api", s)
+	podSpec := d.Spec.Template.Spec
+	assert.Equal(t, "backend", podSpec.Hostname)
+	assert.Equal(t, "internal", podSpec.Subdomain)
+	assert.Equal(t, []apiv1.HostAlias{
+		{IP: "10.0.0.1", Hostnames: []string{"api"}},
+		{IP: "10.0.0.2", Hostnames: []string{"cache", "db"}},
+	}, podSpec.HostAliases)
+	assert.Equal(t, pointer.BoolPtr(true), podSpec.ShareProcessNamespace)
+	assert.Equal(t, &apiv1.PodSecurityContext{
+		Sysctls: []apiv1.Sysctl{
+			{Name: "kernel.shm_rmid_forced", Value: "1"},
+			{Name: "net.core.somaxconn", Value: "1024"},
+		},
+	}, podSpec.SecurityContext)
+	assert.Equal(t, &apiv1.PodDNSConfig{
+		Nameservers: []string{"8.8.8.8"},
+		Searches:    []string{"example.com"},
+		Options: []apiv1.PodDNSConfigOption{
+			{Name: "ndots", Value: pointer.StringPtr("2")},
+			{Name: "use-vc"},
+		},
+	}, podSpec.DNSConfig)
+	assert.Equal(t, "unconfined", d.Spec.Template.Annotations["container.apparmor.security.beta.kubernetes.io/api"])
+
+	container := podSpec.Containers[0]
+	assert.True(t, container.TTY)
+	assert.True(t, container.Stdin)
+	assert.Equal(t, &apiv1.SecurityContext{
+		Capabilities:           &apiv1.Capabilities{},
+		Privileged:             pointer.BoolPtr(true),
+		ReadOnlyRootFilesystem: pointer.BoolPtr(true),
+		SELinuxOptions:         &apiv1.SELinuxOptions{Type: "svirt_lxc_net_t"},
+		SeccompProfile:         &apiv1.SeccompProfile{Type: apiv1.SeccompProfileTypeUnconfined},
+	}, container.SecurityContext)
+
+	s.Services["api"].Privileged = false
+	container = translateJob("api", s).Spec.Template.Spec.Containers[0]
+	assert.Equal(t, pointer.BoolPtr(false), container.SecurityContext.AllowPrivilegeEscalation)
+	assert.Nil(t, container.SecurityContext.Privileged)
+
+	sfs := translateStatefulSet("api", s)
+	assert.Empty(t, sfs.Spec.Template.Spec.Hostname)
+	assert.Len(t, sfs.Spec.Template.Spec.HostAliases, 2)
+}
+
+func Test_translateRolloutAndPlacement(t *testing.T) {
+	s := &model.Stack{
+		Name: "stack-test",
+		Services: map[string]*model.Service{
+			"api": {
+				Image:         "api",
+				RestartPolicy: apiv1.RestartPolicyAlways,
+				Replicas:      3,
+				UpdateConfig: &model.UpdateConfig{
+					Parallelism: pointer.Int32(2),
+					Delay:       10,
+					Order:       model.UpdateOrderStartFirst,
+				},
+				Placement: &model.Placement{
+					Constraints: []model.PlacementConstraint{
+						{Key: "disk", Value: "ssd"},
+						{Key: "kubernetes.io/arch", Value: "amd64", Negated: true},
+					},
+					Spread: []string{"zone"},
+				},
+			},
+		},
+	}
+
+	d := translateDeployment("api", s)
+	two := intstr.FromInt(2)
+	zero := intstr.FromInt(0)
+	assert.Equal(t, appsv1.DeploymentStrategy{
+		Type:          appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: &two, MaxUnavailable: &zero},
+	}, d.Spec.Strategy)
+	assert.Equal(t, int32(10), d.Spec.MinReadySeconds)
+	assert.Equal(t, &apiv1.Affinity{
+		NodeAffinity: &apiv1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &apiv1.NodeSelector{
+				NodeSelectorTerms: []apiv1.NodeSelectorTerm{
+					{
+						MatchExpressions: []apiv1.NodeSelectorRequirement{
+							{Key: "disk", Operator: apiv1.NodeSelectorOpIn, Values: []string{"ssd"}},
+							{Key: "kubernetes.io/arch", Operator: apiv1.NodeSelectorOpNotIn, Values: []string{"amd64"}},
+						},
+					},
+				},
+			},
+		},
+	}, d.Spec.Template.Spec.Affinity)
+	assert.Equal(t, []apiv1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       "zone",
+			WhenUnsatisfiable: apiv1.ScheduleAnyway,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: translateLabelSelector("api", s)},
+		},
+	}, d.Spec.Template.Spec.TopologySpreadConstraints)
+
+	s.Services["api"].UpdateConfig.Order = model.UpdateOrderStopFirst
+	s.Services["api"].UpdateConfig.Parallelism = pointer.Int32(0)
+	d = translateDeployment("api", s)
+	all := intstr.FromString("100%")
+	assert.Equal(t, &appsv1.RollingUpdateDeployment{MaxSurge: &zero, MaxUnavailable: &all}, d.Spec.Strategy.RollingUpdate)
+
+	s.Services["api"].UpdateConfig.Parallelism = nil
+	d = translateDeployment("api", s)
+	one := intstr.FromInt(1)
+	assert.Equal(t, &appsv1.RollingUpdateDeployment{MaxSurge: &zero, MaxUnavailable: &one}, d.Spec.Strategy.RollingUpdate)
+
+	s.Services["api"].UpdateConfig.Parallelism = pointer.Int32(0)
+	sfs := translateStatefulSet("api", s)
+	assert.Equal(t, appsv1.StatefulSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{MaxUnavailable: &all},
+	}, sfs.Spec.UpdateStrategy)
+	assert.NotNil(t, sfs.Spec.Template.Spec.Affinity.NodeAffinity)
+
+	s.Services["api"].Annotations = model.Annotations{model.OktetoComposeUpdateStrategyAnnotation: "recreate"}
+	d = translateDeployment("api", s)
+	assert.Equal(t, appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}, d.Spec.Strategy)
+}
+
+func Test_translateCronJob(t *testing.T) {
+	s := &model.Stack{
+		Name: "stack-test",
+		Services: map[string]*model.Service{
+			"cleanup": {
+				Image:         "cleanup",
+				RestartPolicy: apiv1.RestartPolicyOnFailure,
+				Replicas:      1,
+				Schedule: &model.ServiceSchedule{
+					Cron:                       "0 3 * * *",
+					ConcurrencyPolicy:          model.ScheduleConcurrencyForbid,
+					SuccessfulJobsHistoryLimit: pointer.Int32Ptr(1),
+					FailedJobsHistoryLimit:     pointer.Int32Ptr(2),
+				},
+			},
+		},
+	}
+
+	cronjob := translateCronJob("cleanup", s)
+	assert.Equal(t, "cleanup", cronjob.Name)
+	assert.Equal(t, translateLabels("cleanup", s), cronjob.Labels)
+	assert.Equal(t, "0 3 * * *", cronjob.Spec.Schedule)
+	assert.Equal(t, batchv1.ForbidConcurrent, cronjob.Spec.ConcurrencyPolicy)
+	assert.Equal(t, pointer.BoolPtr(false), cronjob.Spec.Suspend)
+	assert.Equal(t, pointer.Int32Ptr(1), cronjob.Spec.SuccessfulJobsHistoryLimit)
+	assert.Equal(t, pointer.Int32Ptr(2), cronjob.Spec.FailedJobsHistoryLimit)
+	assert.Empty(t, cronjob.Spec.JobTemplate.Labels)
+	assert.Equal(t, translateJob("cleanup", s).Spec, cronjob.Spec.JobTemplate.Spec)
+	assert.Equal(t, apiv1.RestartPolicyOnFailure, cronjob.Spec.JobTemplate.Spec.Template.Spec.RestartPolicy)
+
+	now := time.Date(2022, time.September, 30, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"cleanup: '0 3 * * *' (next run at Sat, 01 Oct 2022 03:00:00 UTC)"}, getScheduledServicesInfo(s, now))
+	s.Services["cleanup"].Schedule.Suspend = true
+	assert.Equal(t, []string{"cleanup: '0 3 * * *' (suspended)"}, getScheduledServicesInfo(s, now))
+}
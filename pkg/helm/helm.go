@@ -0,0 +1,214 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+const (
+	// storageDriver is the same driver used by default by the helm cli, so releases can be managed with both
+	storageDriver = "secret"
+
+	defaultTimeout = 5 * time.Minute
+
+	// InstanceLabel is the label set by the charts to the resources of a release
+	InstanceLabel = "app.kubernetes.io/instance"
+)
+
+var (
+	// ReleaseDeployedStatus is the status of a release successfully installed or upgraded
+	ReleaseDeployedStatus = release.StatusDeployed.String()
+
+	// ReleaseFailedStatus is the status of a release whose last install or upgrade failed
+	ReleaseFailedStatus = release.StatusFailed.String()
+)
+
+// ReleaseOptions represents the options to install or upgrade a release
+type ReleaseOptions struct {
+	Name        string
+	Namespace   string
+	Chart       string
+	Repository  string
+	Version     string
+	ValuesFiles []string
+	Set         map[string]string
+	Wait        bool
+	Timeout     time.Duration
+}
+
+// Client manages helm releases without the helm binary
+type Client struct {
+	settings *cli.EnvSettings
+}
+
+// NewClient returns a helm client that talks to the cluster defined in kubeconfigPath
+func NewClient(kubeconfigPath string) *Client {
+	settings := cli.New()
+	settings.KubeConfig = kubeconfigPath
+	return &Client{
+		settings: settings,
+	}
+}
+
+func (c *Client) getActionConfig(namespace string) (*action.Configuration, error) {
+	c.settings.SetNamespace(namespace)
+	cfg := &action.Configuration{}
+	if err := cfg.Init(c.settings.RESTClientGetter(), namespace, storageDriver, oktetoLog.Infof); err != nil {
+		return nil, fmt.Errorf("error initializing helm: %w", err)
+	}
+	return cfg, nil
+}
+
+// UpgradeInstall upgrades a release, or installs it if it doesn't exist, like 'helm upgrade --install'
+func (c *Client) UpgradeInstall(opts *ReleaseOptions) (*release.Release, error) {
+	cfg, err := c.getActionConfig(opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	chartPathOptions := action.ChartPathOptions{
+		RepoURL: opts.Repository,
+		Version: opts.Version,
+	}
+	ch, err := c.loadChart(chartPathOptions, opts.Chart)
+	if err != nil {
+		return nil, err
+	}
+
+	valueOpts := &values.Options{
+		ValueFiles: opts.ValuesFiles,
+		Values:     getSetValues(opts.Set),
+	}
+	vals, err := valueOpts.MergeValues(getter.All(c.settings))
+	if err != nil {
+		return nil, fmt.Errorf("error reading values of helm release '%s': %w", opts.Name, err)
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	exists, err := releaseExists(cfg, opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		install := action.NewInstall(cfg)
+		install.ChartPathOptions = chartPathOptions
+		install.ReleaseName = opts.Name
+		install.Namespace = opts.Namespace
+		install.Wait = opts.Wait
+		install.Timeout = timeout
+		rel, err := install.Run(ch, vals)
+		if err != nil {
+			return nil, fmt.Errorf("error installing helm release '%s': %w", opts.Name, err)
+		}
+		return rel, nil
+	}
+
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.ChartPathOptions = chartPathOptions
+	upgrade.Namespace = opts.Namespace
+	upgrade.Wait = opts.Wait
+	upgrade.Timeout = timeout
+	rel, err := upgrade.Run(opts.Name, ch, vals)
+	if err != nil {
+		return nil, fmt.Errorf("error upgrading helm release '%s': %w", opts.Name, err)
+	}
+	return rel, nil
+}
+
+// Uninstall uninstalls a release. It doesn't fail if the release doesn't exist
+func (c *Client) Uninstall(name, namespace string) error {
+	cfg, err := c.getActionConfig(namespace)
+	if err != nil {
+		return err
+	}
+	if _, err := action.NewUninstall(cfg).Run(name); err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			oktetoLog.Infof("helm release '%s' not found", name)
+			return nil
+		}
+		return fmt.Errorf("error uninstalling helm release '%s': %w", name, err)
+	}
+	return nil
+}
+
+// GetReleaseStatus returns the status of the last revision of a release, like 'helm status'
+func (c *Client) GetReleaseStatus(name, namespace string) (string, error) {
+	cfg, err := c.getActionConfig(namespace)
+	if err != nil {
+		return "", err
+	}
+	rel, err := action.NewStatus(cfg).Run(name)
+	if err != nil {
+		return "", fmt.Errorf("error getting status of helm release '%s': %w", name, err)
+	}
+	if rel.Info == nil {
+		return release.StatusUnknown.String(), nil
+	}
+	return rel.Info.Status.String(), nil
+}
+
+func (c *Client) loadChart(chartPathOptions action.ChartPathOptions, name string) (*chart.Chart, error) {
+	chartPath, err := chartPathOptions.LocateChart(name, c.settings)
+	if err != nil {
+		return nil, fmt.Errorf("error locating helm chart '%s': %w", name, err)
+	}
+	ch, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading helm chart '%s': %w", name, err)
+	}
+	if ch.Metadata.Deprecated {
+		oktetoLog.Warning("Helm chart '%s' is deprecated", name)
+	}
+	return ch, nil
+}
+
+func releaseExists(cfg *action.Configuration, name string) (bool, error) {
+	history := action.NewHistory(cfg)
+	history.Max = 1
+	if _, err := history.Run(name); err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error getting history of helm release '%s': %w", name, err)
+	}
+	return true, nil
+}
+
+// getSetValues translates the 'set' values into the helm '--set' format, sorted to be deterministic
+func getSetValues(set map[string]string) []string {
+	result := []string{}
+	for key, value := range set {
+		result = append(result, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,36 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSetValues(t *testing.T) {
+	set := map[string]string{
+		"image.tag":        "dev",
+		"ingress.enabled":  "true",
+		"env[0].name":      "KEY",
+		"annotations.name": "a=b",
+	}
+	assert.Equal(t, []string{
+		"annotations.name=a=b",
+		"env[0].name=KEY",
+		"image.tag=dev",
+		"ingress.enabled=true",
+	}, getSetValues(set))
+	assert.Empty(t, getSetValues(nil))
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronjobs
+
+import (
+	"context"
+	"fmt"
+
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Deploy creates or updates a cronjob
+func Deploy(ctx context.Context, cronjob *batchv1.CronJob, c kubernetes.Interface) (*batchv1.CronJob, error) {
+	cronjob.ResourceVersion = ""
+	result, err := c.BatchV1().CronJobs(cronjob.Namespace).Update(ctx, cronjob, metav1.UpdateOptions{})
+	if err == nil {
+		return result, nil
+	}
+
+	if !oktetoErrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	return c.BatchV1().CronJobs(cronjob.Namespace).Create(ctx, cronjob, metav1.CreateOptions{})
+}
+
+// Get returns a cronjob given its name and namespace
+func Get(ctx context.Context, name, namespace string, c kubernetes.Interface) (*batchv1.CronJob, error) {
+	return c.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// List returns the cronjobs of a namespace that match a label selector
+func List(ctx context.Context, namespace, labels string, c kubernetes.Interface) ([]batchv1.CronJob, error) {
+	cronjobList, err := c.BatchV1().CronJobs(namespace).List(
+		ctx,
+		metav1.ListOptions{
+			LabelSelector: labels,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return cronjobList.Items, nil
+}
+
+// Destroy deletes a cronjob and the jobs it created
+func Destroy(ctx context.Context, name, namespace string, c kubernetes.Interface) error {
+	oktetoLog.Infof("deleting cronjob '%s'", name)
+	deletePropagation := metav1.DeletePropagationBackground
+	err := c.BatchV1().CronJobs(namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &deletePropagation})
+	if err != nil {
+		if oktetoErrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error deleting kubernetes cronjob: %s", err)
+	}
+	oktetoLog.Infof("cronjob '%s' deleted", name)
+	return nil
+}
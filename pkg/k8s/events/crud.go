@@ -17,12 +17,16 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 )
 
+const podEventsSelector = "involvedObject.kind=Pod"
+
 func List(ctx context.Context, namespace, podName string, c kubernetes.Interface) ([]apiv1.Event, error) {
 	events, err := c.CoreV1().Events(namespace).List(
 		ctx,
@@ -54,3 +58,51 @@ func GetUnhealthyEventFailure(ctx context.Context, namespace, podName string, c
 	}
 	return ""
 }
+
+// ListPodEvents returns the events of the pods of a namespace
+func ListPodEvents(ctx context.Context, namespace string, c kubernetes.Interface) (*apiv1.EventList, error) {
+	return c.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: podEventsSelector})
+}
+
+// WatchPodEvents watches the events of the pods of a namespace since a resource version
+func WatchPodEvents(ctx context.Context, namespace, resourceVersion string, c kubernetes.Interface) (watch.Interface, error) {
+	return c.CoreV1().Events(namespace).Watch(ctx, metav1.ListOptions{
+		Watch:           true,
+		FieldSelector:   podEventsSelector,
+		ResourceVersion: resourceVersion,
+	})
+}
+
+// GetLastWarningMessage returns the message of the most recent warning event of a pod
+func GetLastWarningMessage(ctx context.Context, namespace, podName string, c kubernetes.Interface) string {
+	events, err := List(ctx, namespace, podName, c)
+	if err != nil {
+		return ""
+	}
+	var last *apiv1.Event
+	for i := range events {
+		if events[i].Type != apiv1.EventTypeWarning {
+			continue
+		}
+		if last == nil || !GetTime(&events[i]).Before(GetTime(last)) {
+			last = &events[i]
+		}
+	}
+	if last == nil {
+		return ""
+	}
+	return last.Message
+}
+
+// GetTime returns the last time an event was observed
+func GetTime(e *apiv1.Event) time.Time {
+	switch {
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime.Time
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
@@ -20,6 +20,7 @@ import (
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	oktetoLog "github.com/okteto/okteto/pkg/log"
 	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -91,3 +92,17 @@ func IsFailed(ctx context.Context, namespace, jobName string, c kubernetes.Inter
 	}
 	return job.Status.Failed > 0 && job.Status.Failed >= *job.Spec.BackoffLimit
 }
+
+// GetFailedMessage returns the message of the failed condition of a job
+func GetFailedMessage(ctx context.Context, namespace, jobName string, c kubernetes.Interface) string {
+	job, err := c.BatchV1().Jobs(namespace).Get(ctx, jobName, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == apiv1.ConditionTrue {
+			return condition.Message
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pods
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AddEphemeralContainer adds an ephemeral container to a running pod
+func AddEphemeralContainer(ctx context.Context, p *apiv1.Pod, container apiv1.EphemeralContainer, c kubernetes.Interface) error {
+	p = p.DeepCopy()
+	p.Spec.EphemeralContainers = append(p.Spec.EphemeralContainers, container)
+	if _, err := c.CoreV1().Pods(p.Namespace).UpdateEphemeralContainers(ctx, p.Name, p, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to add the ephemeral container '%s' to pod '%s': %w", container.Name, p.Name, err)
+	}
+	return nil
+}
+
+// WaitUntilEphemeralContainerIsRunning waits until an ephemeral container of a pod is running
+func WaitUntilEphemeralContainerIsRunning(ctx context.Context, podName, containerName, namespace string, timeout time.Duration, c kubernetes.Interface) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	to := time.Now().Add(timeout)
+
+	for retries := 0; ; retries++ {
+		p, err := c.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get pod '%s': %w", podName, err)
+		}
+
+		running, err := isEphemeralContainerRunning(p, containerName)
+		if err != nil {
+			return err
+		}
+		if running {
+			return nil
+		}
+
+		if time.Now().After(to) && retries > 10 {
+			return fmt.Errorf("ephemeral container '%s' didn't start after %s", containerName, timeout.String())
+		}
+
+		select {
+		case <-ticker.C:
+			if retries%5 == 0 {
+				oktetoLog.Infof("ephemeral container '%s' is not running yet", containerName)
+			}
+		case <-ctx.Done():
+			oktetoLog.Debug("call to pods.WaitUntilEphemeralContainerIsRunning cancelled")
+			return ctx.Err()
+		}
+	}
+}
+
+func isEphemeralContainerRunning(p *apiv1.Pod, containerName string) (bool, error) {
+	for _, status := range p.Status.EphemeralContainerStatuses {
+		if status.Name != containerName {
+			continue
+		}
+		switch {
+		case status.State.Running != nil:
+			return true, nil
+		case status.State.Terminated != nil:
+			return false, fmt.Errorf("ephemeral container '%s' terminated: %s", containerName, status.State.Terminated.Reason)
+		case status.State.Waiting != nil:
+			switch status.State.Waiting.Reason {
+			case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerError":
+				return false, fmt.Errorf("ephemeral container '%s' failed to start: %s", containerName, status.State.Waiting.Message)
+			}
+		}
+	}
+	return false, nil
+}
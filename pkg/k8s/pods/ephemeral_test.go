@@ -0,0 +1,102 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pods
+
+import (
+	"context"
+	"testing"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAddEphemeralContainer(t *testing.T) {
+	ctx := context.Background()
+	p := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "api",
+			Namespace: "test",
+		},
+	}
+	c := fake.NewSimpleClientset(p)
+
+	container := apiv1.EphemeralContainer{
+		EphemeralContainerCommon: apiv1.EphemeralContainerCommon{Name: "okteto-sync"},
+		TargetContainerName:      "api",
+	}
+	if err := AddEphemeralContainer(ctx, p, container, c); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.CoreV1().Pods("test").Get(ctx, "api", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Spec.EphemeralContainers) != 1 || result.Spec.EphemeralContainers[0].Name != "okteto-sync" {
+		t.Errorf("ephemeral container wasn't added: %+v", result.Spec.EphemeralContainers)
+	}
+	if len(p.Spec.EphemeralContainers) != 0 {
+		t.Errorf("the original pod was modified")
+	}
+}
+
+func Test_isEphemeralContainerRunning(t *testing.T) {
+	var tests = []struct {
+		name        string
+		state       apiv1.ContainerState
+		expected    bool
+		expectError bool
+	}{
+		{
+			name:     "running",
+			state:    apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+			expected: true,
+		},
+		{
+			name:  "creating",
+			state: apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+		},
+		{
+			name:        "image-pull-error",
+			state:       apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			expectError: true,
+		},
+		{
+			name:        "terminated",
+			state:       apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{Reason: "Error"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &apiv1.Pod{
+				Status: apiv1.PodStatus{
+					EphemeralContainerStatuses: []apiv1.ContainerStatus{
+						{Name: "other", State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+						{Name: "okteto-sync", State: tt.state},
+					},
+				},
+			}
+			result, err := isEphemeralContainerRunning(p, "okteto-sync")
+			if tt.expectError != (err != nil) {
+				t.Fatalf("expected error %t, got %v", tt.expectError, err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, result)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package replicasets
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/model"
@@ -42,3 +43,45 @@ func GetReplicaSetByDeployment(ctx context.Context, d *appsv1.Deployment, c kube
 	}
 	return nil, oktetoErrors.ErrNotFound
 }
+
+// GetPreviousReplicaSetByDeployment given a deployment, returns the replica set of its previous revision or an error
+func GetPreviousReplicaSetByDeployment(ctx context.Context, d *appsv1.Deployment, c kubernetes.Interface) (*appsv1.ReplicaSet, error) {
+	current, err := strconv.ParseInt(d.Annotations[model.DeploymentRevisionAnnotation], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision of deployment '%s': %w", d.Name, err)
+	}
+
+	rsList, err := c.AppsV1().ReplicaSets(d.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replicasets: %v", err)
+	}
+
+	var result *appsv1.ReplicaSet
+	var resultRevision int64
+	for i := range rsList.Items {
+		if !isOwnedBy(&rsList.Items[i], d) {
+			continue
+		}
+		revision, err := strconv.ParseInt(rsList.Items[i].Annotations[model.DeploymentRevisionAnnotation], 10, 64)
+		if err != nil || revision >= current {
+			continue
+		}
+		if result == nil || revision > resultRevision {
+			result = &rsList.Items[i]
+			resultRevision = revision
+		}
+	}
+	if result == nil {
+		return nil, oktetoErrors.ErrNotFound
+	}
+	return result, nil
+}
+
+func isOwnedBy(rs *appsv1.ReplicaSet, d *appsv1.Deployment) bool {
+	for _, or := range rs.OwnerReferences {
+		if or.UID == d.UID {
+			return true
+		}
+	}
+	return false
+}
@@ -61,9 +61,9 @@ func Create(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset, s *syn
 		return fmt.Errorf("error getting kubernetes secret: %s", err)
 	}
 
-	config, err := getConfigXML(s)
+	syncthingData, err := GetSyncthingData(s)
 	if err != nil {
-		return fmt.Errorf("error generating syncthing configuration: %s", err)
+		return err
 	}
 	data := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -73,11 +73,7 @@ func Create(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset, s *syn
 			},
 		},
 		Type: v1.SecretTypeOpaque,
-		Data: map[string][]byte{
-			"config.xml": config,
-			"cert.pem":   []byte(certPEM),
-			"key.pem":    []byte(keyPEM),
-		},
+		Data: syncthingData,
 	}
 
 	idx := 0
@@ -112,6 +108,19 @@ func Create(ctx context.Context, dev *model.Dev, c *kubernetes.Clientset, s *syn
 	return nil
 }
 
+// GetSyncthingData returns the configuration and certificates of the remote syncthing
+func GetSyncthingData(s *syncthing.Syncthing) (map[string][]byte, error) {
+	config, err := getConfigXML(s)
+	if err != nil {
+		return nil, fmt.Errorf("error generating syncthing configuration: %s", err)
+	}
+	return map[string][]byte{
+		"config.xml": config,
+		"cert.pem":   []byte(certPEM),
+		"key.pem":    []byte(keyPEM),
+	}, nil
+}
+
 // Destroy deletes the syncthing config secret
 func Destroy(ctx context.Context, dev *model.Dev, c kubernetes.Interface) error {
 	secretName := GetSecretName(dev)
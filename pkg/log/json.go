@@ -36,6 +36,14 @@ type jsonMessage struct {
 	Timestamp int64  `json:"timestamp"`
 }
 
+type jsonEvent struct {
+	Level     string      `json:"level"`
+	Stage     string      `json:"stage"`
+	Event     string      `json:"event"`
+	Data      interface{} `json:"data"`
+	Timestamp int64       `json:"timestamp"`
+}
+
 // JSONLogFormat formats the messages into json struct
 type JSONLogFormat struct {
 	Level     string `json:"level"`
@@ -299,3 +307,19 @@ func (w *JSONWriter) Write(p []byte) (n int, err error) {
 	}
 	return w.out.Out.Write([]byte(msg))
 }
+
+// convertEventToJSON returns the json line of an event with structured data
+func convertEventToJSON(stage, event string, data interface{}) (string, error) {
+	eventStruct := jsonEvent{
+		Level:     InfoLevel,
+		Stage:     stage,
+		Event:     event,
+		Data:      data,
+		Timestamp: time.Now().Unix(),
+	}
+	eventJSON, err := json.Marshal(eventStruct)
+	if err != nil {
+		return "", err
+	}
+	return string(eventJSON), nil
+}
@@ -311,16 +311,24 @@ func IsInteractive() bool {
 	return log.writer.IsInteractive()
 }
 
-// AddMaskedWord adds a new
+// AddMaskedWord adds a new word to be redacted. It is redacted right away if masking is enabled
 func AddMaskedWord(word string) {
-	if strings.TrimSpace(word) != "" {
-		log.maskedWords = append(log.maskedWords, word)
+	if strings.TrimSpace(word) == "" {
+		return
+	}
+	log.maskedWords = append(log.maskedWords, word)
+	if log.isMasked {
+		updateReplacer()
 	}
 }
 
 // EnableMasking starts redacting all variables
 func EnableMasking() {
 	log.isMasked = true
+	updateReplacer()
+}
+
+func updateReplacer() {
 	sort.Slice(log.maskedWords, func(i, j int) bool {
 		return len(log.maskedWords[i]) > len(log.maskedWords[j])
 	})
@@ -349,6 +357,22 @@ func GetOutputBuffer() *bytes.Buffer {
 	return log.buf
 }
 
+// AddEvent writes an event with structured data when the output format is json. Other formats ignore it
+func AddEvent(event string, data interface{}) {
+	if log.outputMode != JSONFormat {
+		return
+	}
+	msg, err := convertEventToJSON(log.stage, event, data)
+	if err != nil {
+		Infof("could not marshal event '%s': %s", event, err)
+		return
+	}
+	msg = redactMessage(msg)
+	log.buf.WriteString(msg)
+	log.buf.WriteString("\n")
+	fmt.Fprintln(log.out.Out, msg)
+}
+
 // AddToBuffer logs into the buffer but does not print anything
 func AddToBuffer(level, format string, args ...interface{}) {
 	log.writer.AddToBuffer(level, format, args...)
@@ -14,8 +14,11 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -70,3 +73,22 @@ func Test_GetContextResource(t *testing.T) {
 		})
 	}
 }
+
+func Test_AddEvent(t *testing.T) {
+	Init(logrus.WarnLevel)
+	out := &bytes.Buffer{}
+	log.out.SetOutput(out)
+	defer Init(logrus.WarnLevel)
+
+	SetStage("deploy")
+	AddEvent("commands-summary", []string{"migrate"})
+	assert.Empty(t, out.String())
+
+	SetOutputFormat(JSONFormat)
+	AddEvent("commands-summary", []string{"migrate"})
+	event := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &event))
+	assert.Equal(t, "commands-summary", event["event"])
+	assert.Equal(t, "deploy", event["stage"])
+	assert.Equal(t, []interface{}{"migrate"}, event["data"])
+}
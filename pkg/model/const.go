@@ -37,6 +37,15 @@ const (
 	// OktetoComposeUpdateStrategyAnnotation indicates how a compose service must be updated
 	OktetoComposeUpdateStrategyAnnotation = "dev.okteto.com/update"
 
+	// OktetoConfigsChecksumAnnotation is the checksum of the configs and secrets mounted by a compose service
+	OktetoConfigsChecksumAnnotation = "dev.okteto.com/configs-checksum"
+
+	// OktetoComposeRestartedAtAnnotation is the time a compose service was restarted after redeploying one of its dependencies
+	OktetoComposeRestartedAtAnnotation = "dev.okteto.com/restarted-at"
+
+	// OktetoComposeRolledBackAnnotation is the revision a compose service was rolled back to after a failed update
+	OktetoComposeRolledBackAnnotation = "dev.okteto.com/rolled-back-to-revision"
+
 	// DetachedDevLabel indicates the detached dev pods
 	DetachedDevLabel = "detached.dev.okteto.com"
 
@@ -82,6 +91,9 @@ const (
 	// DeployedByLabel indicates the service account that deployed an object
 	DeployedByLabel = "dev.okteto.com/deployed-by"
 
+	// KubernetesManifestLabel indicates the object was applied from the 'deploy.kubernetes' section of the okteto manifest
+	KubernetesManifestLabel = "dev.okteto.com/kubernetes-manifest"
+
 	// GitDeployLabel indicates the object is an app
 	GitDeployLabel = "dev.okteto.com/git-deploy"
 
@@ -112,6 +124,18 @@ const (
 	// StackVolumeNameLabel indicates the name of the stack volume an object belongs to
 	StackVolumeNameLabel = "stack.okteto.com/volume"
 
+	// StackConfigNameLabel indicates the name of the stack config a configmap belongs to
+	StackConfigNameLabel = "stack.okteto.com/config"
+
+	// StackSecretNameLabel indicates the name of the stack secret a secret belongs to
+	StackSecretNameLabel = "stack.okteto.com/secret"
+
+	// StackNetworkNameLabel indicates the name of the stack network an object belongs to
+	StackNetworkNameLabel = "stack.okteto.com/network"
+
+	// StackNetworkAliasLabel indicates the network alias a kubernetes service is created for
+	StackNetworkAliasLabel = "stack.okteto.com/alias"
+
 	// Deployment k8s deployemnt kind
 	Deployment = "Deployment"
 	// StatefulSet k8s statefulset kind
@@ -148,6 +172,16 @@ const (
 	SyncthingSubPath = "syncthing"
 	// DefaultSyncthingRescanInterval default syncthing re-scan interval
 	DefaultSyncthingRescanInterval = 300
+	// SyncModeTwoWay synchronizes the changes of a sync folder in both directions
+	SyncModeTwoWay SyncMode = "two-way"
+	// SyncModeSendOnly only sends the local changes of a sync folder to the development container
+	SyncModeSendOnly SyncMode = "send-only"
+	// SyncModeReceiveOnly only receives the changes of a sync folder from the development container
+	SyncModeReceiveOnly SyncMode = "receive-only"
+	// SyncEngineSyncthing synchronizes the files of the development container with syncthing
+	SyncEngineSyncthing SyncEngine = "syncthing"
+	// SyncEngineSSH pushes the local changes to the development container over its SSH server
+	SyncEngineSSH SyncEngine = "ssh"
 	// RemoteSubPath subpath in the development container persistent volume for the remote data
 	RemoteSubPath = "okteto-remote"
 	// OktetoURLAnnotation indicates the okteto cluster public url
@@ -188,6 +222,9 @@ const (
 	// OktetoGitBranchEnvVar is the name of the Git branch currently being deployed.
 	OktetoGitBranchEnvVar = "OKTETO_GIT_BRANCH"
 
+	// OktetoOutputEnvVar is the path of the file where deploy commands write the values they want to publish
+	OktetoOutputEnvVar = "OKTETO_OUTPUT"
+
 	// OktetoGitCommitEnvVar is the SHA1 hash of the last commit of the branch.
 	OktetoGitCommitEnvVar = "OKTETO_GIT_COMMIT"
 
@@ -313,6 +350,9 @@ const (
 	// ComposeFileEnvVar defines the compose files to use
 	ComposeFileEnvVar = "COMPOSE_FILE"
 
+	// ComposeProfilesEnvVar defines the compose profiles to enable
+	ComposeProfilesEnvVar = "COMPOSE_PROFILES"
+
 	// BuildkitProgressEnvVar defines the output of buildkit
 	BuildkitProgressEnvVar = "BUILDKIT_PROGRESS"
 
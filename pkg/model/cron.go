@@ -0,0 +1,220 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule represents a parsed cron expression with the syntax accepted by kubernetes cronjobs
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// hourStar, domStar and dowStar are true when the hour, day of month or day of week fields match any value
+	hourStar, domStar, dowStar bool
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinutes = cronField{min: 0, max: 59}
+	cronHours   = cronField{min: 0, max: 23}
+	cronDom     = cronField{min: 1, max: 31}
+	cronMonths  = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	cronDow = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// ParseCronSchedule parses a five fields cron expression or one of the predefined schedules like '@hourly'
+func ParseCronSchedule(expression string) (*CronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if descriptor, ok := cronDescriptors[strings.ToLower(expression)]; ok {
+		expression = descriptor
+	}
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule '%s': expected 5 fields, found %d", expression, len(fields))
+	}
+
+	result := &CronSchedule{}
+	var err error
+	if result.minute, _, err = cronMinutes.parse(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid schedule '%s': %w", expression, err)
+	}
+	if result.hour, result.hourStar, err = cronHours.parse(fields[1]); err != nil {
+		return nil, fmt.Errorf("invalid schedule '%s': %w", expression, err)
+	}
+	if result.dom, result.domStar, err = cronDom.parse(fields[2]); err != nil {
+		return nil, fmt.Errorf("invalid schedule '%s': %w", expression, err)
+	}
+	if result.month, _, err = cronMonths.parse(fields[3]); err != nil {
+		return nil, fmt.Errorf("invalid schedule '%s': %w", expression, err)
+	}
+	if result.dow, result.dowStar, err = cronDow.parse(fields[4]); err != nil {
+		return nil, fmt.Errorf("invalid schedule '%s': %w", expression, err)
+	}
+	// sunday can be written as 0 or 7
+	if result.dow&(1<<7) != 0 {
+		result.dow |= 1
+	}
+	return result, nil
+}
+
+// parse returns the bits of the values matched by a cron field and if the field matches any value
+func (f cronField) parse(field string) (uint64, bool, error) {
+	var result uint64
+	for _, item := range strings.Split(field, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepExpr)
+			if err != nil || step <= 0 {
+				return 0, false, fmt.Errorf("invalid step '%s'", item)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rangeExpr == "*" || rangeExpr == "?":
+			start, end = f.min, f.max
+			if step == 1 && field == item {
+				return f.bits(start, end, 1), true, nil
+			}
+		case strings.Contains(rangeExpr, "-"):
+			startExpr, endExpr, _ := strings.Cut(rangeExpr, "-")
+			var err error
+			if start, err = f.value(startExpr); err != nil {
+				return 0, false, err
+			}
+			if end, err = f.value(endExpr); err != nil {
+				return 0, false, err
+			}
+		default:
+			var err error
+			if start, err = f.value(rangeExpr); err != nil {
+				return 0, false, err
+			}
+			end = start
+			if hasStep {
+				end = f.max
+			}
+		}
+		if start > end {
+			return 0, false, fmt.Errorf("invalid range '%s'", item)
+		}
+		result |= f.bits(start, end, step)
+	}
+	return result, false, nil
+}
+
+func (f cronField) value(expr string) (int, error) {
+	if v, ok := f.names[strings.ToLower(expr)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s'", expr)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value '%d' out of range [%d-%d]", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (cronField) bits(start, end, step int) uint64 {
+	var result uint64
+	for i := start; i <= end; i += step {
+		result |= 1 << uint(i)
+	}
+	return result
+}
+
+// Next returns the first time after t that matches the schedule, or the zero time if there is none in the next five years
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = startOfDay(t.Year(), t.Month()+1, 1, t.Location())
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = startOfDay(t.Year(), t.Month(), t.Day()+1, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			// adding the minutes left instead of building the next hour skips the hours that don't exist because of daylight saving time
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = c.nextMinute(t)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// startOfDay returns the first time of a day, that isn't midnight when the clock is moved forward at midnight
+func startOfDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	if t.Hour() > 12 {
+		// the missing midnight was normalized to the previous day
+		t = t.Add(time.Hour)
+	}
+	return t
+}
+
+// nextMinute returns the minute after t. When the clock goes back because of a daylight saving time change,
+// the repeated hour is skipped unless the hour field matches any value, so schedules at a fixed time run only once
+func (c *CronSchedule) nextMinute(t time.Time) time.Time {
+	next := t.Add(time.Minute)
+	_, offset := t.Zone()
+	_, nextOffset := next.Zone()
+	if nextOffset < offset && !c.hourStar {
+		next = next.Add(time.Duration(offset-nextOffset) * time.Second)
+	}
+	return next
+}
+
+// matchesDay follows the cron convention: when both the day of month and the day of week are restricted, any of them can match
+func (c *CronSchedule) matchesDay(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
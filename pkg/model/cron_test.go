@@ -0,0 +1,188 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// Friday
+	now := time.Date(2022, time.September, 30, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		expression string
+		expected   time.Time
+	}{
+		{expression: "*/5 * * * *", expected: time.Date(2022, time.September, 30, 10, 10, 0, 0, time.UTC)},
+		{expression: "@hourly", expected: time.Date(2022, time.September, 30, 11, 0, 0, 0, time.UTC)},
+		{expression: "30 2 * * *", expected: time.Date(2022, time.October, 1, 2, 30, 0, 0, time.UTC)},
+		{expression: "0 9 * * mon-fri", expected: time.Date(2022, time.October, 3, 9, 0, 0, 0, time.UTC)},
+		{expression: "0 0 * * 7", expected: time.Date(2022, time.October, 2, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 15 * mon", expected: time.Date(2022, time.October, 3, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 1 jan *", expected: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 31 2 *", expected: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next := schedule.Next(now); !next.Equal(tt.expected) {
+				t.Errorf("expected %s, got %s", tt.expected, next)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNextDayOfMonthAndDayOfWeek(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		now        time.Time
+		expected   time.Time
+	}{
+		{
+			name:       "dom-or-dow-matches-dow",
+			expression: "0 0 1 * mon",
+			now:        time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC),
+			expected:   time.Date(2022, time.May, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "dom-or-dow-matches-dom",
+			expression: "0 0 1 * mon",
+			now:        time.Date(2022, time.May, 30, 0, 0, 0, 0, time.UTC),
+			expected:   time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "friday-or-13th",
+			expression: "0 0 13 * 5",
+			now:        time.Date(2022, time.October, 8, 0, 0, 0, 0, time.UTC),
+			expected:   time.Date(2022, time.October, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "dow-with-step-and-any-dom",
+			expression: "0 0 * * */2",
+			now:        time.Date(2022, time.September, 30, 0, 0, 0, 0, time.UTC),
+			expected:   time.Date(2022, time.October, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "dow-with-step-1-matches-any-day",
+			expression: "0 0 1 * */1",
+			now:        time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC),
+			expected:   time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "skips-months-without-the-day",
+			expression: "0 0 31 * *",
+			now:        time.Date(2022, time.January, 31, 0, 0, 0, 0, time.UTC),
+			expected:   time.Date(2022, time.March, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "leap-day",
+			expression: "0 0 29 2 *",
+			now:        time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expected:   time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next := schedule.Next(tt.now); !next.Equal(tt.expected) {
+				t.Errorf("expected %s, got %s", tt.expected, next)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNextDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the clock is moved forward at midnight in Santiago
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		expression string
+		now        time.Time
+		expected   time.Time
+	}{
+		{
+			name:       "skipped-hour-does-not-run",
+			expression: "30 2 * * *",
+			now:        time.Date(2022, time.March, 12, 3, 0, 0, 0, loc),
+			expected:   time.Date(2022, time.March, 14, 2, 30, 0, 0, loc),
+		},
+		{
+			name:       "hourly-after-skipped-hour",
+			expression: "0 * * * *",
+			now:        time.Date(2022, time.March, 13, 1, 30, 0, 0, loc),
+			expected:   time.Date(2022, time.March, 13, 3, 0, 0, 0, loc),
+		},
+		{
+			// 01:30 EDT, the first time 01:30 happens that day
+			name:       "repeated-hour-runs-once",
+			expression: "30 1 * * *",
+			now:        time.Date(2022, time.November, 6, 5, 30, 0, 0, time.UTC).In(loc),
+			expected:   time.Date(2022, time.November, 7, 1, 30, 0, 0, loc),
+		},
+		{
+			// 01:00 EST, after the clock goes back from 02:00 EDT
+			name:       "hourly-runs-in-repeated-hour",
+			expression: "0 * * * *",
+			now:        time.Date(2022, time.November, 6, 5, 30, 0, 0, time.UTC).In(loc),
+			expected:   time.Date(2022, time.November, 6, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "skipped-midnight",
+			expression: "0 * * * *",
+			now:        time.Date(2022, time.September, 10, 23, 30, 0, 0, santiago),
+			expected:   time.Date(2022, time.September, 11, 1, 0, 0, 0, santiago),
+		},
+		{
+			name:       "daily-after-skipped-midnight",
+			expression: "@daily",
+			now:        time.Date(2022, time.September, 10, 23, 30, 0, 0, santiago),
+			expected:   time.Date(2022, time.September, 12, 0, 0, 0, 0, santiago),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next := schedule.Next(tt.now); !next.Equal(tt.expected) {
+				t.Errorf("expected %s, got %s", tt.expected, next)
+			}
+		})
+	}
+}
+
+func TestParseCronScheduleErrors(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *", "@every 5m"} {
+		if _, err := ParseCronSchedule(expression); err == nil {
+			t.Errorf("expected error parsing '%s'", expression)
+		}
+	}
+}
@@ -26,7 +26,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/a8m/envsubst"
 	"github.com/compose-spec/godotenv"
 	"github.com/google/uuid"
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
@@ -225,17 +224,44 @@ type Sync struct {
 	Compression    bool         `json:"compression" yaml:"compression"`
 	Verbose        bool         `json:"verbose" yaml:"verbose"`
 	RescanInterval int          `json:"rescanInterval,omitempty" yaml:"rescanInterval,omitempty"`
+	UploadLimit    int          `json:"uploadLimit,omitempty" yaml:"uploadLimit,omitempty"`
+	DownloadLimit  int          `json:"downloadLimit,omitempty" yaml:"downloadLimit,omitempty"`
+	Engine         SyncEngine   `json:"engine,omitempty" yaml:"engine,omitempty"`
 	Folders        []SyncFolder `json:"folders,omitempty" yaml:"folders,omitempty"`
 	LocalPath      string
 	RemotePath     string
 }
 
+// GetEngine returns the engine used to synchronize the files, syncthing if it is not defined
+func (s Sync) GetEngine() SyncEngine {
+	if s.Engine == "" {
+		return SyncEngineSyncthing
+	}
+	return s.Engine
+}
+
+// SyncEngine defines the service used to synchronize the files of a development container
+type SyncEngine string
+
 // SyncFolder represents a sync folder in the development container
 type SyncFolder struct {
-	LocalPath  string
-	RemotePath string
+	LocalPath    string
+	RemotePath   string
+	Mode         SyncMode
+	IgnoreDelete bool
 }
 
+// GetMode returns the sync mode of the folder, two-way if it is not defined
+func (s SyncFolder) GetMode() SyncMode {
+	if s.Mode == "" {
+		return SyncModeTwoWay
+	}
+	return s.Mode
+}
+
+// SyncMode defines in which direction the changes of a sync folder are synchronized
+type SyncMode string
+
 // ExternalVolume represents a external volume in the development container
 type ExternalVolume struct {
 	Name      string
@@ -793,7 +819,25 @@ func (dev *Dev) Validate() error {
 }
 
 func (dev *Dev) validateSync() error {
+	if dev.Sync.UploadLimit < 0 {
+		return fmt.Errorf("'sync.uploadLimit' must be greater than or equal to 0")
+	}
+	if dev.Sync.DownloadLimit < 0 {
+		return fmt.Errorf("'sync.downloadLimit' must be greater than or equal to 0")
+	}
+	switch dev.Sync.Engine {
+	case "", SyncEngineSyncthing, SyncEngineSSH:
+	default:
+		return fmt.Errorf("supported values for 'sync.engine' are: '%s' or '%s'", SyncEngineSyncthing, SyncEngineSSH)
+	}
 	for _, folder := range dev.Sync.Folders {
+		if err := validateSyncMode(folder.Mode); err != nil {
+			return err
+		}
+		if dev.Sync.GetEngine() == SyncEngineSSH && folder.GetMode() == SyncModeReceiveOnly {
+			return fmt.Errorf("the '%s' sync mode is not supported by the '%s' sync engine", SyncModeReceiveOnly, SyncEngineSSH)
+		}
+
 		validPath, err := os.Stat(folder.LocalPath)
 
 		if err != nil {
@@ -821,6 +865,15 @@ func (dev *Dev) validateSync() error {
 	return nil
 }
 
+func validateSyncMode(mode SyncMode) error {
+	switch mode {
+	case "", SyncModeTwoWay, SyncModeSendOnly, SyncModeReceiveOnly:
+	default:
+		return fmt.Errorf("supported values for the 'mode' of a sync folder are: '%s', '%s' or '%s'", SyncModeTwoWay, SyncModeSendOnly, SyncModeReceiveOnly)
+	}
+	return nil
+}
+
 func validatePullPolicy(pullPolicy apiv1.PullPolicy) error {
 	switch pullPolicy {
 	case apiv1.PullAlways:
@@ -1207,11 +1260,11 @@ func (s *Secret) GetFileName() string {
 	return filepath.Base(s.RemotePath)
 }
 
-// ExpandEnv expands the environments supporting the notation "${var:-$DEFAULT}"
+// ExpandEnv expands the environment variables of a value with the same syntax as compose files, like "${var:-$DEFAULT}"
 func ExpandEnv(value string, expandIfEmpty bool) (string, error) {
-	result, err := envsubst.String(value)
+	result, err := Interpolate(value, os.LookupEnv)
 	if err != nil {
-		return "", fmt.Errorf("error expanding environment on '%s': %s", value, err.Error())
+		return "", fmt.Errorf("error expanding environment on '%s': %w", value, err)
 	}
 	if result == "" && !expandIfEmpty {
 		return value, nil
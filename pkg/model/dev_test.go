@@ -954,6 +954,71 @@ func Test_validate(t *testing.T) {
         runAsGroup: 0`),
 			expectErr: false,
 		},
+		{
+			name: "sync-folder-with-mode",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        folders:
+          - localPath: .
+            remotePath: /app
+            mode: send-only
+            ignoreDelete: true
+        uploadLimit: 100`),
+			expectErr: false,
+		},
+		{
+			name: "sync-folder-with-wrong-mode",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        - localPath: .
+          remotePath: /app
+          mode: upload`),
+			expectErr: true,
+		},
+		{
+			name: "sync-negative-download-limit",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        folders:
+          - .:/app
+        downloadLimit: -1`),
+			expectErr: true,
+		},
+		{
+			name: "sync-ssh-engine",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        folders:
+          - .:/app
+        engine: ssh`),
+			expectErr: false,
+		},
+		{
+			name: "sync-wrong-engine",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        folders:
+          - .:/app
+        engine: rsync`),
+			expectErr: true,
+		},
+		{
+			name: "sync-ssh-engine-with-receive-only-folder",
+			manifest: []byte(`
+      name: deployment
+      sync:
+        folders:
+          - localPath: .
+            remotePath: /app
+            mode: receive-only
+        engine: ssh`),
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1050,6 +1115,16 @@ func Test_ExpandEnv(t *testing.T) {
 			value:  "value-${FOO:-foo}-value",
 			result: "value-foo-value",
 		},
+		{
+			name:   "alternative",
+			value:  "value-${BAR:+alt}-value",
+			result: "value-alt-value",
+		},
+		{
+			name:   "escaped",
+			value:  "value-$${BAR}-value",
+			result: "value-${BAR}-value",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,297 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// InterpolationError is returned when a value references a required variable that is not set or has an invalid syntax
+type InterpolationError struct {
+	// File, Line and Column are the location of the expression, when known
+	File   string
+	Line   int
+	Column int
+
+	// Expression is the expression that failed, like '${VAR:?message}'
+	Expression string
+	Message    string
+
+	// offset is the position of the expression in the interpolated value
+	offset int
+}
+
+// Error returns the error message prefixed by its location
+func (e *InterpolationError) Error() string {
+	switch {
+	case e.File != "" && e.Line > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+	case e.File != "":
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	case e.Line > 0:
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+	}
+	return e.Message
+}
+
+// Interpolate replaces the variables of a value with the values returned by lookup. It supports the compose syntax:
+//   - $VAR and ${VAR}
+//   - ${VAR:-default} when VAR is unset or empty, ${VAR-default} when VAR is unset
+//   - ${VAR:?message} fails when VAR is unset or empty, ${VAR?message} fails when VAR is unset
+//   - ${VAR:+alternative} when VAR is set and not empty, ${VAR+alternative} when VAR is set
+//   - $$ is replaced by a literal $
+//
+// Defaults, alternatives and messages are interpolated too, only when they are used.
+func Interpolate(value string, lookup func(string) (string, bool)) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(value); {
+		if value[i] != '$' || i+1 == len(value) {
+			sb.WriteByte(value[i])
+			i++
+			continue
+		}
+
+		next := value[i+1]
+		switch {
+		case next == '$':
+			sb.WriteByte('$')
+			i += 2
+		case next == '{':
+			end := findClosingBrace(value, i+2)
+			if end < 0 {
+				return "", &InterpolationError{
+					Expression: value[i:],
+					Message:    fmt.Sprintf("invalid interpolation format for '%s': missing closing brace", value[i:]),
+					offset:     i,
+				}
+			}
+			result, err := interpolateExpression(value[i+2:end], lookup)
+			if err != nil {
+				interpolationErr := &InterpolationError{}
+				if errors.As(err, &interpolationErr) {
+					if interpolationErr.Expression == "" {
+						interpolationErr.Expression = value[i : end+1]
+						interpolationErr.offset = i
+					} else {
+						// the error comes from a nested expression
+						interpolationErr.offset += i + 2
+					}
+				}
+				return "", err
+			}
+			sb.WriteString(result)
+			i = end + 1
+		case isVariableNameStart(next):
+			end := i + 1
+			for end < len(value) && isVariableNameChar(value[end]) {
+				end++
+			}
+			result, _ := lookup(value[i+1 : end])
+			sb.WriteString(result)
+			i = end
+		default:
+			sb.WriteByte('$')
+			i++
+		}
+	}
+	return sb.String(), nil
+}
+
+// interpolateExpression returns the value of the content of a ${...} expression
+func interpolateExpression(expr string, lookup func(string) (string, bool)) (string, error) {
+	nameEnd := 0
+	for nameEnd < len(expr) && isVariableNameChar(expr[nameEnd]) {
+		nameEnd++
+	}
+	name := expr[:nameEnd]
+	if name == "" || !isVariableNameStart(name[0]) {
+		return "", &InterpolationError{Message: fmt.Sprintf("invalid interpolation format for '${%s}': invalid variable name", expr)}
+	}
+
+	value, isSet := lookup(name)
+	modifier := expr[nameEnd:]
+	if modifier == "" {
+		return value, nil
+	}
+
+	requireNotEmpty := strings.HasPrefix(modifier, ":")
+	modifier = strings.TrimPrefix(modifier, ":")
+	if modifier == "" {
+		return "", &InterpolationError{Message: fmt.Sprintf("invalid interpolation format for '${%s}'", expr)}
+	}
+	isDefined := isSet && (!requireNotEmpty || value != "")
+	arg := modifier[1:]
+	// position of the argument in expr, used to locate errors of nested expressions
+	argOffset := len(expr) - len(arg)
+
+	switch modifier[0] {
+	case '-', '=':
+		if isDefined {
+			return value, nil
+		}
+		return interpolateArgument(arg, argOffset, lookup)
+	case '+':
+		if !isDefined {
+			return "", nil
+		}
+		return interpolateArgument(arg, argOffset, lookup)
+	case '?':
+		if isDefined {
+			return value, nil
+		}
+		message, err := interpolateArgument(arg, argOffset, lookup)
+		if err != nil {
+			return "", err
+		}
+		if message == "" {
+			return "", &InterpolationError{Message: fmt.Sprintf("required variable '%s' is missing a value", name)}
+		}
+		return "", &InterpolationError{Message: fmt.Sprintf("required variable '%s' is missing a value: %s", name, message)}
+	}
+	return "", &InterpolationError{Message: fmt.Sprintf("invalid interpolation format for '${%s}'", expr)}
+}
+
+func interpolateArgument(arg string, offset int, lookup func(string) (string, bool)) (string, error) {
+	result, err := Interpolate(arg, lookup)
+	if err != nil {
+		interpolationErr := &InterpolationError{}
+		if errors.As(err, &interpolationErr) {
+			interpolationErr.offset += offset
+		}
+		return "", err
+	}
+	return result, nil
+}
+
+// findClosingBrace returns the position of the brace that closes an expression starting at start, or -1 if it is not closed
+func findClosingBrace(value string, start int) int {
+	depth := 0
+	for i := start; i < len(value); i++ {
+		switch {
+		case value[i] == '$' && i+1 < len(value) && value[i+1] == '$':
+			i++
+		case value[i] == '$' && i+1 < len(value) && value[i+1] == '{':
+			depth++
+			i++
+		case value[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+func isVariableNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isVariableNameChar(c byte) bool {
+	return isVariableNameStart(c) || (c >= '0' && c <= '9')
+}
+
+// interpolateNode interpolates the scalars of a yaml document, setting the line and column of the errors
+func interpolateNode(node *yaml3.Node, lookup func(string) (string, bool)) error {
+	if node.Kind == yaml3.ScalarNode {
+		value, err := Interpolate(node.Value, lookup)
+		if err != nil {
+			setInterpolationErrorLocation(err, node)
+			return err
+		}
+		node.Value = value
+		return nil
+	}
+	for _, child := range node.Content {
+		if err := interpolateNode(child, lookup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setInterpolationErrorLocation sets the line and column of an error in the value of a scalar node
+func setInterpolationErrorLocation(err error, node *yaml3.Node) {
+	interpolationErr := &InterpolationError{}
+	if !errors.As(err, &interpolationErr) || interpolationErr.Line > 0 {
+		return
+	}
+	before := node.Value[:interpolationErr.offset]
+	lines := strings.Count(before, "\n")
+	if lines == 0 {
+		interpolationErr.Line = node.Line
+		interpolationErr.Column = node.Column + len(before)
+		if node.Style&(yaml3.DoubleQuotedStyle|yaml3.SingleQuotedStyle) != 0 {
+			interpolationErr.Column++
+		}
+		return
+	}
+	interpolationErr.Line = node.Line + lines
+	if node.Style&(yaml3.LiteralStyle|yaml3.FoldedStyle) != 0 {
+		// the content of block scalars starts in the line after the indicator
+		interpolationErr.Line++
+	}
+	interpolationErr.Column = len(before) - strings.LastIndex(before, "\n")
+}
+
+// locateInterpolationError sets the location of an interpolation error found while unmarshalling a manifest,
+// looking for the first scalar of the file that contains the failing expression
+func locateInterpolationError(err error, file string, content []byte) error {
+	interpolationErr := &InterpolationError{}
+	if !errors.As(err, &interpolationErr) {
+		return err
+	}
+	interpolationErr.File = file
+	if interpolationErr.Line > 0 {
+		// the column is more accurate looking for the expression in the line, like in indented block scalars
+		lines := strings.Split(string(content), "\n")
+		if interpolationErr.Line <= len(lines) {
+			if column := strings.Index(lines[interpolationErr.Line-1], interpolationErr.Expression); column >= 0 {
+				interpolationErr.Column = column + 1
+			}
+		}
+		return interpolationErr
+	}
+	if interpolationErr.Expression == "" {
+		return interpolationErr
+	}
+	doc := &yaml3.Node{}
+	if yaml3.Unmarshal(content, doc) != nil {
+		return interpolationErr
+	}
+	if node := findScalarNode(doc, interpolationErr.Expression); node != nil {
+		interpolationErr.offset = strings.Index(node.Value, interpolationErr.Expression)
+		setInterpolationErrorLocation(interpolationErr, node)
+	}
+	return interpolationErr
+}
+
+func findScalarNode(node *yaml3.Node, substr string) *yaml3.Node {
+	if node.Kind == yaml3.ScalarNode {
+		if strings.Contains(node.Value, substr) {
+			return node
+		}
+		return nil
+	}
+	for _, child := range node.Content {
+		if result := findScalarNode(child, substr); result != nil {
+			return result
+		}
+	}
+	return nil
+}
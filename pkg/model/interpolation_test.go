@@ -0,0 +1,162 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+func testLookup(name string) (string, bool) {
+	value, ok := map[string]string{
+		"SET":   "value",
+		"EMPTY": "",
+	}[name]
+	return value, ok
+}
+
+func Test_Interpolate(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{value: "no variables", expected: "no variables"},
+		{value: "$SET-$UNSET", expected: "value-"},
+		{value: "${SET}suffix", expected: "valuesuffix"},
+		{value: "${UNSET:-default}", expected: "default"},
+		{value: "${EMPTY:-default}", expected: "default"},
+		{value: "${EMPTY-default}", expected: ""},
+		{value: "${UNSET-default}", expected: "default"},
+		{value: "${SET:-default}", expected: "value"},
+		{value: "${SET:+alt}", expected: "alt"},
+		{value: "${EMPTY:+alt}", expected: ""},
+		{value: "${EMPTY+alt}", expected: "alt"},
+		{value: "${UNSET+alt}", expected: ""},
+		{value: "${SET:?required}", expected: "value"},
+		{value: "${EMPTY?required}", expected: ""},
+		{value: "${UNSET:-${SET}}", expected: "value"},
+		{value: "${UNSET:-$SET}", expected: "value"},
+		{value: "${SET:-${UNSET:?not evaluated}}", expected: "value"},
+		{value: "${UNSET:-a:b}", expected: "a:b"},
+		{value: "$$SET and $${SET}", expected: "$SET and ${SET}"},
+		{value: "${UNSET:-$$}", expected: "$"},
+		{value: "cost: 5$ or $1", expected: "cost: 5$ or $1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			result, err := Interpolate(tt.value, testLookup)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_InterpolateErrors(t *testing.T) {
+	tests := []struct {
+		value      string
+		message    string
+		expression string
+		offset     int
+	}{
+		{
+			value:      "${UNSET:?the variable is required}",
+			message:    "required variable 'UNSET' is missing a value: the variable is required",
+			expression: "${UNSET:?the variable is required}",
+		},
+		{
+			value:      "prefix ${EMPTY:?}",
+			message:    "required variable 'EMPTY' is missing a value",
+			expression: "${EMPTY:?}",
+			offset:     7,
+		},
+		{
+			value:      "${UNSET:-${OTHER?set $SET}}",
+			message:    "required variable 'OTHER' is missing a value: set value",
+			expression: "${OTHER?set $SET}",
+			offset:     9,
+		},
+		{
+			value:      "${UNSET",
+			message:    "invalid interpolation format for '${UNSET': missing closing brace",
+			expression: "${UNSET",
+		},
+		{
+			value:      "${1VAR}",
+			message:    "invalid interpolation format for '${1VAR}': invalid variable name",
+			expression: "${1VAR}",
+		},
+		{
+			value:      "${SET:}",
+			message:    "invalid interpolation format for '${SET:}'",
+			expression: "${SET:}",
+		},
+		{
+			value:      "${SET^^}",
+			message:    "invalid interpolation format for '${SET^^}'",
+			expression: "${SET^^}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			_, err := Interpolate(tt.value, testLookup)
+			interpolationErr := &InterpolationError{}
+			assert.True(t, errors.As(err, &interpolationErr))
+			assert.Equal(t, tt.message, err.Error())
+			assert.Equal(t, tt.expression, interpolationErr.Expression)
+			assert.Equal(t, tt.offset, interpolationErr.offset)
+		})
+	}
+}
+
+func Test_interpolateNodeErrorLocation(t *testing.T) {
+	content := []byte(`services:
+  api:
+    image: "api:${UNSET:?tag is required}"
+    command: |
+      echo start
+      echo ${EMPTY:?}
+`)
+	doc := &yaml3.Node{}
+	assert.NoError(t, yaml3.Unmarshal(content, doc))
+	err := interpolateNode(doc, testLookup)
+	assert.Equal(t, "line 3, column 17: required variable 'UNSET' is missing a value: tag is required", err.Error())
+
+	doc = &yaml3.Node{}
+	assert.NoError(t, yaml3.Unmarshal(content, doc))
+	err = interpolateNode(doc, func(name string) (string, bool) {
+		if name == "UNSET" {
+			return "v1", true
+		}
+		return testLookup(name)
+	})
+	err = locateInterpolationError(err, "docker-compose.yml", content)
+	assert.Equal(t, "docker-compose.yml:6:12: required variable 'EMPTY' is missing a value", err.Error())
+}
+
+func Test_locateInterpolationError(t *testing.T) {
+	content := []byte(`dev:
+  api:
+    environment:
+      - TOKEN=${TOKEN:?}
+`)
+	_, err := ExpandEnv("${TOKEN:?}", true)
+	err = locateInterpolationError(err, "okteto.yml", content)
+	assert.Equal(t, "okteto.yml:4:15: required variable 'TOKEN' is missing a value", err.Error())
+
+	err = locateInterpolationError(errors.New("other error"), "okteto.yml", content)
+	assert.Equal(t, "other error", err.Error())
+}
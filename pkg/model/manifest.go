@@ -18,12 +18,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
-	"github.com/a8m/envsubst"
 	"github.com/okteto/okteto/pkg/discovery"
 	oktetoErrors "github.com/okteto/okteto/pkg/errors"
 	"github.com/okteto/okteto/pkg/filesystem"
@@ -175,10 +176,45 @@ func NewManifestFromDev(dev *Dev) *Manifest {
 type DeployInfo struct {
 	Commands       []DeployCommand     `json:"commands,omitempty" yaml:"commands,omitempty"`
 	ComposeSection *ComposeSectionInfo `json:"compose,omitempty" yaml:"compose,omitempty"`
+	Kubernetes     *DeployKubernetes   `json:"kubernetes,omitempty" yaml:"kubernetes,omitempty"`
+	Helm           *DeployHelm         `json:"helm,omitempty" yaml:"helm,omitempty"`
 	Endpoints      EndpointSpec        `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
 	Divert         *DivertDeploy       `json:"divert,omitempty" yaml:"divert,omitempty"`
 }
 
+// DeployKubernetes represents the kubernetes manifests applied by okteto deploy
+type DeployKubernetes struct {
+	// Paths are files or folders with kubernetes manifests
+	Paths []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	// Kustomize are folders with a kustomization file
+	Kustomize []string `json:"kustomize,omitempty" yaml:"kustomize,omitempty"`
+	// Prune deletes the resources applied by a previous deploy that are no longer in the manifests
+	Prune bool `json:"prune,omitempty" yaml:"prune,omitempty"`
+}
+
+// DeployHelm represents the helm release installed by okteto deploy
+type DeployHelm struct {
+	// ReleaseName is the name of the release. Defaults to the name of the development environment
+	ReleaseName string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Chart is a local chart path, or the chart name if Repository is set
+	Chart      string `json:"chart,omitempty" yaml:"chart,omitempty"`
+	Repository string `json:"repository,omitempty" yaml:"repository,omitempty"`
+	Version    string `json:"version,omitempty" yaml:"version,omitempty"`
+	// ValuesFiles are the values files passed to the release, in order of precedence
+	ValuesFiles []string          `json:"values,omitempty" yaml:"values,omitempty"`
+	Set         map[string]string `json:"set,omitempty" yaml:"set,omitempty"`
+	Wait        bool              `json:"wait,omitempty" yaml:"wait,omitempty"`
+	Timeout     time.Duration     `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// GetReleaseName returns the name of the helm release for the development environment 'name'
+func (h *DeployHelm) GetReleaseName(name string) string {
+	if h.ReleaseName != "" {
+		return h.ReleaseName
+	}
+	return name
+}
+
 // DivertDeploy represents information about the deploy divert configuration
 type DivertDeploy struct {
 	Namespace  string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
@@ -206,6 +242,36 @@ type ServicesToDeploy []string
 type DeployCommand struct {
 	Name    string `json:"name,omitempty" yaml:"name,omitempty"`
 	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+	// Timeout is the maximum duration of each attempt of the command
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Retries is the number of times the command is retried if it fails
+	Retries         int                     `json:"retries,omitempty" yaml:"retries,omitempty"`
+	When            *DeployCommandCondition `json:"when,omitempty" yaml:"when,omitempty"`
+	ContinueOnError bool                    `json:"continueOnError,omitempty" yaml:"continueOnError,omitempty"`
+}
+
+// DeployCommandCondition represents the conditions to run a command. All of them must be met
+type DeployCommandCondition struct {
+	// Env is an environment variable that must be set with a non-empty value
+	Env string `json:"env,omitempty" yaml:"env,omitempty"`
+	// Status is the status of the previous commands: 'always' (default), 'success' or 'failure'
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+	// Branch is a glob pattern the deployed branch must match
+	Branch string `json:"branch,omitempty" yaml:"branch,omitempty"`
+}
+
+const (
+	// AlwaysCommandStatus runs the command no matter the status of the previous commands
+	AlwaysCommandStatus = "always"
+	// SuccessCommandStatus runs the command only if none of the previous commands failed
+	SuccessCommandStatus = "success"
+	// FailureCommandStatus runs the command only if any of the previous commands failed
+	FailureCommandStatus = "failure"
+)
+
+// IsExtended returns true if the command defines any option besides its name and command
+func (c *DeployCommand) IsExtended() bool {
+	return c.Timeout != 0 || c.Retries != 0 || c.When != nil || c.ContinueOnError
 }
 
 // NewDeployInfo creates a deploy Info
@@ -580,6 +646,7 @@ func getOktetoManifest(devPath string) (*Manifest, error) {
 		if errors.Is(err, oktetoErrors.ErrNotManifestContentDetected) {
 			return nil, err
 		}
+		err = locateInterpolationError(err, devPath, b)
 		return nil, fmt.Errorf("%w: %s", oktetoErrors.ErrInvalidManifest, err.Error())
 	}
 
@@ -630,6 +697,11 @@ func Read(bytes []byte) (*Manifest, error) {
 				return nil, fmt.Errorf("\n%s", sb.String())
 			}
 
+			interpolationErr := &InterpolationError{}
+			if errors.As(err, &interpolationErr) {
+				return nil, err
+			}
+
 			msg := strings.TrimSuffix(err.Error(), "in type model.Manifest")
 			return nil, fmt.Errorf("\n%s", msg)
 		}
@@ -659,9 +731,63 @@ func (m *Manifest) validate() error {
 	if err := m.Build.validate(); err != nil {
 		return err
 	}
+	if err := m.Dependencies.validate(); err != nil {
+		return err
+	}
+	if err := m.validateKubernetes(); err != nil {
+		return err
+	}
+	if err := m.validateHelm(); err != nil {
+		return err
+	}
+	if err := m.validateDeployCommands(); err != nil {
+		return err
+	}
 	return m.validateDivert()
 }
 
+func (md ManifestDependencies) validate() error {
+	for name, dep := range md {
+		for _, dependsOn := range dep.DependsOn {
+			if _, ok := md[dependsOn]; !ok {
+				return fmt.Errorf("manifest validation failed: dependency '%s' depends on '%s', which is not defined in the 'dependencies' section", name, dependsOn)
+			}
+		}
+	}
+	cycle := getDependentCyclic(md.toGraph())
+	if len(cycle) == 1 {
+		return fmt.Errorf("manifest validation failed: dependency '%s' is referenced on its own 'depends_on'", cycle[0])
+	} else if len(cycle) > 1 {
+		sort.Strings(cycle)
+		depsDependents := fmt.Sprintf("%s and %s", strings.Join(cycle[:len(cycle)-1], ", "), cycle[len(cycle)-1])
+		return fmt.Errorf("manifest validation failed: cyclic dependency found between dependencies %s", depsDependents)
+	}
+	return nil
+}
+
+func (md ManifestDependencies) toGraph() graph {
+	g := graph{}
+	for k, v := range md {
+		g[k] = v.DependsOn
+	}
+	return g
+}
+
+// GetDependents returns the dependencies that declare 'name' in their 'depends_on' field
+func (md ManifestDependencies) GetDependents(name string) []string {
+	dependents := []string{}
+	for depName, dep := range md {
+		for _, dependsOn := range dep.DependsOn {
+			if dependsOn == name {
+				dependents = append(dependents, depName)
+				break
+			}
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
 func (b *ManifestBuild) validate() error {
 	cycle := getDependentCyclic(b.toGraph())
 	if len(cycle) == 1 { // depends on the same node
@@ -736,6 +862,55 @@ func (m *Manifest) SanitizeSvcNames() error {
 	return nil
 }
 
+func (m *Manifest) validateKubernetes() error {
+	if m.Deploy == nil || m.Deploy.Kubernetes == nil {
+		return nil
+	}
+	if len(m.Deploy.Kubernetes.Paths) == 0 && len(m.Deploy.Kubernetes.Kustomize) == 0 {
+		return fmt.Errorf("the field 'deploy.kubernetes' must define 'paths' or 'kustomize'")
+	}
+	return nil
+}
+
+func (m *Manifest) validateHelm() error {
+	if m.Deploy == nil || m.Deploy.Helm == nil {
+		return nil
+	}
+	if m.Deploy.Helm.Chart == "" {
+		return fmt.Errorf("the field 'deploy.helm.chart' is mandatory")
+	}
+	if m.Deploy.Helm.Timeout < 0 {
+		return fmt.Errorf("the field 'deploy.helm.timeout' must be a positive duration")
+	}
+	return nil
+}
+
+func (m *Manifest) validateDeployCommands() error {
+	if m.Deploy == nil {
+		return nil
+	}
+	for _, command := range m.Deploy.Commands {
+		if command.Timeout < 0 {
+			return fmt.Errorf("command '%s': 'timeout' must be a positive duration", command.Name)
+		}
+		if command.Retries < 0 {
+			return fmt.Errorf("command '%s': 'retries' must be a positive number", command.Name)
+		}
+		if command.When == nil {
+			continue
+		}
+		switch command.When.Status {
+		case "", AlwaysCommandStatus, SuccessCommandStatus, FailureCommandStatus:
+		default:
+			return fmt.Errorf("command '%s': 'when.status' must be one of: ['%s', '%s', '%s']", command.Name, AlwaysCommandStatus, SuccessCommandStatus, FailureCommandStatus)
+		}
+		if _, err := path.Match(command.When.Branch, ""); err != nil {
+			return fmt.Errorf("command '%s': 'when.branch' is not a valid pattern: %w", command.Name, err)
+		}
+	}
+	return nil
+}
+
 func (m *Manifest) validateDivert() error {
 	if m.Deploy == nil {
 		return nil
@@ -859,9 +1034,9 @@ func (manifest *Manifest) ExpandEnvVars() error {
 	}
 	if manifest.Destroy != nil {
 		for idx, cmd := range manifest.Destroy {
-			cmd.Command, err = envsubst.String(cmd.Command)
+			cmd.Command, err = ExpandEnv(cmd.Command, true)
 			if err != nil {
-				return errors.New("could not parse env vars")
+				return err
 			}
 			manifest.Destroy[idx] = cmd
 		}
@@ -891,6 +1066,7 @@ type Dependency struct {
 	Branch       string      `json:"branch,omitempty" yaml:"branch,omitempty"`
 	Variables    Environment `json:"variables,omitempty" yaml:"variables,omitempty"`
 	Wait         bool        `json:"wait,omitempty" yaml:"wait,omitempty"`
+	DependsOn    []string    `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
 
 // InferFromStack infers data from a stackfile
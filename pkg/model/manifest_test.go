@@ -276,6 +276,159 @@ func Test_validateDivert(t *testing.T) {
 	}
 }
 
+func Test_validateKubernetes(t *testing.T) {
+	tests := []struct {
+		name        string
+		kubernetes  *DeployKubernetes
+		expectedErr bool
+	}{
+		{
+			name:       "no-kubernetes-section",
+			kubernetes: nil,
+		},
+		{
+			name:       "paths",
+			kubernetes: &DeployKubernetes{Paths: []string{"k8s"}},
+		},
+		{
+			name:       "kustomize",
+			kubernetes: &DeployKubernetes{Kustomize: []string{"overlays/dev"}},
+		},
+		{
+			name:        "empty",
+			kubernetes:  &DeployKubernetes{Prune: true},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{
+				Deploy: &DeployInfo{
+					Kubernetes: tt.kubernetes,
+				},
+			}
+			err := m.validateKubernetes()
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateHelm(t *testing.T) {
+	tests := []struct {
+		name        string
+		helm        *DeployHelm
+		expectedErr bool
+	}{
+		{
+			name: "no-helm-section",
+			helm: nil,
+		},
+		{
+			name: "local-chart",
+			helm: &DeployHelm{Chart: "./chart"},
+		},
+		{
+			name: "repository-chart",
+			helm: &DeployHelm{Chart: "nginx", Repository: "https://charts.bitnami.com/bitnami", Version: "13.2.0"},
+		},
+		{
+			name:        "without-chart",
+			helm:        &DeployHelm{ReleaseName: "app"},
+			expectedErr: true,
+		},
+		{
+			name:        "negative-timeout",
+			helm:        &DeployHelm{Chart: "./chart", Timeout: -time.Minute},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{
+				Deploy: &DeployInfo{
+					Helm: tt.helm,
+				},
+			}
+			err := m.validateHelm()
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateDeployCommands(t *testing.T) {
+	tests := []struct {
+		name        string
+		command     DeployCommand
+		expectedErr bool
+	}{
+		{
+			name:    "plain-command",
+			command: DeployCommand{Name: "build", Command: "okteto build"},
+		},
+		{
+			name: "extended-command",
+			command: DeployCommand{
+				Name:    "migrate",
+				Command: "make migrate",
+				Timeout: time.Minute,
+				Retries: 3,
+				When:    &DeployCommandCondition{Env: "DATABASE_URL", Status: FailureCommandStatus, Branch: "release-*"},
+			},
+		},
+		{
+			name:        "negative-timeout",
+			command:     DeployCommand{Name: "build", Command: "okteto build", Timeout: -time.Minute},
+			expectedErr: true,
+		},
+		{
+			name:        "negative-retries",
+			command:     DeployCommand{Name: "build", Command: "okteto build", Retries: -1},
+			expectedErr: true,
+		},
+		{
+			name:        "wrong-status",
+			command:     DeployCommand{Name: "build", Command: "okteto build", When: &DeployCommandCondition{Status: "failed"}},
+			expectedErr: true,
+		},
+		{
+			name:        "wrong-branch-pattern",
+			command:     DeployCommand{Name: "build", Command: "okteto build", When: &DeployCommandCondition{Branch: "release-["}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{
+				Deploy: &DeployInfo{
+					Commands: []DeployCommand{tt.command},
+				},
+			}
+			err := m.validateDeployCommands()
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDeployHelmGetReleaseName(t *testing.T) {
+	assert.Equal(t, "app", (&DeployHelm{}).GetReleaseName("app"))
+	assert.Equal(t, "release", (&DeployHelm{ReleaseName: "release"}).GetReleaseName("app"))
+}
+
 func Test_validateManifestBuild(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -357,6 +510,89 @@ func Test_validateManifestBuild(t *testing.T) {
 	}
 }
 
+func Test_validateManifestDependencies(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependencies ManifestDependencies
+		expectedErr  bool
+	}{
+		{
+			name: "no cycle - no connections",
+			dependencies: ManifestDependencies{
+				"a": &Dependency{},
+				"b": &Dependency{},
+			},
+			expectedErr: false,
+		},
+		{
+			name: "no cycle - connections",
+			dependencies: ManifestDependencies{
+				"a": &Dependency{
+					DependsOn: []string{"b", "c"},
+				},
+				"b": &Dependency{
+					DependsOn: []string{"c"},
+				},
+				"c": &Dependency{},
+			},
+			expectedErr: false,
+		},
+		{
+			name: "unknown dependency",
+			dependencies: ManifestDependencies{
+				"a": &Dependency{
+					DependsOn: []string{"unknown"},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "cycle - same node dependency",
+			dependencies: ManifestDependencies{
+				"a": &Dependency{
+					DependsOn: []string{"a"},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "cycle - indirect cycle",
+			dependencies: ManifestDependencies{
+				"a": &Dependency{
+					DependsOn: []string{"b"},
+				},
+				"b": &Dependency{
+					DependsOn: []string{"c"},
+				},
+				"c": &Dependency{
+					DependsOn: []string{"a"},
+				},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{
+				Dependencies: tt.dependencies,
+			}
+			assert.Equal(t, tt.expectedErr, m.validate() != nil)
+		})
+	}
+}
+
+func TestGetDependents(t *testing.T) {
+	dependencies := ManifestDependencies{
+		"a": &Dependency{DependsOn: []string{"c"}},
+		"b": &Dependency{DependsOn: []string{"a", "c"}},
+		"c": &Dependency{},
+	}
+	assert.Equal(t, []string{"a", "b"}, dependencies.GetDependents("c"))
+	assert.Equal(t, []string{"b"}, dependencies.GetDependents("a"))
+	assert.Equal(t, []string{}, dependencies.GetDependents("b"))
+}
+
 func TestInferFromStack(t *testing.T) {
 	dirtest := filepath.Clean("/stack/dir/")
 	devInterface := PrivilegedLocalhost
@@ -0,0 +1,378 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/okteto/okteto/pkg/model/forward"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+	definitionsRefPrefix = "#/definitions/"
+)
+
+// JSONSchema represents a JSON Schema (draft 7) describing the accepted shapes of a manifest
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	PatternProperties    map[string]*JSONSchema `json:"patternProperties,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	Definitions          map[string]*JSONSchema `json:"definitions,omitempty"`
+}
+
+var (
+	unmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+	durationType    = reflect.TypeOf(time.Duration(0))
+)
+
+// schemaShapes has the shapes accepted by the types with a custom unmarshaler, that can't be inferred from their fields
+var schemaShapes map[reflect.Type]func(g *schemaGenerator) *JSONSchema
+
+// the shapes are set on init because they use the generator that reads them
+func init() {
+	schemaShapes = map[reflect.Type]func(g *schemaGenerator) *JSONSchema{
+		reflect.TypeOf(Manifest{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(g.objectOf(manifestRaw{}), g.schemaOf(Dev{}))
+		},
+		reflect.TypeOf(Dev{}): func(g *schemaGenerator) *JSONSchema {
+			return g.objectOf(Dev{})
+		},
+		reflect.TypeOf(devRaw{}): func(g *schemaGenerator) *JSONSchema {
+			return g.schemaOf(Dev{})
+		},
+		reflect.TypeOf(ManifestDevs{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(listOf(stringSchema()), mapOf(g.schemaOf(devRaw{})))
+		},
+		reflect.TypeOf(ManifestDependencies{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(listOf(stringSchema()), mapOf(g.schemaOf(Dependency{})))
+		},
+		reflect.TypeOf(Dependency{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), g.objectOf(dependenciesRaw{}))
+		},
+		reflect.TypeOf(DeployInfo{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(listOf(g.schemaOf(DeployCommand{})), g.objectOf(DeployInfo{}))
+		},
+		reflect.TypeOf(DeployCommand{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), g.objectOf(DeployCommand{}))
+		},
+		reflect.TypeOf(ComposeSectionInfo{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(g.schemaOf(ComposeInfoList{}), g.objectOf(ComposeSectionInfo{}))
+		},
+		reflect.TypeOf(ComposeInfoList{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(g.schemaOf(ComposeInfo{}), listOf(g.schemaOf(ComposeInfo{})))
+		},
+		reflect.TypeOf(ComposeInfo{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), g.objectOf(ComposeInfo{}))
+		},
+		reflect.TypeOf(BuildInfo{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), g.objectOf(buildInfoRaw{}))
+		},
+		reflect.TypeOf(Sync{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(listOf(g.schemaOf(SyncFolder{})), g.objectOf(syncRaw{}))
+		},
+		reflect.TypeOf(SyncFolder{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), g.objectOf(syncFolderRaw{}))
+		},
+		reflect.TypeOf(StorageResource{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(g.schemaOf(Quantity{}), g.objectOf(storageResourceRaw{}))
+		},
+		reflect.TypeOf(Probes{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(&JSONSchema{Type: "boolean"}, g.objectOf(probesRaw{}))
+		},
+		reflect.TypeOf(Lifecycle{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(&JSONSchema{Type: "boolean"}, g.objectOf(lifecycleRaw{}))
+		},
+		reflect.TypeOf(Timeout{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(durationSchema(), g.objectOf(Timeout{}))
+		},
+		reflect.TypeOf(Endpoint{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(listOf(g.schemaOf(EndpointRule{})), g.objectOf(Endpoint{}))
+		},
+		reflect.TypeOf(Affinity{}): func(g *schemaGenerator) *JSONSchema {
+			return g.schemaOf(AffinityRaw{})
+		},
+		reflect.TypeOf(forward.Forward{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), g.objectOf(forward.ForwardRaw{}))
+		},
+		reflect.TypeOf(forward.GlobalForward{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), g.objectOf(forward.GlobalForwardRaw{}))
+		},
+		reflect.TypeOf(Labels{}):      keyValueSchema,
+		reflect.TypeOf(Annotations{}): keyValueSchema,
+		reflect.TypeOf(Environment{}): keyValueSchema,
+		reflect.TypeOf(BuildArgs{}):   keyValueSchema,
+		reflect.TypeOf(Sysctls{}):     keyValueSchema,
+		reflect.TypeOf(ExtraHosts{}):  keyValueSchema,
+		reflect.TypeOf(ResourceList{}): func(g *schemaGenerator) *JSONSchema {
+			return mapOf(g.schemaOf(Quantity{}))
+		},
+		reflect.TypeOf(Quantity{}): func(*schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), &JSONSchema{Type: "number"})
+		},
+		reflect.TypeOf(Duration(0)): func(*schemaGenerator) *JSONSchema {
+			return durationSchema()
+		},
+		reflect.TypeOf(RawMessage{}): func(*schemaGenerator) *JSONSchema {
+			return durationSchema()
+		},
+		reflect.TypeOf(Entrypoint{}):       stringOrListSchema,
+		reflect.TypeOf(Command{}):          stringOrListSchema,
+		reflect.TypeOf(Args{}):             stringOrListSchema,
+		reflect.TypeOf(CommandStack{}):     stringOrListSchema,
+		reflect.TypeOf(ArgsStack{}):        stringOrListSchema,
+		reflect.TypeOf(HealtcheckTest{}):   stringOrListSchema,
+		reflect.TypeOf(BuildDependsOn{}):   stringOrListSchema,
+		reflect.TypeOf(EnvFiles{}):         stringOrListSchema,
+		reflect.TypeOf(StringList{}):       stringOrListSchema,
+		reflect.TypeOf(ServicesToDeploy{}): stringOrListSchema,
+		reflect.TypeOf(BuildArg{}):         stringShape,
+		reflect.TypeOf(EnvVar{}):           stringShape,
+		reflect.TypeOf(Secret{}):           stringShape,
+		reflect.TypeOf(Reverse{}):          stringShape,
+		reflect.TypeOf(Volume{}):           stringShape,
+		reflect.TypeOf(ExternalVolume{}):   stringShape,
+		reflect.TypeOf(StackVolume{}):      stringShape,
+		reflect.TypeOf(PortRaw{}): func(*schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), &JSONSchema{Type: "integer"})
+		},
+		reflect.TypeOf(Stack{}): func(g *schemaGenerator) *JSONSchema {
+			s := g.objectOf(StackRaw{})
+			// the top level of a compose file accepts extension fields
+			s.PatternProperties = map[string]*JSONSchema{"^x-": {}}
+			return s
+		},
+		reflect.TypeOf(composeBuildInfo{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), g.objectOf(composeBuildInfo{}))
+		},
+		reflect.TypeOf(ServiceConfig{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), g.objectOf(ServiceConfig{}))
+		},
+		reflect.TypeOf(ServiceNetworksRaw{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(listOf(stringSchema()), mapOf(g.schemaOf(ServiceNetworkRaw{})))
+		},
+		reflect.TypeOf(ExtendsRaw{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), g.objectOf(ExtendsRaw{}))
+		},
+		reflect.TypeOf(ServiceScheduleRaw{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), g.objectOf(ServiceScheduleRaw{}))
+		},
+		reflect.TypeOf(HealthCheck{}): func(g *schemaGenerator) *JSONSchema {
+			return g.objectOf(healthCheckunmarshaller{})
+		},
+		reflect.TypeOf(HTTPHealtcheck{}): func(g *schemaGenerator) *JSONSchema {
+			return g.objectOf(HTTPHealtcheck{})
+		},
+		reflect.TypeOf(StackSecurityContext{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(stringSchema(), &JSONSchema{Type: "integer"}, g.objectOf(StackSecurityContext{}))
+		},
+		reflect.TypeOf(DependsOn{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(listOf(stringSchema()), mapOf(g.schemaOf(DependsOnConditionSpec{})))
+		},
+		reflect.TypeOf(EndpointSpec{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(g.schemaOf(Endpoint{}), mapOf(g.schemaOf(Endpoint{})))
+		},
+		reflect.TypeOf(StackResources{}): func(g *schemaGenerator) *JSONSchema {
+			return anyOf(g.objectOf(StackResources{}), g.objectOf(ServiceResources{}))
+		},
+		reflect.TypeOf(WarningType{}): func(*schemaGenerator) *JSONSchema {
+			// unsupported fields are accepted with a warning
+			return &JSONSchema{}
+		},
+	}
+}
+
+// schemaGenerator infers the json schema of a type from its yaml tags and the shapes of its custom unmarshalers
+type schemaGenerator struct {
+	definitions map[string]*JSONSchema
+	names       map[reflect.Type]string
+
+	// unknownShapes are the types with a custom unmarshaler not included in schemaShapes
+	unknownShapes []reflect.Type
+}
+
+// GetManifestSchema returns the json schema of the okteto manifest
+func GetManifestSchema() *JSONSchema {
+	return newSchemaGenerator().generate("Okteto manifest", Manifest{})
+}
+
+// GetStackSchema returns the json schema of the compose files supported by okteto
+func GetStackSchema() *JSONSchema {
+	return newSchemaGenerator().generate("Okteto compose", Stack{})
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		definitions: map[string]*JSONSchema{},
+		names:       map[reflect.Type]string{},
+	}
+}
+
+func (g *schemaGenerator) generate(title string, v interface{}) *JSONSchema {
+	root := g.schemaOf(v)
+	root.Schema = jsonSchemaDraft
+	root.Title = title
+	root.Definitions = g.definitions
+	return root
+}
+
+func (g *schemaGenerator) schemaOf(v interface{}) *JSONSchema {
+	return g.typeSchema(reflect.TypeOf(v))
+}
+
+// objectOf returns the schema of the fields of a struct, ignoring its custom unmarshaler
+func (g *schemaGenerator) objectOf(v interface{}) *JSONSchema {
+	return g.structSchema(reflect.TypeOf(v))
+}
+
+func (g *schemaGenerator) typeSchema(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == durationType {
+		return durationSchema()
+	}
+	if shape, ok := schemaShapes[t]; ok {
+		return g.definition(t, func() *JSONSchema { return shape(g) })
+	}
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		g.unknownShapes = append(g.unknownShapes, t)
+		return &JSONSchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return stringSchema()
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return listOf(g.typeSchema(t.Elem()))
+	case reflect.Map:
+		return mapOf(g.typeSchema(t.Elem()))
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.definition(t, func() *JSONSchema { return g.structSchema(t) })
+	}
+	return &JSONSchema{}
+}
+
+// definition returns a reference to the definition of a named type, building it the first time
+func (g *schemaGenerator) definition(t reflect.Type, build func() *JSONSchema) *JSONSchema {
+	if name, ok := g.names[t]; ok {
+		return &JSONSchema{Ref: definitionsRefPrefix + name}
+	}
+	name := t.Name()
+	if _, ok := g.definitions[name]; ok {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	// registered before building it to support recursive types
+	g.names[t] = name
+	g.definitions[name] = &JSONSchema{}
+
+	s := build()
+	if s.Ref != "" {
+		// the type is an alias of another definition
+		delete(g.definitions, name)
+		g.names[t] = strings.TrimPrefix(s.Ref, definitionsRefPrefix)
+		return s
+	}
+	g.definitions[name] = s
+	return &JSONSchema{Ref: definitionsRefPrefix + name}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *JSONSchema {
+	s := &JSONSchema{
+		Type:                 "object",
+		Properties:           map[string]*JSONSchema{},
+		AdditionalProperties: false,
+	}
+	g.addFields(s, t)
+	return s
+}
+
+func (g *schemaGenerator) addFields(s *JSONSchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("yaml")
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if strings.Contains(options, "inline") {
+			// inlined maps collect the extensions, they are validated by each type
+			if field.Type.Kind() == reflect.Struct {
+				g.addFields(s, field.Type)
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		s.Properties[name] = g.typeSchema(field.Type)
+	}
+}
+
+func stringSchema() *JSONSchema {
+	return &JSONSchema{Type: "string"}
+}
+
+func durationSchema() *JSONSchema {
+	return anyOf(stringSchema(), &JSONSchema{Type: "integer"})
+}
+
+func listOf(items *JSONSchema) *JSONSchema {
+	return &JSONSchema{Type: "array", Items: items}
+}
+
+func mapOf(values *JSONSchema) *JSONSchema {
+	return &JSONSchema{Type: "object", AdditionalProperties: values}
+}
+
+func anyOf(schemas ...*JSONSchema) *JSONSchema {
+	return &JSONSchema{AnyOf: schemas}
+}
+
+func stringShape(*schemaGenerator) *JSONSchema {
+	return stringSchema()
+}
+
+func stringOrListSchema(*schemaGenerator) *JSONSchema {
+	return anyOf(stringSchema(), listOf(stringSchema()))
+}
+
+// keyValueSchema is the shape of the fields accepting a list of 'name=value' or a map
+func keyValueSchema(*schemaGenerator) *JSONSchema {
+	return anyOf(
+		listOf(stringSchema()),
+		mapOf(anyOf(stringSchema(), &JSONSchema{Type: "number"}, &JSONSchema{Type: "boolean"})),
+	)
+}
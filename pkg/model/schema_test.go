@@ -0,0 +1,238 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_schemaShapes(t *testing.T) {
+	for _, v := range []interface{}{Manifest{}, Stack{}} {
+		g := newSchemaGenerator()
+		g.generate("test", v)
+		assert.Empty(t, g.unknownShapes, "types with a custom unmarshaler must have a shape in schemaShapes")
+	}
+}
+
+func Test_GetManifestSchema(t *testing.T) {
+	schema := GetManifestSchema()
+	assert.Equal(t, jsonSchemaDraft, schema.Schema)
+	assert.Equal(t, "#/definitions/Manifest", schema.Ref)
+
+	manifest := schema.Definitions["Manifest"]
+	if len(manifest.AnyOf) != 2 {
+		t.Fatalf("expected 2 alternatives, got %d", len(manifest.AnyOf))
+	}
+	assert.Contains(t, manifest.AnyOf[0].Properties, "deploy")
+	assert.Contains(t, manifest.AnyOf[0].Properties, "dev")
+	assert.Equal(t, "#/definitions/Dev", manifest.AnyOf[1].Ref)
+
+	dev := schema.Definitions["Dev"]
+	assert.Equal(t, false, dev.AdditionalProperties)
+	assert.Equal(t, &JSONSchema{Ref: "#/definitions/Command"}, dev.Properties["command"])
+	assert.Equal(t, &JSONSchema{Ref: "#/definitions/Dev"}, dev.Properties["services"].Items)
+	assert.NotContains(t, dev.Properties, "username")
+
+	b, err := json.Marshal(schema.Definitions["Command"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"anyOf":[{"type":"string"},{"type":"array","items":{"type":"string"}}]}`, string(b))
+}
+
+func Test_GetStackSchema(t *testing.T) {
+	schema := GetStackSchema()
+	stack := schema.Definitions["Stack"]
+	assert.Contains(t, stack.Properties, "services")
+	assert.Contains(t, stack.PatternProperties, "^x-")
+
+	service := schema.Definitions["ServiceRaw"]
+	assert.Contains(t, service.Properties, "x-okteto-schedule")
+	assert.NotContains(t, service.Properties, "extensions")
+}
+
+func Test_ValidateManifestSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expected []string
+	}{
+		{
+			name: "valid manifest",
+			manifest: `deploy:
+  - okteto build
+  - name: migrate
+    command: make migrate
+dev:
+  api:
+    image: okteto/api
+    command: bash
+    sync:
+      folders:
+        - .:/app
+        - localPath: dist
+          remotePath: /app/dist
+          mode: receive-only
+      uploadLimit: 500
+    forward:
+      - 8080:80
+      - localPort: 9090
+        remotePort: 90
+        name: db
+    probes: true
+    timeout: 5m
+    environment:
+      DEBUG: true
+`,
+		},
+		{
+			name: "valid dev manifest",
+			manifest: `name: api
+image: okteto/api
+sync: [".:/app"]
+autocreate: yes
+`,
+		},
+		{
+			name: "all the problems",
+			manifest: `deploy:
+  - okteto build
+  - name: migrate
+    command: make migrate
+    unknown: value
+dev:
+  api:
+    sync:
+      rescanInterval: fast
+    forward:
+      - localPort: a
+    remote: [8080]
+`,
+			expected: []string{
+				"line 5, column 5: 'deploy[1].unknown' is not a supported field",
+				"line 9, column 23: 'dev.api.sync.rescanInterval' must be an integer",
+				"line 11, column 20: 'dev.api.forward[0].localPort' must be an integer",
+				"line 12, column 13: 'dev.api.remote' must be an integer",
+			},
+		},
+		{
+			name: "wrong shape of a custom unmarshaler",
+			manifest: `dev:
+  api:
+    command:
+      run: bash
+`,
+			expected: []string{
+				"line 4, column 7: 'dev.api.command' must be a string or a list",
+			},
+		},
+		{
+			name:     "not an object",
+			manifest: `- okteto build`,
+			expected: []string{
+				"line 1, column 1: the manifest must be an object",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateManifestSchema([]byte(tt.manifest))
+			var result []string
+			for _, err := range errs {
+				result = append(result, err.Error())
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_ValidateStackSchema(t *testing.T) {
+	t.Setenv("API_PORT", "8080")
+	compose := `services:
+  api:
+    image: okteto/api
+    ports:
+      - ${API_PORT}
+      - 80:80
+    scale: ${API_PORT}
+    replicas: ${REPLICAS:?replicas is required}
+    depends_on: [db]
+    healthcheck:
+      test: curl localhost
+      interval: 3s
+    unknown: value
+  db:
+    image: postgres
+    deploy:
+      replicas: many
+x-common: value
+`
+	errs := ValidateStackSchema([]byte(compose))
+	var result []string
+	for _, err := range errs {
+		result = append(result, err.Error())
+	}
+	assert.Equal(t, []string{
+		"line 8, column 15: required variable 'REPLICAS' is missing a value: replicas is required",
+		"line 13, column 5: 'services.api.unknown' is not a supported field",
+		"line 17, column 17: 'services.db.deploy.replicas' must be an integer",
+	}, result)
+}
+
+func Test_ValidateManifestFile(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "okteto.yml")
+	content := []byte(`dev:
+  api:
+    image: okteto/api
+    imagePullPolicy: Sometimes
+    command: bash
+    sync:
+      - .:/app
+  web:
+    image: okteto/web
+    command: bash
+    sync:
+      - .:/app
+`)
+	if err := os.WriteFile(manifestPath, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	errs, err := ValidateManifestFile(manifestPath)
+	assert.NoError(t, err)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 problem, got %v", errs)
+	}
+	assert.Contains(t, errs[0].Error(), "dev 'api':")
+
+	content = []byte(`dev:
+  api:
+    sync: .:/app
+    remote: none
+`)
+	if err := os.WriteFile(manifestPath, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	errs, err = ValidateManifestFile(manifestPath)
+	assert.NoError(t, err)
+	assert.Len(t, errs, 2)
+
+	_, err = ValidateManifestFile(filepath.Join(dir, "missing.yml"))
+	assert.Error(t, err)
+}
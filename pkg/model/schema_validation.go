@@ -0,0 +1,416 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// SchemaError is a problem found validating a manifest against its json schema
+type SchemaError struct {
+	Line   int
+	Column int
+
+	// Path is the field with the problem, like 'dev.api.sync[0]'
+	Path    string
+	Message string
+}
+
+// Error returns the error message prefixed by its location
+func (e *SchemaError) Error() string {
+	msg := e.Message
+	if e.Path != "" {
+		msg = fmt.Sprintf("'%s' %s", e.Path, e.Message)
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, msg)
+	}
+	return msg
+}
+
+// yaml11Booleans are the values decoded as booleans by the yaml pkg that yaml 1.2 considers strings
+var yaml11Booleans = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true,
+	"n": true, "N": true, "no": true, "No": true, "NO": true,
+	"on": true, "On": true, "ON": true,
+	"off": true, "Off": true, "OFF": true,
+}
+
+// ValidateManifestSchema validates an okteto manifest against its json schema, returning all the problems found
+func ValidateManifestSchema(content []byte) []*SchemaError {
+	doc, errs := parseSchemaDocument(content)
+	if doc == nil {
+		return errs
+	}
+	return validateSchema(doc, GetManifestSchema())
+}
+
+// ValidateStackSchema validates a compose file against its json schema, returning all the problems found.
+// Its variables are interpolated first, like when the compose file is deployed
+func ValidateStackSchema(content []byte) []*SchemaError {
+	doc, errs := parseSchemaDocument(content)
+	if doc == nil {
+		return errs
+	}
+	errs = interpolateSchemaNode(doc, os.LookupEnv)
+	errs = append(errs, validateSchema(doc, GetStackSchema())...)
+	sortSchemaErrors(errs)
+	return errs
+}
+
+// ValidateManifestFile returns all the problems of an okteto manifest file: the ones found validating it against its schema or,
+// when it matches the schema, the ones found loading the manifest and validating its development containers
+func ValidateManifestFile(manifestPath string) ([]error, error) {
+	b, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	if schemaErrs := ValidateManifestSchema(b); len(schemaErrs) > 0 {
+		return toErrors(schemaErrs), nil
+	}
+
+	manifest, err := getOktetoManifest(manifestPath)
+	if err != nil {
+		return []error{err}, nil
+	}
+	names := make([]string, 0, len(manifest.Dev))
+	for name := range manifest.Dev {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		if err := manifest.Dev[name].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("dev '%s': %w", name, err))
+		}
+	}
+	return errs, nil
+}
+
+// ValidateStackFile returns all the problems of a compose file: the ones found validating it against its schema or,
+// when it matches the schema, the one found loading it
+func ValidateStackFile(stackPath string) ([]error, error) {
+	b, err := os.ReadFile(stackPath)
+	if err != nil {
+		return nil, err
+	}
+	if schemaErrs := ValidateStackSchema(b); len(schemaErrs) > 0 {
+		return toErrors(schemaErrs), nil
+	}
+	if _, err := LoadStack("", []string{stackPath}, true); err != nil {
+		return []error{err}, nil
+	}
+	return nil, nil
+}
+
+func toErrors(schemaErrs []*SchemaError) []error {
+	result := make([]error, 0, len(schemaErrs))
+	for _, err := range schemaErrs {
+		result = append(result, err)
+	}
+	return result
+}
+
+func parseSchemaDocument(content []byte) (*yaml3.Node, []*SchemaError) {
+	doc := &yaml3.Node{}
+	if err := yaml3.Unmarshal(content, doc); err != nil {
+		return nil, []*SchemaError{{Message: err.Error()}}
+	}
+	if len(doc.Content) == 0 {
+		return nil, []*SchemaError{{Message: "the manifest is empty"}}
+	}
+	return doc.Content[0], nil
+}
+
+func validateSchema(node *yaml3.Node, schema *JSONSchema) []*SchemaError {
+	v := &schemaValidator{definitions: schema.Definitions, patterns: map[string]*regexp.Regexp{}}
+	errs := v.validate(node, schema, "")
+	sortSchemaErrors(errs)
+	return errs
+}
+
+func sortSchemaErrors(errs []*SchemaError) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+}
+
+// interpolateSchemaNode interpolates the scalars of a document, returning the errors of all the scalars
+func interpolateSchemaNode(node *yaml3.Node, lookup func(string) (string, bool)) []*SchemaError {
+	if node.Kind != yaml3.ScalarNode {
+		var errs []*SchemaError
+		for _, child := range node.Content {
+			errs = append(errs, interpolateSchemaNode(child, lookup)...)
+		}
+		return errs
+	}
+
+	value, err := Interpolate(node.Value, lookup)
+	if err != nil {
+		setInterpolationErrorLocation(err, node)
+		// the value is unknown, it is not validated against the schema
+		node.Value = ""
+		node.Tag = "!!null"
+		interpolationErr := &InterpolationError{}
+		if errors.As(err, &interpolationErr) {
+			return []*SchemaError{{Line: interpolationErr.Line, Column: interpolationErr.Column, Message: interpolationErr.Message}}
+		}
+		return []*SchemaError{{Line: node.Line, Column: node.Column, Message: err.Error()}}
+	}
+	if value != node.Value {
+		node.Value = value
+		if node.Style == 0 {
+			// plain scalars are resolved again, like when the interpolated file is unmarshalled
+			node.Tag = ""
+		}
+	}
+	return nil
+}
+
+type schemaValidator struct {
+	definitions map[string]*JSONSchema
+	patterns    map[string]*regexp.Regexp
+}
+
+func (v *schemaValidator) resolve(schema *JSONSchema) *JSONSchema {
+	for schema.Ref != "" {
+		schema = v.definitions[strings.TrimPrefix(schema.Ref, definitionsRefPrefix)]
+	}
+	return schema
+}
+
+func (v *schemaValidator) validate(node *yaml3.Node, schema *JSONSchema, path string) []*SchemaError {
+	if node.Kind == yaml3.AliasNode {
+		node = node.Alias
+	}
+	schema = v.resolve(schema)
+	if node.Kind == yaml3.ScalarNode && node.ShortTag() == "!!null" {
+		// null values are decoded as the zero value of any type
+		return nil
+	}
+
+	if len(schema.AnyOf) > 0 {
+		return v.validateAnyOf(node, schema, path)
+	}
+	if !isSchemaType(node, schema.Type) {
+		return []*SchemaError{newSchemaTypeError(node, path, []string{schema.Type})}
+	}
+
+	switch node.Kind {
+	case yaml3.MappingNode:
+		return v.validateObject(node, schema, path)
+	case yaml3.SequenceNode:
+		if schema.Items == nil {
+			return nil
+		}
+		var errs []*SchemaError
+		for i, item := range node.Content {
+			errs = append(errs, v.validate(item, schema.Items, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	}
+	return nil
+}
+
+// validateAnyOf returns the problems of the alternative closest to the node: the one of its type with less unsupported fields
+// and, when they have the same number of them, with less problems
+func (v *schemaValidator) validateAnyOf(node *yaml3.Node, schema *JSONSchema, path string) []*SchemaError {
+	var closest []*SchemaError
+	closestUnsupported := 0
+	matched := false
+	for _, alternative := range schema.AnyOf {
+		if !v.acceptsType(node, alternative) {
+			continue
+		}
+		errs := v.validate(node, alternative, path)
+		if len(errs) == 0 {
+			return nil
+		}
+		unsupported := v.countUnsupportedFields(node, alternative)
+		if !matched || unsupported < closestUnsupported || (unsupported == closestUnsupported && len(errs) < len(closest)) {
+			closest = errs
+			closestUnsupported = unsupported
+			matched = true
+		}
+	}
+	if matched {
+		return closest
+	}
+	return []*SchemaError{newSchemaTypeError(node, path, v.types(schema))}
+}
+
+// countUnsupportedFields returns the number of fields of an object not supported by a schema
+func (v *schemaValidator) countUnsupportedFields(node *yaml3.Node, schema *JSONSchema) int {
+	schema = v.resolve(schema)
+	if node.Kind != yaml3.MappingNode || len(schema.AnyOf) > 0 {
+		return 0
+	}
+	result := 0
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].ShortTag() != "!!merge" && v.fieldSchema(schema, node.Content[i].Value) == nil {
+			result++
+		}
+	}
+	return result
+}
+
+func (v *schemaValidator) validateObject(node *yaml3.Node, schema *JSONSchema, path string) []*SchemaError {
+	var errs []*SchemaError
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		value := node.Content[i+1]
+		if key.ShortTag() == "!!merge" {
+			errs = append(errs, v.validate(value, schema, path)...)
+			continue
+		}
+
+		fieldPath := key.Value
+		if path != "" {
+			fieldPath = path + "." + key.Value
+		}
+		fieldSchema := v.fieldSchema(schema, key.Value)
+		if fieldSchema == nil {
+			errs = append(errs, &SchemaError{Line: key.Line, Column: key.Column, Path: fieldPath, Message: "is not a supported field"})
+			continue
+		}
+		errs = append(errs, v.validate(value, fieldSchema, fieldPath)...)
+	}
+	return errs
+}
+
+// fieldSchema returns the schema of a field of an object, or nil if the field is not supported
+func (v *schemaValidator) fieldSchema(schema *JSONSchema, name string) *JSONSchema {
+	if s, ok := schema.Properties[name]; ok {
+		return s
+	}
+	for pattern, s := range schema.PatternProperties {
+		r, ok := v.patterns[pattern]
+		if !ok {
+			r = regexp.MustCompile(pattern)
+			v.patterns[pattern] = r
+		}
+		if r.MatchString(name) {
+			return s
+		}
+	}
+	switch additional := schema.AdditionalProperties.(type) {
+	case *JSONSchema:
+		return additional
+	case bool:
+		if !additional {
+			return nil
+		}
+	}
+	return &JSONSchema{}
+}
+
+// acceptsType returns if the node has one of the types of the schema
+func (v *schemaValidator) acceptsType(node *yaml3.Node, schema *JSONSchema) bool {
+	for _, t := range v.types(schema) {
+		if isSchemaType(node, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// types returns the types accepted by a schema, an empty type meaning any type
+func (v *schemaValidator) types(schema *JSONSchema) []string {
+	schema = v.resolve(schema)
+	if len(schema.AnyOf) == 0 {
+		return []string{schema.Type}
+	}
+	var result []string
+	for _, alternative := range schema.AnyOf {
+		for _, t := range v.types(alternative) {
+			if !contains(result, t) {
+				result = append(result, t)
+			}
+		}
+	}
+	return result
+}
+
+// isSchemaType returns if a node can be decoded into a value of a json schema type, following the rules of the yaml pkg
+func isSchemaType(node *yaml3.Node, schemaType string) bool {
+	switch schemaType {
+	case "":
+		return true
+	case "object":
+		return node.Kind == yaml3.MappingNode
+	case "array":
+		return node.Kind == yaml3.SequenceNode
+	}
+	if node.Kind != yaml3.ScalarNode {
+		return false
+	}
+	tag := node.ShortTag()
+	switch schemaType {
+	case "string":
+		return true
+	case "boolean":
+		return tag == "!!bool" || (node.Style == 0 && yaml11Booleans[node.Value])
+	case "integer":
+		return tag == "!!int"
+	case "number":
+		return tag == "!!int" || tag == "!!float"
+	}
+	return false
+}
+
+func newSchemaTypeError(node *yaml3.Node, path string, types []string) *SchemaError {
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, schemaTypeName(t))
+	}
+	expected := names[len(names)-1]
+	if len(names) > 1 {
+		expected = fmt.Sprintf("%s or %s", strings.Join(names[:len(names)-1], ", "), expected)
+	}
+	if path == "" {
+		return &SchemaError{Line: node.Line, Column: node.Column, Message: fmt.Sprintf("the manifest must be %s", expected)}
+	}
+	return &SchemaError{Line: node.Line, Column: node.Column, Path: path, Message: fmt.Sprintf("must be %s", expected)}
+}
+
+func schemaTypeName(schemaType string) string {
+	switch schemaType {
+	case "object":
+		return "an object"
+	case "array":
+		return "a list"
+	case "integer":
+		return "an integer"
+	}
+	return "a " + schemaType
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
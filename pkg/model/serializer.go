@@ -53,11 +53,21 @@ type syncRaw struct {
 	Compression    bool         `json:"compression" yaml:"compression"`
 	Verbose        bool         `json:"verbose" yaml:"verbose"`
 	RescanInterval int          `json:"rescanInterval,omitempty" yaml:"rescanInterval,omitempty"`
+	UploadLimit    int          `json:"uploadLimit,omitempty" yaml:"uploadLimit,omitempty"`
+	DownloadLimit  int          `json:"downloadLimit,omitempty" yaml:"downloadLimit,omitempty"`
+	Engine         SyncEngine   `json:"engine,omitempty" yaml:"engine,omitempty"`
 	Folders        []SyncFolder `json:"folders,omitempty" yaml:"folders,omitempty"`
 	LocalPath      string
 	RemotePath     string
 }
 
+type syncFolderRaw struct {
+	LocalPath    string   `json:"localPath" yaml:"localPath"`
+	RemotePath   string   `json:"remotePath" yaml:"remotePath"`
+	Mode         SyncMode `json:"mode,omitempty" yaml:"mode,omitempty"`
+	IgnoreDelete bool     `json:"ignoreDelete,omitempty" yaml:"ignoreDelete,omitempty"`
+}
+
 type storageResourceRaw struct {
 	Size  Quantity `json:"size,omitempty" yaml:"size,omitempty"`
 	Class string   `json:"class,omitempty" yaml:"class,omitempty"`
@@ -300,13 +310,16 @@ func (sync *Sync) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	sync.Compression = rawSync.Compression
 	sync.Verbose = rawSync.Verbose
 	sync.RescanInterval = rawSync.RescanInterval
+	sync.UploadLimit = rawSync.UploadLimit
+	sync.DownloadLimit = rawSync.DownloadLimit
+	sync.Engine = rawSync.Engine
 	sync.Folders = rawSync.Folders
 	return nil
 }
 
 // MarshalYAML Implements the marshaler interface of the yaml pkg.
 func (sync Sync) MarshalYAML() (interface{}, error) {
-	if !sync.Compression && sync.RescanInterval == DefaultSyncthingRescanInterval {
+	if !sync.Compression && sync.RescanInterval == DefaultSyncthingRescanInterval && sync.UploadLimit == 0 && sync.DownloadLimit == 0 && sync.Engine == "" {
 		return sync.Folders, nil
 	}
 	return syncRaw(sync), nil
@@ -574,7 +587,7 @@ func (s *SyncFolder) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var raw string
 	err := unmarshal(&raw)
 	if err != nil {
-		return err
+		return s.unmarshalExtendedForm(unmarshal)
 	}
 
 	parts := strings.Split(raw, ":")
@@ -604,17 +617,46 @@ func (s *SyncFolder) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return fmt.Errorf("each element in the 'sync' field must follow the syntax 'localPath:remotePath'")
 }
 
-// MarshalYAML Implements the marshaler interface of the yaml pkg.
-func (s SyncFolder) MarshalYAML() (interface{}, error) {
-	cwd, err := os.Getwd()
+func (s *SyncFolder) unmarshalExtendedForm(unmarshal func(interface{}) error) error {
+	var raw syncFolderRaw
+	if err := unmarshal(&raw); err != nil {
+		return fmt.Errorf("each element in the 'sync' field must follow the syntax 'localPath:remotePath' or define 'localPath' and 'remotePath'")
+	}
+	if raw.LocalPath == "" || raw.RemotePath == "" {
+		return fmt.Errorf("each element in the 'sync' field must define 'localPath' and 'remotePath'")
+	}
+
+	var err error
+	s.LocalPath, err = ExpandEnv(raw.LocalPath, true)
 	if err != nil {
-		return s.LocalPath + ":" + s.RemotePath, nil
+		return err
 	}
-	relPath, err := filepath.Rel(cwd, s.LocalPath)
+	s.RemotePath, err = ExpandEnv(raw.RemotePath, true)
 	if err != nil {
-		return s.LocalPath + ":" + s.RemotePath, nil
+		return err
 	}
-	return relPath + ":" + s.RemotePath, nil
+	s.Mode = raw.Mode
+	s.IgnoreDelete = raw.IgnoreDelete
+	return nil
+}
+
+// MarshalYAML Implements the marshaler interface of the yaml pkg.
+func (s SyncFolder) MarshalYAML() (interface{}, error) {
+	localPath := s.LocalPath
+	if cwd, err := os.Getwd(); err == nil {
+		if relPath, err := filepath.Rel(cwd, s.LocalPath); err == nil {
+			localPath = relPath
+		}
+	}
+	if s.GetMode() == SyncModeTwoWay && !s.IgnoreDelete {
+		return localPath + ":" + s.RemotePath, nil
+	}
+	return syncFolderRaw{
+		LocalPath:    localPath,
+		RemotePath:   s.RemotePath,
+		Mode:         s.Mode,
+		IgnoreDelete: s.IgnoreDelete,
+	}, nil
 }
 
 // UnmarshalYAML Implements the Unmarshaler interface of the yaml pkg.
@@ -752,6 +794,7 @@ type dependenciesRaw struct {
 	Branch       string      `json:"branch,omitempty" yaml:"branch,omitempty"`
 	Variables    Environment `json:"variables,omitempty" yaml:"variables,omitempty"`
 	Wait         bool        `json:"wait,omitempty" yaml:"wait,omitempty"`
+	DependsOn    []string    `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
 }
 
 func getRepoNameFromGitURL(repo *url.URL) string {
@@ -809,6 +852,7 @@ func (dependency *Dependency) UnmarshalYAML(unmarshal func(interface{}) error) e
 	dependency.Branch = rawDependency.Branch
 	dependency.Variables = rawDependency.Variables
 	dependency.Wait = rawDependency.Wait
+	dependency.DependsOn = rawDependency.DependsOn
 
 	return nil
 }
@@ -877,9 +921,12 @@ func (d *DeployInfo) MarshalYAML() (interface{}, error) {
 	if d.ComposeSection != nil && len(d.ComposeSection.ComposesInfo) != 0 {
 		return d, nil
 	}
+	if d.Kubernetes != nil || d.Helm != nil {
+		return d, nil
+	}
 	isCommandList := true
 	for _, cmd := range d.Commands {
-		if cmd.Command != cmd.Name {
+		if cmd.Command != cmd.Name || cmd.IsExtended() {
 			isCommandList = false
 		}
 	}
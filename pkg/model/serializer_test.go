@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -969,6 +970,46 @@ rescanInterval: 10`),
 				RescanInterval: 10,
 			},
 		},
+		{
+			name: "bandwidth-limits",
+			data: []byte(`folders:
+  - .:/usr/src/app
+  - localPath: node_modules
+    remotePath: /usr/src/app/node_modules
+    mode: receive-only
+    ignoreDelete: true
+uploadLimit: 500
+downloadLimit: 1000`),
+			expected: Sync{
+				Folders: []SyncFolder{
+					{
+						LocalPath:  ".",
+						RemotePath: "/usr/src/app"},
+					{
+						LocalPath:    "node_modules",
+						RemotePath:   "/usr/src/app/node_modules",
+						Mode:         SyncModeReceiveOnly,
+						IgnoreDelete: true,
+					},
+				},
+				UploadLimit:   500,
+				DownloadLimit: 1000,
+			},
+		},
+		{
+			name: "ssh-engine",
+			data: []byte(`folders:
+  - .:/usr/src/app
+engine: ssh`),
+			expected: Sync{
+				Folders: []SyncFolder{
+					{
+						LocalPath:  ".",
+						RemotePath: "/usr/src/app"},
+				},
+				Engine: SyncEngineSSH,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -989,9 +1030,10 @@ rescanInterval: 10`),
 func TestSyncFoldersUnmashalling(t *testing.T) {
 	os.Setenv("REMOTE_PATH", "/usr/src/app")
 	tests := []struct {
-		name     string
-		data     []byte
-		expected SyncFolder
+		name      string
+		data      []byte
+		expected  SyncFolder
+		expectErr bool
 	}{
 		{
 			name:     "same dir",
@@ -1018,13 +1060,37 @@ func TestSyncFoldersUnmashalling(t *testing.T) {
 			data:     []byte(`C:/Users/src/test:/usr/src/app`),
 			expected: SyncFolder{LocalPath: "C:/Users/src/test", RemotePath: "/usr/src/app"},
 		},
+		{
+			name: "extended form",
+			data: []byte(`localPath: .
+remotePath: ${REMOTE_PATH}
+mode: send-only`),
+			expected: SyncFolder{LocalPath: ".", RemotePath: "/usr/src/app", Mode: SyncModeSendOnly},
+		},
+		{
+			name:      "extended form without remote path",
+			data:      []byte(`localPath: .`),
+			expectErr: true,
+		},
+		{
+			name:      "wrong syntax",
+			data:      []byte(`[".", "/usr/src/app"]`),
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := SyncFolder{}
 
-			if err := yaml.UnmarshalStrict(tt.data, &result); err != nil {
+			err := yaml.UnmarshalStrict(tt.data, &result)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
 				t.Fatal(err)
 			}
 
@@ -1035,6 +1101,39 @@ func TestSyncFoldersUnmashalling(t *testing.T) {
 	}
 }
 
+func TestSyncFoldersMarshalling(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		folder   SyncFolder
+		expected string
+	}{
+		{
+			name:     "two-way",
+			folder:   SyncFolder{LocalPath: filepath.Join(cwd, "src"), RemotePath: "/app"},
+			expected: "src:/app\n",
+		},
+		{
+			name:     "receive-only",
+			folder:   SyncFolder{LocalPath: filepath.Join(cwd, "dist"), RemotePath: "/app/dist", Mode: SyncModeReceiveOnly, IgnoreDelete: true},
+			expected: "localPath: dist\nremotePath: /app/dist\nmode: receive-only\nignoreDelete: true\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marshalled, err := yaml.Marshal(tt.folder)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, string(marshalled))
+		})
+	}
+}
+
 func TestManifestUnmarshalling(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -1817,6 +1916,35 @@ func TestDeployInfoUnmarshalling(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "list of commands with retries and conditions",
+			deployInfoManifest: []byte(`
+- name: migrate
+  command: make migrate
+  timeout: 10m
+  retries: 3
+  continueOnError: true
+  when:
+    env: DATABASE_URL
+    status: success
+    branch: release-*`),
+			expected: &DeployInfo{
+				Commands: []DeployCommand{
+					{
+						Name:            "migrate",
+						Command:         "make migrate",
+						Timeout:         10 * time.Minute,
+						Retries:         3,
+						ContinueOnError: true,
+						When: &DeployCommandCondition{
+							Env:    "DATABASE_URL",
+							Status: SuccessCommandStatus,
+							Branch: "release-*",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "commands",
 			deployInfoManifest: []byte(`commands:
@@ -1830,6 +1958,45 @@ func TestDeployInfoUnmarshalling(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "kubernetes",
+			deployInfoManifest: []byte(`kubernetes:
+  paths:
+  - k8s/app.yml
+  - k8s/db
+  kustomize:
+  - overlays/dev
+  prune: true`),
+			expected: &DeployInfo{
+				Kubernetes: &DeployKubernetes{
+					Paths:     []string{"k8s/app.yml", "k8s/db"},
+					Kustomize: []string{"overlays/dev"},
+					Prune:     true,
+				},
+			},
+		},
+		{
+			name: "helm",
+			deployInfoManifest: []byte(`helm:
+  name: movies
+  chart: ./chart
+  values:
+  - values.yaml
+  set:
+    image.tag: dev
+  wait: true
+  timeout: 10m`),
+			expected: &DeployInfo{
+				Helm: &DeployHelm{
+					ReleaseName: "movies",
+					Chart:       "./chart",
+					ValuesFiles: []string{"values.yaml"},
+					Set:         map[string]string{"image.tag": "dev"},
+					Wait:        true,
+					Timeout:     10 * time.Minute,
+				},
+			},
+		},
 		{
 			name: "compose with endpoints",
 			deployInfoManifest: []byte(`compose:
@@ -1915,6 +2082,32 @@ func TestDeployInfoMarshalling(t *testing.T) {
 			}},
 			expected: "commands:\n- name: build\n  command: okteto build\n- name: deploy\n  command: okteto deploy\n",
 		},
+		{
+			name: "command-with-retries",
+			deployInfo: &DeployInfo{Commands: []DeployCommand{
+				{
+					Name:    "okteto build",
+					Command: "okteto build",
+					Retries: 2,
+				},
+			}},
+			expected: "commands:\n- name: okteto build\n  command: okteto build\n  retries: 2\n",
+		},
+		{
+			name: "commands-and-kubernetes",
+			deployInfo: &DeployInfo{
+				Commands: []DeployCommand{
+					{
+						Name:    "okteto build",
+						Command: "okteto build",
+					},
+				},
+				Kubernetes: &DeployKubernetes{
+					Paths: []string{"k8s"},
+				},
+			},
+			expected: "commands:\n- name: okteto build\n  command: okteto build\nkubernetes:\n  paths:\n  - k8s\n",
+		},
 	}
 
 	for _, tt := range tests {
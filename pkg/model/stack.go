@@ -32,6 +32,7 @@ import (
 	yaml "gopkg.in/yaml.v2"
 	apiv1 "k8s.io/api/core/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 var (
@@ -41,16 +42,26 @@ var (
 
 // Stack represents an okteto stack
 type Stack struct {
-	Manifest  []byte                 `yaml:"-"`
-	Paths     []string               `yaml:"-"`
-	Warnings  StackWarnings          `yaml:"-"`
-	IsCompose bool                   `yaml:"-"`
-	Name      string                 `yaml:"name"`
-	Volumes   map[string]*VolumeSpec `yaml:"volumes,omitempty"`
-	Namespace string                 `yaml:"namespace,omitempty"`
-	Context   string                 `yaml:"context,omitempty"`
-	Services  composeServices        `yaml:"services,omitempty"`
-	Endpoints EndpointSpec           `yaml:"endpoints,omitempty"`
+	Manifest  []byte                   `yaml:"-"`
+	Paths     []string                 `yaml:"-"`
+	Warnings  StackWarnings            `yaml:"-"`
+	IsCompose bool                     `yaml:"-"`
+	Name      string                   `yaml:"name"`
+	Volumes   map[string]*VolumeSpec   `yaml:"volumes,omitempty"`
+	Namespace string                   `yaml:"namespace,omitempty"`
+	Context   string                   `yaml:"context,omitempty"`
+	Services  composeServices          `yaml:"services,omitempty"`
+	Endpoints EndpointSpec             `yaml:"endpoints,omitempty"`
+	Configs   map[string]*StackConfig  `yaml:"configs,omitempty"`
+	Secrets   map[string]*StackConfig  `yaml:"secrets,omitempty"`
+	Networks  map[string]*StackNetwork `yaml:"networks,omitempty"`
+
+	// dir is the folder relative paths are resolved from. The current folder is used if it's empty
+	dir string
+	// extendsChain are the services of other files being extended while reading this stack, to detect circular references
+	extendsChain map[string]bool
+	// explicitFields are the fields set in the definition of each service, see mergeService
+	explicitFields map[string]*explicitServiceFields
 }
 
 type composeServices map[string]*Service
@@ -65,18 +76,44 @@ type Service struct {
 	EnvFiles   EnvFiles           `yaml:"env_file,omitempty"`
 	DependsOn  DependsOn          `yaml:"depends_on,omitempty"`
 
-	Environment     Environment           `yaml:"environment,omitempty"`
-	Image           string                `yaml:"image,omitempty"`
-	Labels          Labels                `json:"labels,omitempty" yaml:"labels,omitempty"`
-	Annotations     Annotations           `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	Ports           []Port                `yaml:"ports,omitempty"`
-	RestartPolicy   apiv1.RestartPolicy   `yaml:"restart,omitempty"`
-	StopGracePeriod int64                 `yaml:"stop_grace_period,omitempty"`
-	Volumes         []StackVolume         `yaml:"volumes,omitempty"`
-	Workdir         string                `yaml:"workdir,omitempty"`
-	BackOffLimit    int32                 `yaml:"max_attempts,omitempty"`
-	Healtcheck      *HealthCheck          `yaml:"healthcheck,omitempty"`
-	User            *StackSecurityContext `yaml:"user,omitempty"`
+	Environment     Environment                `yaml:"environment,omitempty"`
+	Image           string                     `yaml:"image,omitempty"`
+	Labels          Labels                     `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations     Annotations                `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Ports           []Port                     `yaml:"ports,omitempty"`
+	RestartPolicy   apiv1.RestartPolicy        `yaml:"restart,omitempty"`
+	StopGracePeriod int64                      `yaml:"stop_grace_period,omitempty"`
+	Volumes         []StackVolume              `yaml:"volumes,omitempty"`
+	Workdir         string                     `yaml:"workdir,omitempty"`
+	BackOffLimit    int32                      `yaml:"max_attempts,omitempty"`
+	Healtcheck      *HealthCheck               `yaml:"healthcheck,omitempty"`
+	User            *StackSecurityContext      `yaml:"user,omitempty"`
+	Profiles        []string                   `yaml:"profiles,omitempty"`
+	Configs         []ServiceConfig            `yaml:"configs,omitempty"`
+	Secrets         []ServiceConfig            `yaml:"secrets,omitempty"`
+	Networks        map[string]*ServiceNetwork `yaml:"networks,omitempty"`
+
+	DNS         []string          `yaml:"dns,omitempty"`
+	DNSOpt      []string          `yaml:"dns_opt,omitempty"`
+	DNSSearch   []string          `yaml:"dns_search,omitempty"`
+	DomainName  string            `yaml:"domainname,omitempty"`
+	ExtraHosts  map[string]string `yaml:"extra_hosts,omitempty"`
+	Hostname    string            `yaml:"hostname,omitempty"`
+	Init        bool              `yaml:"init,omitempty"`
+	Privileged  bool              `yaml:"privileged,omitempty"`
+	ReadOnly    bool              `yaml:"read_only,omitempty"`
+	SecurityOpt []string          `yaml:"security_opt,omitempty"`
+	StdinOpen   bool              `yaml:"stdin_open,omitempty"`
+	Sysctls     map[string]string `yaml:"sysctls,omitempty"`
+	Tty         bool              `yaml:"tty,omitempty"`
+
+	UpdateConfig   *UpdateConfig `yaml:"update_config,omitempty"`
+	RollbackConfig *UpdateConfig `yaml:"rollback_config,omitempty"`
+	Placement      *Placement    `yaml:"placement,omitempty"`
+
+	Schedule         *ServiceSchedule         `yaml:"x-okteto-schedule,omitempty"`
+	Autoscaling      *ServiceAutoscaling      `yaml:"autoscaling,omitempty"`
+	DisruptionBudget *ServiceDisruptionBudget `yaml:"disruption_budget,omitempty"`
 
 	// Fields only for okteto stacks
 	Public    bool            `yaml:"public,omitempty"`
@@ -189,6 +226,10 @@ type DependsOn map[string]DependsOnConditionSpec
 
 type DependsOnConditionSpec struct {
 	Condition DependsOnCondition `json:"condition,omitempty" yaml:"condition,omitempty"`
+	// Restart restarts the service when the dependency is redeployed
+	Restart bool `json:"restart,omitempty" yaml:"restart,omitempty"`
+	// Timeout is the maximum time to wait for the dependency to reach the condition
+	Timeout time.Duration `json:"x-okteto-timeout,omitempty" yaml:"x-okteto-timeout,omitempty"`
 }
 
 type DependsOnCondition string
@@ -201,6 +242,151 @@ const (
 	DependsOnServiceCompleted DependsOnCondition = "service_completed_successfully"
 )
 
+// StackConfig represents a top-level config or secret of a compose file
+type StackConfig struct {
+	File        string `yaml:"file,omitempty"`
+	Environment string `yaml:"environment,omitempty"`
+	Content     string `yaml:"content,omitempty"`
+	// External configs and secrets are not created by okteto, they must exist in the namespace
+	External bool   `yaml:"external,omitempty"`
+	Name     string `yaml:"name,omitempty"`
+}
+
+// ServiceConfig represents a config or secret mounted by a service
+type ServiceConfig struct {
+	Source string `yaml:"source"`
+	Target string `yaml:"target,omitempty"`
+	UID    string `yaml:"uid,omitempty"`
+	GID    string `yaml:"gid,omitempty"`
+	Mode   *int32 `yaml:"mode,omitempty"`
+}
+
+// StringList represents a compose field that can be a single string or a list of strings
+type StringList []string
+
+// ExtraHosts represents the extra hosts of a service: each host is mapped to an ip
+type ExtraHosts map[string]string
+
+// Sysctls represents the namespaced kernel parameters of a service
+type Sysctls map[string]string
+
+// StackSecurityOpts represents the security_opt values of a service that have a kubernetes equivalent
+type StackSecurityOpts struct {
+	NoNewPrivileges bool
+	SELinuxOptions  *apiv1.SELinuxOptions
+	SeccompProfile  *apiv1.SeccompProfile
+	// AppArmorProfile is the value of the apparmor annotation of the container
+	AppArmorProfile string
+}
+
+// UpdateOrder defines if the old pods of a service are stopped before starting the new ones
+type UpdateOrder string
+
+// UpdateFailureAction defines what to do when the update of a service fails
+type UpdateFailureAction string
+
+const (
+	// UpdateOrderStopFirst stops the old pods before starting the new ones
+	UpdateOrderStopFirst UpdateOrder = "stop-first"
+	// UpdateOrderStartFirst starts the new pods before stopping the old ones
+	UpdateOrderStartFirst UpdateOrder = "start-first"
+
+	// UpdateFailureActionPause leaves the failed update as it is
+	UpdateFailureActionPause UpdateFailureAction = "pause"
+	// UpdateFailureActionContinue ignores the failed update
+	UpdateFailureActionContinue UpdateFailureAction = "continue"
+	// UpdateFailureActionRollback rolls back the service to its previous revision
+	UpdateFailureActionRollback UpdateFailureAction = "rollback"
+
+	kubernetesHostnameLabel = "kubernetes.io/hostname"
+	kubernetesOSLabel       = "kubernetes.io/os"
+	kubernetesArchLabel     = "kubernetes.io/arch"
+)
+
+// UpdateConfig represents how the pods of a service are replaced when it is updated or rolled back
+type UpdateConfig struct {
+	// Parallelism is the number of pods replaced at the same time. It replaces one pod at a time if it's not set,
+	// and all of them at once if it's 0
+	Parallelism *int32 `yaml:"parallelism,omitempty"`
+	// Delay is the number of seconds a new pod must be ready before replacing the next ones
+	Delay         int64               `yaml:"delay,omitempty"`
+	FailureAction UpdateFailureAction `yaml:"failure_action,omitempty"`
+	Order         UpdateOrder         `yaml:"order,omitempty"`
+}
+
+// Placement represents the nodes where the pods of a service are scheduled
+type Placement struct {
+	Constraints []PlacementConstraint `yaml:"constraints,omitempty"`
+	// Spread are the node labels used to spread the pods of the service
+	Spread []string `yaml:"spread,omitempty"`
+}
+
+// PlacementConstraint represents a node label required by a service
+type PlacementConstraint struct {
+	Key     string `yaml:"key"`
+	Value   string `yaml:"value"`
+	Negated bool   `yaml:"negated,omitempty"`
+}
+
+// ScheduleConcurrencyPolicy defines how to treat the concurrent runs of a scheduled service
+type ScheduleConcurrencyPolicy string
+
+const (
+	// ScheduleConcurrencyAllow allows concurrent runs
+	ScheduleConcurrencyAllow ScheduleConcurrencyPolicy = "Allow"
+	// ScheduleConcurrencyForbid skips a run if the previous one hasn't finished yet
+	ScheduleConcurrencyForbid ScheduleConcurrencyPolicy = "Forbid"
+	// ScheduleConcurrencyReplace replaces the previous run if it hasn't finished yet
+	ScheduleConcurrencyReplace ScheduleConcurrencyPolicy = "Replace"
+)
+
+// ServiceSchedule represents a service that runs periodically instead of being deployed once
+type ServiceSchedule struct {
+	Cron                       string                    `yaml:"cron"`
+	ConcurrencyPolicy          ScheduleConcurrencyPolicy `yaml:"concurrency_policy,omitempty"`
+	SuccessfulJobsHistoryLimit *int32                    `yaml:"successful_jobs_history_limit,omitempty"`
+	FailedJobsHistoryLimit     *int32                    `yaml:"failed_jobs_history_limit,omitempty"`
+	Suspend                    bool                      `yaml:"suspend,omitempty"`
+}
+
+// NextRun returns the next time the scheduled service runs after t
+func (s *ServiceSchedule) NextRun(t time.Time) (time.Time, error) {
+	schedule, err := ParseCronSchedule(s.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(t), nil
+}
+
+// ServiceAutoscaling represents the horizontal autoscaling of a service
+type ServiceAutoscaling struct {
+	MinReplicas int32 `yaml:"min_replicas"`
+	MaxReplicas int32 `yaml:"max_replicas"`
+	// CPU and Memory are the target average utilization of the pods, as a percentage of their requests
+	CPU    int32 `yaml:"cpu,omitempty"`
+	Memory int32 `yaml:"memory,omitempty"`
+}
+
+// ServiceDisruptionBudget represents the pods of a service that must be available during voluntary disruptions
+type ServiceDisruptionBudget struct {
+	MinAvailable   *intstr.IntOrString `yaml:"min_available,omitempty"`
+	MaxUnavailable *intstr.IntOrString `yaml:"max_unavailable,omitempty"`
+}
+
+// defaultNetworkName is the network of the services that don't declare any network
+const defaultNetworkName = "default"
+
+// StackNetwork represents a top-level network of a compose file
+type StackNetwork struct {
+	// Internal networks block the traffic of their services to anything outside of the network
+	Internal bool `yaml:"internal,omitempty"`
+}
+
+// ServiceNetwork represents a network a service is attached to
+type ServiceNetwork struct {
+	Aliases []string `yaml:"aliases,omitempty"`
+}
+
 // GetStackFromPath returns an okteto stack object from a given file
 func GetStackFromPath(name, stackPath string, isCompose bool) (*Stack, error) {
 	b, err := os.ReadFile(stackPath)
@@ -226,7 +412,7 @@ func GetStackFromPath(name, stackPath string, isCompose bool) (*Stack, error) {
 
 	s, err := ReadStack(b, isCompose)
 	if err != nil {
-		return nil, err
+		return nil, locateInterpolationError(err, stackPath, b)
 	}
 	s.Paths = []string{stackPath}
 	s.Name, err = getStackName(name, stackPath, s.Name)
@@ -295,9 +481,16 @@ func getStackName(name, stackPath, actualStackName string) (string, error) {
 
 // ReadStack reads an okteto stack
 func ReadStack(bytes []byte, isCompose bool) (*Stack, error) {
+	return readStack(bytes, isCompose, "", nil)
+}
+
+// readStack reads an okteto stack resolving its relative paths from dir
+func readStack(bytes []byte, isCompose bool, dir string, extendsChain map[string]bool) (*Stack, error) {
 	s := &Stack{
-		Manifest:  bytes,
-		IsCompose: isCompose,
+		Manifest:     bytes,
+		IsCompose:    isCompose,
+		dir:          dir,
+		extendsChain: extendsChain,
 	}
 	expandedManifest, err := ExpandStackEnvs(bytes)
 	if err != nil {
@@ -335,7 +528,7 @@ func ReadStack(bytes []byte, isCompose bool) (*Stack, error) {
 			svc.Resources.Requests.Storage.Size.Value = resource.MustParse("1Gi")
 		}
 
-		if svc.IsJob() {
+		if svc.IsJob() || svc.IsCronJob() {
 			for idx, volume := range svc.Volumes {
 				volumeName := fmt.Sprintf("pvc-%s-0", svcName)
 				if volume.LocalPath == "" {
@@ -390,7 +583,7 @@ func (svc *Service) ToDev(svcName string) (*Dev, error) {
 	}
 	for _, v := range svc.VolumeMounts {
 		if pathExistsAndDir(v.LocalPath) {
-			d.Sync.Folders = append(d.Sync.Folders, SyncFolder(v))
+			d.Sync.Folders = append(d.Sync.Folders, SyncFolder{LocalPath: v.LocalPath, RemotePath: v.RemotePath})
 		}
 	}
 	d.Command = svc.Command
@@ -435,6 +628,10 @@ func (s *Stack) Validate() error {
 			return fmt.Errorf(fmt.Sprintf("Invalid service '%s': image cannot be empty", name))
 		}
 
+		if (svc.Autoscaling != nil || svc.DisruptionBudget != nil) && !svc.IsDeployment() && !svc.IsStatefulset() {
+			return fmt.Errorf("Invalid service '%s': autoscaling and disruption budgets are only supported by services with restart policy 'always'", name)
+		}
+
 		for _, v := range svc.VolumeMounts {
 			if svc.Build == nil && filesystem.FileExists(v.LocalPath) {
 				continue
@@ -448,9 +645,87 @@ func (s *Stack) Validate() error {
 		}
 		svc.ignoreSyncVolumes()
 	}
+	if err := validateStackConfigs(s); err != nil {
+		return err
+	}
+	if err := validateStackNetworks(s); err != nil {
+		return err
+	}
 	return validateDependsOn(s)
 }
 
+func validateStackConfigs(s *Stack) error {
+	for kind, configs := range map[string]map[string]*StackConfig{"config": s.Configs, "secret": s.Secrets} {
+		for name, config := range configs {
+			if err := validateStackName(name); err != nil {
+				return fmt.Errorf("Invalid %s name '%s': %s", kind, name, err)
+			}
+			sources := 0
+			for _, source := range []string{config.File, config.Environment, config.Content} {
+				if source != "" {
+					sources++
+				}
+			}
+			if config.External && sources > 0 {
+				return fmt.Errorf("Invalid %s '%s': external %ss can't define 'file', 'environment' or 'content'", kind, name, kind)
+			}
+			if !config.External && sources != 1 {
+				return fmt.Errorf("Invalid %s '%s': exactly one of 'file', 'environment' or 'content' must be defined", kind, name)
+			}
+			if kind == "secret" && config.Content != "" {
+				return fmt.Errorf("Invalid secret '%s': 'content' is only supported for configs", name)
+			}
+		}
+	}
+
+	for svcName, svc := range s.Services {
+		for _, config := range svc.Configs {
+			if _, ok := s.Configs[config.Source]; !ok {
+				return fmt.Errorf("Invalid service '%s': config '%s' is not defined in the 'configs' section", svcName, config.Source)
+			}
+		}
+		for _, secret := range svc.Secrets {
+			if _, ok := s.Secrets[secret.Source]; !ok {
+				return fmt.Errorf("Invalid service '%s': secret '%s' is not defined in the 'secrets' section", svcName, secret.Source)
+			}
+		}
+	}
+	return nil
+}
+
+func validateStackNetworks(s *Stack) error {
+	for name := range s.Networks {
+		if err := validateStackName(name); err != nil {
+			return fmt.Errorf("Invalid network name '%s': %s", name, err)
+		}
+	}
+
+	aliases := map[string]string{}
+	for svcName, svc := range s.Services {
+		for networkName, network := range svc.Networks {
+			if _, ok := s.Networks[networkName]; !ok {
+				return fmt.Errorf("Invalid service '%s': network '%s' is not defined in the 'networks' section", svcName, networkName)
+			}
+			if network == nil {
+				continue
+			}
+			for _, alias := range network.Aliases {
+				if err := validateStackName(alias); err != nil {
+					return fmt.Errorf("Invalid alias '%s' in service '%s': %s", alias, svcName, err)
+				}
+				if _, ok := s.Services[alias]; ok && alias != svcName {
+					return fmt.Errorf("Invalid alias '%s' in service '%s': there is a service with the same name", alias, svcName)
+				}
+				if other, ok := aliases[alias]; ok && other != svcName {
+					return fmt.Errorf("Invalid alias '%s' in service '%s': it is already an alias of service '%s'", alias, svcName, other)
+				}
+				aliases[alias] = svcName
+			}
+		}
+	}
+	return nil
+}
+
 func validateStackName(name string) error {
 	if name == "" {
 		return fmt.Errorf("name cannot be empty")
@@ -473,6 +748,9 @@ func validateDependsOn(s *Stack) error {
 			if _, ok := s.Services[dependentSvc]; !ok {
 				return fmt.Errorf(" Service '%s' depends on service '%s' which is undefined.", svcName, dependentSvc)
 			}
+			if s.Services[dependentSvc].IsCronJob() {
+				return fmt.Errorf(" Service '%s' depends on service '%s' which is scheduled. Scheduled services can't be dependencies.", svcName, dependentSvc)
+			}
 			if condition.Condition == DependsOnServiceCompleted && !s.Services[dependentSvc].IsJob() {
 				return fmt.Errorf(" Service '%s' is not a job. Please make sure the 'restart_policy' is not set to 'always' in service '%s' ", dependentSvc, dependentSvc)
 			}
@@ -576,15 +854,22 @@ func isInVolumesTopLevelSection(volumeName string, s *Stack) bool {
 }
 
 func (svc *Service) IsDeployment() bool {
-	return len(svc.Volumes) == 0 && (svc.RestartPolicy == apiv1.RestartPolicyAlways || (svc.RestartPolicy == apiv1.RestartPolicyOnFailure && svc.BackOffLimit == 0))
+	return !svc.IsCronJob() && len(svc.Volumes) == 0 && (svc.RestartPolicy == apiv1.RestartPolicyAlways || (svc.RestartPolicy == apiv1.RestartPolicyOnFailure && svc.BackOffLimit == 0))
 }
 func (svc *Service) IsStatefulset() bool {
-	return len(svc.Volumes) != 0 && (svc.RestartPolicy == apiv1.RestartPolicyAlways || (svc.RestartPolicy == apiv1.RestartPolicyOnFailure && svc.BackOffLimit == 0))
+	return !svc.IsCronJob() && len(svc.Volumes) != 0 && (svc.RestartPolicy == apiv1.RestartPolicyAlways || (svc.RestartPolicy == apiv1.RestartPolicyOnFailure && svc.BackOffLimit == 0))
 }
 func (svc *Service) IsJob() bool {
-	return svc.RestartPolicy == apiv1.RestartPolicyNever || (svc.RestartPolicy == apiv1.RestartPolicyOnFailure && svc.BackOffLimit != 0)
+	return !svc.IsCronJob() && (svc.RestartPolicy == apiv1.RestartPolicyNever || (svc.RestartPolicy == apiv1.RestartPolicyOnFailure && svc.BackOffLimit != 0))
+}
+
+// IsCronJob returns if the service runs periodically
+func (svc *Service) IsCronJob() bool {
+	return svc.Schedule != nil
 }
 
+// Merge merges otherStack into stack following the compose specification:
+// single values are overridden, mappings are merged by key and sequences are appended
 func (stack *Stack) Merge(otherStack *Stack) *Stack {
 	if stack == nil {
 		return otherStack
@@ -593,10 +878,44 @@ func (stack *Stack) Merge(otherStack *Stack) *Stack {
 		stack.Namespace = otherStack.Namespace
 	}
 	if len(otherStack.Endpoints) > 0 {
-		stack.Endpoints = otherStack.Endpoints
+		if stack.Endpoints == nil {
+			stack.Endpoints = EndpointSpec{}
+		}
+		for name, endpoint := range otherStack.Endpoints {
+			stack.Endpoints[name] = endpoint
+		}
 	}
 	if len(otherStack.Volumes) > 0 {
-		stack.Volumes = otherStack.Volumes
+		if stack.Volumes == nil {
+			stack.Volumes = map[string]*VolumeSpec{}
+		}
+		for name, volume := range otherStack.Volumes {
+			stack.Volumes[name] = volume
+		}
+	}
+	if len(otherStack.Configs) > 0 {
+		if stack.Configs == nil {
+			stack.Configs = map[string]*StackConfig{}
+		}
+		for name, config := range otherStack.Configs {
+			stack.Configs[name] = config
+		}
+	}
+	if len(otherStack.Secrets) > 0 {
+		if stack.Secrets == nil {
+			stack.Secrets = map[string]*StackConfig{}
+		}
+		for name, secret := range otherStack.Secrets {
+			stack.Secrets[name] = secret
+		}
+	}
+	if len(otherStack.Networks) > 0 {
+		if stack.Networks == nil {
+			stack.Networks = map[string]*StackNetwork{}
+		}
+		for name, network := range otherStack.Networks {
+			stack.Networks[name] = network
+		}
 	}
 	stack.Paths = append(stack.Paths, otherStack.Paths...)
 	stack = stack.mergeServices(otherStack)
@@ -609,70 +928,11 @@ func (stack *Stack) mergeServices(otherStack *Stack) *Stack {
 			stack.Services[svcName] = svc
 			continue
 		}
-		resultSvc := stack.Services[svcName]
-		if svc.Image != "" {
-			resultSvc.Image = svc.Image
-		}
-		if svc.RestartPolicy != apiv1.RestartPolicyAlways {
-			resultSvc.RestartPolicy = svc.RestartPolicy
-		}
-		if svc.Workdir != "" {
-			resultSvc.Workdir = svc.Workdir
-		}
-		if svc.Replicas != 1 {
-			resultSvc.Replicas = svc.Replicas
-		}
-		if svc.StopGracePeriod != 0 {
-			resultSvc.StopGracePeriod = svc.StopGracePeriod
-		}
-		if svc.BackOffLimit != 0 {
-			resultSvc.BackOffLimit = svc.BackOffLimit
-		}
-		if svc.Build != nil {
-			resultSvc.Build = svc.Build
-		}
-		if svc.Healtcheck != nil {
-			resultSvc.Healtcheck = svc.Healtcheck
-		}
-
-		if len(svc.CapAdd) > 0 {
-			resultSvc.CapAdd = svc.CapAdd
-		}
-		if len(svc.CapDrop) > 0 {
-			resultSvc.CapDrop = svc.CapDrop
-		}
-
-		if len(svc.Entrypoint.Values) > 0 {
-			resultSvc.Entrypoint = svc.Entrypoint
-		}
-		if len(svc.Command.Values) > 0 {
-			resultSvc.Command = svc.Command
-		}
-		if len(svc.EnvFiles) > 0 {
-			resultSvc.EnvFiles = svc.EnvFiles
-		}
-		if len(svc.DependsOn) > 0 {
-			resultSvc.DependsOn = svc.DependsOn
-		}
-		if len(svc.Environment) > 0 {
-			resultSvc.Environment = svc.Environment
-		}
-		if len(svc.Labels) > 0 {
-			resultSvc.Labels = svc.Labels
-		}
-		if len(svc.Annotations) > 0 {
-			resultSvc.Annotations = svc.Annotations
-		}
-		if len(svc.Ports) > 0 {
-			resultSvc.Ports = svc.Ports
-		}
-		if len(svc.Volumes) > 0 {
-			resultSvc.Volumes = svc.Volumes
-			resultSvc.VolumeMounts = svc.VolumeMounts
-		}
-		if !svc.Resources.IsDefaultValue() {
-			resultSvc.Resources = svc.Resources
+		var explicit *explicitServiceFields
+		if otherStack.explicitFields != nil {
+			explicit = otherStack.explicitFields[svcName]
 		}
+		stack.Services[svcName] = mergeService(stack.Services[svcName], svc, explicit)
 	}
 	return stack
 }
@@ -725,6 +985,8 @@ func LoadStack(name string, stackPaths []string, validate bool) (*Stack, error)
 			return nil, fmt.Errorf("'%s' does not exist", stackPath)
 		}
 	}
+	resultStack.applyProfiles(getActiveProfiles())
+	resultStack.setDefaultNetwork()
 	if validate {
 		if err := resultStack.Validate(); err != nil {
 			return nil, err
@@ -756,7 +1018,10 @@ func getStack(name, manifestPath string) (*Stack, error) {
 		return nil, err
 	}
 	overrideStack, err := getOverrideFile(manifestPath)
-	if err == nil {
+	if err != nil {
+		return nil, err
+	}
+	if overrideStack != nil {
 		oktetoLog.Info("override file detected. Merging it")
 		stack = stack.Merge(overrideStack)
 	}
@@ -773,22 +1038,19 @@ func isDeprecatedExtension(stackPath string) bool {
 	return false
 }
 
+// getOverrideFile returns the stack defined in '<name>.override<ext>', or nil if the file doesn't exist
 func getOverrideFile(stackPath string) (*Stack, error) {
 	extension := filepath.Ext(stackPath)
 	fileName := strings.TrimSuffix(stackPath, extension)
 	overridePath := fmt.Sprintf("%s.override%s", fileName, extension)
-	var isCompose bool
-	if filesystem.FileExists(stackPath) {
-		if isPathAComposeFile(stackPath) {
-			isCompose = true
-		}
-		stack, err := GetStackFromPath("", overridePath, isCompose)
-		if err != nil {
-			return nil, err
-		}
-		return stack, nil
+	if !filesystem.FileExists(overridePath) {
+		return nil, nil
+	}
+	stack, err := GetStackFromPath("", overridePath, isPathAComposeFile(stackPath))
+	if err != nil {
+		return nil, fmt.Errorf("error reading override file '%s': %w", overridePath, err)
 	}
-	return nil, fmt.Errorf("override file not found")
+	return stack, nil
 }
 
 func loadEnvFiles(svc *Service, svcName string) error {
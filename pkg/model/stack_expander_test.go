@@ -137,6 +137,14 @@ volumes:
 `,
 			expectedError: false,
 		},
+		{
+			name: "required env",
+			file: []byte(`
+services:
+    myservice:
+        image: ${IMAGE:?the image is required}`),
+			expectedError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,559 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// explicitServiceFields tracks the fields set in the definition of a service whose zero or default value is also a valid override
+type explicitServiceFields struct {
+	init          bool
+	privileged    bool
+	readOnly      bool
+	stdinOpen     bool
+	tty           bool
+	replicas      bool
+	restartPolicy bool
+}
+
+func getExplicitServiceFields(raw *ServiceRaw) *explicitServiceFields {
+	result := &explicitServiceFields{
+		init:          raw.Init != nil,
+		privileged:    raw.Privileged != nil,
+		readOnly:      raw.ReadOnly != nil,
+		stdinOpen:     raw.StdinOpen != nil,
+		tty:           raw.Tty != nil,
+		replicas:      raw.Replicas != nil || raw.Scale != nil,
+		restartPolicy: raw.Restart != "",
+	}
+	if raw.Deploy != nil {
+		result.replicas = result.replicas || raw.Deploy.Replicas != nil
+		result.restartPolicy = result.restartPolicy || (raw.Deploy.RestartPolicy != nil && raw.Deploy.RestartPolicy.Condition != "")
+	}
+	return result
+}
+
+// mergeService returns the result of overriding base with override following the compose merge rules:
+// - single values (image, command, entrypoint, healthcheck...) are replaced
+// - mappings (environment, labels, annotations, depends_on, build args) are merged by key
+// - sequences are appended, except ports and volumes that are unique by their target
+// The fields in explicit are replaced whenever override sets them, even to their default value.
+// If explicit is nil, only the values of override different from the default value are applied
+func mergeService(base, override *Service, explicit *explicitServiceFields) *Service {
+	result := *base
+
+	if override.Image != "" {
+		result.Image = override.Image
+	}
+	if explicit != nil {
+		if explicit.restartPolicy {
+			result.RestartPolicy = override.RestartPolicy
+		}
+		if explicit.replicas {
+			result.Replicas = override.Replicas
+		}
+		if explicit.init {
+			result.Init = override.Init
+		}
+		if explicit.privileged {
+			result.Privileged = override.Privileged
+		}
+		if explicit.readOnly {
+			result.ReadOnly = override.ReadOnly
+		}
+		if explicit.stdinOpen {
+			result.StdinOpen = override.StdinOpen
+		}
+		if explicit.tty {
+			result.Tty = override.Tty
+		}
+	} else {
+		if override.RestartPolicy != apiv1.RestartPolicyAlways && override.RestartPolicy != "" {
+			result.RestartPolicy = override.RestartPolicy
+		}
+		if override.Replicas != 1 && override.Replicas != 0 {
+			result.Replicas = override.Replicas
+		}
+		result.Init = base.Init || override.Init
+		result.Privileged = base.Privileged || override.Privileged
+		result.ReadOnly = base.ReadOnly || override.ReadOnly
+		result.StdinOpen = base.StdinOpen || override.StdinOpen
+		result.Tty = base.Tty || override.Tty
+	}
+	if override.Workdir != "" {
+		result.Workdir = override.Workdir
+	}
+	if override.StopGracePeriod != 0 {
+		result.StopGracePeriod = override.StopGracePeriod
+	}
+	if override.BackOffLimit != 0 {
+		result.BackOffLimit = override.BackOffLimit
+	}
+	if override.Healtcheck != nil {
+		result.Healtcheck = override.Healtcheck
+	}
+	if override.User != nil {
+		result.User = override.User
+	}
+	if override.Public {
+		result.Public = true
+	}
+	if override.UpdateConfig != nil {
+		result.UpdateConfig = override.UpdateConfig
+	}
+	if override.RollbackConfig != nil {
+		result.RollbackConfig = override.RollbackConfig
+	}
+	if override.Placement != nil {
+		result.Placement = override.Placement
+	}
+	if override.Schedule != nil {
+		result.Schedule = override.Schedule
+	}
+	if override.Autoscaling != nil {
+		result.Autoscaling = override.Autoscaling
+	}
+	if override.DisruptionBudget != nil {
+		result.DisruptionBudget = override.DisruptionBudget
+	}
+	if override.Hostname != "" {
+		result.Hostname = override.Hostname
+	}
+	if override.DomainName != "" {
+		result.DomainName = override.DomainName
+	}
+	if len(override.Entrypoint.Values) > 0 {
+		result.Entrypoint = override.Entrypoint
+	}
+	if len(override.Command.Values) > 0 {
+		result.Command = override.Command
+	}
+	if !override.Resources.IsDefaultValue() {
+		result.Resources = override.Resources
+	}
+
+	result.Build = mergeBuildInfo(base.Build, override.Build)
+	result.CapAdd = mergeCapabilities(base.CapAdd, override.CapAdd)
+	result.CapDrop = mergeCapabilities(base.CapDrop, override.CapDrop)
+	result.EnvFiles = mergeUniqueStrings(base.EnvFiles, override.EnvFiles)
+	result.Profiles = mergeUniqueStrings(base.Profiles, override.Profiles)
+	result.Environment = mergeEnvironment(base.Environment, override.Environment)
+	result.Ports = mergePorts(base.Ports, override.Ports)
+	result.Volumes, result.VolumeMounts = mergeVolumes(base, override)
+	result.Configs = mergeServiceConfigs(base.Configs, override.Configs)
+	result.Secrets = mergeServiceConfigs(base.Secrets, override.Secrets)
+	result.DNS = mergeUniqueStrings(base.DNS, override.DNS)
+	result.DNSOpt = mergeUniqueStrings(base.DNSOpt, override.DNSOpt)
+	result.DNSSearch = mergeUniqueStrings(base.DNSSearch, override.DNSSearch)
+	result.SecurityOpt = mergeUniqueStrings(base.SecurityOpt, override.SecurityOpt)
+	result.ExtraHosts = mergeStringMaps(base.ExtraHosts, override.ExtraHosts)
+	result.Sysctls = mergeStringMaps(base.Sysctls, override.Sysctls)
+
+	if len(base.Networks) > 0 || len(override.Networks) > 0 {
+		result.Networks = map[string]*ServiceNetwork{}
+		for name, network := range base.Networks {
+			result.Networks[name] = network
+		}
+		for name, network := range override.Networks {
+			result.Networks[name] = network
+		}
+	}
+
+	if len(base.Labels) > 0 || len(override.Labels) > 0 {
+		result.Labels = Labels{}
+		for k, v := range base.Labels {
+			result.Labels[k] = v
+		}
+		for k, v := range override.Labels {
+			result.Labels[k] = v
+		}
+	}
+	if len(base.Annotations) > 0 || len(override.Annotations) > 0 {
+		result.Annotations = Annotations{}
+		for k, v := range base.Annotations {
+			result.Annotations[k] = v
+		}
+		for k, v := range override.Annotations {
+			result.Annotations[k] = v
+		}
+	}
+	if len(base.DependsOn) > 0 || len(override.DependsOn) > 0 {
+		result.DependsOn = DependsOn{}
+		for k, v := range base.DependsOn {
+			result.DependsOn[k] = v
+		}
+		for k, v := range override.DependsOn {
+			result.DependsOn[k] = v
+		}
+	}
+	return &result
+}
+
+func mergeBuildInfo(base, override *BuildInfo) *BuildInfo {
+	if base == nil {
+		return override
+	}
+	if override == nil {
+		return base
+	}
+	result := *base
+	if override.Name != "" {
+		result.Name = override.Name
+	}
+	if override.Context != "" {
+		result.Context = override.Context
+	}
+	if override.Dockerfile != "" {
+		result.Dockerfile = override.Dockerfile
+	}
+	if override.Target != "" {
+		result.Target = override.Target
+	}
+	if override.Image != "" {
+		result.Image = override.Image
+	}
+	if override.ExportCache != "" {
+		result.ExportCache = override.ExportCache
+	}
+	result.CacheFrom = mergeUniqueStrings(base.CacheFrom, override.CacheFrom)
+	result.Args = BuildArgs{}
+	for _, arg := range base.Args {
+		result.Args = append(result.Args, arg)
+	}
+	for _, arg := range override.Args {
+		found := false
+		for i := range result.Args {
+			if result.Args[i].Name == arg.Name {
+				result.Args[i] = arg
+				found = true
+				break
+			}
+		}
+		if !found {
+			result.Args = append(result.Args, arg)
+		}
+	}
+	if len(result.Args) == 0 {
+		result.Args = nil
+	}
+	return &result
+}
+
+// mergeStringMaps returns the keys of base and override, override wins on conflicts
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return base
+	}
+	result := map[string]string{}
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range override {
+		result[k] = v
+	}
+	return result
+}
+
+// mergeUniqueStrings appends the values of override that are not already in base
+func mergeUniqueStrings(base, override []string) []string {
+	if len(base) == 0 && len(override) == 0 {
+		return base
+	}
+	result := []string{}
+	seen := map[string]bool{}
+	for _, value := range append(append([]string{}, base...), override...) {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	return result
+}
+
+func mergeCapabilities(base, override []apiv1.Capability) []apiv1.Capability {
+	if len(base) == 0 && len(override) == 0 {
+		return base
+	}
+	result := []apiv1.Capability{}
+	seen := map[apiv1.Capability]bool{}
+	for _, value := range append(append([]apiv1.Capability{}, base...), override...) {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	return result
+}
+
+func mergeEnvironment(base, override Environment) Environment {
+	if len(base) == 0 && len(override) == 0 {
+		return base
+	}
+	result := Environment{}
+	for _, env := range base {
+		result = append(result, env)
+	}
+	for _, env := range override {
+		found := false
+		for i := range result {
+			if result[i].Name == env.Name {
+				result[i] = env
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, env)
+		}
+	}
+	return result
+}
+
+// mergePorts appends the ports of override that are not already defined in base
+func mergePorts(base, override []Port) []Port {
+	if len(base) == 0 && len(override) == 0 {
+		return base
+	}
+	result := append([]Port{}, base...)
+	for _, p := range override {
+		if !IsAlreadyAdded(p, result) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// mergeVolumes merges the volumes by their remote path: a volume of override replaces the volume of base mounted in the same path,
+// even if one of them is a named volume and the other one a bind mount
+func mergeVolumes(base, override *Service) ([]StackVolume, []StackVolume) {
+	overridden := map[string]bool{}
+	for _, v := range override.Volumes {
+		overridden[v.RemotePath] = true
+	}
+	for _, v := range override.VolumeMounts {
+		overridden[v.RemotePath] = true
+	}
+
+	merge := func(baseVolumes, overrideVolumes []StackVolume) []StackVolume {
+		if len(baseVolumes) == 0 && len(overrideVolumes) == 0 {
+			return baseVolumes
+		}
+		result := []StackVolume{}
+		for _, v := range baseVolumes {
+			if !overridden[v.RemotePath] {
+				result = append(result, v)
+			}
+		}
+		return append(result, overrideVolumes...)
+	}
+	return merge(base.Volumes, override.Volumes), merge(base.VolumeMounts, override.VolumeMounts)
+}
+
+// mergeServiceConfigs appends the configs or secrets of override, replacing the ones of base mounted in the same target
+func mergeServiceConfigs(base, override []ServiceConfig) []ServiceConfig {
+	if len(base) == 0 && len(override) == 0 {
+		return base
+	}
+	overridden := map[string]bool{}
+	for _, config := range override {
+		overridden[config.Target] = true
+	}
+	result := []ServiceConfig{}
+	for _, config := range base {
+		if !overridden[config.Target] {
+			result = append(result, config)
+		}
+	}
+	return append(result, override...)
+}
+
+// resolveExtends replaces the services with an 'extends' field by the result of merging them on top of the service they extend
+func (s *Stack) resolveExtends(extends map[string]*ExtendsRaw) error {
+	dir, err := s.getDir()
+	if err != nil {
+		return err
+	}
+	resolved := map[string]bool{}
+	var resolve func(name string, visiting map[string]bool) error
+	resolve = func(name string, visiting map[string]bool) error {
+		ext, ok := extends[name]
+		if !ok || resolved[name] {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("service '%s' has a circular 'extends' reference", name)
+		}
+		visiting[name] = true
+
+		var base *Service
+		if ext.File == "" {
+			baseName := sanitizeName(ext.Service)
+			if err := resolve(baseName, visiting); err != nil {
+				return err
+			}
+			base, ok = s.Services[baseName]
+			if !ok {
+				return fmt.Errorf("service '%s' extends service '%s', which is not defined", name, ext.Service)
+			}
+		} else {
+			var err error
+			base, err = s.getExtendedService(name, ext, dir)
+			if err != nil {
+				return err
+			}
+		}
+
+		// depends_on is never inherited from the extended service
+		extended := *base
+		extended.DependsOn = nil
+		s.Services[name] = mergeService(&extended, s.Services[name], s.explicitFields[name])
+		resolved[name] = true
+		return nil
+	}
+
+	for name := range extends {
+		if err := resolve(name, map[string]bool{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getExtendedService reads a service from another compose file, resolving the file from dir.
+// The relative paths of the service are resolved from the folder of that file
+func (s *Stack) getExtendedService(name string, ext *ExtendsRaw, dir string) (*Service, error) {
+	file := filepath.Clean(loadAbsPath(dir, ext.File))
+	key := fmt.Sprintf("%s:%s", file, sanitizeName(ext.Service))
+	if s.extendsChain[key] {
+		return nil, fmt.Errorf("service '%s' has a circular 'extends' reference to service '%s' of '%s'", name, ext.Service, ext.File)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("service '%s' extends from '%s': %w", name, ext.File, err)
+	}
+
+	// the services extended by the other file can't extend any of the services being extended
+	chain := map[string]bool{key: true}
+	for k := range s.extendsChain {
+		chain[k] = true
+	}
+	extendedDir := filepath.Dir(file)
+	other, err := readStack(b, s.IsCompose, extendedDir, chain)
+	if err != nil {
+		err = locateInterpolationError(err, ext.File, b)
+		return nil, fmt.Errorf("service '%s' extends from '%s': %w", name, ext.File, err)
+	}
+	svc, ok := other.Services[sanitizeName(ext.Service)]
+	if !ok {
+		return nil, fmt.Errorf("service '%s' extends service '%s', which is not defined in '%s'", name, ext.Service, ext.File)
+	}
+
+	if svc.Build != nil {
+		if _, err := url.ParseRequestURI(svc.Build.Context); err != nil {
+			svc.Build.Context = loadAbsPath(extendedDir, svc.Build.Context)
+		}
+	}
+	for i := range svc.EnvFiles {
+		svc.EnvFiles[i] = loadAbsPath(extendedDir, svc.EnvFiles[i])
+	}
+	return svc, nil
+}
+
+// getDir returns the absolute path of the folder relative paths of the stack are resolved from
+func (s *Stack) getDir() (string, error) {
+	if s.dir != "" {
+		return filepath.Abs(s.dir)
+	}
+	return os.Getwd()
+}
+
+// getActiveProfiles returns the profiles enabled by $COMPOSE_PROFILES
+func getActiveProfiles() []string {
+	profiles := []string{}
+	for _, profile := range strings.Split(os.Getenv(ComposeProfilesEnvVar), ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// applyProfiles removes the services assigned to profiles that are not active. Services without profiles are always enabled
+func (s *Stack) applyProfiles(profiles []string) {
+	if s == nil {
+		return
+	}
+	active := map[string]bool{}
+	for _, profile := range profiles {
+		active[profile] = true
+	}
+	if active["*"] {
+		return
+	}
+	for name, svc := range s.Services {
+		if len(svc.Profiles) == 0 {
+			continue
+		}
+		enabled := false
+		for _, profile := range svc.Profiles {
+			if active[profile] {
+				enabled = true
+				break
+			}
+		}
+		if !enabled {
+			oktetoLog.Infof("service '%s' is disabled, none of its profiles [%s] is active", name, strings.Join(svc.Profiles, ", "))
+			delete(s.Services, name)
+		}
+	}
+}
+
+// setDefaultNetwork attaches the services without networks to the 'default' network, as docker compose does.
+// Stacks that don't use networks are left untouched, all their services can reach each other
+func (s *Stack) setDefaultNetwork() {
+	if s == nil {
+		return
+	}
+	usesNetworks := len(s.Networks) > 0
+	for _, svc := range s.Services {
+		if len(svc.Networks) > 0 {
+			usesNetworks = true
+		}
+	}
+	if !usesNetworks {
+		return
+	}
+	for _, svc := range s.Services {
+		if len(svc.Networks) > 0 {
+			continue
+		}
+		svc.Networks = map[string]*ServiceNetwork{defaultNetworkName: {}}
+		if s.Networks == nil {
+			s.Networks = map[string]*StackNetwork{}
+		}
+		if _, ok := s.Networks[defaultNetworkName]; !ok {
+			s.Networks[defaultNetworkName] = &StackNetwork{}
+		}
+	}
+}
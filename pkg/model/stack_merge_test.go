@@ -0,0 +1,449 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yaml "gopkg.in/yaml.v2"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func Test_ExtendsRawUnmarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest []byte
+		expected ExtendsRaw
+	}{
+		{
+			name:     "short syntax",
+			manifest: []byte("base"),
+			expected: ExtendsRaw{Service: "base"},
+		},
+		{
+			name:     "service",
+			manifest: []byte("service: base"),
+			expected: ExtendsRaw{Service: "base"},
+		},
+		{
+			name:     "service and file",
+			manifest: []byte("file: common.yml\nservice: base"),
+			expected: ExtendsRaw{File: "common.yml", Service: "base"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result ExtendsRaw
+			if err := yaml.Unmarshal(tt.manifest, &result); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_ExtendsSameFile(t *testing.T) {
+	manifest := []byte(`services:
+  base:
+    image: okteto/base
+    command: ["run"]
+    environment:
+      A: base
+      B: base
+    labels:
+      tier: backend
+  db:
+    image: postgres
+  api:
+    extends: base
+    depends_on:
+      - db
+    environment:
+      B: api
+  worker:
+    extends:
+      service: api
+    command: ["work"]
+`)
+	s, err := ReadStack(manifest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := s.Services["api"]
+	assert.Equal(t, "okteto/base", api.Image)
+	assert.Equal(t, []string{"run"}, api.Command.Values)
+	assert.Equal(t, Environment{{Name: "A", Value: "base"}, {Name: "B", Value: "api"}}, sortedEnvironment(api.Environment))
+	assert.Equal(t, "backend", api.Annotations["tier"])
+	assert.Contains(t, api.DependsOn, "db")
+
+	worker := s.Services["worker"]
+	assert.Equal(t, "okteto/base", worker.Image)
+	assert.Equal(t, []string{"work"}, worker.Command.Values)
+	assert.Equal(t, Environment{{Name: "A", Value: "base"}, {Name: "B", Value: "api"}}, sortedEnvironment(worker.Environment))
+	assert.Empty(t, worker.DependsOn)
+}
+
+func Test_ExtendsOtherFile(t *testing.T) {
+	dir := t.TempDir()
+	common := []byte(`services:
+  base:
+    image: okteto/base
+    env_file: .env
+    volumes:
+      - ./src:/app
+`)
+	if err := os.MkdirAll(filepath.Join(dir, "common"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "common", "common.yml"), common, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "common", ".env"), []byte("A=1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := []byte(`services:
+  api:
+    extends:
+      file: common/common.yml
+      service: base
+    ports:
+      - 8080
+`)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := ReadStack(manifest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := s.Services["api"]
+	assert.Equal(t, "okteto/base", api.Image)
+	assert.Len(t, api.Ports, 1)
+	commonDir, err := filepath.EvalSymlinks(filepath.Join(dir, "common"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, EnvFiles{filepath.Join(commonDir, ".env")}, api.EnvFiles)
+	assert.Len(t, api.VolumeMounts, 1)
+	assert.Equal(t, filepath.Join(commonDir, "src"), api.VolumeMounts[0].LocalPath)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cwd, err = filepath.EvalSymlinks(cwd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, filepath.Dir(commonDir), cwd)
+}
+
+func Test_ExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest []byte
+	}{
+		{
+			name: "undefined service",
+			manifest: []byte(`services:
+  api:
+    extends: base
+    image: okteto/api
+`),
+		},
+		{
+			name: "circular reference",
+			manifest: []byte(`services:
+  api:
+    extends: worker
+    image: okteto/api
+  worker:
+    extends: api
+    image: okteto/worker
+`),
+		},
+		{
+			name: "file not found",
+			manifest: []byte(`services:
+  api:
+    extends:
+      file: not-found.yml
+      service: base
+`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadStack(tt.manifest, true)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_ExtendsOtherFileFromDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "common", "src"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	common := []byte(`services:
+  base:
+    image: okteto/base
+    volumes:
+      - ./src:/app
+`)
+	if err := os.WriteFile(filepath.Join(dir, "common", "common.yml"), common, 0600); err != nil {
+		t.Fatal(err)
+	}
+	manifest := []byte(`services:
+  api:
+    extends:
+      file: common/common.yml
+      service: base
+`)
+
+	// the extended file is resolved from the folder of the stack, not from the current folder
+	s, err := readStack(manifest, true, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := s.Services["api"]
+	assert.Equal(t, "okteto/base", api.Image)
+	assert.Len(t, api.VolumeMounts, 1)
+	assert.Equal(t, filepath.Join(dir, "common", "src"), api.VolumeMounts[0].LocalPath)
+}
+
+func Test_ExtendsCircularReferenceBetweenFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := []byte(`services:
+  api:
+    extends:
+      file: b.yml
+      service: worker
+`)
+	b := []byte(`services:
+  worker:
+    extends:
+      file: a.yml
+      service: api
+`)
+	if err := os.WriteFile(filepath.Join(dir, "a.yml"), a, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.yml"), b, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := readStack(a, true, dir, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "circular 'extends' reference")
+}
+
+func Test_ExtendsExplicitDefaultValues(t *testing.T) {
+	manifest := []byte(`services:
+  base:
+    image: okteto/base
+    init: true
+    privileged: true
+    read_only: true
+    stdin_open: true
+    tty: true
+    restart: "no"
+    deploy:
+      replicas: 3
+  api:
+    extends: base
+    init: false
+    privileged: false
+    read_only: false
+    stdin_open: false
+    tty: false
+    restart: always
+    deploy:
+      replicas: 1
+  worker:
+    extends: base
+`)
+	s, err := ReadStack(manifest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := s.Services["api"]
+	assert.False(t, api.Init)
+	assert.False(t, api.Privileged)
+	assert.False(t, api.ReadOnly)
+	assert.False(t, api.StdinOpen)
+	assert.False(t, api.Tty)
+	assert.Equal(t, apiv1.RestartPolicyAlways, api.RestartPolicy)
+	assert.Equal(t, int32(1), api.Replicas)
+
+	worker := s.Services["worker"]
+	assert.True(t, worker.Init)
+	assert.True(t, worker.Privileged)
+	assert.True(t, worker.ReadOnly)
+	assert.True(t, worker.StdinOpen)
+	assert.True(t, worker.Tty)
+	assert.Equal(t, apiv1.RestartPolicyNever, worker.RestartPolicy)
+	assert.Equal(t, int32(3), worker.Replicas)
+}
+
+func Test_applyProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []string
+		expected []string
+	}{
+		{
+			name:     "no active profiles",
+			profiles: []string{},
+			expected: []string{"api"},
+		},
+		{
+			name:     "one active profile",
+			profiles: []string{"debug"},
+			expected: []string{"api", "debugger"},
+		},
+		{
+			name:     "several active profiles",
+			profiles: []string{"debug", "test"},
+			expected: []string{"api", "debugger", "e2e"},
+		},
+		{
+			name:     "all profiles",
+			profiles: []string{"*"},
+			expected: []string{"api", "debugger", "e2e"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Stack{
+				Services: map[string]*Service{
+					"api":      {},
+					"debugger": {Profiles: []string{"debug"}},
+					"e2e":      {Profiles: []string{"test", "ci"}},
+				},
+			}
+			s.applyProfiles(tt.profiles)
+			result := []string{}
+			for name := range s.Services {
+				result = append(result, name)
+			}
+			sort.Strings(result)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_getActiveProfiles(t *testing.T) {
+	t.Setenv(ComposeProfilesEnvVar, "debug, test,,")
+	assert.Equal(t, []string{"debug", "test"}, getActiveProfiles())
+}
+
+func Test_getOverrideFile(t *testing.T) {
+	dir := t.TempDir()
+	stackPath := filepath.Join(dir, "docker-compose.yml")
+	if err := os.WriteFile(stackPath, []byte("services:\n  api:\n    image: okteto/api\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	s, err := getOverrideFile(stackPath)
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+
+	overridePath := filepath.Join(dir, "docker-compose.override.yml")
+	if err := os.WriteFile(overridePath, []byte("services:\n  api:\n    image: okteto/api:dev\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s, err = getOverrideFile(stackPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "okteto/api:dev", s.Services["api"].Image)
+
+	if err := os.WriteFile(overridePath, []byte("services:\n  api:\n    unknown: field\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = getOverrideFile(stackPath)
+	assert.Error(t, err)
+}
+
+func Test_mergeServiceBuild(t *testing.T) {
+	base := &Service{
+		Build: &BuildInfo{
+			Context:   "api",
+			CacheFrom: []string{"okteto/api:cache"},
+			Args:      BuildArgs{{Name: "A", Value: "1"}, {Name: "B", Value: "1"}},
+		},
+	}
+	override := &Service{
+		Build: &BuildInfo{
+			Target:    "dev",
+			CacheFrom: []string{"okteto/api:dev-cache"},
+			Args:      BuildArgs{{Name: "B", Value: "2"}, {Name: "C", Value: "2"}},
+		},
+	}
+	expected := &BuildInfo{
+		Context:   "api",
+		Target:    "dev",
+		CacheFrom: []string{"okteto/api:cache", "okteto/api:dev-cache"},
+		Args:      BuildArgs{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}, {Name: "C", Value: "2"}},
+	}
+	result := mergeService(base, override, nil)
+	if !reflect.DeepEqual(expected, result.Build) {
+		t.Fatalf("expected %+v but got %+v", expected, result.Build)
+	}
+	assert.Equal(t, "1", base.Build.Args[1].Value)
+}
+
+func Test_setDefaultNetwork(t *testing.T) {
+	s := &Stack{
+		Services: map[string]*Service{
+			"api": {},
+			"db":  {},
+		},
+	}
+	s.setDefaultNetwork()
+	assert.Empty(t, s.Networks)
+	assert.Empty(t, s.Services["api"].Networks)
+
+	s.Networks = map[string]*StackNetwork{"back": {Internal: true}}
+	s.Services["db"].Networks = map[string]*ServiceNetwork{"back": {}}
+	s.setDefaultNetwork()
+	assert.Equal(t, map[string]*StackNetwork{"back": {Internal: true}, "default": {}}, s.Networks)
+	assert.Equal(t, map[string]*ServiceNetwork{"default": {}}, s.Services["api"].Networks)
+	assert.Equal(t, map[string]*ServiceNetwork{"back": {}}, s.Services["db"].Networks)
+}
+
+func sortedEnvironment(env Environment) Environment {
+	result := append(Environment{}, env...)
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -29,6 +30,8 @@ import (
 	"github.com/okteto/okteto/pkg/model/forward"
 	apiv1 "k8s.io/api/core/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -37,23 +40,20 @@ const (
 
 // StackRaw represents an okteto stack
 type StackRaw struct {
-	Version   string                     `yaml:"version,omitempty"`
-	Name      string                     `yaml:"name"`
-	Namespace string                     `yaml:"namespace,omitempty"`
-	Context   string                     `yaml:"context,omitempty"`
-	Services  map[string]*ServiceRaw     `yaml:"services,omitempty"`
-	Endpoints EndpointSpec               `yaml:"endpoints,omitempty"`
-	Volumes   map[string]*VolumeTopLevel `yaml:"volumes,omitempty"`
+	Version   string                      `yaml:"version,omitempty"`
+	Name      string                      `yaml:"name"`
+	Namespace string                      `yaml:"namespace,omitempty"`
+	Context   string                      `yaml:"context,omitempty"`
+	Services  map[string]*ServiceRaw      `yaml:"services,omitempty"`
+	Endpoints EndpointSpec                `yaml:"endpoints,omitempty"`
+	Volumes   map[string]*VolumeTopLevel  `yaml:"volumes,omitempty"`
+	Configs   map[string]*StackConfig     `yaml:"configs,omitempty"`
+	Secrets   map[string]*StackConfig     `yaml:"secrets,omitempty"`
+	Networks  map[string]*NetworkTopLevel `yaml:"networks,omitempty"`
 
 	// Extensions
 	Extensions map[string]interface{} `yaml:",inline" json:"-"`
 
-	// Docker-compose not implemented
-	Networks *WarningType `yaml:"networks,omitempty"`
-
-	Configs *WarningType `yaml:"configs,omitempty"`
-	Secrets *WarningType `yaml:"secrets,omitempty"`
-
 	Warnings StackWarnings
 }
 
@@ -90,6 +90,25 @@ type ServiceRaw struct {
 	WorkingDirSneakCase      string                `yaml:"working_dir,omitempty"`
 	Workdir                  string                `yaml:"workdir,omitempty"`
 	DependsOn                DependsOn             `yaml:"depends_on,omitempty"`
+	Extends                  *ExtendsRaw           `yaml:"extends,omitempty"`
+	Profiles                 []string              `yaml:"profiles,omitempty"`
+	Configs                  []ServiceConfig       `yaml:"configs,omitempty"`
+	Secrets                  []ServiceConfig       `yaml:"secrets,omitempty"`
+	Networks                 ServiceNetworksRaw    `yaml:"networks,omitempty"`
+	DNS                      StringList            `yaml:"dns,omitempty"`
+	DNSOpt                   []string              `yaml:"dns_opt,omitempty"`
+	DNSSearch                StringList            `yaml:"dns_search,omitempty"`
+	DomainName               string                `yaml:"domainname,omitempty"`
+	ExtraHosts               ExtraHosts            `yaml:"extra_hosts,omitempty"`
+	Hostname                 string                `yaml:"hostname,omitempty"`
+	Init                     *bool                 `yaml:"init,omitempty"`
+	Privileged               *bool                 `yaml:"privileged,omitempty"`
+	ReadOnly                 *bool                 `yaml:"read_only,omitempty"`
+	SecurityOpt              []string              `yaml:"security_opt,omitempty"`
+	StdinOpen                *bool                 `yaml:"stdin_open,omitempty"`
+	Sysctls                  Sysctls               `yaml:"sysctls,omitempty"`
+	Tty                      *bool                 `yaml:"tty,omitempty"`
+	Schedule                 *ServiceScheduleRaw   `yaml:"x-okteto-schedule,omitempty"`
 
 	Public    bool            `yaml:"public,omitempty"`
 	Replicas  *int32          `yaml:"replicas"`
@@ -104,27 +123,17 @@ type ServiceRaw struct {
 	CpuRtPeriod       *WarningType `yaml:"cpu_rt_period,omitempty"`
 	Cpuset            *WarningType `yaml:"cpuset,omitempty"`
 	CgroupParent      *WarningType `yaml:"cgroup_parent,omitempty"`
-	Configs           *WarningType `yaml:"configs,omitempty"`
 	ContainerName     *WarningType `yaml:"container_name,omitempty"`
 	CredentialSpec    *WarningType `yaml:"credential_spec,omitempty"`
 	DeviceCgroupRules *WarningType `yaml:"device_cgroup_rules,omitempty"`
 	Devices           *WarningType `yaml:"devices,omitempty"`
-	Dns               *WarningType `yaml:"dns,omitempty"`
-	DnsOpt            *WarningType `yaml:"dns_opt,omitempty"`
-	DnsSearch         *WarningType `yaml:"dns_search,omitempty"`
-	DomainName        *WarningType `yaml:"domainname,omitempty"`
-	Extends           *WarningType `yaml:"extends,omitempty"`
 	ExternalLinks     *WarningType `yaml:"external_links,omitempty"`
-	ExtraHosts        *WarningType `yaml:"extra_hosts,omitempty"`
 	GroupAdd          *WarningType `yaml:"group_add,omitempty"`
-	Hostname          *WarningType `yaml:"hostname,omitempty"`
-	Init              *WarningType `yaml:"init,omitempty"`
 	Ipc               *WarningType `yaml:"ipc,omitempty"`
 	Isolation         *WarningType `yaml:"isolation,omitempty"`
 	Links             *WarningType `yaml:"links,omitempty"`
 	Logging           *WarningType `yaml:"logging,omitempty"`
 	Network_mode      *WarningType `yaml:"network_mode,omitempty"`
-	Networks          *WarningType `yaml:"networks,omitempty"`
 	MacAddress        *WarningType `yaml:"mac_address,omitempty"`
 	MemSwappiness     *WarningType `yaml:"mem_swappiness,omitempty"`
 	MemswapLimit      *WarningType `yaml:"memswap_limit,omitempty"`
@@ -133,20 +142,12 @@ type ServiceRaw struct {
 	Pid               *WarningType `yaml:"pid,omitempty"`
 	PidLimit          *WarningType `yaml:"pid_limit,omitempty"`
 	Platform          *WarningType `yaml:"platform,omitempty"`
-	Privileged        *WarningType `yaml:"privileged,omitempty"`
-	Profiles          *WarningType `yaml:"profiles,omitempty"`
 	PullPolicy        *WarningType `yaml:"pull_policy,omitempty"`
-	ReadOnly          *WarningType `yaml:"read_only,omitempty"`
 	Runtime           *WarningType `yaml:"runtime,omitempty"`
-	Secrets           *WarningType `yaml:"secrets,omitempty"`
-	SecurityOpt       *WarningType `yaml:"security_opt,omitempty"`
 	ShmSize           *WarningType `yaml:"shm_size,omitempty"`
-	StdinOpen         *WarningType `yaml:"stdin_open,omitempty"`
 	StopSignal        *WarningType `yaml:"stop_signal,omitempty"`
 	StorageOpts       *WarningType `yaml:"storage_opts,omitempty"`
-	Sysctls           *WarningType `yaml:"sysctls,omitempty"`
 	Tmpfs             *WarningType `yaml:"tmpfs,omitempty"`
-	Tty               *WarningType `yaml:"tty,omitempty"`
 	Ulimits           *WarningType `yaml:"ulimits,omitempty"`
 	UsernsMode        *WarningType `yaml:"userns_mode,omitempty"`
 	VolumesFrom       *WarningType `yaml:"volumes_from,omitempty"`
@@ -155,19 +156,29 @@ type ServiceRaw struct {
 	Extensions map[string]interface{} `yaml:",inline" json:"-"`
 }
 
+// ExtendsRaw represents the service a compose service extends from
+type ExtendsRaw struct {
+	File    string `yaml:"file,omitempty"`
+	Service string `yaml:"service"`
+}
+
 type DeployInfoRaw struct {
 	Replicas      *int32            `yaml:"replicas,omitempty"`
 	Resources     ResourcesRaw      `yaml:"resources,omitempty"`
 	Labels        Labels            `yaml:"labels,omitempty"`
 	RestartPolicy *RestartPolicyRaw `yaml:"restart_policy,omitempty"`
 
-	EndpointMode   *WarningType `yaml:"endpoint_mode,omitempty"`
-	Mode           *WarningType `yaml:"mode,omitempty"`
-	Placement      *WarningType `yaml:"placement,omitempty"`
-	Constraints    *WarningType `yaml:"constraints,omitempty"`
-	Preferences    *WarningType `yaml:"preferences,omitempty"`
-	RollbackConfig *WarningType `yaml:"rollback_config,omitempty"`
-	UpdateConfig   *WarningType `yaml:"update_config,omitempty"`
+	Placement      *PlacementRaw    `yaml:"placement,omitempty"`
+	RollbackConfig *UpdateConfigRaw `yaml:"rollback_config,omitempty"`
+	UpdateConfig   *UpdateConfigRaw `yaml:"update_config,omitempty"`
+
+	Autoscaling      *AutoscalingRaw      `yaml:"x-okteto-autoscaling,omitempty"`
+	DisruptionBudget *DisruptionBudgetRaw `yaml:"x-okteto-disruption-budget,omitempty"`
+
+	EndpointMode *WarningType `yaml:"endpoint_mode,omitempty"`
+	Mode         *WarningType `yaml:"mode,omitempty"`
+	Constraints  *WarningType `yaml:"constraints,omitempty"`
+	Preferences  *WarningType `yaml:"preferences,omitempty"`
 
 	// Extensions
 	Extensions map[string]interface{} `yaml:",inline" json:"-"`
@@ -184,6 +195,55 @@ type RestartPolicyRaw struct {
 	Extensions map[string]interface{} `yaml:",inline" json:"-"`
 }
 
+// AutoscalingRaw represents the x-okteto-autoscaling extension of the deploy section of a compose service
+type AutoscalingRaw struct {
+	MinReplicas *int32 `yaml:"min_replicas,omitempty"`
+	MaxReplicas int32  `yaml:"max_replicas"`
+	CPU         int32  `yaml:"cpu,omitempty"`
+	Memory      int32  `yaml:"memory,omitempty"`
+}
+
+// DisruptionBudgetRaw represents the x-okteto-disruption-budget extension of the deploy section of a compose service.
+// Its values can be a number of pods or a percentage
+type DisruptionBudgetRaw struct {
+	MinAvailable   string `yaml:"min_available,omitempty"`
+	MaxUnavailable string `yaml:"max_unavailable,omitempty"`
+}
+
+// ServiceScheduleRaw represents the x-okteto-schedule extension of a compose service.
+// It can be a cron expression or a mapping with the cronjob settings
+type ServiceScheduleRaw struct {
+	Cron                       string `yaml:"cron"`
+	ConcurrencyPolicy          string `yaml:"concurrency_policy,omitempty"`
+	SuccessfulJobsHistoryLimit *int32 `yaml:"successful_jobs_history_limit,omitempty"`
+	FailedJobsHistoryLimit     *int32 `yaml:"failed_jobs_history_limit,omitempty"`
+	Suspend                    bool   `yaml:"suspend,omitempty"`
+}
+
+// UpdateConfigRaw represents the update_config or rollback_config of a compose service
+type UpdateConfigRaw struct {
+	Parallelism   *int32      `yaml:"parallelism,omitempty"`
+	Delay         *RawMessage `yaml:"delay,omitempty"`
+	FailureAction string      `yaml:"failure_action,omitempty"`
+	Order         string      `yaml:"order,omitempty"`
+
+	Monitor         *WarningType `yaml:"monitor,omitempty"`
+	MaxFailureRatio *WarningType `yaml:"max_failure_ratio,omitempty"`
+}
+
+// PlacementRaw represents the placement of a compose service
+type PlacementRaw struct {
+	Constraints []string                 `yaml:"constraints,omitempty"`
+	Preferences []PlacementPreferenceRaw `yaml:"preferences,omitempty"`
+
+	MaxReplicasPerNode *WarningType `yaml:"max_replicas_per_node,omitempty"`
+}
+
+// PlacementPreferenceRaw represents a placement preference of a compose service
+type PlacementPreferenceRaw struct {
+	Spread string `yaml:"spread"`
+}
+
 type PortRaw struct {
 	ContainerPort int32
 	HostPort      int32
@@ -218,6 +278,33 @@ type DeployComposeResources struct {
 	Extensions map[string]interface{} `yaml:",inline" json:"-"`
 }
 
+// NetworkTopLevel represents a top-level network of a compose file
+type NetworkTopLevel struct {
+	Internal bool `yaml:"internal,omitempty"`
+
+	Attachable *WarningType `yaml:"attachable,omitempty"`
+	Driver     *WarningType `yaml:"driver,omitempty"`
+	DriverOpts *WarningType `yaml:"driver_opts,omitempty"`
+	EnableIPv6 *WarningType `yaml:"enable_ipv6,omitempty"`
+	External   *WarningType `yaml:"external,omitempty"`
+	Ipam       *WarningType `yaml:"ipam,omitempty"`
+	Labels     *WarningType `yaml:"labels,omitempty"`
+	Name       *WarningType `yaml:"name,omitempty"`
+}
+
+// ServiceNetworksRaw represents the networks a service is attached to, either as a list or as a map
+type ServiceNetworksRaw map[string]*ServiceNetworkRaw
+
+// ServiceNetworkRaw represents the attachment of a service to a network
+type ServiceNetworkRaw struct {
+	Aliases []string `yaml:"aliases,omitempty"`
+
+	Ipv4Address  *WarningType `yaml:"ipv4_address,omitempty"`
+	Ipv6Address  *WarningType `yaml:"ipv6_address,omitempty"`
+	LinkLocalIPs *WarningType `yaml:"link_local_ips,omitempty"`
+	Priority     *WarningType `yaml:"priority,omitempty"`
+}
+
 type VolumeTopLevel struct {
 	Labels      Labels            `json:"labels,omitempty" yaml:"labels,omitempty"`
 	Annotations Annotations       `json:"annotations,omitempty" yaml:"annotations,omitempty"`
@@ -283,6 +370,202 @@ func (c *composeBuildInfo) UnmarshalYAML(unmarshal func(interface{}) error) erro
 	return nil
 }
 
+// UnmarshalYAML allows the short syntax of configs and secrets: '- source'
+func (c *ServiceConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var rawString string
+	if err := unmarshal(&rawString); err == nil {
+		c.Source = rawString
+		return nil
+	}
+
+	type serviceConfig ServiceConfig // prevent recursion
+	var raw serviceConfig
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*c = ServiceConfig(raw)
+	return nil
+}
+
+// UnmarshalYAML allows the short syntax 'networks: [front, back]'
+func (n *ServiceNetworksRaw) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []string
+	if err := unmarshal(&list); err == nil {
+		*n = make(ServiceNetworksRaw)
+		for _, name := range list {
+			(*n)[name] = nil
+		}
+		return nil
+	}
+
+	var raw map[string]*ServiceNetworkRaw
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*n = raw
+	return nil
+}
+
+func (n ServiceNetworksRaw) toServiceNetworks() map[string]*ServiceNetwork {
+	if len(n) == 0 {
+		return nil
+	}
+	result := make(map[string]*ServiceNetwork)
+	for name, network := range n {
+		serviceNetwork := &ServiceNetwork{}
+		if network != nil {
+			for _, alias := range network.Aliases {
+				serviceNetwork.Aliases = append(serviceNetwork.Aliases, sanitizeName(alias))
+			}
+		}
+		result[sanitizeName(name)] = serviceNetwork
+	}
+	return result
+}
+
+// UnmarshalYAML accepts a single string or a list of strings
+func (l *StringList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*l = StringList{single}
+		return nil
+	}
+
+	var multi []string
+	if err := unmarshal(&multi); err != nil {
+		return err
+	}
+	*l = multi
+	return nil
+}
+
+// UnmarshalYAML accepts a list of 'host:ip' or 'host=ip' values or a map of hosts to ips
+func (e *ExtraHosts) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	result := make(ExtraHosts)
+	var rawList []string
+	if err := unmarshal(&rawList); err == nil {
+		for _, value := range rawList {
+			separator := ":"
+			if strings.Contains(value, "=") {
+				separator = "="
+			}
+			parts := strings.SplitN(value, separator, 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("invalid extra host '%s': must be 'host:ip'", value)
+			}
+			result[parts[0]] = parts[1]
+		}
+		*e = result
+		return nil
+	}
+
+	var rawMap map[string]string
+	if err := unmarshal(&rawMap); err != nil {
+		return err
+	}
+	for host, ip := range rawMap {
+		result[host] = ip
+	}
+	*e = result
+	return nil
+}
+
+// UnmarshalYAML accepts a list of 'name=value' values or a map
+func (s *Sysctls) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	result, err := getKeyValue(unmarshal)
+	if err != nil {
+		return err
+	}
+	*s = result
+	return nil
+}
+
+// isValidHostname returns if a compose hostname or domainname is a valid kubernetes hostname or subdomain
+func isValidHostname(name string) bool {
+	return name != "" && len(validation.IsDNS1123Label(name)) == 0
+}
+
+// ParseSecurityOpts returns the security_opt values of a service that have a kubernetes equivalent,
+// and the ones that can't be translated
+func ParseSecurityOpts(opts []string) (*StackSecurityOpts, []string) {
+	result := &StackSecurityOpts{}
+	unsupported := []string{}
+	for _, opt := range opts {
+		key, value := opt, ""
+		if i := strings.IndexAny(opt, ":="); i != -1 {
+			key, value = opt[:i], opt[i+1:]
+		}
+		switch key {
+		case "no-new-privileges":
+			if value == "" || value == "true" {
+				result.NoNewPrivileges = true
+				continue
+			}
+			if value == "false" {
+				continue
+			}
+		case "label":
+			if i := strings.IndexAny(value, ":="); i != -1 {
+				if result.SELinuxOptions == nil {
+					result.SELinuxOptions = &apiv1.SELinuxOptions{}
+				}
+				switch labelKey, labelValue := value[:i], value[i+1:]; labelKey {
+				case "user":
+					result.SELinuxOptions.User = labelValue
+					continue
+				case "role":
+					result.SELinuxOptions.Role = labelValue
+					continue
+				case "type":
+					result.SELinuxOptions.Type = labelValue
+					continue
+				case "level":
+					result.SELinuxOptions.Level = labelValue
+					continue
+				}
+			}
+		case "apparmor":
+			switch value {
+			case "":
+			case "unconfined":
+				result.AppArmorProfile = "unconfined"
+				continue
+			case "docker-default", "runtime/default":
+				result.AppArmorProfile = "runtime/default"
+				continue
+			default:
+				result.AppArmorProfile = fmt.Sprintf("localhost/%s", value)
+				continue
+			}
+		case "seccomp":
+			// seccomp profiles from local files can't be loaded in the cluster nodes
+			if value == "unconfined" {
+				result.SeccompProfile = &apiv1.SeccompProfile{Type: apiv1.SeccompProfileTypeUnconfined}
+				continue
+			}
+		}
+		unsupported = append(unsupported, opt)
+	}
+	return result, unsupported
+}
+
+// UnmarshalYAML allows the short syntax 'extends: service'
+func (e *ExtendsRaw) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var rawString string
+	if err := unmarshal(&rawString); err == nil {
+		e.Service = rawString
+		return nil
+	}
+
+	type extendsRaw ExtendsRaw // prevent recursion
+	var raw extendsRaw
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*e = ExtendsRaw(raw)
+	return nil
+}
+
 func (s *Stack) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var stackRaw StackRaw
 	err := unmarshal(&stackRaw)
@@ -309,8 +592,32 @@ func (s *Stack) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		s.Volumes[sanitizeName(volumeName)] = volumeSpec
 	}
 
+	dir, err := s.getDir()
+	if err != nil {
+		return err
+	}
+	s.Configs, err = unmarshalStackConfigs(stackRaw.Configs, dir)
+	if err != nil {
+		return err
+	}
+	s.Secrets, err = unmarshalStackConfigs(stackRaw.Secrets, dir)
+	if err != nil {
+		return err
+	}
+
+	s.Networks = make(map[string]*StackNetwork)
+	for networkName, network := range stackRaw.Networks {
+		networkSpec := &StackNetwork{}
+		if network != nil {
+			networkSpec.Internal = network.Internal
+		}
+		s.Networks[sanitizeName(networkName)] = networkSpec
+	}
+
 	sanitizedServicesNames := make(map[string]string)
 	s.Services = make(map[string]*Service)
+	s.explicitFields = make(map[string]*explicitServiceFields)
+	extends := make(map[string]*ExtendsRaw)
 	for svcName, svcRaw := range stackRaw.Services {
 		if shouldBeSanitized(svcName) {
 			newName := sanitizeName(svcName)
@@ -321,6 +628,13 @@ func (s *Stack) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		if err != nil {
 			return err
 		}
+		s.explicitFields[svcName] = getExplicitServiceFields(svcRaw)
+		if svcRaw.Extends != nil {
+			extends[svcName] = svcRaw.Extends
+		}
+	}
+	if err := s.resolveExtends(extends); err != nil {
+		return err
 	}
 
 	s.Warnings.NotSupportedFields = getNotSupportedFields(&stackRaw)
@@ -329,6 +643,21 @@ func (s *Stack) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// unmarshalStackConfigs sanitizes the names of the configs or secrets and resolves their files from dir
+func unmarshalStackConfigs(configs map[string]*StackConfig, dir string) (map[string]*StackConfig, error) {
+	result := make(map[string]*StackConfig)
+	for name, config := range configs {
+		if config == nil {
+			config = &StackConfig{}
+		}
+		if config.File != "" {
+			config.File = loadAbsPath(dir, config.File)
+		}
+		result[sanitizeName(name)] = config
+	}
+	return result, nil
+}
+
 func unmarshalVolume(volume *VolumeTopLevel) (*VolumeSpec, error) {
 
 	result := &VolumeSpec{}
@@ -466,6 +795,28 @@ func (serviceRaw *ServiceRaw) ToService(svcName string, stack *Stack) (*Service,
 	for name, condition := range serviceRaw.DependsOn {
 		svc.DependsOn[sanitizeName(name)] = condition
 	}
+	svc.Profiles = serviceRaw.Profiles
+	svc.Configs = unmarshalServiceConfigs(serviceRaw.Configs, "/")
+	svc.Secrets = unmarshalServiceConfigs(serviceRaw.Secrets, "/run/secrets")
+	svc.Networks = serviceRaw.Networks.toServiceNetworks()
+
+	svc.DNS = serviceRaw.DNS
+	svc.DNSOpt = serviceRaw.DNSOpt
+	svc.DNSSearch = serviceRaw.DNSSearch
+	svc.ExtraHosts = serviceRaw.ExtraHosts
+	if isValidHostname(serviceRaw.Hostname) {
+		svc.Hostname = serviceRaw.Hostname
+	}
+	if isValidHostname(serviceRaw.DomainName) {
+		svc.DomainName = serviceRaw.DomainName
+	}
+	svc.Init = serviceRaw.Init != nil && *serviceRaw.Init
+	svc.Privileged = serviceRaw.Privileged != nil && *serviceRaw.Privileged
+	svc.ReadOnly = serviceRaw.ReadOnly != nil && *serviceRaw.ReadOnly
+	svc.SecurityOpt = serviceRaw.SecurityOpt
+	svc.StdinOpen = serviceRaw.StdinOpen != nil && *serviceRaw.StdinOpen
+	svc.Sysctls = serviceRaw.Sysctls
+	svc.Tty = serviceRaw.Tty != nil && *serviceRaw.Tty
 
 	svc.Public, svc.Ports, err = getSvcPorts(serviceRaw.Public, serviceRaw.Ports, serviceRaw.Expose)
 	if err != nil {
@@ -484,15 +835,16 @@ func (serviceRaw *ServiceRaw) ToService(svcName string, stack *Stack) (*Service,
 		}
 	}
 
+	dir, err := stack.getDir()
+	if err != nil {
+		return nil, err
+	}
 	svc.Volumes, svc.VolumeMounts = splitVolumesByType(serviceRaw.Volumes, stack)
 	for idx, volume := range svc.VolumeMounts {
-		if !isNamedVolumeDeclared(volume) {
+		if !isNamedVolumeDeclared(volume, dir) {
 			return nil, fmt.Errorf("named volume '%s' is used in service '%s' but no declaration was found in the volumes section", volume.ToString(), svcName)
 		}
-		volume.LocalPath, err = filepath.Abs(volume.LocalPath)
-		if err != nil {
-			return nil, err
-		}
+		volume.LocalPath = filepath.Clean(loadAbsPath(dir, volume.LocalPath))
 		svc.VolumeMounts[idx] = volume
 	}
 
@@ -510,9 +862,266 @@ func (serviceRaw *ServiceRaw) ToService(svcName string, stack *Stack) (*Service,
 	if serviceRaw.Deploy != nil && serviceRaw.Deploy.RestartPolicy != nil {
 		svc.BackOffLimit = serviceRaw.Deploy.RestartPolicy.MaxAttempts
 	}
+
+	if serviceRaw.Deploy != nil {
+		svc.Autoscaling, err = serviceRaw.Deploy.Autoscaling.toServiceAutoscaling(svc.Replicas)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x-okteto-autoscaling of service '%s': %w", svcName, err)
+		}
+		if svc.Autoscaling != nil && svc.Replicas < svc.Autoscaling.MinReplicas {
+			svc.Replicas = svc.Autoscaling.MinReplicas
+		}
+		svc.DisruptionBudget, err = serviceRaw.Deploy.DisruptionBudget.toServiceDisruptionBudget()
+		if err != nil {
+			return nil, fmt.Errorf("invalid x-okteto-disruption-budget of service '%s': %w", svcName, err)
+		}
+	}
+
+	svc.Schedule, err = serviceRaw.Schedule.toServiceSchedule()
+	if err != nil {
+		return nil, fmt.Errorf("invalid x-okteto-schedule of service '%s': %w", svcName, err)
+	}
+	if svc.Schedule != nil && svc.RestartPolicy == apiv1.RestartPolicyAlways {
+		// the pods of a cronjob can't be restarted always, they run until completion
+		svc.RestartPolicy = apiv1.RestartPolicyOnFailure
+	}
+
+	if serviceRaw.Deploy != nil {
+		svc.UpdateConfig, err = serviceRaw.Deploy.UpdateConfig.toUpdateConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid update_config of service '%s': %w", svcName, err)
+		}
+		svc.RollbackConfig, err = serviceRaw.Deploy.RollbackConfig.toUpdateConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid rollback_config of service '%s': %w", svcName, err)
+		}
+		svc.Placement, _ = serviceRaw.Deploy.Placement.toPlacement()
+	}
 	return svc, nil
 }
 
+// defaultAutoscalingCPU is the cpu target used by kubernetes when an autoscaler has no metrics
+const defaultAutoscalingCPU = 80
+
+func (raw *AutoscalingRaw) toServiceAutoscaling(replicas int32) (*ServiceAutoscaling, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	result := &ServiceAutoscaling{
+		MinReplicas: replicas,
+		MaxReplicas: raw.MaxReplicas,
+		CPU:         raw.CPU,
+		Memory:      raw.Memory,
+	}
+	if raw.MinReplicas != nil {
+		result.MinReplicas = *raw.MinReplicas
+	}
+	if result.MinReplicas < 1 {
+		return nil, fmt.Errorf("'min_replicas' must be greater than 0")
+	}
+	if result.MaxReplicas < result.MinReplicas {
+		return nil, fmt.Errorf("'max_replicas' must be greater or equal than 'min_replicas'")
+	}
+	if result.CPU < 0 || result.Memory < 0 {
+		return nil, fmt.Errorf("'cpu' and 'memory' must be greater than 0")
+	}
+	if result.CPU == 0 && result.Memory == 0 {
+		result.CPU = defaultAutoscalingCPU
+	}
+	return result, nil
+}
+
+func (raw *DisruptionBudgetRaw) toServiceDisruptionBudget() (*ServiceDisruptionBudget, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	if (raw.MinAvailable == "") == (raw.MaxUnavailable == "") {
+		return nil, fmt.Errorf("one of 'min_available' or 'max_unavailable' must be set")
+	}
+	result := &ServiceDisruptionBudget{}
+	var err error
+	if raw.MinAvailable != "" {
+		result.MinAvailable, err = parseIntOrPercentage(raw.MinAvailable)
+		if err != nil {
+			return nil, fmt.Errorf("'min_available' %w", err)
+		}
+	}
+	if raw.MaxUnavailable != "" {
+		result.MaxUnavailable, err = parseIntOrPercentage(raw.MaxUnavailable)
+		if err != nil {
+			return nil, fmt.Errorf("'max_unavailable' %w", err)
+		}
+	}
+	return result, nil
+}
+
+// parseIntOrPercentage parses a number of pods like '2' or a percentage like '50%'
+func parseIntOrPercentage(value string) (*intstr.IntOrString, error) {
+	value = strings.TrimSpace(value)
+	number := strings.TrimSuffix(value, "%")
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 0 || (number != value && n > 100) {
+		return nil, fmt.Errorf("must be a number of pods or a percentage: '%s'", value)
+	}
+	result := intstr.Parse(value)
+	return &result, nil
+}
+
+// UnmarshalYAML allows the short syntax 'x-okteto-schedule: "*/5 * * * *"'
+func (schedule *ServiceScheduleRaw) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var rawString string
+	if err := unmarshal(&rawString); err == nil {
+		schedule.Cron = rawString
+		return nil
+	}
+
+	type serviceScheduleRaw ServiceScheduleRaw // prevent recursion
+	var raw serviceScheduleRaw
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*schedule = ServiceScheduleRaw(raw)
+	return nil
+}
+
+func (raw *ServiceScheduleRaw) toServiceSchedule() (*ServiceSchedule, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	if _, err := ParseCronSchedule(raw.Cron); err != nil {
+		return nil, err
+	}
+	result := &ServiceSchedule{
+		Cron:                       strings.TrimSpace(raw.Cron),
+		ConcurrencyPolicy:          ScheduleConcurrencyAllow,
+		SuccessfulJobsHistoryLimit: raw.SuccessfulJobsHistoryLimit,
+		FailedJobsHistoryLimit:     raw.FailedJobsHistoryLimit,
+		Suspend:                    raw.Suspend,
+	}
+	switch strings.ToLower(raw.ConcurrencyPolicy) {
+	case "", "allow":
+	case "forbid":
+		result.ConcurrencyPolicy = ScheduleConcurrencyForbid
+	case "replace":
+		result.ConcurrencyPolicy = ScheduleConcurrencyReplace
+	default:
+		return nil, fmt.Errorf("'concurrency_policy' must be 'allow', 'forbid' or 'replace'")
+	}
+	if raw.SuccessfulJobsHistoryLimit != nil && *raw.SuccessfulJobsHistoryLimit < 0 {
+		return nil, fmt.Errorf("'successful_jobs_history_limit' must be greater or equal than 0")
+	}
+	if raw.FailedJobsHistoryLimit != nil && *raw.FailedJobsHistoryLimit < 0 {
+		return nil, fmt.Errorf("'failed_jobs_history_limit' must be greater or equal than 0")
+	}
+	return result, nil
+}
+
+func (raw *UpdateConfigRaw) toUpdateConfig() (*UpdateConfig, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	result := &UpdateConfig{
+		Order:         UpdateOrderStopFirst,
+		FailureAction: UpdateFailureActionPause,
+	}
+	if raw.Parallelism != nil {
+		if *raw.Parallelism < 0 {
+			return nil, fmt.Errorf("'parallelism' must be greater or equal than 0")
+		}
+		parallelism := *raw.Parallelism
+		result.Parallelism = &parallelism
+	}
+	delay, err := unmarshalDuration(raw.Delay)
+	if err != nil {
+		return nil, fmt.Errorf("'delay' must be a duration: %w", err)
+	}
+	result.Delay = delay
+
+	switch order := UpdateOrder(raw.Order); order {
+	case "":
+	case UpdateOrderStopFirst, UpdateOrderStartFirst:
+		result.Order = order
+	default:
+		return nil, fmt.Errorf("'order' must be '%s' or '%s'", UpdateOrderStopFirst, UpdateOrderStartFirst)
+	}
+
+	switch action := UpdateFailureAction(raw.FailureAction); action {
+	case "":
+	case UpdateFailureActionPause, UpdateFailureActionContinue, UpdateFailureActionRollback:
+		result.FailureAction = action
+	default:
+		return nil, fmt.Errorf("'failure_action' must be '%s', '%s' or '%s'", UpdateFailureActionPause, UpdateFailureActionContinue, UpdateFailureActionRollback)
+	}
+	return result, nil
+}
+
+// toPlacement translates the placement of a compose service into node labels.
+// It also returns the constraints and preferences that can't be translated
+func (raw *PlacementRaw) toPlacement() (*Placement, []string) {
+	if raw == nil {
+		return nil, nil
+	}
+	result := &Placement{}
+	unsupported := []string{}
+	for _, constraint := range raw.Constraints {
+		operator := "=="
+		if strings.Contains(constraint, "!=") {
+			operator = "!="
+		}
+		parts := strings.SplitN(constraint, operator, 2)
+		if len(parts) != 2 {
+			unsupported = append(unsupported, constraint)
+			continue
+		}
+		key, ok := getPlacementNodeLabel(strings.TrimSpace(parts[0]))
+		if !ok {
+			unsupported = append(unsupported, constraint)
+			continue
+		}
+		value := strings.TrimSpace(parts[1])
+		if key == kubernetesArchLabel {
+			value = getKubernetesArch(value)
+		}
+		result.Constraints = append(result.Constraints, PlacementConstraint{Key: key, Value: value, Negated: operator == "!="})
+	}
+	for _, preference := range raw.Preferences {
+		key, ok := getPlacementNodeLabel(preference.Spread)
+		if !ok {
+			unsupported = append(unsupported, fmt.Sprintf("spread=%s", preference.Spread))
+			continue
+		}
+		result.Spread = append(result.Spread, key)
+	}
+	return result, unsupported
+}
+
+// getPlacementNodeLabel returns the node label of a compose placement attribute
+func getPlacementNodeLabel(attribute string) (string, bool) {
+	switch attribute {
+	case "node.hostname":
+		return kubernetesHostnameLabel, true
+	case "node.platform.os":
+		return kubernetesOSLabel, true
+	case "node.platform.arch":
+		return kubernetesArchLabel, true
+	}
+	if strings.HasPrefix(attribute, "node.labels.") {
+		return strings.TrimPrefix(attribute, "node.labels."), true
+	}
+	return "", false
+}
+
+// getKubernetesArch translates the architectures reported by docker into the values of the kubernetes arch label
+func getKubernetesArch(arch string) string {
+	switch arch {
+	case "x86_64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	}
+	return arch
+}
+
 type healthCheckunmarshaller struct {
 	HTTP        *HTTPHealtcheck `yaml:"http,omitempty"`
 	Test        HealtcheckTest  `yaml:"test,omitempty"`
@@ -734,15 +1343,12 @@ func validatePort(newPort PortRaw, ports []Port) error {
 	return nil
 }
 
-func isNamedVolumeDeclared(volume StackVolume) bool {
+// isNamedVolumeDeclared checks if a volume is a bind mount of a path, relative paths are resolved from dir
+func isNamedVolumeDeclared(volume StackVolume, dir string) bool {
 	if volume.LocalPath != "" {
-		wd, err := os.Getwd()
-		if err != nil {
-			return false
-		}
 		relative := true
 		if filepath.IsAbs(volume.LocalPath) {
-			_, err = filepath.Rel(wd, volume.LocalPath)
+			_, err := filepath.Rel(dir, volume.LocalPath)
 			relative = err == nil
 		}
 
@@ -752,13 +1358,32 @@ func isNamedVolumeDeclared(volume StackVolume) bool {
 		if strings.HasPrefix(volume.LocalPath, "./") && relative {
 			return true
 		}
-		if filesystem.FileExists(volume.LocalPath) && relative {
+		if filesystem.FileExists(loadAbsPath(dir, volume.LocalPath)) && relative {
 			return true
 		}
 	}
 	return false
 }
 
+// unmarshalServiceConfigs sets the default target of the configs or secrets mounted by a service: '<targetDir>/<source>'.
+// Relative targets are also resolved from targetDir
+func unmarshalServiceConfigs(configs []ServiceConfig, targetDir string) []ServiceConfig {
+	if len(configs) == 0 {
+		return nil
+	}
+	result := make([]ServiceConfig, 0, len(configs))
+	for _, config := range configs {
+		if config.Target == "" {
+			config.Target = path.Join(targetDir, config.Source)
+		} else if !path.IsAbs(config.Target) {
+			config.Target = path.Join(targetDir, config.Target)
+		}
+		config.Source = sanitizeName(config.Source)
+		result = append(result, config)
+	}
+	return result
+}
+
 func splitVolumesByType(volumes []StackVolume, s *Stack) ([]StackVolume, []StackVolume) {
 	topLevelVolumes := make([]StackVolume, 0)
 	mountedVolumes := make([]StackVolume, 0)
@@ -855,9 +1480,15 @@ func (dependsOn *DependsOn) UnmarshalYAML(unmarshal func(interface{}) error) err
 	err := unmarshal(&d)
 	if err == nil {
 		for key, value := range d {
+			if value.Condition == "" {
+				value.Condition = DependsOnServiceRunning
+			}
 			if value.Condition != DependsOnServiceRunning && value.Condition != DependsOnServiceHealthy && value.Condition != DependsOnServiceCompleted {
 				return fmt.Errorf("'%s' is unsupported. Condition must be one of '%s', '%s' or '%s'", value.Condition, DependsOnServiceRunning, DependsOnServiceHealthy, DependsOnServiceCompleted)
 			}
+			if value.Timeout < 0 {
+				return fmt.Errorf("'x-okteto-timeout' of dependency '%s' must be a positive duration", key)
+			}
 			result[key] = value
 		}
 		*dependsOn = result
@@ -1324,14 +1955,39 @@ func getNotSupportedFields(s *StackRaw) []string {
 
 func getTopLevelNotSupportedFields(s *StackRaw) []string {
 	notSupported := make([]string, 0)
-	if s.Networks != nil {
-		notSupported = append(notSupported, "networks")
+	for name, network := range s.Networks {
+		if network != nil {
+			notSupported = append(notSupported, getNetworksNotSupportedFields(name, network)...)
+		}
+	}
+	return notSupported
+}
+
+func getNetworksNotSupportedFields(networkName string, networkInfo *NetworkTopLevel) []string {
+	notSupported := make([]string, 0)
+	if networkInfo.Driver != nil {
+		notSupported = append(notSupported, fmt.Sprintf("networks[%s].driver", networkName))
+	}
+	if networkInfo.DriverOpts != nil {
+		notSupported = append(notSupported, fmt.Sprintf("networks[%s].driver_opts", networkName))
+	}
+	if networkInfo.Attachable != nil {
+		notSupported = append(notSupported, fmt.Sprintf("networks[%s].attachable", networkName))
 	}
-	if s.Configs != nil {
-		notSupported = append(notSupported, "configs")
+	if networkInfo.EnableIPv6 != nil {
+		notSupported = append(notSupported, fmt.Sprintf("networks[%s].enable_ipv6", networkName))
 	}
-	if s.Secrets != nil {
-		notSupported = append(notSupported, "secrets")
+	if networkInfo.External != nil {
+		notSupported = append(notSupported, fmt.Sprintf("networks[%s].external", networkName))
+	}
+	if networkInfo.Ipam != nil {
+		notSupported = append(notSupported, fmt.Sprintf("networks[%s].ipam", networkName))
+	}
+	if networkInfo.Labels != nil {
+		notSupported = append(notSupported, fmt.Sprintf("networks[%s].labels", networkName))
+	}
+	if networkInfo.Name != nil {
+		notSupported = append(notSupported, fmt.Sprintf("networks[%s].name", networkName))
 	}
 	return notSupported
 }
@@ -1369,9 +2025,6 @@ func getServiceNotSupportedFields(svcName string, svcInfo *ServiceRaw) []string
 	if svcInfo.CgroupParent != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].cgroup_parent", svcName))
 	}
-	if svcInfo.Configs != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].configs", svcName))
-	}
 	if svcInfo.CredentialSpec != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].credential_spec", svcName))
 	}
@@ -1381,36 +2034,18 @@ func getServiceNotSupportedFields(svcName string, svcInfo *ServiceRaw) []string
 	if svcInfo.Devices != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].devices", svcName))
 	}
-	if svcInfo.Dns != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].dns", svcName))
-	}
-	if svcInfo.DnsOpt != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].dns_opt", svcName))
-	}
-	if svcInfo.DnsSearch != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].dns_search", svcName))
-	}
-	if svcInfo.DomainName != nil {
+	if svcInfo.DomainName != "" && !isValidHostname(svcInfo.DomainName) {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].domainname", svcName))
 	}
-	if svcInfo.Extends != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].extends", svcName))
-	}
 	if svcInfo.ExternalLinks != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].external_links", svcName))
 	}
-	if svcInfo.ExtraHosts != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].extra_hosts", svcName))
-	}
 	if svcInfo.GroupAdd != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].group_add", svcName))
 	}
-	if svcInfo.Hostname != nil {
+	if svcInfo.Hostname != "" && !isValidHostname(svcInfo.Hostname) {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].hostname", svcName))
 	}
-	if svcInfo.Init != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].init", svcName))
-	}
 	if svcInfo.Ipc != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].ipc", svcName))
 	}
@@ -1426,8 +2061,22 @@ func getServiceNotSupportedFields(svcName string, svcInfo *ServiceRaw) []string
 	if svcInfo.Network_mode != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].network_mode", svcName))
 	}
-	if svcInfo.Networks != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].networks", svcName))
+	for networkName, network := range svcInfo.Networks {
+		if network == nil {
+			continue
+		}
+		if network.Ipv4Address != nil {
+			notSupported = append(notSupported, fmt.Sprintf("services[%s].networks[%s].ipv4_address", svcName, networkName))
+		}
+		if network.Ipv6Address != nil {
+			notSupported = append(notSupported, fmt.Sprintf("services[%s].networks[%s].ipv6_address", svcName, networkName))
+		}
+		if network.LinkLocalIPs != nil {
+			notSupported = append(notSupported, fmt.Sprintf("services[%s].networks[%s].link_local_ips", svcName, networkName))
+		}
+		if network.Priority != nil {
+			notSupported = append(notSupported, fmt.Sprintf("services[%s].networks[%s].priority", svcName, networkName))
+		}
 	}
 	if svcInfo.MacAddress != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].mac_address", svcName))
@@ -1453,48 +2102,28 @@ func getServiceNotSupportedFields(svcName string, svcInfo *ServiceRaw) []string
 	if svcInfo.Platform != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].platform", svcName))
 	}
-	if svcInfo.Privileged != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].privileged", svcName))
-	}
-	if svcInfo.Profiles != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].profiles", svcName))
-	}
 	if svcInfo.PullPolicy != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].pull_policy", svcName))
 	}
-	if svcInfo.ReadOnly != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].read_only", svcName))
-	}
 	if svcInfo.Runtime != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].runtime", svcName))
 	}
-	if svcInfo.Secrets != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].secrets", svcName))
-	}
-	if svcInfo.SecurityOpt != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].security_opt", svcName))
+	_, unsupportedOpts := ParseSecurityOpts(svcInfo.SecurityOpt)
+	for _, opt := range unsupportedOpts {
+		notSupported = append(notSupported, fmt.Sprintf("services[%s].security_opt[%s]", svcName, opt))
 	}
 	if svcInfo.ShmSize != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].shm_size", svcName))
 	}
-	if svcInfo.StdinOpen != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].stdin_open", svcName))
-	}
 	if svcInfo.StopSignal != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].stop_signal", svcName))
 	}
 	if svcInfo.StorageOpts != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].storage_opts", svcName))
 	}
-	if svcInfo.Sysctls != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].sysctls", svcName))
-	}
 	if svcInfo.Tmpfs != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].tmpfs", svcName))
 	}
-	if svcInfo.Tty != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].tty", svcName))
-	}
 	if svcInfo.Ulimits != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].ulimits", svcName))
 	}
@@ -1525,28 +2154,44 @@ func getDeployNotSupportedFields(svcName string, deploy *DeployInfoRaw) []string
 			notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.window", svcName))
 		}
 	}
+	if deploy.Placement != nil {
+		_, unsupported := deploy.Placement.toPlacement()
+		for _, value := range unsupported {
+			notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.placement[%s]", svcName, value))
+		}
+		if deploy.Placement.MaxReplicasPerNode != nil {
+			notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.placement.max_replicas_per_node", svcName))
+		}
+	}
+	notSupported = append(notSupported, getUpdateConfigNotSupportedFields(svcName, "update_config", deploy.UpdateConfig)...)
+	notSupported = append(notSupported, getUpdateConfigNotSupportedFields(svcName, "rollback_config", deploy.RollbackConfig)...)
 	if deploy.EndpointMode != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.endpoint_mode", svcName))
 	}
 	if deploy.Mode != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.mode", svcName))
 	}
-	if deploy.Placement != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.placement", svcName))
-	}
 	if deploy.Constraints != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.constraints", svcName))
 	}
 	if deploy.Preferences != nil {
 		notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.preferences", svcName))
 	}
-	if deploy.RollbackConfig != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.rollback_config", svcName))
+
+	return notSupported
+}
+
+func getUpdateConfigNotSupportedFields(svcName, field string, config *UpdateConfigRaw) []string {
+	notSupported := make([]string, 0)
+	if config == nil {
+		return notSupported
 	}
-	if deploy.UpdateConfig != nil {
-		notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.update_config", svcName))
+	if config.Monitor != nil {
+		notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.%s.monitor", svcName, field))
+	}
+	if config.MaxFailureRatio != nil {
+		notSupported = append(notSupported, fmt.Sprintf("services[%s].deploy.%s.max_failure_ratio", svcName, field))
 	}
-
 	return notSupported
 }
 
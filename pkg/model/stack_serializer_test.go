@@ -18,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -2015,3 +2016,374 @@ func Test_TranslateOktetoStackPortsToComposePorts(t *testing.T) {
 		})
 	}
 }
+
+func Test_ConfigsAndSecretsUnmarshalling(t *testing.T) {
+	manifest := []byte(`services:
+  app:
+    image: okteto/app
+    configs:
+      - nginx_conf
+      - source: settings
+        target: /etc/app/settings.ini
+        mode: 0440
+    secrets:
+      - api_token
+      - source: db_password
+        target: db
+        uid: "1000"
+        gid: "2000"
+configs:
+  nginx_conf:
+    file: ./nginx.conf
+  settings:
+    content: debug=true
+secrets:
+  api_token:
+    environment: API_TOKEN
+  db_password:
+    external: true
+    name: db-credentials
+`)
+	s, err := ReadStack(manifest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, map[string]*StackConfig{
+		"nginx-conf": {File: filepath.Join(wd, "nginx.conf")},
+		"settings":   {Content: "debug=true"},
+	}, s.Configs)
+	assert.Equal(t, map[string]*StackConfig{
+		"api-token":   {Environment: "API_TOKEN"},
+		"db-password": {External: true, Name: "db-credentials"},
+	}, s.Secrets)
+
+	svc := s.Services["app"]
+	assert.Equal(t, []ServiceConfig{
+		{Source: "nginx-conf", Target: "/nginx_conf"},
+		{Source: "settings", Target: "/etc/app/settings.ini", Mode: pointer.Int32Ptr(0440)},
+	}, svc.Configs)
+	assert.Equal(t, []ServiceConfig{
+		{Source: "api-token", Target: "/run/secrets/api_token"},
+		{Source: "db-password", Target: "/run/secrets/db", UID: "1000", GID: "2000"},
+	}, svc.Secrets)
+	assert.Empty(t, s.Warnings.NotSupportedFields)
+}
+
+func Test_NetworksUnmarshalling(t *testing.T) {
+	manifest := []byte(`services:
+  web:
+    image: okteto/web
+    networks:
+      - front
+  api:
+    image: okteto/api
+    networks:
+      front:
+        aliases:
+          - backend_api
+      back:
+        ipv4_address: 172.16.238.10
+  db:
+    image: postgres
+    networks: [back]
+networks:
+  front:
+  back:
+    internal: true
+    driver: bridge
+`)
+	s, err := ReadStack(manifest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, map[string]*StackNetwork{
+		"front": {},
+		"back":  {Internal: true},
+	}, s.Networks)
+	assert.Equal(t, map[string]*ServiceNetwork{"front": {}}, s.Services["web"].Networks)
+	assert.Equal(t, map[string]*ServiceNetwork{
+		"front": {Aliases: []string{"backend-api"}},
+		"back":  {},
+	}, s.Services["api"].Networks)
+	assert.Equal(t, map[string]*ServiceNetwork{"back": {}}, s.Services["db"].Networks)
+
+	sort.Strings(s.Warnings.NotSupportedFields)
+	assert.Equal(t, []string{"networks[back].driver", "services[api].networks[back].ipv4_address"}, s.Warnings.NotSupportedFields)
+}
+
+func Test_ContainerOptionsUnmarshalling(t *testing.T) {
+	manifest := []byte(`services:
+  app:
+    image: okteto/app
+    hostname: backend
+    domainname: example.com
+    extra_hosts:
+      - "db:10.0.0.2"
+      - "ipv6:::1"
+      - "cache=10.0.0.3"
+    init: true
+    privileged: true
+    read_only: true
+    security_opt:
+      - no-new-privileges:true
+      - label:disable
+      - seccomp:./profile.json
+    sysctls:
+      - net.core.somaxconn=1024
+    dns: 8.8.8.8
+    dns_search:
+      - example.com
+    dns_opt:
+      - ndots:2
+    tty: true
+    stdin_open: true
+  worker:
+    image: okteto/worker
+    extra_hosts:
+      api: 10.0.0.1
+    sysctls:
+      net.core.somaxconn: 1024
+`)
+	s, err := ReadStack(manifest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := s.Services["app"]
+	assert.Equal(t, "backend", app.Hostname)
+	assert.Empty(t, app.DomainName)
+	assert.Equal(t, map[string]string{"db": "10.0.0.2", "ipv6": "::1", "cache": "10.0.0.3"}, app.ExtraHosts)
+	assert.True(t, app.Init)
+	assert.True(t, app.Privileged)
+	assert.True(t, app.ReadOnly)
+	assert.True(t, app.Tty)
+	assert.True(t, app.StdinOpen)
+	assert.Equal(t, map[string]string{"net.core.somaxconn": "1024"}, app.Sysctls)
+	assert.Equal(t, []string{"8.8.8.8"}, app.DNS)
+	assert.Equal(t, []string{"example.com"}, app.DNSSearch)
+	assert.Equal(t, []string{"ndots:2"}, app.DNSOpt)
+
+	worker := s.Services["worker"]
+	assert.Equal(t, map[string]string{"api": "10.0.0.1"}, worker.ExtraHosts)
+	assert.Equal(t, map[string]string{"net.core.somaxconn": "1024"}, worker.Sysctls)
+
+	sort.Strings(s.Warnings.NotSupportedFields)
+	assert.Equal(t, []string{
+		"services[app].domainname",
+		"services[app].security_opt[label:disable]",
+		"services[app].security_opt[seccomp:./profile.json]",
+	}, s.Warnings.NotSupportedFields)
+}
+
+func Test_ParseSecurityOpts(t *testing.T) {
+	opts, unsupported := ParseSecurityOpts([]string{
+		"no-new-privileges",
+		"label=user:USER",
+		"label:level:s0:c100,c200",
+		"apparmor=my-profile",
+		"seccomp=unconfined",
+		"credentialspec=file://spec.json",
+	})
+	assert.Equal(t, &StackSecurityOpts{
+		NoNewPrivileges: true,
+		SELinuxOptions:  &apiv1.SELinuxOptions{User: "USER", Level: "s0:c100,c200"},
+		SeccompProfile:  &apiv1.SeccompProfile{Type: apiv1.SeccompProfileTypeUnconfined},
+		AppArmorProfile: "localhost/my-profile",
+	}, opts)
+	assert.Equal(t, []string{"credentialspec=file://spec.json"}, unsupported)
+}
+
+func Test_DeployRolloutUnmarshalling(t *testing.T) {
+	manifest := []byte(`services:
+  app:
+    image: okteto/app
+    deploy:
+      update_config:
+        parallelism: 2
+        delay: 10s
+        order: start-first
+        failure_action: rollback
+        monitor: 30s
+      rollback_config:
+        parallelism: 0
+      placement:
+        constraints:
+          - node.labels.disk == ssd
+          - node.platform.arch != x86_64
+          - node.role == manager
+        preferences:
+          - spread: node.labels.zone
+        max_replicas_per_node: 1
+`)
+	s, err := ReadStack(manifest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := s.Services["app"]
+	assert.Equal(t, &UpdateConfig{
+		Parallelism:   pointer.Int32(2),
+		Delay:         10,
+		Order:         UpdateOrderStartFirst,
+		FailureAction: UpdateFailureActionRollback,
+	}, app.UpdateConfig)
+	assert.Equal(t, &UpdateConfig{
+		Parallelism:   pointer.Int32(0),
+		Order:         UpdateOrderStopFirst,
+		FailureAction: UpdateFailureActionPause,
+	}, app.RollbackConfig)
+	assert.Equal(t, &Placement{
+		Constraints: []PlacementConstraint{
+			{Key: "disk", Value: "ssd"},
+			{Key: "kubernetes.io/arch", Value: "amd64", Negated: true},
+		},
+		Spread: []string{"zone"},
+	}, app.Placement)
+
+	sort.Strings(s.Warnings.NotSupportedFields)
+	assert.Equal(t, []string{
+		"services[app].deploy.placement.max_replicas_per_node",
+		"services[app].deploy.placement[node.role == manager]",
+		"services[app].deploy.update_config.monitor",
+	}, s.Warnings.NotSupportedFields)
+
+	_, err = ReadStack([]byte(`services:
+  app:
+    image: okteto/app
+    deploy:
+      update_config:
+        order: random
+`), true)
+	assert.Error(t, err)
+}
+
+func Test_ScheduleUnmarshalling(t *testing.T) {
+	manifest := []byte(`services:
+  cleanup:
+    image: okteto/cleanup
+    x-okteto-schedule: "*/5 * * * *"
+  report:
+    image: okteto/report
+    restart: "no"
+    x-okteto-schedule:
+      cron: "@daily"
+      concurrency_policy: forbid
+      successful_jobs_history_limit: 1
+      failed_jobs_history_limit: 3
+      suspend: true
+`)
+	s, err := ReadStack(manifest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cleanup := s.Services["cleanup"]
+	assert.Equal(t, &ServiceSchedule{Cron: "*/5 * * * *", ConcurrencyPolicy: ScheduleConcurrencyAllow}, cleanup.Schedule)
+	assert.Equal(t, apiv1.RestartPolicyOnFailure, cleanup.RestartPolicy)
+	assert.True(t, cleanup.IsCronJob())
+	assert.False(t, cleanup.IsDeployment())
+	assert.False(t, cleanup.IsJob())
+
+	one := int32(1)
+	three := int32(3)
+	report := s.Services["report"]
+	assert.Equal(t, &ServiceSchedule{
+		Cron:                       "@daily",
+		ConcurrencyPolicy:          ScheduleConcurrencyForbid,
+		SuccessfulJobsHistoryLimit: &one,
+		FailedJobsHistoryLimit:     &three,
+		Suspend:                    true,
+	}, report.Schedule)
+	assert.Equal(t, apiv1.RestartPolicyNever, report.RestartPolicy)
+
+	for _, schedule := range []string{`"* * *"`, `{cron: "@daily", concurrency_policy: queue}`} {
+		_, err = ReadStack([]byte(fmt.Sprintf("services:\n  app:\n    image: okteto/app\n    x-okteto-schedule: %s\n", schedule)), true)
+		assert.Error(t, err)
+	}
+}
+
+func Test_AutoscalingUnmarshalling(t *testing.T) {
+	manifest := []byte(`services:
+  api:
+    image: okteto/api
+    deploy:
+      replicas: 1
+      x-okteto-autoscaling:
+        min_replicas: 2
+        max_replicas: 5
+        memory: 70
+      x-okteto-disruption-budget:
+        min_available: 1
+  web:
+    image: okteto/web
+    deploy:
+      x-okteto-autoscaling:
+        max_replicas: 3
+      x-okteto-disruption-budget:
+        max_unavailable: 25%
+`)
+	s, err := ReadStack(manifest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := s.Services["api"]
+	assert.Equal(t, &ServiceAutoscaling{MinReplicas: 2, MaxReplicas: 5, Memory: 70}, api.Autoscaling)
+	assert.Equal(t, int32(2), api.Replicas)
+	assert.Equal(t, 1, api.DisruptionBudget.MinAvailable.IntValue())
+	assert.Nil(t, api.DisruptionBudget.MaxUnavailable)
+
+	web := s.Services["web"]
+	assert.Equal(t, &ServiceAutoscaling{MinReplicas: 1, MaxReplicas: 3, CPU: 80}, web.Autoscaling)
+	assert.Equal(t, "25%", web.DisruptionBudget.MaxUnavailable.StrVal)
+
+	for _, deploy := range []string{
+		`{x-okteto-autoscaling: {min_replicas: 3, max_replicas: 2}}`,
+		`{x-okteto-disruption-budget: {min_available: 1, max_unavailable: 1}}`,
+		`{x-okteto-disruption-budget: {min_available: 150%}}`,
+	} {
+		_, err = ReadStack([]byte(fmt.Sprintf("services:\n  app:\n    image: okteto/app\n    deploy: %s\n", deploy)), true)
+		assert.Error(t, err)
+	}
+}
+
+func Test_DependsOnUnmarshalling(t *testing.T) {
+	manifest := []byte(`services:
+  app:
+    image: okteto/app
+    depends_on:
+      db:
+        condition: service_healthy
+        restart: true
+        x-okteto-timeout: 2m
+      migrations:
+        condition: service_completed_successfully
+      cache:
+        restart: true
+  db:
+    image: postgres
+  cache:
+    image: redis
+  migrations:
+    image: okteto/app
+    restart: "no"
+`)
+	s, err := ReadStack(manifest, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, DependsOn{
+		"db":         {Condition: DependsOnServiceHealthy, Restart: true, Timeout: 2 * time.Minute},
+		"migrations": {Condition: DependsOnServiceCompleted},
+		"cache":      {Condition: DependsOnServiceRunning, Restart: true},
+	}, s.Services["app"].DependsOn)
+
+	_, err = ReadStack([]byte("services:\n  app:\n    image: okteto/app\n    depends_on:\n      db:\n        x-okteto-timeout: -1s\n  db:\n    image: postgres\n"), true)
+	assert.Error(t, err)
+}
@@ -440,6 +440,95 @@ func TestStack_validate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "undefined-config",
+			stack: &Stack{
+				Name: "name",
+				Services: map[string]*Service{
+					"app": {Image: "test", Configs: []ServiceConfig{{Source: "settings", Target: "/settings"}}},
+				},
+			},
+		},
+		{
+			name: "undefined-secret",
+			stack: &Stack{
+				Name: "name",
+				Services: map[string]*Service{
+					"app": {Image: "test", Secrets: []ServiceConfig{{Source: "token", Target: "/run/secrets/token"}}},
+				},
+			},
+		},
+		{
+			name: "config-without-source",
+			stack: &Stack{
+				Name:    "name",
+				Configs: map[string]*StackConfig{"settings": {}},
+				Services: map[string]*Service{
+					"app": {Image: "test"},
+				},
+			},
+		},
+		{
+			name: "external-config-with-source",
+			stack: &Stack{
+				Name:    "name",
+				Configs: map[string]*StackConfig{"settings": {External: true, File: "settings.ini"}},
+				Services: map[string]*Service{
+					"app": {Image: "test"},
+				},
+			},
+		},
+		{
+			name: "secret-with-content",
+			stack: &Stack{
+				Name:    "name",
+				Secrets: map[string]*StackConfig{"token": {Content: "token"}},
+				Services: map[string]*Service{
+					"app": {Image: "test"},
+				},
+			},
+		},
+		{
+			name: "undefined-network",
+			stack: &Stack{
+				Name: "name",
+				Services: map[string]*Service{
+					"app": {Image: "test", Networks: map[string]*ServiceNetwork{"front": {}}},
+				},
+			},
+		},
+		{
+			name: "alias-collides-with-service",
+			stack: &Stack{
+				Name:     "name",
+				Networks: map[string]*StackNetwork{"front": {}},
+				Services: map[string]*Service{
+					"app": {Image: "test", Networks: map[string]*ServiceNetwork{"front": {Aliases: []string{"db"}}}},
+					"db":  {Image: "test"},
+				},
+			},
+		},
+		{
+			name: "duplicated-alias",
+			stack: &Stack{
+				Name:     "name",
+				Networks: map[string]*StackNetwork{"front": {}},
+				Services: map[string]*Service{
+					"app": {Image: "test", Networks: map[string]*ServiceNetwork{"front": {Aliases: []string{"web"}}}},
+					"api": {Image: "test", Networks: map[string]*ServiceNetwork{"front": {Aliases: []string{"web"}}}},
+				},
+			},
+		},
+		{
+			name: "depends-on-scheduled-service",
+			stack: &Stack{
+				Name: "name",
+				Services: map[string]*Service{
+					"app":     {Image: "test", DependsOn: DependsOn{"cleanup": DependsOnConditionSpec{Condition: DependsOnServiceRunning}}},
+					"cleanup": {Image: "test", Schedule: &ServiceSchedule{Cron: "@daily"}},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -534,7 +623,7 @@ func TestStack_Merge(t *testing.T) {
 			},
 		},
 		{
-			name: "volumes overwrite",
+			name: "volumes merged by remote path",
 			stack: &Stack{
 				Services: map[string]*Service{
 					"app": {
@@ -575,6 +664,10 @@ func TestStack_Merge(t *testing.T) {
 				Services: map[string]*Service{
 					"app": {
 						Volumes: []StackVolume{
+							{
+								LocalPath:  "/app",
+								RemotePath: "/app",
+							},
 							{
 								LocalPath:  "/app-test",
 								RemotePath: "/app-test",
@@ -708,7 +801,7 @@ func TestStack_Merge(t *testing.T) {
 			},
 		},
 		{
-			name: "Overwrite list field",
+			name: "Merge list and map fields",
 			stack: &Stack{
 				Services: map[string]*Service{
 					"app": {
@@ -770,15 +863,15 @@ func TestStack_Merge(t *testing.T) {
 			result: &Stack{
 				Services: map[string]*Service{
 					"app": {
-						CapAdd:  []corev1.Capability{"cpu"},
-						CapDrop: []corev1.Capability{"tpu"},
+						CapAdd:  []corev1.Capability{"tpu", "cpu"},
+						CapDrop: []corev1.Capability{"cpu", "tpu"},
 						Entrypoint: Entrypoint{
 							Values: []string{"go"},
 						},
 						Command: Command{
 							Values: []string{"run", "main.go"},
 						},
-						EnvFiles: EnvFiles{".env-test"},
+						EnvFiles: EnvFiles{".env", ".env-test"},
 						Environment: Environment{
 							EnvVar{
 								Name:  "test",
@@ -788,6 +881,10 @@ func TestStack_Merge(t *testing.T) {
 						Labels:      Labels{"test": "overwrite"},
 						Annotations: Annotations{"test": "overwrite"},
 						Ports: []Port{
+							{
+								HostPort:      8080,
+								ContainerPort: 8080,
+							},
 							{
 								HostPort:      3000,
 								ContainerPort: 3000,
@@ -69,7 +69,7 @@ func (dev *Dev) translateDeprecatedVolumes() {
 			volumes = append(volumes, v)
 			continue
 		}
-		dev.Sync.Folders = append(dev.Sync.Folders, SyncFolder(v))
+		dev.Sync.Folders = append(dev.Sync.Folders, SyncFolder{LocalPath: v.LocalPath, RemotePath: v.RemotePath})
 	}
 	dev.Volumes = volumes
 }
@@ -277,7 +277,7 @@ func (dev *Dev) validateDuplicatedSyncFolders() error {
 	for _, sync := range dev.Sync.Folders {
 		key := sync.LocalPath + ":" + sync.RemotePath
 		if seen[key] {
-			return fmt.Errorf("duplicated sync '%s'", sync)
+			return fmt.Errorf("duplicated sync '%s'", key)
 		}
 		seen[key] = true
 		result, err := dev.IsSubPathFolder(sync.LocalPath)
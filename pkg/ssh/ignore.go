@@ -0,0 +1,173 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+)
+
+// ignoreRule is a pattern of a .stignore file
+type ignoreRule struct {
+	re     *regexp.Regexp
+	negate bool
+}
+
+// ignoreMatcher decides which paths of a sync folder are not synchronized, following the syntax of the .stignore files
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+// loadIgnoreMatcher reads the .stignore file of a sync folder, if it exists
+func loadIgnoreMatcher(localPath string) (*ignoreMatcher, error) {
+	m := &ignoreMatcher{}
+	f, err := os.Open(filepath.Join(localPath, ".stignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, fmt.Errorf("error reading the .stignore file of '%s': %w", localPath, err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			oktetoLog.Debugf("Error closing .stignore file of '%s': %s", localPath, err)
+		}
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m.add(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading the .stignore file of '%s': %w", localPath, err)
+	}
+	return m, nil
+}
+
+// add parses a line of a .stignore file
+func (m *ignoreMatcher) add(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "//") {
+		return
+	}
+	if strings.HasPrefix(line, "#include") {
+		oktetoLog.Infof("'%s' is not supported by the ssh sync engine, ignoring it", line)
+		return
+	}
+
+	rule := ignoreRule{}
+	caseInsensitive := false
+	for {
+		switch {
+		case strings.HasPrefix(line, "!"):
+			rule.negate = true
+			line = line[1:]
+		case strings.HasPrefix(line, "(?i)"):
+			caseInsensitive = true
+			line = line[4:]
+		case strings.HasPrefix(line, "(?d)"):
+			line = line[4:]
+		default:
+			expr := globToRegexp(line)
+			if caseInsensitive {
+				expr = "(?i)" + expr
+			}
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				oktetoLog.Infof("invalid .stignore pattern '%s': %s", line, err)
+				return
+			}
+			rule.re = re
+			m.rules = append(m.rules, rule)
+			return
+		}
+	}
+}
+
+// globToRegexp translates a .stignore pattern to a regular expression.
+// Patterns starting with '/' only match in the root of the sync folder, the rest match at any depth
+func globToRegexp(pattern string) string {
+	var b strings.Builder
+	if strings.HasPrefix(pattern, "/") {
+		b.WriteString("^")
+		pattern = strings.TrimPrefix(pattern, "/")
+	} else {
+		b.WriteString("^(.*/)?")
+	}
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(pattern[i:]))
+				i = len(pattern)
+				continue
+			}
+			b.WriteString(pattern[i : i+end+1])
+			i += end
+		case '\\':
+			if i+1 < len(pattern) {
+				b.WriteString(regexp.QuoteMeta(pattern[i+1 : i+2]))
+				i++
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// ignored returns if a path relative to the sync folder is ignored.
+// The content of ignored directories is ignored as well
+func (m *ignoreMatcher) ignored(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	if rel == ".stignore" || rel == ".stfolder" || rel == ".stversions" {
+		return true
+	}
+	parts := strings.Split(rel, "/")
+	for i := range parts {
+		if m.match(strings.Join(parts[:i+1], "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns if the first rule matching the path ignores it
+func (m *ignoreMatcher) match(rel string) bool {
+	for _, rule := range m.rules {
+		if rule.re.MatchString(rel) {
+			return !rule.negate
+		}
+	}
+	return false
+}
@@ -0,0 +1,594 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alessio/shellescape"
+	"github.com/fsnotify/fsnotify"
+	oktetoErrors "github.com/okteto/okteto/pkg/errors"
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/okteto/okteto/pkg/model"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// syncDebounce is the time without local changes to wait before pushing them
+	syncDebounce = 500 * time.Millisecond
+
+	// syncKeepAlive is the interval to check the connection with the development container
+	syncKeepAlive = 10 * time.Second
+
+	// syncBatchSize is the maximum size of the files sent in a single tar stream during the initial synchronization
+	syncBatchSize = 32 * 1024 * 1024
+)
+
+// Syncer synchronizes the sync folders with the development container through its SSH server.
+// Local changes are detected with a file watcher and pushed as tar streams.
+// It's a one-way synchronization: changes made in the development container are not downloaded
+type Syncer struct {
+	addr    string
+	folders []*syncFolder
+	client  *ssh.Client
+	watcher *fsnotify.Watcher
+
+	mu         sync.Mutex
+	pending    map[string]bool
+	lastChange time.Time
+	lost       bool
+}
+
+type syncFolder struct {
+	localPath    string
+	remotePath   string
+	ignoreDelete bool
+	ignores      *ignoreMatcher
+}
+
+// remoteFile is the metadata of a file of the development container
+type remoteFile struct {
+	dir     bool
+	size    int64
+	modTime int64
+}
+
+// pushEntry is a local path to send to the development container
+type pushEntry struct {
+	rel  string
+	size int64
+}
+
+// NewSyncer returns a syncer for the sync folders of a development container
+func NewSyncer(dev *model.Dev) (*Syncer, error) {
+	s := &Syncer{
+		addr:    net.JoinHostPort(dev.Interface, strconv.Itoa(dev.RemotePort)),
+		folders: []*syncFolder{},
+		pending: map[string]bool{},
+	}
+	for _, folder := range dev.Sync.Folders {
+		isSubPath, err := dev.IsSubPathFolder(folder.LocalPath)
+		if err != nil {
+			return nil, err
+		}
+		if isSubPath {
+			continue
+		}
+		ignores, err := loadIgnoreMatcher(folder.LocalPath)
+		if err != nil {
+			return nil, err
+		}
+		s.folders = append(s.folders, &syncFolder{
+			localPath:    folder.LocalPath,
+			remotePath:   folder.RemotePath,
+			ignoreDelete: folder.IgnoreDelete,
+			ignores:      ignores,
+		})
+	}
+	return s, nil
+}
+
+// Start connects to the SSH server of the development container and starts watching the sync folders
+func (s *Syncer) Start(ctx context.Context) error {
+	sshConfig, err := getSSHClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get SSH configuration: %w", err)
+	}
+	client, err := start(ctx, s.addr, sshConfig, syncKeepAlive)
+	if err != nil {
+		oktetoLog.Infof("failed to connect to the SSH server: %s", err)
+		return oktetoErrors.ErrSSHConnectError
+	}
+	s.client = client
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating file watcher: %w", err)
+	}
+	s.watcher = watcher
+	for _, folder := range s.folders {
+		if err := s.addWatchDir(folder, folder.localPath); err != nil {
+			return err
+		}
+	}
+	go s.collectChanges()
+	return nil
+}
+
+// addWatchDir watches dir and all its subfolders that are not ignored, fsnotify is not recursive
+func (s *Syncer) addWatchDir(folder *syncFolder, dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if rel, _ := filepath.Rel(folder.localPath, p); rel != "." && folder.ignores.ignored(rel) {
+			return filepath.SkipDir
+		}
+		if err := s.watcher.Add(p); err != nil {
+			return fmt.Errorf("error watching '%s': %w", p, err)
+		}
+		return nil
+	})
+}
+
+// collectChanges records the paths changed locally until the watcher is closed
+func (s *Syncer) collectChanges() {
+	for {
+		select {
+		case event, ok := <-s.watcher.Events:
+			if !ok {
+				return
+			}
+			folder, rel := s.getFolder(event.Name)
+			if folder == nil || folder.ignores.ignored(rel) {
+				continue
+			}
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := s.addWatchDir(folder, event.Name); err != nil {
+						oktetoLog.Infof("could not watch '%s': %s", event.Name, err)
+					}
+				}
+			}
+			s.mu.Lock()
+			s.pending[event.Name] = true
+			s.lastChange = time.Now()
+			s.mu.Unlock()
+		case err, ok := <-s.watcher.Errors:
+			if !ok {
+				return
+			}
+			oktetoLog.Infof("error watching files: %s", err)
+		}
+	}
+}
+
+// getFolder returns the sync folder of a local path and the path relative to it
+func (s *Syncer) getFolder(p string) (*syncFolder, string) {
+	for _, folder := range s.folders {
+		rel, err := filepath.Rel(folder.localPath, p)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		return folder, rel
+	}
+	return nil, ""
+}
+
+// WaitForCompletion sends the local files that are missing or outdated in the development container.
+// Files are compared by size and modification time
+func (s *Syncer) WaitForCompletion(ctx context.Context, reporter chan float64) error {
+	defer close(reporter)
+
+	entries := map[*syncFolder][]pushEntry{}
+	var total int64
+	for _, folder := range s.folders {
+		remote, err := s.getRemoteFiles(ctx, folder)
+		if err != nil {
+			return err
+		}
+		outdated, err := getOutdatedEntries(folder, remote)
+		if err != nil {
+			return err
+		}
+		entries[folder] = outdated
+		for _, e := range outdated {
+			total += e.size
+		}
+	}
+
+	var done int64
+	for _, folder := range s.folders {
+		for _, batch := range splitBatches(entries[folder], syncBatchSize) {
+			if err := s.push(ctx, folder, batch); err != nil {
+				return err
+			}
+			for _, e := range batch {
+				done += e.size
+			}
+			if total > 0 {
+				reporter <- float64(done) * 100 / float64(total)
+			}
+		}
+	}
+	return nil
+}
+
+// getRemoteFiles returns the files of a sync folder in the development container
+func (s *Syncer) getRemoteFiles(ctx context.Context, folder *syncFolder) (map[string]remoteFile, error) {
+	cmd := fmt.Sprintf("cd %s 2>/dev/null || exit 0; find . -exec stat -c '%%f %%s %%Y %%n' {} +", shellescape.Quote(folder.remotePath))
+	output, err := s.run(ctx, cmd, nil)
+	if err == oktetoErrors.ErrLostSyncthing {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing the files of '%s' in your development container: %w", folder.remotePath, err)
+	}
+	return parseRemoteFiles(output), nil
+}
+
+// parseRemoteFiles parses the output of 'stat -c "%f %s %Y %n"'
+func parseRemoteFiles(output []byte) map[string]remoteFile {
+	result := map[string]remoteFile{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		mode, err := strconv.ParseUint(fields[0], 16, 32)
+		if err != nil {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		modTime, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		name := strings.TrimPrefix(fields[3], "./")
+		if name == "." {
+			continue
+		}
+		result[name] = remoteFile{
+			dir:     mode&0170000 == 0040000,
+			size:    size,
+			modTime: modTime,
+		}
+	}
+	return result
+}
+
+// getOutdatedEntries returns the local paths of a sync folder that don't match the development container
+func getOutdatedEntries(folder *syncFolder, remote map[string]remoteFile) ([]pushEntry, error) {
+	result := []pushEntry{}
+	err := filepath.WalkDir(folder.localPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(folder.localPath, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		if folder.ignores.ignored(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		r, ok := remote[filepath.ToSlash(rel)]
+		switch {
+		case d.IsDir():
+			if !ok || !r.dir {
+				result = append(result, pushEntry{rel: rel})
+			}
+		case info.Mode().IsRegular():
+			if !ok || r.dir || r.size != info.Size() || r.modTime != info.ModTime().Unix() {
+				result = append(result, pushEntry{rel: rel, size: info.Size()})
+			}
+		case info.Mode()&fs.ModeSymlink != 0:
+			if !ok {
+				result = append(result, pushEntry{rel: rel})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading '%s': %w", folder.localPath, err)
+	}
+	return result, nil
+}
+
+// splitBatches groups the entries in batches of at most size bytes, unless a single file is bigger
+func splitBatches(entries []pushEntry, size int64) [][]pushEntry {
+	result := [][]pushEntry{}
+	var current []pushEntry
+	var currentSize int64
+	for _, e := range entries {
+		if len(current) > 0 && currentSize+e.size > size {
+			result = append(result, current)
+			current = nil
+			currentSize = 0
+		}
+		current = append(current, e)
+		currentSize += e.size
+	}
+	if len(current) > 0 {
+		result = append(result, current)
+	}
+	return result
+}
+
+// push sends the given paths of a sync folder to the development container as a tar stream
+func (s *Syncer) push(ctx context.Context, folder *syncFolder, entries []pushEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(writeTar(w, folder.localPath, entries))
+	}()
+	remotePath := shellescape.Quote(folder.remotePath)
+	cmd := fmt.Sprintf("mkdir -p %s && tar -xf - -C %s", remotePath, remotePath)
+	_, err := s.run(ctx, cmd, r)
+	if err == oktetoErrors.ErrLostSyncthing {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("error sending files to '%s' in your development container: %w", folder.remotePath, err)
+	}
+	oktetoLog.Infof("sent %d paths to '%s'", len(entries), folder.remotePath)
+	return nil
+}
+
+// writeTar writes the given paths relative to dir as a tar stream
+func writeTar(w io.Writer, dir string, entries []pushEntry) error {
+	tw := tar.NewWriter(w)
+	for _, e := range entries {
+		if err := addTarEntry(tw, dir, e.rel); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func addTarEntry(tw *tar.Writer, dir, rel string) error {
+	p := filepath.Join(dir, rel)
+	info, err := os.Lstat(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// the file was removed after listing it, the watcher will remove it remotely
+			return nil
+		}
+		return err
+	}
+	link := ""
+	if info.Mode()&fs.ModeSymlink != 0 {
+		if link, err = os.Readlink(p); err != nil {
+			return err
+		}
+	}
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(rel)
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			oktetoLog.Debugf("Error closing file %s: %s", p, err)
+		}
+	}()
+	_, err = io.CopyN(tw, f, header.Size)
+	return err
+}
+
+// run executes a command in the development container
+func (s *Syncer) run(ctx context.Context, cmd string, stdin io.Reader) ([]byte, error) {
+	session, err := s.client.NewSession()
+	if err != nil {
+		s.setLost()
+		return nil, oktetoErrors.ErrLostSyncthing
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		if err := session.Close(); err != nil && err != io.EOF {
+			oktetoLog.Debugf("Error closing session: %s", err)
+		}
+	}()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdin = stdin
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	oktetoLog.Infof("executing command over ssh: '%s'", cmd)
+	if err := session.Run(cmd); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// Status returns the progress of the synchronization of the local changes
+func (s *Syncer) Status() (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lost {
+		return 0, oktetoErrors.ErrLostSyncthing
+	}
+	if len(s.pending) > 0 {
+		return 0, nil
+	}
+	return 100, nil
+}
+
+func (s *Syncer) setLost() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lost = true
+}
+
+// Watch pushes the local changes to the development container until ctx is done.
+// Losing the connection with the development container is notified on the disconnect channel
+func (s *Syncer) Watch(ctx context.Context, disconnect chan error) {
+	flush := time.NewTicker(syncDebounce)
+	defer flush.Stop()
+	keepAlive := time.NewTicker(syncKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			if _, _, err := s.client.SendRequest("dev.okteto.com/keepalive", true, nil); err != nil {
+				oktetoLog.Infof("failed to send SSH keepalive: %s", err)
+				s.setLost()
+				disconnect <- oktetoErrors.ErrLostSyncthing
+				return
+			}
+		case <-flush.C:
+			paths := s.takePending()
+			if len(paths) == 0 {
+				continue
+			}
+			if err := s.pushChanges(ctx, paths); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				oktetoLog.Infof("error synchronizing local changes: %s", err)
+				s.setLost()
+				disconnect <- oktetoErrors.ErrLostSyncthing
+				return
+			}
+		}
+	}
+}
+
+// takePending returns the paths changed locally once no changes happened during syncDebounce
+func (s *Syncer) takePending() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) == 0 || time.Since(s.lastChange) < syncDebounce {
+		return nil
+	}
+	result := make([]string, 0, len(s.pending))
+	for p := range s.pending {
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	s.pending = map[string]bool{}
+	return result
+}
+
+// pushChanges sends the changed paths that exist locally and removes the rest from the development container
+func (s *Syncer) pushChanges(ctx context.Context, paths []string) error {
+	for _, folder := range s.folders {
+		updated := []pushEntry{}
+		deleted := []string{}
+		for _, p := range paths {
+			f, rel := s.getFolder(p)
+			if f != folder {
+				continue
+			}
+			info, err := os.Lstat(p)
+			switch {
+			case err == nil && info.IsDir():
+				entries, err := getOutdatedEntries(&syncFolder{localPath: p, ignores: &ignoreMatcher{}}, nil)
+				if err != nil {
+					return err
+				}
+				updated = append(updated, pushEntry{rel: rel})
+				for _, e := range entries {
+					e.rel = filepath.Join(rel, e.rel)
+					if !folder.ignores.ignored(e.rel) {
+						updated = append(updated, e)
+					}
+				}
+			case err == nil:
+				updated = append(updated, pushEntry{rel: rel, size: info.Size()})
+			case os.IsNotExist(err):
+				deleted = append(deleted, path.Join(folder.remotePath, filepath.ToSlash(rel)))
+			default:
+				return err
+			}
+		}
+		if err := s.push(ctx, folder, updated); err != nil {
+			return err
+		}
+		if len(deleted) == 0 || folder.ignoreDelete {
+			continue
+		}
+		cmd := shellescape.QuoteCommand(append([]string{"rm", "-rf", "--"}, deleted...))
+		if _, err := s.run(ctx, cmd, nil); err != nil {
+			return fmt.Errorf("error removing files from your development container: %w", err)
+		}
+	}
+	return nil
+}
+
+// Stop stops watching the sync folders and closes the connection with the development container
+func (s *Syncer) Stop() error {
+	if s.watcher != nil {
+		if err := s.watcher.Close(); err != nil {
+			oktetoLog.Infof("failed to close the file watcher: %s", err)
+		}
+	}
+	if s.client == nil {
+		return nil
+	}
+	if err := s.client.Close(); err != nil && !oktetoErrors.IsClosedNetwork(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_ignoreMatcher(t *testing.T) {
+	m := &ignoreMatcher{}
+	for _, line := range []string{
+		"// comment",
+		"!node_modules/keep",
+		"node_modules",
+		"/build",
+		"*.log",
+		"(?i)*.TMP",
+		"(?d).DS_Store",
+		"docs/**/draft",
+	} {
+		m.add(line)
+	}
+
+	tests := map[string]bool{
+		"main.go":                     false,
+		"node_modules":                true,
+		"node_modules/react/index.js": true,
+		"web/node_modules/index.js":   true,
+		"build":                       true,
+		"build/app":                   true,
+		"src/build/app":               false,
+		"app.log":                     true,
+		"logs/app.log":                true,
+		"cache.tmp":                   true,
+		".DS_Store":                   true,
+		"docs/api/v1/draft":           true,
+		"docs/readme.md":              false,
+		".stignore":                   true,
+		filepath.Join("src", "a.log"): true,
+	}
+	for rel, expected := range tests {
+		if result := m.ignored(rel); result != expected {
+			t.Errorf("'%s': got %t, expected %t", rel, result, expected)
+		}
+	}
+}
+
+func Test_loadIgnoreMatcher(t *testing.T) {
+	dir := t.TempDir()
+	m, err := loadIgnoreMatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.ignored("main.go") {
+		t.Error("'main.go' ignored without a .stignore file")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".stignore"), []byte("// comment\n#include other\nvendor\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m, err = loadIgnoreMatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.rules) != 1 {
+		t.Fatalf("got %d rules, expected 1", len(m.rules))
+	}
+	if !m.ignored("vendor/lib.go") {
+		t.Error("'vendor/lib.go' is not ignored")
+	}
+}
+
+func Test_parseRemoteFiles(t *testing.T) {
+	output := []byte(`41ed 4096 1665400000 .
+41ed 4096 1665400000 ./src
+81a4 120 1665400001 ./src/main.go
+81a4 7 1665400002 ./file with spaces.txt
+invalid line
+`)
+	expected := map[string]remoteFile{
+		"src":                  {dir: true, size: 4096, modTime: 1665400000},
+		"src/main.go":          {size: 120, modTime: 1665400001},
+		"file with spaces.txt": {size: 7, modTime: 1665400002},
+	}
+	if result := parseRemoteFiles(output); !reflect.DeepEqual(result, expected) {
+		t.Errorf("got %+v, expected %+v", result, expected)
+	}
+}
+
+func Test_getOutdatedEntries(t *testing.T) {
+	dir := t.TempDir()
+	modTime := time.Unix(1665400000, 0)
+	for name, content := range map[string]string{
+		"same.go":         "same",
+		"changed.go":      "changed",
+		"new.go":          "new",
+		"src/main.go":     "main",
+		"ignored/data.go": "data",
+	} {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ignores := &ignoreMatcher{}
+	ignores.add("ignored")
+	folder := &syncFolder{localPath: dir, ignores: ignores}
+	remote := map[string]remoteFile{
+		"same.go":    {size: 4, modTime: modTime.Unix()},
+		"changed.go": {size: 7, modTime: modTime.Unix() - 10},
+		"src":        {dir: true},
+	}
+
+	result, err := getOutdatedEntries(folder, remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []pushEntry{
+		{rel: "changed.go", size: 7},
+		{rel: "new.go", size: 3},
+		{rel: filepath.Join("src", "main.go"), size: 4},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("got %+v, expected %+v", result, expected)
+	}
+}
+
+func Test_splitBatches(t *testing.T) {
+	entries := []pushEntry{{rel: "a", size: 6}, {rel: "b", size: 3}, {rel: "c", size: 20}, {rel: "d"}}
+	expected := [][]pushEntry{
+		{{rel: "a", size: 6}, {rel: "b", size: 3}},
+		{{rel: "c", size: 20}},
+		{{rel: "d"}},
+	}
+	if result := splitBatches(entries, 10); !reflect.DeepEqual(result, expected) {
+		t.Errorf("got %+v, expected %+v", result, expected)
+	}
+	if result := splitBatches(nil, 10); len(result) != 0 {
+		t.Errorf("got %+v, expected no batches", result)
+	}
+}
+
+func Test_writeTar(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("package main"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	entries := []pushEntry{{rel: "src"}, {rel: filepath.Join("src", "main.go")}, {rel: "removed.go"}}
+	if err := writeTar(&b, dir, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	tr := tar.NewReader(&b)
+	names := []string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, header.Name)
+		if header.Name == "src/main.go" {
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != "package main" {
+				t.Errorf("got content '%s'", string(content))
+			}
+		}
+	}
+	if expected := []string{"src", "src/main.go"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("got %v, expected %v", names, expected)
+	}
+}
+
+func Test_takePending(t *testing.T) {
+	s := &Syncer{pending: map[string]bool{"/src/b": true, "/src/a": true}, lastChange: time.Now()}
+	if result := s.takePending(); result != nil {
+		t.Errorf("got %v before the debounce time", result)
+	}
+
+	s.lastChange = time.Now().Add(-syncDebounce)
+	if result := s.takePending(); !reflect.DeepEqual(result, []string{"/src/a", "/src/b"}) {
+		t.Errorf("got %v", result)
+	}
+	if len(s.pending) != 0 {
+		t.Errorf("pending changes not cleared: %v", s.pending)
+	}
+}
@@ -15,7 +15,7 @@ package syncthing
 
 const configXML = `<configuration version="32">
 {{ range .Folders }}
-<folder id="okteto-{{ .Name }}" label="{{ .Name }}" path="{{ .LocalPath }}" type="{{ $.Type }}" rescanIntervalS="{{ $.RescanInterval }}" fsWatcherEnabled="true" fsWatcherDelayS="1" ignorePerms="false" autoNormalize="true">
+<folder id="okteto-{{ .Name }}" label="{{ .Name }}" path="{{ .LocalPath }}" type="{{ .LocalType $.Type }}" rescanIntervalS="{{ $.RescanInterval }}" fsWatcherEnabled="true" fsWatcherDelayS="1" ignorePerms="false" autoNormalize="true">
     <filesystemType>basic</filesystemType>
     <device id="ABKAVQF-RUO4CYO-FSC2VIP-VRX4QDA-TQQRN2J-MRDXJUC-FXNWP6N-S6ZSAAR" introducedBy=""></device>
     <device id="{{$.RemoteDeviceID}}" introducedBy=""></device>
@@ -25,7 +25,7 @@ const configXML = `<configuration version="32">
     <pullerMaxPendingKiB>0</pullerMaxPendingKiB>
     <hashers>0</hashers>
     <order>random</order>
-    <ignoreDelete>{{ $.IgnoreDelete }}</ignoreDelete>
+    <ignoreDelete>{{ or $.IgnoreDelete .IgnoreDelete }}</ignoreDelete>
     <scanProgressIntervalS>1</scanProgressIntervalS>
     <pullerPauseS>0</pullerPauseS>
     <maxConflicts>0</maxConflicts>
@@ -65,8 +65,8 @@ const configXML = `<configuration version="32">
 <options>
     <globalAnnounceEnabled>false</globalAnnounceEnabled>
     <localAnnounceEnabled>false</localAnnounceEnabled>
-    <maxSendKbps>0</maxSendKbps>
-    <maxRecvKbps>0</maxRecvKbps>
+    <maxSendKbps>{{ .UploadLimit }}</maxSendKbps>
+    <maxRecvKbps>{{ .DownloadLimit }}</maxRecvKbps>
     <reconnectionIntervalS>1</reconnectionIntervalS>
     <relaysEnabled>false</relaysEnabled>
     <startBrowser>false</startBrowser>
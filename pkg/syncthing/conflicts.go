@@ -0,0 +1,254 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncthing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	conflictMarker = ".sync-conflict-"
+
+	// conflictSuffixLength is the length of "20060102-150405-ABKAVQF", the suffix syncthing adds to conflict copies
+	conflictSuffixLength = 23
+)
+
+// Conflict represents a conflict copy created by syncthing when the local and remote versions of a file diverge.
+// Syncthing keeps the most recent version in the original path and the other one in the conflict copy
+type Conflict struct {
+	Folder *Folder
+	// Path is the path of the file in conflict, relative to the local path of the folder
+	Path string
+	// ConflictPath is the path of the conflict copy, relative to the local path of the folder
+	ConflictPath string
+	// ModifiedBy is the short id of the device that modified the version kept in the conflict copy
+	ModifiedBy string
+	// Time is when syncthing created the conflict copy
+	Time time.Time
+}
+
+// ChangeDetectedEvent represents a local or remote change detected by syncthing
+type ChangeDetectedEvent struct {
+	ID   int                     `json:"id"`
+	Type string                  `json:"type"`
+	Data DataChangeDetectedEvent `json:"data"`
+}
+
+// DataChangeDetectedEvent represents the data of a change detected by syncthing
+type DataChangeDetectedEvent struct {
+	Folder string `json:"folder"`
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	Type   string `json:"type"`
+}
+
+// MonitorConflicts sends to conflicts every conflict copy created by syncthing
+func (s *Syncthing) MonitorConflicts(ctx context.Context, conflicts chan<- Conflict) {
+	ticker := time.NewTicker(5 * time.Second)
+	since := 0
+	for {
+		select {
+		case <-ticker.C:
+			events, err := s.getChangeDetectedEvents(ctx, since)
+			if err != nil {
+				oktetoLog.Infof("error getting syncthing conflicts: %s", err)
+				continue
+			}
+			for _, e := range events {
+				since = e.ID
+				if e.Data.Type != "file" || e.Data.Action == "deleted" {
+					continue
+				}
+				folder := s.getFolderByName(e.Data.Folder)
+				if folder == nil {
+					continue
+				}
+				conflict, ok := newConflict(folder, e.Data.Path)
+				if !ok {
+					continue
+				}
+				select {
+				case conflicts <- conflict:
+				case <-ctx.Done():
+					return
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Syncthing) getChangeDetectedEvents(ctx context.Context, since int) ([]ChangeDetectedEvent, error) {
+	params := map[string]string{
+		"since":   strconv.Itoa(since),
+		"timeout": "0",
+		"events":  "LocalChangeDetected,RemoteChangeDetected",
+	}
+	body, err := s.APICall(ctx, "rest/events", "GET", 200, params, true, nil, true, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []ChangeDetectedEvent{}
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("error unmarshalling events: %w", err)
+	}
+	return events, nil
+}
+
+func (s *Syncthing) getFolderByName(name string) *Folder {
+	for _, folder := range s.Folders {
+		if GetFolderName(folder) == name {
+			return folder
+		}
+	}
+	return nil
+}
+
+// GetConflicts returns the conflict copies found in the local path of the sync folders
+func (s *Syncthing) GetConflicts() ([]Conflict, error) {
+	result := []Conflict{}
+	for _, folder := range s.Folders {
+		err := filepath.WalkDir(folder.LocalPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == ".stfolder" || d.Name() == ".stversions" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			relPath, err := filepath.Rel(folder.LocalPath, path)
+			if err != nil {
+				return err
+			}
+			conflict, ok := newConflict(folder, relPath)
+			if !ok {
+				return nil
+			}
+			result = append(result, conflict)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look for conflicts in '%s': %w", folder.LocalPath, err)
+		}
+	}
+	return result, nil
+}
+
+// newConflict returns the conflict represented by a conflict copy, following the syntax
+// <name>.sync-conflict-<date>-<time>-<modifiedBy>.<ext>
+func newConflict(folder *Folder, conflictPath string) (Conflict, bool) {
+	dir, base := filepath.Split(conflictPath)
+	index := strings.LastIndex(base, conflictMarker)
+	if index < 0 {
+		return Conflict{}, false
+	}
+
+	suffix := base[index+len(conflictMarker):]
+	if len(suffix) < conflictSuffixLength {
+		return Conflict{}, false
+	}
+	t, err := time.ParseInLocation("20060102-150405", suffix[:15], time.Local)
+	if err != nil || suffix[15] != '-' {
+		return Conflict{}, false
+	}
+
+	return Conflict{
+		Folder:       folder,
+		Path:         filepath.Join(dir, base[:index]+suffix[conflictSuffixLength:]),
+		ConflictPath: conflictPath,
+		ModifiedBy:   suffix[16:conflictSuffixLength],
+		Time:         t,
+	}, true
+}
+
+// IsLocalCopy returns if the conflict copy keeps the local version of the file
+func (c *Conflict) IsLocalCopy() bool {
+	return strings.HasPrefix(LocalDeviceID, c.ModifiedBy)
+}
+
+func (c *Conflict) getVersionPaths() (string, string) {
+	original := filepath.Join(c.Folder.LocalPath, c.Path)
+	conflictCopy := filepath.Join(c.Folder.LocalPath, c.ConflictPath)
+	if c.IsLocalCopy() {
+		return conflictCopy, original
+	}
+	return original, conflictCopy
+}
+
+// KeepLocal resolves the conflict keeping the local version of the file
+func (c *Conflict) KeepLocal() error {
+	local, _ := c.getVersionPaths()
+	return c.keep(local)
+}
+
+// KeepRemote resolves the conflict keeping the remote version of the file
+func (c *Conflict) KeepRemote() error {
+	_, remote := c.getVersionPaths()
+	return c.keep(remote)
+}
+
+func (c *Conflict) keep(version string) error {
+	original := filepath.Join(c.Folder.LocalPath, c.Path)
+	conflictCopy := filepath.Join(c.Folder.LocalPath, c.ConflictPath)
+	if version == original {
+		if err := os.Remove(conflictCopy); err != nil {
+			return fmt.Errorf("failed to delete the conflict copy '%s': %w", conflictCopy, err)
+		}
+		return nil
+	}
+	if err := os.Rename(conflictCopy, original); err != nil {
+		return fmt.Errorf("failed to replace '%s' with the conflict copy: %w", original, err)
+	}
+	return nil
+}
+
+// Diff returns the differences between the local and the remote versions of the file
+func (c *Conflict) Diff() (string, error) {
+	local, remote := c.getVersionPaths()
+	localContent, err := os.ReadFile(local)
+	if err != nil {
+		return "", err
+	}
+	remoteContent, err := os.ReadFile(remote)
+	if err != nil {
+		return "", err
+	}
+	if bytes.IndexByte(localContent, 0) >= 0 || bytes.IndexByte(remoteContent, 0) >= 0 {
+		return fmt.Sprintf("Binary files '%s' and '%s' differ\n", local, remote), nil
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(localContent)),
+		B:        difflib.SplitLines(string(remoteContent)),
+		FromFile: fmt.Sprintf("local/%s", filepath.ToSlash(c.Path)),
+		ToFile:   fmt.Sprintf("remote/%s", filepath.ToSlash(c.Path)),
+		Context:  3,
+	})
+}
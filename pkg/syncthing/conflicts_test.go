@@ -0,0 +1,171 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncthing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_newConflict(t *testing.T) {
+	folder := &Folder{Name: "1", LocalPath: "/src"}
+	tests := []struct {
+		name         string
+		conflictPath string
+		expectedOK   bool
+		expectedPath string
+		expectedBy   string
+	}{
+		{
+			name:         "file with extension",
+			conflictPath: filepath.Join("app", "main.sync-conflict-20221010-101010-ABKAVQF.go"),
+			expectedOK:   true,
+			expectedPath: filepath.Join("app", "main.go"),
+			expectedBy:   "ABKAVQF",
+		},
+		{
+			name:         "file without extension",
+			conflictPath: "Makefile.sync-conflict-20221010-101010-ATOPHFJ",
+			expectedOK:   true,
+			expectedPath: "Makefile",
+			expectedBy:   "ATOPHFJ",
+		},
+		{
+			name:         "dot file",
+			conflictPath: ".sync-conflict-20221010-101010-ATOPHFJ.bashrc",
+			expectedOK:   true,
+			expectedPath: ".bashrc",
+			expectedBy:   "ATOPHFJ",
+		},
+		{
+			name:         "not a conflict",
+			conflictPath: "main.go",
+		},
+		{
+			name:         "wrong date",
+			conflictPath: "main.sync-conflict-2022-ABKAVQF.go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflict, ok := newConflict(folder, tt.conflictPath)
+			if ok != tt.expectedOK {
+				t.Fatalf("got %t, expected %t", ok, tt.expectedOK)
+			}
+			if !ok {
+				return
+			}
+			if conflict.Path != tt.expectedPath {
+				t.Errorf("got path %s, expected %s", conflict.Path, tt.expectedPath)
+			}
+			if conflict.ModifiedBy != tt.expectedBy {
+				t.Errorf("got modified by %s, expected %s", conflict.ModifiedBy, tt.expectedBy)
+			}
+		})
+	}
+}
+
+func writeConflictFiles(t *testing.T, dir, local, remote string) {
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(remote), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.sync-conflict-20221010-101010-ABKAVQF.go"), []byte(local), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetConflicts(t *testing.T) {
+	dir := t.TempDir()
+	writeConflictFiles(t, dir, "local", "remote")
+	if err := os.MkdirAll(filepath.Join(dir, ".stversions"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".stversions", "main.sync-conflict-20221010-101010-ABKAVQF.go"), []byte(""), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Syncthing{Folders: []*Folder{{Name: "1", LocalPath: dir}}}
+	conflicts, err := s.GetConflicts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("got %d conflicts, expected 1", len(conflicts))
+	}
+	if conflicts[0].Path != "main.go" {
+		t.Errorf("got path %s, expected main.go", conflicts[0].Path)
+	}
+	if !conflicts[0].IsLocalCopy() {
+		t.Errorf("the conflict copy should keep the local version")
+	}
+}
+
+func TestConflictResolution(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolve  func(c *Conflict) error
+		expected string
+	}{
+		{
+			name:     "keep local",
+			resolve:  (*Conflict).KeepLocal,
+			expected: "local",
+		},
+		{
+			name:     "keep remote",
+			resolve:  (*Conflict).KeepRemote,
+			expected: "remote",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConflictFiles(t, dir, "local", "remote")
+			conflict, _ := newConflict(&Folder{Name: "1", LocalPath: dir}, "main.sync-conflict-20221010-101010-ABKAVQF.go")
+
+			if err := tt.resolve(&conflict); err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := os.ReadFile(filepath.Join(dir, "main.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.expected {
+				t.Errorf("got %s, expected %s", string(b), tt.expected)
+			}
+			if _, err := os.Stat(filepath.Join(dir, conflict.ConflictPath)); !os.IsNotExist(err) {
+				t.Errorf("the conflict copy wasn't removed")
+			}
+		})
+	}
+}
+
+func TestConflictDiff(t *testing.T) {
+	dir := t.TempDir()
+	writeConflictFiles(t, dir, "package main\n\nfunc local() {}\n", "package main\n\nfunc remote() {}\n")
+	conflict, _ := newConflict(&Folder{Name: "1", LocalPath: dir}, "main.sync-conflict-20221010-101010-ABKAVQF.go")
+
+	diff, err := conflict.Diff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"--- local/main.go", "+++ remote/main.go", "-func local() {}", "+func remote() {}"} {
+		if !strings.Contains(diff, expected) {
+			t.Errorf("diff doesn't contain '%s':\n%s", expected, diff)
+		}
+	}
+}
@@ -98,15 +98,19 @@ type Syncthing struct {
 	pid              int           `yaml:"-"`
 	RescanInterval   string        `yaml:"-"`
 	Compression      string        `yaml:"-"`
+	UploadLimit      int           `yaml:"uploadLimit,omitempty"`
+	DownloadLimit    int           `yaml:"downloadLimit,omitempty"`
 	timeout          time.Duration `yaml:"-"`
 }
 
 // Folder represents a sync folder
 type Folder struct {
-	Name        string `yaml:"name"`
-	LocalPath   string `yaml:"localPath"`
-	RemotePath  string `yaml:"remotePath"`
-	Overwritten bool   `yaml:"-"`
+	Name         string         `yaml:"name"`
+	LocalPath    string         `yaml:"localPath"`
+	RemotePath   string         `yaml:"remotePath"`
+	Mode         model.SyncMode `yaml:"mode,omitempty"`
+	IgnoreDelete bool           `yaml:"ignoreDelete,omitempty"`
+	Overwritten  bool           `yaml:"-"`
 }
 
 // Status represents the status of a syncthing folder.
@@ -123,7 +127,10 @@ type StateChangedEvent struct {
 
 // DataStateChangedEvent represents data state changed in syncthing.
 type DataStateChangedEvent struct {
-	State string `json:"to"`
+	Folder   string  `json:"folder"`
+	From     string  `json:"from"`
+	State    string  `json:"to"`
+	Duration float64 `json:"duration"`
 }
 
 // FolderSummaryEvent represents folder summary in syncthing.
@@ -164,9 +171,17 @@ type ItemEvent struct {
 
 // Connections represents syncthing connections.
 type Connections struct {
+	Total       ConnectionsTotal      `json:"total"`
 	Connections map[string]Connection `json:"connections"`
 }
 
+// ConnectionsTotal represents the bytes transferred by all the syncthing connections.
+type ConnectionsTotal struct {
+	At            time.Time `json:"at"`
+	InBytesTotal  int64     `json:"inBytesTotal"`
+	OutBytesTotal int64     `json:"outBytesTotal"`
+}
+
 // Connection represents syncthing connection.
 type Connection struct {
 	Connected bool `json:"connected"`
@@ -175,6 +190,7 @@ type Connection struct {
 // DownloadProgressData represents an the information about a DownloadProgress event
 type DownloadProgressData struct {
 	BytesTotal int64 `json:"bytesTotal"`
+	BytesDone  int64 `json:"bytesDone"`
 }
 
 // New constructs a new Syncthing.
@@ -235,6 +251,8 @@ func New(dev *model.Dev) (*Syncthing, error) {
 		Folders:          []*Folder{},
 		RescanInterval:   strconv.Itoa(dev.Sync.RescanInterval),
 		Compression:      compression,
+		UploadLimit:      dev.Sync.UploadLimit,
+		DownloadLimit:    dev.Sync.DownloadLimit,
 		timeout:          time.Duration(dev.Timeout.Default),
 	}
 	index := 1
@@ -247,9 +265,11 @@ func New(dev *model.Dev) (*Syncthing, error) {
 			s.Folders = append(
 				s.Folders,
 				&Folder{
-					Name:       strconv.Itoa(index),
-					LocalPath:  sync.LocalPath,
-					RemotePath: sync.RemotePath,
+					Name:         strconv.Itoa(index),
+					LocalPath:    sync.LocalPath,
+					RemotePath:   sync.RemotePath,
+					Mode:         sync.GetMode(),
+					IgnoreDelete: sync.IgnoreDelete,
 				},
 			)
 			index++
@@ -259,6 +279,31 @@ func New(dev *model.Dev) (*Syncthing, error) {
 	return s, nil
 }
 
+// LocalType returns the syncthing type of the local folder.
+// Two-way folders use the given type, which changes once the initial synchronization is completed
+func (f *Folder) LocalType(twoWayType string) string {
+	switch f.Mode {
+	case model.SyncModeSendOnly:
+		return "sendonly"
+	case model.SyncModeReceiveOnly:
+		return "receiveonly"
+	default:
+		return twoWayType
+	}
+}
+
+// RemoteType returns the syncthing type of the remote folder
+func (f *Folder) RemoteType() string {
+	switch f.Mode {
+	case model.SyncModeSendOnly:
+		return "receiveonly"
+	case model.SyncModeReceiveOnly:
+		return "sendonly"
+	default:
+		return "sendreceive"
+	}
+}
+
 func (s *Syncthing) initConfig() error {
 	if err := os.MkdirAll(s.Home, 0700); err != nil {
 		return fmt.Errorf("failed to create %s: %s", s.Home, err)
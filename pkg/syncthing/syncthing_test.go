@@ -16,6 +16,7 @@ package syncthing
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/okteto/okteto/pkg/model"
@@ -41,3 +42,85 @@ func TestGetFiles(t *testing.T) {
 		t.Errorf("got %s, expected %s", info, expected)
 	}
 }
+
+func TestFolderTypes(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           model.SyncMode
+		expectedLocal  string
+		expectedRemote string
+	}{
+		{
+			name:           "two-way",
+			mode:           model.SyncModeTwoWay,
+			expectedLocal:  "sendonly",
+			expectedRemote: "sendreceive",
+		},
+		{
+			name:           "send-only",
+			mode:           model.SyncModeSendOnly,
+			expectedLocal:  "sendonly",
+			expectedRemote: "receiveonly",
+		},
+		{
+			name:           "receive-only",
+			mode:           model.SyncModeReceiveOnly,
+			expectedLocal:  "receiveonly",
+			expectedRemote: "sendonly",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folder := &Folder{Mode: tt.mode}
+			if result := folder.LocalType("sendonly"); result != tt.expectedLocal {
+				t.Errorf("got local type %s, expected %s", result, tt.expectedLocal)
+			}
+			if result := folder.RemoteType(); result != tt.expectedRemote {
+				t.Errorf("got remote type %s, expected %s", result, tt.expectedRemote)
+			}
+		})
+	}
+}
+
+func TestUpdateConfig(t *testing.T) {
+	s := &Syncthing{
+		Home:          t.TempDir(),
+		Type:          "sendreceive",
+		UploadLimit:   500,
+		DownloadLimit: 1000,
+		Folders: []*Folder{
+			{
+				Name:      "1",
+				LocalPath: "/src",
+				Mode:      model.SyncModeTwoWay,
+			},
+			{
+				Name:         "2",
+				LocalPath:    "/src/dist",
+				Mode:         model.SyncModeReceiveOnly,
+				IgnoreDelete: true,
+			},
+		},
+	}
+	if err := s.UpdateConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(s.Home, configFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := string(b)
+	for _, expected := range []string{
+		`<folder id="okteto-1" label="1" path="/src" type="sendreceive"`,
+		`<folder id="okteto-2" label="2" path="/src/dist" type="receiveonly"`,
+		"<ignoreDelete>false</ignoreDelete>",
+		"<ignoreDelete>true</ignoreDelete>",
+		"<maxSendKbps>500</maxSendKbps>",
+		"<maxRecvKbps>1000</maxRecvKbps>",
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("config doesn't contain '%s'", expected)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncthing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	oktetoLog "github.com/okteto/okteto/pkg/log"
+)
+
+const (
+	// UploadDirection is the direction of the files sent to the remote syncthing
+	UploadDirection = "upload"
+	// DownloadDirection is the direction of the files received from the remote syncthing
+	DownloadDirection = "download"
+
+	// largeDirectorySize is the size from which a synchronized directory is reported as a candidate for the .stignore file
+	largeDirectorySize = 100 * 1024 * 1024
+
+	maxInFlightFiles    = 5
+	maxLargeDirectories = 5
+)
+
+// Telemetry represents the performance of the file synchronization
+type Telemetry struct {
+	UploadRate       float64           `json:"uploadBytesPerSecond"`
+	DownloadRate     float64           `json:"downloadBytesPerSecond"`
+	Folders          []FolderTelemetry `json:"folders"`
+	InFlightFiles    []InFlightFile    `json:"inFlightFiles"`
+	LargeDirectories []LargeDirectory  `json:"largeDirectories"`
+}
+
+// FolderTelemetry represents the performance of the synchronization of a sync folder
+type FolderTelemetry struct {
+	LocalPath            string  `json:"localPath"`
+	RemotePath           string  `json:"remotePath"`
+	State                string  `json:"state"`
+	LocalFiles           int64   `json:"localFiles"`
+	PendingUploadFiles   int64   `json:"pendingUploadFiles"`
+	PendingUploadBytes   int64   `json:"pendingUploadBytes"`
+	PendingDownloadFiles int64   `json:"pendingDownloadFiles"`
+	PendingDownloadBytes int64   `json:"pendingDownloadBytes"`
+	LocalScanDuration    float64 `json:"localScanDurationSeconds"`
+	RemoteScanDuration   float64 `json:"remoteScanDurationSeconds"`
+}
+
+// InFlightFile represents a file being transferred by syncthing
+type InFlightFile struct {
+	Path       string `json:"path"`
+	Direction  string `json:"direction"`
+	BytesTotal int64  `json:"bytesTotal"`
+	BytesDone  int64  `json:"bytesDone"`
+}
+
+// LargeDirectory represents a large synchronized directory that could be added to the .stignore file
+type LargeDirectory struct {
+	Path     string `json:"path"`
+	Bytes    int64  `json:"bytes"`
+	Files    int64  `json:"files"`
+	Stignore string `json:"stignore"`
+}
+
+// FolderDBStatus represents the database status of a syncthing folder
+type FolderDBStatus struct {
+	State      string `json:"state"`
+	LocalFiles int64  `json:"localFiles"`
+	NeedFiles  int64  `json:"needFiles"`
+	NeedBytes  int64  `json:"needBytes"`
+}
+
+// GetTelemetry returns the performance of the file synchronization.
+// The transfer rates are measured during the given interval
+func (s *Syncthing) GetTelemetry(ctx context.Context, interval time.Duration) (*Telemetry, error) {
+	startTime := time.Now()
+	start, err := s.getConnectionsTotal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Telemetry{
+		Folders:          []FolderTelemetry{},
+		InFlightFiles:    []InFlightFile{},
+		LargeDirectories: []LargeDirectory{},
+	}
+	localScans := s.getScanDurations(ctx, true)
+	remoteScans := s.getScanDurations(ctx, false)
+	for _, folder := range s.Folders {
+		folderTelemetry, err := s.getFolderTelemetry(ctx, folder)
+		if err != nil {
+			return nil, err
+		}
+		folderTelemetry.LocalScanDuration = localScans[GetFolderName(folder)]
+		folderTelemetry.RemoteScanDuration = remoteScans[GetFolderName(folder)]
+		t.Folders = append(t.Folders, *folderTelemetry)
+	}
+
+	t.InFlightFiles = append(t.InFlightFiles, s.getInFlightFiles(ctx, false, UploadDirection)...)
+	t.InFlightFiles = append(t.InFlightFiles, s.getInFlightFiles(ctx, true, DownloadDirection)...)
+	sort.SliceStable(t.InFlightFiles, func(i, j int) bool {
+		return t.InFlightFiles[i].BytesTotal > t.InFlightFiles[j].BytesTotal
+	})
+	if len(t.InFlightFiles) > maxInFlightFiles {
+		t.InFlightFiles = t.InFlightFiles[:maxInFlightFiles]
+	}
+
+	t.LargeDirectories = s.getLargeDirectories(ctx)
+
+	select {
+	case <-time.After(time.Until(startTime.Add(interval))):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	end, err := s.getConnectionsTotal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.UploadRate = getTransferRate(start.OutBytesTotal, end.OutBytesTotal, end.At.Sub(start.At))
+	t.DownloadRate = getTransferRate(start.InBytesTotal, end.InBytesTotal, end.At.Sub(start.At))
+	return t, nil
+}
+
+func (s *Syncthing) getConnectionsTotal(ctx context.Context) (*ConnectionsTotal, error) {
+	body, err := s.APICall(ctx, "rest/system/connections", "GET", 200, nil, true, nil, true, 3)
+	if err != nil {
+		return nil, fmt.Errorf("error getting syncthing connections: %w", err)
+	}
+	connections := &Connections{}
+	if err := json.Unmarshal(body, connections); err != nil {
+		return nil, fmt.Errorf("error unmarshalling syncthing connections: %w", err)
+	}
+	return &connections.Total, nil
+}
+
+func getTransferRate(start, end int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 || end < start {
+		return 0
+	}
+	return float64(end-start) / elapsed.Seconds()
+}
+
+func (s *Syncthing) getFolderTelemetry(ctx context.Context, folder *Folder) (*FolderTelemetry, error) {
+	local, err := s.getFolderDBStatus(ctx, folder, true)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := s.getFolderDBStatus(ctx, folder, false)
+	if err != nil {
+		return nil, err
+	}
+	return &FolderTelemetry{
+		LocalPath:            folder.LocalPath,
+		RemotePath:           folder.RemotePath,
+		State:                local.State,
+		LocalFiles:           local.LocalFiles,
+		PendingUploadFiles:   remote.NeedFiles,
+		PendingUploadBytes:   remote.NeedBytes,
+		PendingDownloadFiles: local.NeedFiles,
+		PendingDownloadBytes: local.NeedBytes,
+	}, nil
+}
+
+func (s *Syncthing) getFolderDBStatus(ctx context.Context, folder *Folder, local bool) (*FolderDBStatus, error) {
+	params := map[string]string{"folder": GetFolderName(folder)}
+	body, err := s.APICall(ctx, "rest/db/status", "GET", 200, params, local, nil, true, 3)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the status of '%s' local=%t: %w", folder.LocalPath, local, err)
+	}
+	status := &FolderDBStatus{}
+	if err := json.Unmarshal(body, status); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the status of '%s' local=%t: %w", folder.LocalPath, local, err)
+	}
+	return status, nil
+}
+
+// getScanDurations returns the duration in seconds of the last scan of each folder
+func (s *Syncthing) getScanDurations(ctx context.Context, local bool) map[string]float64 {
+	result := map[string]float64{}
+	params := map[string]string{
+		"since":   "0",
+		"timeout": "0",
+		"events":  "StateChanged",
+	}
+	body, err := s.APICall(ctx, "rest/events", "GET", 200, params, local, nil, true, 3)
+	if err != nil {
+		oktetoLog.Infof("error getting scan durations local=%t: %s", local, err)
+		return result
+	}
+	events := []StateChangedEvent{}
+	if err := json.Unmarshal(body, &events); err != nil {
+		oktetoLog.Infof("error unmarshalling events: %s", err)
+		return result
+	}
+	for _, e := range events {
+		if e.Data.From == "scanning" {
+			result[e.Data.Folder] = e.Data.Duration
+		}
+	}
+	return result
+}
+
+func (s *Syncthing) getInFlightFiles(ctx context.Context, local bool, direction string) []InFlightFile {
+	result := []InFlightFile{}
+	params := map[string]string{
+		"since":   "0",
+		"limit":   "1",
+		"timeout": "0",
+		"events":  "DownloadProgress",
+	}
+	body, err := s.APICall(ctx, "rest/events", "GET", 200, params, local, nil, true, 3)
+	if err != nil {
+		oktetoLog.Infof("error getting in-flight files local=%t: %s", local, err)
+		return result
+	}
+	events := []ItemEvent{}
+	if err := json.Unmarshal(body, &events); err != nil {
+		oktetoLog.Infof("error unmarshalling events: %s", err)
+		return result
+	}
+	if len(events) == 0 {
+		return result
+	}
+
+	for folderName, files := range events[len(events)-1].Data {
+		localPath := ""
+		if folder := s.getFolderByName(folderName); folder != nil {
+			localPath = folder.LocalPath
+		}
+		for name, progress := range files {
+			result = append(result, InFlightFile{
+				Path:       filepath.Join(localPath, name),
+				Direction:  direction,
+				BytesTotal: progress.BytesTotal,
+				BytesDone:  progress.BytesDone,
+			})
+		}
+	}
+	return result
+}
+
+// getLargeDirectories returns the largest top level directories synchronized by syncthing
+func (s *Syncthing) getLargeDirectories(ctx context.Context) []LargeDirectory {
+	result := []LargeDirectory{}
+	for _, folder := range s.Folders {
+		entries, err := os.ReadDir(folder.LocalPath)
+		if err != nil {
+			oktetoLog.Infof("error reading '%s': %s", folder.LocalPath, err)
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || entry.Name() == ".stfolder" || entry.Name() == ".stversions" {
+				continue
+			}
+			if !s.isSynchronized(ctx, folder, entry.Name()) {
+				continue
+			}
+			dir := getDirectorySize(filepath.Join(folder.LocalPath, entry.Name()))
+			if dir.Bytes < largeDirectorySize {
+				continue
+			}
+			dir.Stignore = entry.Name()
+			result = append(result, dir)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Bytes > result[j].Bytes
+	})
+	if len(result) > maxLargeDirectories {
+		result = result[:maxLargeDirectories]
+	}
+	return result
+}
+
+// isSynchronized returns if a path is in the index of the local syncthing, that is, it's not ignored
+func (s *Syncthing) isSynchronized(ctx context.Context, folder *Folder, name string) bool {
+	params := map[string]string{"folder": GetFolderName(folder), "file": name}
+	_, err := s.APICall(ctx, "rest/db/file", "GET", 200, params, true, nil, false, 0)
+	return err == nil
+}
+
+func getDirectorySize(path string) LargeDirectory {
+	result := LargeDirectory{Path: path}
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		result.Bytes += info.Size()
+		result.Files++
+		return nil
+	})
+	if err != nil {
+		oktetoLog.Infof("error computing the size of '%s': %s", path, err)
+	}
+	return result
+}
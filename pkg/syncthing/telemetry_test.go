@@ -0,0 +1,166 @@
+// Copyright 2022 The Okteto Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncthing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTelemetryServer(t *testing.T, needFiles int, progress string) *httptest.Server {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/system/connections":
+			requests++
+			at := time.Date(2022, 10, 10, 10, 10, requests*2, 0, time.UTC).Format(time.RFC3339)
+			fmt.Fprintf(w, `{"total": {"at": "%s", "inBytesTotal": %d, "outBytesTotal": %d}}`, at, requests*1000, requests*4000)
+		case "/rest/db/status":
+			fmt.Fprintf(w, `{"state": "idle", "localFiles": 10, "needFiles": %d, "needBytes": %d}`, needFiles, needFiles*100)
+		case "/rest/db/file":
+			if r.URL.Query().Get("file") == "ignored" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		case "/rest/events":
+			if r.URL.Query().Get("events") == "StateChanged" {
+				fmt.Fprint(w, `[{"type": "StateChanged", "data": {"folder": "okteto-1", "from": "scanning", "to": "idle", "duration": 1.5}}, {"type": "StateChanged", "data": {"folder": "okteto-1", "from": "idle", "to": "scanning", "duration": 10}}]`)
+				return
+			}
+			fmt.Fprintf(w, `[{"id": 1, "data": {"okteto-1": %s}}]`, progress)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetTelemetry(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"big", "ignored", "small"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "small", "a.txt"), []byte("a"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"big", "ignored"} {
+		f, err := os.Create(filepath.Join(dir, name, "data.bin"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Truncate(2 * largeDirectorySize); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+
+	local := newTelemetryServer(t, 1, `{"big/data.bin": {"bytesTotal": 300, "bytesDone": 100}}`)
+	remote := newTelemetryServer(t, 2, `{"main.go": {"bytesTotal": 500, "bytesDone": 400}}`)
+	s := &Syncthing{
+		Client:           NewAPIClient(),
+		GUIAddress:       strings.TrimPrefix(local.URL, "http://"),
+		RemoteGUIAddress: strings.TrimPrefix(remote.URL, "http://"),
+		Folders:          []*Folder{{Name: "1", LocalPath: dir, RemotePath: "/app"}},
+	}
+
+	telemetry, err := s.GetTelemetry(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if telemetry.UploadRate != 2000 || telemetry.DownloadRate != 500 {
+		t.Errorf("got rates %f/%f, expected 2000/500", telemetry.UploadRate, telemetry.DownloadRate)
+	}
+
+	if len(telemetry.Folders) != 1 {
+		t.Fatalf("got %d folders, expected 1", len(telemetry.Folders))
+	}
+	folder := telemetry.Folders[0]
+	if folder.PendingUploadFiles != 2 || folder.PendingUploadBytes != 200 || folder.PendingDownloadFiles != 1 || folder.PendingDownloadBytes != 100 {
+		t.Errorf("wrong pending files: %+v", folder)
+	}
+	if folder.LocalScanDuration != 1.5 || folder.RemoteScanDuration != 1.5 {
+		t.Errorf("wrong scan durations: %+v", folder)
+	}
+
+	expectedFiles := []InFlightFile{
+		{Path: filepath.Join(dir, "main.go"), Direction: UploadDirection, BytesTotal: 500, BytesDone: 400},
+		{Path: filepath.Join(dir, "big", "data.bin"), Direction: DownloadDirection, BytesTotal: 300, BytesDone: 100},
+	}
+	if len(telemetry.InFlightFiles) != len(expectedFiles) {
+		t.Fatalf("got in-flight files %+v, expected %+v", telemetry.InFlightFiles, expectedFiles)
+	}
+	for i := range expectedFiles {
+		if telemetry.InFlightFiles[i] != expectedFiles[i] {
+			t.Errorf("got in-flight file %+v, expected %+v", telemetry.InFlightFiles[i], expectedFiles[i])
+		}
+	}
+
+	if len(telemetry.LargeDirectories) != 1 {
+		t.Fatalf("got large directories %+v, expected 1", telemetry.LargeDirectories)
+	}
+	expectedDir := LargeDirectory{Path: filepath.Join(dir, "big"), Bytes: 2 * largeDirectorySize, Files: 1, Stignore: "big"}
+	if telemetry.LargeDirectories[0] != expectedDir {
+		t.Errorf("got large directory %+v, expected %+v", telemetry.LargeDirectories[0], expectedDir)
+	}
+}
+
+func Test_getTransferRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    int64
+		end      int64
+		elapsed  time.Duration
+		expected float64
+	}{
+		{
+			name:     "transfer",
+			start:    1000,
+			end:      3000,
+			elapsed:  2 * time.Second,
+			expected: 1000,
+		},
+		{
+			name:     "no-elapsed-time",
+			start:    1000,
+			end:      3000,
+			expected: 0,
+		},
+		{
+			name:     "restarted-counters",
+			start:    3000,
+			end:      1000,
+			elapsed:  time.Second,
+			expected: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := getTransferRate(tt.start, tt.end, tt.elapsed); result != tt.expected {
+				t.Errorf("got %f, expected %f", result, tt.expected)
+			}
+		})
+	}
+}